@@ -0,0 +1,102 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/imroc/req/v3"
+	"github.com/tidwall/gjson"
+)
+
+// secUIDRegex 主播 sec_uid，可出现在主页链接 /user/<sec_uid> 或分享链接的 sec_uid 参数中
+var secUIDRegex = regexp.MustCompile(`MS4wLjABAAAA[0-9A-Za-z_\-]+`)
+
+// AnchorInfo 主播及其直播间信息
+type AnchorInfo struct {
+	SecUID   string
+	Nickname string
+	LiveID   string // 网页直播间号（web_rid），未开过播时可能为空
+	RoomID   string // 当前场次的 room_id，未开播时可能为空
+	Title    string
+	Living   bool
+}
+
+// LookupAnchor 按主播的 sec_uid 或主页链接（www.douyin.com/user/... 或指向主页的短链）查询其直播间，
+// 用于按人而不是按场次的房间号跟踪主播
+func LookupAnchor(ctx context.Context, input string) (*AnchorInfo, error) {
+	dl := &DouyinLive{client: req.C()}
+	return dl.lookupAnchor(ctx, input)
+}
+
+// lookupAnchor 见 LookupAnchor
+func (dl *DouyinLive) lookupAnchor(ctx context.Context, input string) (*AnchorInfo, error) {
+	secUID := secUIDRegex.FindString(input)
+	if secUID == "" {
+		link := shareURLRegex.FindString(input)
+		if link == "" {
+			return nil, fmt.Errorf("%w: 无法识别的主播: %q", ErrRoomNotFound, input)
+		}
+		final, err := dl.followRedirects(ctx, link, func(u string) bool { return secUIDRegex.MatchString(u) })
+		if err != nil {
+			return nil, err
+		}
+		if secUID = secUIDRegex.FindString(final); secUID == "" {
+			return nil, fmt.Errorf("%w: 链接未指向主播主页: %s", ErrRoomNotFound, final)
+		}
+	}
+	return dl.anchorBySecUID(ctx, secUID)
+}
+
+// anchorBySecUID 按 sec_uid 查询主播的直播间
+func (dl *DouyinLive) anchorBySecUID(ctx context.Context, secUID string) (*AnchorInfo, error) {
+	result, err := dl.reflowInfo(ctx, map[string]string{"sec_user_id": secUID})
+	if err != nil {
+		return nil, err
+	}
+	room := result.Get("data.room")
+	info := &AnchorInfo{
+		SecUID:   secUID,
+		Nickname: room.Get("owner.nickname").String(),
+		LiveID:   room.Get("owner.web_rid").String(),
+		RoomID:   room.Get("id_str").String(),
+		Title:    room.Get("title").String(),
+		Living:   room.Get("status").Int() == 2,
+	}
+	if info.Nickname == "" {
+		info.Nickname = result.Get("data.user.nickname").String()
+	}
+	if !room.Exists() && info.Nickname == "" {
+		return nil, fmt.Errorf("%w: 未找到主播 %s", ErrRoomNotFound, secUID)
+	}
+	return info, nil
+}
+
+// liveIDBySecUID 返回主播的网页直播间号，查不到时按是否开播返回对应的哨兵错误
+func (dl *DouyinLive) liveIDBySecUID(ctx context.Context, secUID string) (string, error) {
+	info, err := dl.anchorBySecUID(ctx, secUID)
+	if err != nil {
+		return "", err
+	}
+	if !liveIDRegex.MatchString(info.LiveID) {
+		return "", fmt.Errorf("%w: 主播 %s 没有可用的直播间号", ErrRoomOffline, info.Nickname)
+	}
+	return info.LiveID, nil
+}
+
+// reflowInfo 调用 reflow/info 接口
+func (dl *DouyinLive) reflowInfo(ctx context.Context, params map[string]string) (gjson.Result, error) {
+	resp, err := dl.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"type_id": "0",
+			"live_id": "1",
+			"app_id":  "1128",
+		}).
+		SetQueryParams(params).
+		Get(reflowInfoURL)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("查询直播间信息失败: %w", err)
+	}
+	return gjson.Parse(resp.String()), nil
+}
@@ -0,0 +1,211 @@
+// Package api 不建立 WebSocket 连接、只访问抖音直播 HTTP 接口的客户端：ttwid、直播间页面、
+// 进房接口、礼物列表与榜单。同一 Client 下的直播间共享 ttwid、登录 cookie、msToken/a_bogus 与限速
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// Options 客户端配置
+type Options struct {
+	Cookies    string                     // 登录 cookie，格式同浏览器中的 Cookie 请求头，可为空
+	Interval   time.Duration              // 相邻两次请求的最小间隔，0 表示不限速
+	TTWIDStore douyinLive.TTWIDStore      // ttwid 缓存，为空时使用进程内缓存
+	MsToken    douyinLive.MsTokenProvider // msToken 来源，为空时随机生成
+	ABogus     douyinLive.ABogusSigner    // a_bogus 签名实现，为空时不附加
+	Extra      []douyinLive.Option        // 附加到每个直播间实例的选项
+}
+
+// Client 抖音直播 HTTP 接口客户端，可并发使用
+type Client struct {
+	opts    Options
+	limiter *limiter
+
+	mu    sync.Mutex
+	rooms map[string]*douyinLive.DouyinLive
+}
+
+// New 创建客户端
+func New(opts Options) *Client {
+	if opts.TTWIDStore == nil {
+		opts.TTWIDStore = &douyinLive.MemoryTTWIDStore{}
+	}
+	return &Client{
+		opts:    opts,
+		limiter: &limiter{interval: opts.Interval},
+		rooms:   make(map[string]*douyinLive.DouyinLive),
+	}
+}
+
+// TTWID 返回共享的 ttwid，缓存未命中时请求首页
+func (c *Client) TTWID(ctx context.Context) (string, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return "", err
+	}
+	dl, err := c.newRoom("")
+	if err != nil {
+		return "", err
+	}
+	return dl.FetchTTWID(ctx)
+}
+
+// Resolve 把直播间链接、短链、分享文案或主播 sec_uid 解析为直播间号
+func (c *Client) Resolve(ctx context.Context, input string) (string, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return "", err
+	}
+	return douyinLive.ResolveLiveID(ctx, input)
+}
+
+// CheckLive 检查直播间是否开播，未开播时返回包装了 douyinLive.ErrRoomOffline 的错误
+func (c *Client) CheckLive(ctx context.Context, liveID string) error {
+	dl, err := c.room(ctx, liveID, false)
+	if err != nil {
+		return err
+	}
+	if err := c.limiter.wait(ctx); err != nil {
+		return err
+	}
+	return dl.CheckLive(ctx)
+}
+
+// Page 重新解析直播间页面，返回页面中的房间与主播信息
+func (c *Client) Page(ctx context.Context, liveID string) (douyinLive.RoomInfo, error) {
+	dl, err := c.room(ctx, liveID, false)
+	if err != nil {
+		return douyinLive.RoomInfo{}, err
+	}
+	if err := c.prefetch(ctx, dl); err != nil {
+		return douyinLive.RoomInfo{}, err
+	}
+	return dl.RoomInfo(), nil
+}
+
+// EnterRoom 通过进房接口查询房间信息，接口不可用时回退到页面解析
+func (c *Client) EnterRoom(ctx context.Context, liveID string) (*douyinLive.RoomInfo, error) {
+	dl, err := c.room(ctx, liveID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	return dl.FetchRoomInfo(ctx)
+}
+
+// GiftList 拉取直播间的礼物字典
+func (c *Client) GiftList(ctx context.Context, liveID string) (*douyinLive.GiftCatalog, error) {
+	dl, err := c.room(ctx, liveID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	return dl.FetchGiftCatalog(ctx)
+}
+
+// AudienceRank 查询直播间当前的观众贡献榜
+func (c *Client) AudienceRank(ctx context.Context, liveID string) (*douyinLive.RankList, error) {
+	dl, err := c.room(ctx, liveID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	return dl.FetchAudienceRank(ctx)
+}
+
+// OnlineAudience 查询直播间的在线观众列表
+func (c *Client) OnlineAudience(ctx context.Context, liveID string) (*douyinLive.RankList, error) {
+	dl, err := c.room(ctx, liveID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	return dl.FetchOnlineAudience(ctx)
+}
+
+// room 返回直播间对应的实例，prefetch 为 true 时确保已解析过页面得到 roomID
+func (c *Client) room(ctx context.Context, liveID string, prefetch bool) (*douyinLive.DouyinLive, error) {
+	c.mu.Lock()
+	dl, ok := c.rooms[liveID]
+	c.mu.Unlock()
+	if !ok {
+		var err error
+		if dl, err = c.newRoom(liveID); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.rooms[liveID] = dl
+		c.mu.Unlock()
+	}
+	if prefetch && dl.RoomID() == "" {
+		if err := c.prefetch(ctx, dl); err != nil {
+			return nil, err
+		}
+	}
+	return dl, nil
+}
+
+// prefetch 解析直播间页面，计入一次限速
+func (c *Client) prefetch(ctx context.Context, dl *douyinLive.DouyinLive) error {
+	if err := c.limiter.wait(ctx); err != nil {
+		return err
+	}
+	return dl.Prefetch(ctx)
+}
+
+// newRoom 创建不连接的直播间实例
+func (c *Client) newRoom(liveID string) (*douyinLive.DouyinLive, error) {
+	opts := []douyinLive.Option{douyinLive.WithTTWIDStore(c.opts.TTWIDStore, 0)}
+	if c.opts.Cookies != "" {
+		opts = append(opts, douyinLive.WithCookieString(c.opts.Cookies))
+	}
+	if c.opts.MsToken != nil {
+		opts = append(opts, douyinLive.WithMsToken(c.opts.MsToken))
+	}
+	if c.opts.ABogus != nil {
+		opts = append(opts, douyinLive.WithABogus(c.opts.ABogus))
+	}
+	opts = append(opts, c.opts.Extra...)
+	return douyinLive.NewDouyinLive(liveID, nil, opts...)
+}
+
+// limiter 保证相邻请求之间至少间隔 interval
+type limiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// wait 等待下一个可用的请求时刻
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
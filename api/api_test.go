@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestClientSharesTTWID(t *testing.T) {
+	store := &douyinLive.MemoryTTWIDStore{}
+	store.Set(context.Background(), "cached", time.Hour)
+	c := New(Options{TTWIDStore: store})
+	for range 2 {
+		ttwid, err := c.TTWID(context.Background())
+		if err != nil || ttwid != "cached" {
+			t.Fatalf("TTWID = %q, %v", ttwid, err)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := &limiter{interval: 20 * time.Millisecond}
+	start := time.Now()
+	for range 4 {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("4 次请求耗时 %s，应至少间隔 3 个 interval", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.next = time.Now().Add(time.Hour)
+	if err := l.wait(ctx); err == nil {
+		t.Fatal("ctx 取消后应返回错误")
+	}
+}
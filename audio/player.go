@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Player 播放单个音效，阻塞到播放结束或 ctx 结束
+type Player interface {
+	Play(ctx context.Context, sound string) error
+}
+
+// PlayerFunc 函数形式的 Player
+type PlayerFunc func(ctx context.Context, sound string) error
+
+// Play 实现 Player
+func (f PlayerFunc) Play(ctx context.Context, sound string) error {
+	return f(ctx, sound)
+}
+
+// CommandPlayer 调用外部播放器播放，参数中的 {file} 替换为音效路径，
+// 也可用于把音效名转交给 OBS、Voicemeeter 等外部程序的命令行
+type CommandPlayer struct {
+	Name string
+	Args []string
+}
+
+// DefaultPlayer 按系统选择播放器：Windows 用 PowerShell，macOS 用 afplay，其余用 ffplay
+func DefaultPlayer() CommandPlayer {
+	switch runtime.GOOS {
+	case "windows":
+		return CommandPlayer{Name: "powershell", Args: []string{"-NoProfile", "-Command",
+			"(New-Object Media.SoundPlayer '{file}').PlaySync()"}}
+	case "darwin":
+		return CommandPlayer{Name: "afplay", Args: []string{"{file}"}}
+	default:
+		return CommandPlayer{Name: "ffplay", Args: []string{"-nodisp", "-autoexit", "-loglevel", "quiet", "{file}"}}
+	}
+}
+
+// Play 实现 Player
+func (p CommandPlayer) Play(ctx context.Context, sound string) error {
+	if p.Name == "" {
+		return errors.New("未指定播放器命令")
+	}
+	args := make([]string, len(p.Args))
+	for i, arg := range p.Args {
+		args[i] = strings.ReplaceAll(arg, "{file}", sound)
+	}
+	return exec.CommandContext(ctx, p.Name, args...).Run()
+}
@@ -0,0 +1,170 @@
+// Package audio 按礼物规则播放音效，音效排队依次播放，不会互相重叠
+package audio
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// defaultMaxQueue 队列默认容量
+const defaultMaxQueue = 32
+
+// Rule 礼物音效规则，GiftID、GiftName、MinDiamond 中设置了的条件需全部满足
+type Rule struct {
+	GiftID     uint64
+	GiftName   string
+	MinDiamond int64  // 整次赠送的总价值（抖币）下限
+	Sound      string // 音效文件路径，或外部播放器可识别的名称
+	Priority   int    // 优先级高的音效插队到队列前面，同优先级按到达顺序
+}
+
+// match 判断礼物是否满足规则
+func (r Rule) match(gift *douyinLive.GiftEvent) bool {
+	if r.GiftID != 0 && r.GiftID != gift.GiftID {
+		return false
+	}
+	if r.GiftName != "" && r.GiftName != gift.GiftName {
+		return false
+	}
+	return gift.TotalDiamond() >= r.MinDiamond
+}
+
+// Options 音效队列配置
+type Options struct {
+	Rules    []Rule        // 按顺序匹配，取第一条满足的规则
+	Player   Player        // 播放器，默认 DefaultPlayer()
+	MaxQueue int           // 排队的最大音效数，满时丢弃新到达的低优先级音效，默认 32
+	Gap      time.Duration // 两个音效之间的间隔
+	Logger   *slog.Logger
+}
+
+// item 排队中的音效
+type item struct {
+	sound    string
+	priority int
+	seq      uint64
+}
+
+// Queue 音效播放队列
+type Queue struct {
+	opts Options
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  []item
+	seq    uint64
+	closed bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewQueue 创建队列并启动后台播放协程
+func NewQueue(opts Options) *Queue {
+	if opts.Player == nil {
+		opts.Player = DefaultPlayer()
+	}
+	if opts.MaxQueue <= 0 {
+		opts.MaxQueue = defaultMaxQueue
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{opts: opts, cancel: cancel, done: make(chan struct{})}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run(ctx)
+	return q
+}
+
+// Watch 订阅直播间的礼物，连击合并后按规则排队播放，返回的订阅 ID 可用于 Unsubscribe
+func (q *Queue) Watch(dl *douyinLive.DouyinLive) string {
+	return dl.SubscribeGiftCombo(0, q.ObserveGift)
+}
+
+// ObserveGift 按规则为礼物排队音效，没有匹配的规则时忽略
+func (q *Queue) ObserveGift(gift *douyinLive.GiftEvent) {
+	for _, rule := range q.opts.Rules {
+		if rule.match(gift) {
+			q.Enqueue(rule.Sound, rule.Priority)
+			return
+		}
+	}
+}
+
+// Enqueue 排队一个音效，队列已满且优先级不高于队尾时丢弃并返回 false
+func (q *Queue) Enqueue(sound string, priority int) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return false
+	}
+	if len(q.items) >= q.opts.MaxQueue {
+		last := q.items[len(q.items)-1]
+		if priority <= last.priority {
+			q.opts.Logger.Warn("音效队列已满，丢弃", "sound", sound)
+			return false
+		}
+		q.opts.Logger.Warn("音效队列已满，丢弃低优先级音效", "sound", last.sound)
+		q.items = q.items[:len(q.items)-1]
+	}
+	q.seq++
+	q.items = append(q.items, item{sound: sound, priority: priority, seq: q.seq})
+	sort.SliceStable(q.items, func(i, j int) bool {
+		return q.items[i].priority > q.items[j].priority
+	})
+	q.cond.Signal()
+	return true
+}
+
+// Pending 返回排队中的音效数
+func (q *Queue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Close 停止播放，正在播放的音效会被中断，排队中的音效被丢弃
+func (q *Queue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.items = nil
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	q.cancel()
+	<-q.done
+}
+
+// run 依次播放队列中的音效
+func (q *Queue) run(ctx context.Context) {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		next := q.items[0]
+		q.items = q.items[1:]
+		q.mu.Unlock()
+
+		if err := q.opts.Player.Play(ctx, next.sound); err != nil && ctx.Err() == nil {
+			q.opts.Logger.Warn("播放音效失败", "sound", next.sound, "error", err)
+		}
+		if q.opts.Gap > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(q.opts.Gap):
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestQueuePlaysInOrderWithoutOverlap(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		played  []string
+		playing atomic.Int32
+		overlap atomic.Bool
+	)
+	release := make(chan struct{})
+	q := NewQueue(Options{
+		Rules: []Rule{
+			{GiftName: "嘉年华", Sound: "carnival.mp3", Priority: 10},
+			{MinDiamond: 10, Sound: "big.mp3"},
+			{GiftID: 1, Sound: "rose.mp3"},
+		},
+		Player: PlayerFunc(func(ctx context.Context, sound string) error {
+			if playing.Add(1) > 1 {
+				overlap.Store(true)
+			}
+			<-release
+			mu.Lock()
+			played = append(played, sound)
+			mu.Unlock()
+			playing.Add(-1)
+			return nil
+		}),
+	})
+	defer q.Close()
+
+	q.ObserveGift(&douyinLive.GiftEvent{GiftID: 1, DiamondCount: 1, Count: 1})
+	// 等第一个音效开始播放，之后到达的音效进入队列
+	for playing.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	q.ObserveGift(&douyinLive.GiftEvent{GiftID: 2, DiamondCount: 1, Count: 20})
+	q.ObserveGift(&douyinLive.GiftEvent{GiftID: 3, DiamondCount: 1, Count: 1}) // 无匹配规则
+	q.ObserveGift(&douyinLive.GiftEvent{GiftID: 4, GiftName: "嘉年华", DiamondCount: 3000, Count: 1})
+	if q.Pending() != 2 {
+		t.Fatalf("排队数 = %d, want 2", q.Pending())
+	}
+	for i := 0; i < 3; i++ {
+		release <- struct{}{}
+	}
+	for playing.Load() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"rose.mp3", "carnival.mp3", "big.mp3"}
+	if len(played) != len(want) || overlap.Load() {
+		t.Fatalf("played=%v overlap=%v", played, overlap.Load())
+	}
+	for i := range want {
+		if played[i] != want[i] {
+			t.Fatalf("播放顺序 = %v, want %v", played, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package douyinLive
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// loginCookieNames 代表登录态的 cookie
+var loginCookieNames = []string{"sessionid", "sessionid_ss", "sid_tt", "sid_guard", "uid_tt"}
+
+// WithCookies 使用账号的登录 cookie（sessionid、sid_tt 等），页面与接口请求、WebSocket 握手都会携带，
+// 连接以登录观众的身份进入直播间。包含 ttwid 时直接使用，不再单独获取
+func WithCookies(cookies ...*http.Cookie) Option {
+	return func(dl *DouyinLive) {
+		for _, c := range cookies {
+			if c == nil || c.Name == "" {
+				continue
+			}
+			if c.Name == "ttwid" {
+				dl.ttwid = c.Value
+				dl.ttwidFixed = true
+				continue
+			}
+			dl.accountCookies = append(dl.accountCookies, &http.Cookie{Name: c.Name, Value: c.Value})
+		}
+		dl.client.SetCommonCookies(dl.accountCookies...)
+	}
+}
+
+// WithCookieString 解析浏览器中复制的 "name=value; name2=value2" 形式的 cookie，见 WithCookies
+func WithCookieString(s string) Option {
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && name != "" {
+			cookies = append(cookies, &http.Cookie{Name: name, Value: value})
+		}
+	}
+	return WithCookies(cookies...)
+}
+
+// Authenticated 判断是否配置了登录 cookie
+func (dl *DouyinLive) Authenticated() bool {
+	for _, c := range dl.accountCookies {
+		if c.Value != "" && slices.Contains(loginCookieNames, c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// cookieHeader 返回 WebSocket 握手使用的 Cookie 头
+func (dl *DouyinLive) cookieHeader() string {
+	parts := []string{"ttwid=" + dl.ttwid}
+	for _, c := range dl.accountCookies {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,43 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCookies(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithCookieString("sessionid=abc; ttwid=t1; sid_tt=xyz"))
+	if !dl.Authenticated() {
+		t.Fatal("应识别为登录状态")
+	}
+	if err := dl.fetchTTWID(context.Background()); err != nil || dl.ttwid != "t1" {
+		t.Fatalf("应直接使用提供的 ttwid: %v, %s", err, dl.ttwid)
+	}
+	if err := dl.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if got := dl.headers.Get("Cookie"); got != "ttwid=t1; sessionid=abc; sid_tt=xyz" {
+		t.Fatalf("握手 Cookie = %s", got)
+	}
+
+	var session string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("sessionid"); err == nil {
+			session = c.Value
+		}
+	}))
+	defer srv.Close()
+	if _, err := dl.client.R().Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if session != "abc" {
+		t.Fatalf("HTTP 请求未携带登录 cookie: %q", session)
+	}
+
+	anonymous, _ := NewDouyinLive("1", nil)
+	if anonymous.Authenticated() {
+		t.Fatal("未配置 cookie 时不应为登录状态")
+	}
+}
@@ -0,0 +1,177 @@
+package douyinLive
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/tiga210/douyinLive/generated"
+)
+
+// modulePath 本库的模块路径
+const modulePath = "github.com/tiga210/douyinLive"
+
+// CapabilityInfo 当前构建支持的能力，用于上层做特性探测与兼容处理
+type CapabilityInfo struct {
+	Version      string       // 库版本，无法获取时为 (devel)
+	MessageTypes []string     // 可解码的消息类型
+	Sinks        []string     // 已链接进当前程序的 Sink 实现
+	Signer       string       // WebSocket 签名实现
+	Protocol     ProtocolInfo // 连接参数版本
+	Features     []string     // 可用的可选特性
+}
+
+// ProtocolInfo 连接抖音时使用的协议参数
+type ProtocolInfo struct {
+	VersionCode       string // version_code
+	WebcastSDKVersion string // webcast_sdk_version
+	Aid               string
+	Compress          string // 推送帧的压缩方式
+}
+
+// features 本库提供的可选特性
+var features = []string{
+	"anchor_lookup",
+	"api_client",
+	"async_dispatch",
+	"auth_cookies",
+	"chat_guard",
+	"chat_match",
+	"chinese_conversion",
+	"classifier",
+	"compression",
+	"conditional_request",
+	"connect_timings",
+	"cookie_store",
+	"dead_letter",
+	"deadlines",
+	"decode_pipeline",
+	"dedup",
+	"diagnostics",
+	"dns_override",
+	"drop_policy",
+	"event_ext",
+	"exit_codes",
+	"fansclub_events",
+	"fast_start",
+	"feature_flags",
+	"filters",
+	"game",
+	"gift_catalog",
+	"gift_combo",
+	"gift_correction",
+	"handler_timeout",
+	"hot_patch",
+	"http_polling",
+	"interactions",
+	"latency_slo",
+	"message_pool",
+	"message_source",
+	"method_filter",
+	"middleware",
+	"page_protocol_params",
+	"product_timeline",
+	"proxy_pool",
+	"push_host_rotation",
+	"rank_list",
+	"raw_frames",
+	"remote_signer",
+	"replay",
+	"response_interceptor",
+	"risk_params",
+	"room_info",
+	"sampling",
+	"send_chat",
+	"session_resume",
+	"shared_connection",
+	"short_link",
+	"signature_cache",
+	"slog",
+	"stats",
+	"summary",
+	"timezone",
+	"tls_config",
+	"topics",
+	"tracing",
+	"transform",
+	"ttwid_store",
+	"unknown_messages",
+	"user_agent",
+	"user_list",
+	"visitor_filter",
+	"weighted_signer",
+}
+
+var (
+	sinksMu sync.Mutex
+	sinks   []string
+)
+
+// RegisterSink 登记一个 Sink 实现，由各 Sink 包在 init 中调用
+func RegisterSink(name string) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for _, s := range sinks {
+		if s == name {
+			return
+		}
+	}
+	sinks = append(sinks, name)
+	sort.Strings(sinks)
+}
+
+// Capabilities 返回当前构建支持的消息类型、Sink、签名实现与协议参数
+func Capabilities() CapabilityInfo {
+	sinksMu.Lock()
+	registered := append([]string(nil), sinks...)
+	sinksMu.Unlock()
+
+	return CapabilityInfo{
+		Version:      moduleVersion(),
+		MessageTypes: generated.MessageNames(),
+		Sinks:        registered,
+		Signer:       defaultSignerName,
+		Protocol: ProtocolInfo{
+			VersionCode:       protocolVersionCode,
+			WebcastSDKVersion: webcastSDKVersion,
+			Aid:               webcastAid,
+			Compress:          "gzip",
+		},
+		Features: append([]string(nil), features...),
+	}
+}
+
+// Capabilities 返回当前构建的能力，等同于包级的 Capabilities()
+func (dl *DouyinLive) Capabilities() CapabilityInfo {
+	return Capabilities()
+}
+
+// Supports 判断当前构建是否支持某个特性
+func (c CapabilityInfo) Supports(feature string) bool {
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleVersion 从构建信息中读取本库的版本
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
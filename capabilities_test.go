@@ -0,0 +1,21 @@
+package douyinLive
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	RegisterSink("test")
+	RegisterSink("test")
+	c := Capabilities()
+	if !slices.Contains(c.MessageTypes, WebcastChatMessage) {
+		t.Fatalf("缺少 %s: %v", WebcastChatMessage, c.MessageTypes)
+	}
+	if n := len(slices.DeleteFunc(slices.Clone(c.Sinks), func(s string) bool { return s != "test" })); n != 1 {
+		t.Fatalf("Sink 登记错误: %v", c.Sinks)
+	}
+	if DefaultProtocolParams().VersionCode != c.Protocol.VersionCode || !c.Supports("replay") {
+		t.Fatalf("协议参数或特性错误: %+v", c)
+	}
+}
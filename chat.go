@@ -0,0 +1,71 @@
+package douyinLive
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// ChatEvent 解码后的弹幕
+type ChatEvent struct {
+	RoomID        string
+	Time          time.Time
+	MsgID         uint64
+	UserID        uint64
+	Nickname      string
+	FansclubLevel int // 发送者的粉丝团等级，未加入时为 0
+	Content       string
+	Matches       []string // OnChatMatch 中正则的匹配结果，[0] 为整体匹配，之后为各分组
+	Message       *new_douyin.Webcast_Im_ChatMessage
+}
+
+// DecodeChat 把弹幕消息解码为 ChatEvent
+func (dl *DouyinLive) DecodeChat(event *LiveEvent) (*ChatEvent, error) {
+	if event.Method != WebcastChatMessage {
+		return nil, fmt.Errorf("不是弹幕消息: %s", event.Method)
+	}
+	decoded, err := event.Decode()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := decoded.(*new_douyin.Webcast_Im_ChatMessage)
+	if !ok {
+		return nil, fmt.Errorf("弹幕消息类型不匹配: %T", decoded)
+	}
+	chat := &ChatEvent{
+		RoomID:  event.RoomID,
+		Time:    event.Time,
+		MsgID:   event.MsgID,
+		Content: msg.Content,
+		Message: msg,
+	}
+	if user := msg.User; user != nil {
+		chat.UserID = user.Id
+		chat.Nickname = user.Nickname
+		chat.FansclubLevel = int(user.GetFansClub().GetData().GetLevel())
+	}
+	return chat, nil
+}
+
+// OnChatMatch 订阅内容匹配 pattern 的弹幕，是"点歌"、"抽奖"等指令型机器人的入口，
+// 如 regexp.MustCompile(`^点歌\s*(.+)$`) 后从 Matches[1] 取出歌名。返回的 ID 可用于 Unsubscribe
+func (dl *DouyinLive) OnChatMatch(pattern *regexp.Regexp, handler func(ChatEvent)) string {
+	return dl.SubscribeEvent(func(event *LiveEvent) {
+		if event.Method != WebcastChatMessage {
+			return
+		}
+		chat, err := dl.DecodeChat(event)
+		if err != nil {
+			dl.log().Warn("解析弹幕消息失败", "error", err)
+			return
+		}
+		matches := pattern.FindStringSubmatch(chat.Content)
+		if matches == nil {
+			return
+		}
+		chat.Matches = matches
+		handler(*chat)
+	})
+}
@@ -0,0 +1,146 @@
+package douyinLive
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// defaultDuplicateWindow 相同内容的弹幕在该时间内不会重复发送
+const defaultDuplicateWindow = 5 * time.Minute
+
+// DefaultSensitiveWords 内置的敏感词，多为导流与刷量用语，机器人账号发出后容易被封禁
+var DefaultSensitiveWords = []string{
+	"加微信", "加v", "vx", "薇信", "威信", "qq群", "私信我", "加群",
+	"代刷", "刷粉", "刷单", "兼职", "返利", "赌博", "博彩",
+}
+
+// ChatGuard SendChat 的发送前检查：重复内容检测与敏感词预检，避免机器人账号因违规发言被封禁。
+// 未通过 WithChatGuard 指定时使用默认配置，发送弹幕始终经过检查
+type ChatGuard struct {
+	DuplicateWindow time.Duration // 相同内容（忽略空白、标点与大小写）的最短间隔，默认 5 分钟
+	SensitiveWords  []string      // 追加的敏感词，与 DefaultSensitiveWords 一起生效
+	NoDefaultWords  bool          // 不使用 DefaultSensitiveWords
+
+	mu     sync.Mutex
+	words  []string
+	recent map[string]time.Time // 归一化内容 → 最近发送时间
+}
+
+// WithChatGuard 自定义 SendChat 的发送前检查，nil 时保留默认检查
+func WithChatGuard(g *ChatGuard) Option {
+	return func(dl *DouyinLive) {
+		if g != nil {
+			dl.chatGuard = g
+		}
+	}
+}
+
+// Check 检查弹幕是否可以发送，重复时返回包装了 ErrDuplicateChat 的错误，
+// 命中敏感词时返回包装了 ErrSensitiveChat 的错误
+func (g *ChatGuard) Check(text string) error {
+	key := normalizeChat(text)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.init()
+	return g.check(key)
+}
+
+// Reserve 检查弹幕并在同一把锁内登记为已发送，并发发送相同内容时只有一个通过。
+// 发送失败时调用返回的 cancel 撤销登记
+func (g *ChatGuard) Reserve(text string) (cancel func(), err error) {
+	key := normalizeChat(text)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.init()
+	if err := g.check(key); err != nil {
+		return nil, err
+	}
+	prev, had := g.recent[key]
+	now := g.record(key)
+	return func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if at, ok := g.recent[key]; !ok || !at.Equal(now) {
+			return
+		}
+		if had {
+			g.recent[key] = prev
+		} else {
+			delete(g.recent, key)
+		}
+	}, nil
+}
+
+// check 检查归一化后的内容，调用方持有 mu
+func (g *ChatGuard) check(key string) error {
+	for _, word := range g.words {
+		if strings.Contains(key, word) {
+			return fmt.Errorf("%w: %s", ErrSensitiveChat, word)
+		}
+	}
+	if at, ok := g.recent[key]; ok && time.Since(at) < g.window() {
+		return fmt.Errorf("%w: %s 前已发送过", ErrDuplicateChat, time.Since(at).Round(time.Second))
+	}
+	return nil
+}
+
+// Record 记录一条已发送的弹幕，顺带清理过期记录
+func (g *ChatGuard) Record(text string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.init()
+	g.record(normalizeChat(text))
+}
+
+// record 记录归一化后的内容并返回记录的时间，调用方持有 mu
+func (g *ChatGuard) record(key string) time.Time {
+	now := time.Now()
+	for k, at := range g.recent {
+		if now.Sub(at) >= g.window() {
+			delete(g.recent, k)
+		}
+	}
+	g.recent[key] = now
+	return now
+}
+
+// init 首次使用时归一化敏感词，调用方持有 mu
+func (g *ChatGuard) init() {
+	if g.recent != nil {
+		return
+	}
+	g.recent = make(map[string]time.Time)
+	words := g.SensitiveWords
+	if !g.NoDefaultWords {
+		words = append(append([]string(nil), DefaultSensitiveWords...), words...)
+	}
+	for _, word := range words {
+		if word = normalizeChat(word); word != "" {
+			g.words = append(g.words, word)
+		}
+	}
+}
+
+// window 返回重复检测的时间窗口
+func (g *ChatGuard) window() time.Duration {
+	if g.DuplicateWindow > 0 {
+		return g.DuplicateWindow
+	}
+	return defaultDuplicateWindow
+}
+
+// normalizeChat 去掉空白、标点与符号并转为小写，
+// 使 "加 微-信" 与 "加微信"、"你好！" 与 "你好" 视为相同内容
+func normalizeChat(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
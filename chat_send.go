@@ -0,0 +1,106 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxChatLength 单条弹幕的最大字数
+const maxChatLength = 50
+
+// chatSendURL 发送弹幕的接口，测试中会被替换
+var chatSendURL = "https://live.douyin.com/webcast/room/chat/"
+
+// SendChat 以登录账号的身份向直播间发送弹幕，需要先通过 WithCookies 提供登录 cookie，
+// 并在连接后（已知 roomID）调用。发送前会经过 ChatGuard 的重复内容与敏感词检查
+func (dl *DouyinLive) SendChat(ctx context.Context, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("弹幕内容为空")
+	}
+	if n := utf8.RuneCountInString(text); n > maxChatLength {
+		return fmt.Errorf("弹幕过长: %d 字，最多 %d 字", n, maxChatLength)
+	}
+	cancel, err := dl.chatGuard.Reserve(text)
+	if err != nil {
+		return err
+	}
+	err = dl.webcastCall(ctx, "发送弹幕", http.MethodPost, chatSendURL, map[string]string{
+		"room_id": dl.roomID,
+		"content": text,
+		"type":    "0",
+	})
+	if err != nil {
+		cancel()
+	}
+	return err
+}
+
+// webcastCall 以登录账号的身份调用 webcast 接口，需要已登录且已知 roomID。
+// POST 时 params 作为表单提交，否则附加到查询串
+func (dl *DouyinLive) webcastCall(ctx context.Context, action, method, url string, params map[string]string) error {
+	if !dl.Authenticated() {
+		return ErrNotAuthenticated
+	}
+	if dl.roomID == "" {
+		return fmt.Errorf("%w: 尚未获取 room_id，请在连接后调用", ErrRoomInfoParse)
+	}
+
+	r := dl.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", dl.userAgent).
+		SetHeader("Referer", fmt.Sprintf("https://live.douyin.com/%s", dl.liveID)).
+		SetCookies(dl.ttwidCookie()).
+		SetQueryParams(map[string]string{
+			"aid":             webcastAid,
+			"app_name":        "douyin_web",
+			"device_platform": "web",
+			"browser_name":    "Mozilla",
+			"room_id":         dl.roomID,
+		})
+	if method == http.MethodPost {
+		r.SetFormData(params)
+	} else {
+		r.SetQueryParams(params)
+	}
+	resp, err := r.Send(method, url)
+	if err != nil {
+		return fmt.Errorf("%s失败: %w", action, err)
+	}
+	return checkWebcastResponse(action, resp.StatusCode, resp.String())
+}
+
+// checkWebcastResponse 检查 webcast 接口的响应，status_code 非 0 时返回服务端给出的提示
+func checkWebcastResponse(action string, statusCode int, body string) error {
+	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
+		return fmt.Errorf("%s失败: %w (状态码: %d)", action, ErrNotAuthenticated, statusCode)
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("%s失败 (状态码: %d)", action, statusCode)
+	}
+	result := gjson.Parse(body)
+	code := result.Get("status_code")
+	if !code.Exists() {
+		return fmt.Errorf("%s失败: 响应格式错误", action)
+	}
+	if code.Int() == 0 {
+		return nil
+	}
+	message := result.Get("data.prompts").String()
+	if message == "" {
+		message = result.Get("data.message").String()
+	}
+	if message == "" {
+		message = result.Get("status_message").String()
+	}
+	// 20003 为登录失效
+	if code.Int() == 20003 {
+		return fmt.Errorf("%s失败: %w: %s", action, ErrNotAuthenticated, message)
+	}
+	return fmt.Errorf("%s失败 (status_code=%d): %s", action, code.Int(), message)
+}
@@ -0,0 +1,105 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendChat(t *testing.T) {
+	var content, session string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		content = r.PostForm.Get("content")
+		if c, err := r.Cookie("sessionid"); err == nil {
+			session = c.Value
+		}
+		if content == "违规" {
+			w.Write([]byte(`{"status_code":4001,"data":{"prompts":"内容违规"}}`))
+			return
+		}
+		w.Write([]byte(`{"status_code":0,"data":{}}`))
+	}))
+	defer srv.Close()
+	old := chatSendURL
+	chatSendURL = srv.URL + "/webcast/room/chat/"
+	defer func() { chatSendURL = old }()
+
+	anonymous := NewDouyinLive2("100", "200", "test", "t", nil)
+	if err := anonymous.SendChat(context.Background(), "你好"); !errors.Is(err, ErrNotAuthenticated) {
+		t.Fatalf("未登录应返回 ErrNotAuthenticated: %v", err)
+	}
+
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, WithCookieString("sessionid=abc"))
+	if err := dl.SendChat(context.Background(), " 你好 "); err != nil {
+		t.Fatal(err)
+	}
+	if content != "你好" || session != "abc" {
+		t.Fatalf("content = %q, sessionid = %q", content, session)
+	}
+	if err := dl.SendChat(context.Background(), "违规"); err == nil || !strings.Contains(err.Error(), "内容违规") {
+		t.Fatalf("应返回服务端提示: %v", err)
+	}
+	if err := dl.SendChat(context.Background(), strings.Repeat("长", maxChatLength+1)); err == nil {
+		t.Fatal("超长弹幕应返回错误")
+	}
+}
+
+func TestChatGuard(t *testing.T) {
+	sent := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		w.Write([]byte(`{"status_code":0,"data":{}}`))
+	}))
+	defer srv.Close()
+	old := chatSendURL
+	chatSendURL = srv.URL + "/webcast/room/chat/"
+	defer func() { chatSendURL = old }()
+
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, WithCookieString("sessionid=abc"),
+		WithChatGuard(&ChatGuard{SensitiveWords: []string{"竞品"}}))
+	ctx := context.Background()
+	if err := dl.SendChat(ctx, "欢迎来到直播间"); err != nil {
+		t.Fatal(err)
+	}
+	// 忽略空白与标点后相同的内容视为重复
+	if err := dl.SendChat(ctx, "欢迎 来到直播间！"); !errors.Is(err, ErrDuplicateChat) {
+		t.Fatalf("应拦截重复内容: %v", err)
+	}
+	for _, text := range []string{"加 微-信 领福利", "去看看竞品吧", "VX 123"} {
+		if err := dl.SendChat(ctx, text); !errors.Is(err, ErrSensitiveChat) {
+			t.Fatalf("%q 应命中敏感词: %v", text, err)
+		}
+	}
+	if sent != 1 {
+		t.Fatalf("被拦截的弹幕不应发送: %d", sent)
+	}
+
+	g := &ChatGuard{DuplicateWindow: time.Millisecond, NoDefaultWords: true}
+	g.Record("你好")
+	time.Sleep(2 * time.Millisecond)
+	if err := g.Check("你好"); err != nil {
+		t.Fatalf("超过时间窗口后应允许: %v", err)
+	}
+	if err := g.Check("加微信"); err != nil {
+		t.Fatalf("关闭内置词表后不应拦截: %v", err)
+	}
+
+	// 预留后相同内容立即被拦截，撤销后恢复
+	g = &ChatGuard{}
+	cancel, err := g.Reserve("主播好")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Reserve("主播好！"); !errors.Is(err, ErrDuplicateChat) {
+		t.Fatalf("预留中的内容应视为重复: %v", err)
+	}
+	cancel()
+	if err := g.Check("主播好"); err != nil {
+		t.Fatalf("撤销后应允许: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package douyinLive
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestOnChatMatch(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil)
+	var songs []string
+	var from string
+	dl.OnChatMatch(regexp.MustCompile(`^点歌\s*(.+)$`), func(chat ChatEvent) {
+		songs = append(songs, chat.Matches[1])
+		from = chat.Nickname
+	})
+
+	user := &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"}
+	for _, content := range []string{"点歌 晴天", "你好", "点歌"} {
+		dl.deliver(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: content}))
+	}
+	dl.deliver(statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{User: user}))
+
+	if len(songs) != 1 || songs[0] != "晴天" || from != "观众" {
+		t.Fatalf("songs = %v, from = %q", songs, from)
+	}
+}
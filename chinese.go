@@ -0,0 +1,117 @@
+package douyinLive
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ChineseConversion 繁简转换方向
+type ChineseConversion int
+
+const (
+	ConvertS2T  ChineseConversion = iota // 简体到繁体
+	ConvertT2S                           // 繁体到简体
+	ConvertS2TW                          // 简体到台湾正体，含台湾惯用词
+	ConvertS2HK                          // 简体到香港繁体，含香港惯用词
+)
+
+// ChineseConverter OpenCC 风格的繁简转换器：先按词表最长匹配，再逐字转换，最后替换地区用字。
+// 创建后只读，可并发使用；同时实现 Transformer，转换弹幕内容与昵称
+type ChineseConverter struct {
+	chars     map[rune]rune
+	phrases   map[string]string
+	variants  map[rune]rune
+	maxPhrase int // 词表中最长词的字数
+}
+
+// NewChineseConverter 创建繁简转换器，phrases 为自定义词表，优先于内置词表
+func NewChineseConverter(conversion ChineseConversion, phrases map[string]string) *ChineseConverter {
+	c := &ChineseConverter{
+		chars:    make(map[rune]rune),
+		phrases:  make(map[string]string),
+		variants: make(map[rune]rune),
+	}
+	simplified, traditional := []rune(simplifiedChars), []rune(traditionalChars)
+	if conversion == ConvertT2S {
+		for i, r := range traditional {
+			c.chars[r] = simplified[i]
+		}
+		for from, to := range t2sExtraChars {
+			c.chars[from] = to
+		}
+	} else {
+		for i, r := range simplified {
+			c.chars[r] = traditional[i]
+		}
+		c.addPhrases(s2tPhrases)
+	}
+	switch conversion {
+	case ConvertS2TW:
+		c.addPhrases(twPhrases)
+		c.variants = twChars
+	case ConvertS2HK:
+		c.addPhrases(hkPhrases)
+		c.variants = hkChars
+	}
+	c.addPhrases(phrases)
+	return c
+}
+
+// addPhrases 合并词表，同名词覆盖
+func (c *ChineseConverter) addPhrases(phrases map[string]string) {
+	for from, to := range phrases {
+		c.phrases[from] = to
+		c.maxPhrase = max(c.maxPhrase, utf8.RuneCountInString(from))
+	}
+}
+
+// Convert 转换文本
+func (c *ChineseConverter) Convert(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(runes); {
+		if phrase, n := c.matchPhrase(runes[i:]); n > 0 {
+			for _, r := range phrase {
+				b.WriteRune(c.variant(r))
+			}
+			i += n
+			continue
+		}
+		r := runes[i]
+		if to, ok := c.chars[r]; ok {
+			r = to
+		}
+		b.WriteRune(c.variant(r))
+		i++
+	}
+	return b.String()
+}
+
+// matchPhrase 返回以 runes 开头的最长词的转换结果与字数，没有匹配时字数为 0
+func (c *ChineseConverter) matchPhrase(runes []rune) (string, int) {
+	for n := min(c.maxPhrase, len(runes)); n >= 2; n-- {
+		if to, ok := c.phrases[string(runes[:n])]; ok {
+			return to, n
+		}
+	}
+	return "", 0
+}
+
+// variant 替换地区用字
+func (c *ChineseConverter) variant(r rune) rune {
+	if to, ok := c.variants[r]; ok {
+		return to
+	}
+	return r
+}
+
+// Transform 实现 Transformer，转换消息中的文本内容与用户昵称
+func (c *ChineseConverter) Transform(event *LiveEvent) {
+	if content := event.Content(); content != "" {
+		event.SetContent(c.Convert(content))
+	}
+	if nickname := event.Nickname(); nickname != "" {
+		event.SetNickname(c.Convert(nickname))
+	}
+}
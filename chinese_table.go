@@ -0,0 +1,84 @@
+package douyinLive
+
+// 繁简转换的字表与词表，参照 OpenCC 的 STCharacters、STPhrases 及港台异体整理常用部分
+
+var (
+	// simplifiedChars 与 traditionalChars 逐字对应，一简对多繁的字取最常用写法，其余写法由词表处理
+	simplifiedChars = "爱碍袄罢摆败办帮宝报备贝笔币毕边变标别宾饼并补参蚕惨残仓层产长尝厂场车陈称诚迟齿" +
+		"冲虫丑处触传闯创词从聪错达带单担胆弹当党导岛灯邓敌递点电调东动冻斗独读断队对吨夺" +
+		"恶儿尔发罚饭访飞费丰风疯凤妇复负该盖干赶刚钢个给巩沟构购顾关观馆惯广归龟规贵国过" +
+		"还汉号贺红后护话华画划怀坏欢环换黄挥辉汇会绘货获鸡积极际继纪计记济价驾坚间检简见" +
+		"剑荐将讲奖酱骄脚较阶节结洁紧尽进惊经静镜旧举剧据觉军开课块宽况亏扩来蓝篮览懒劳乐" +
+		"泪类离礼丽历厉励联连怜脸练炼恋凉两辆谅疗辽猎临邻灵岭领刘龙楼录陆驴乱论罗逻萝马妈" +
+		"吗骂买卖麦满猫贸么没门们梦觅绵庙灭鸣谋难脑闹内拟鸟宁农浓诺欧盘赔喷鹏骗飘贫苹凭评" +
+		"仆扑齐骑气弃钱铅迁签浅枪强墙抢桥乔窍亲轻庆穷区驱权劝确让热认荣软洒伞丧扫杀晒闪伤" +
+		"赏烧绍设绅审婶声胜圣师诗时识实势视试适释寿书输属术树帅双谁税顺说丝饲苏诉肃虽随岁" +
+		"孙损锁态谈叹汤烫涛讨题体条铁听厅头图团袜弯湾万网为违围伟卫纬稳问闻无务雾误戏细虾" +
+		"吓鲜闲显险县现线宪献乡详响项协胁写谢兴许续选学寻训讯压鸭亚烟严盐颜验阳养样药爷页" +
+		"业叶医仪亿忆艺义议异译阴银饮隐应婴营赢拥佣优忧邮犹鱼与语誉园员圆远愿约跃阅云运晕" +
+		"杂灾载赞责则泽贼赠闸战张涨帐账赵这针侦阵镇争睁证郑织职执纸质钟种众猪烛嘱筑专转赚" +
+		"装状壮准浊资综总纵邹组钻链码库频级绩饿机统几采弥闭阔顶须预颗额轮辑贡财贤贩贪贯贱" +
+		"贴赌赐赖赛键锅锋销锦闷鸿鹅请谨订纯纳终绕绝维绿缘编缓肠肤肿腾舰莱萨虑蛮贞轨迈遗钓" +
+		"铺厌叠启吴呐呜哑哗唤啰坛垄垒够夸夹奋妆娱宠尘尧届岂庄废彻径恼悦悬愤户扬扰抚抛拦择" +
+		"挂挤掷摄摇撑数斋旷昼晋晓暂杨杰柜栏档测浏润渐温湿滚滩潜炉烂烦牵狮狭玛畅矿砖础祸窃" +
+		"竞笼粮纠纤纷艰芦苍茧荡萤蚁衬趋践踪轰辞逊钥铃锐陕隶靓韩顽顿颁饱饰饶驶驻鲁龄"
+	traditionalChars = "愛礙襖罷擺敗辦幫寶報備貝筆幣畢邊變標別賓餅並補參蠶慘殘倉層產長嘗廠場車陳稱誠遲齒" +
+		"衝蟲醜處觸傳闖創詞從聰錯達帶單擔膽彈當黨導島燈鄧敵遞點電調東動凍鬥獨讀斷隊對噸奪" +
+		"惡兒爾發罰飯訪飛費豐風瘋鳳婦復負該蓋幹趕剛鋼個給鞏溝構購顧關觀館慣廣歸龜規貴國過" +
+		"還漢號賀紅後護話華畫劃懷壞歡環換黃揮輝匯會繪貨獲雞積極際繼紀計記濟價駕堅間檢簡見" +
+		"劍薦將講獎醬驕腳較階節結潔緊盡進驚經靜鏡舊舉劇據覺軍開課塊寬況虧擴來藍籃覽懶勞樂" +
+		"淚類離禮麗歷厲勵聯連憐臉練煉戀涼兩輛諒療遼獵臨鄰靈嶺領劉龍樓錄陸驢亂論羅邏蘿馬媽" +
+		"嗎罵買賣麥滿貓貿麼沒門們夢覓綿廟滅鳴謀難腦鬧內擬鳥寧農濃諾歐盤賠噴鵬騙飄貧蘋憑評" +
+		"僕撲齊騎氣棄錢鉛遷簽淺槍強牆搶橋喬竅親輕慶窮區驅權勸確讓熱認榮軟灑傘喪掃殺曬閃傷" +
+		"賞燒紹設紳審嬸聲勝聖師詩時識實勢視試適釋壽書輸屬術樹帥雙誰稅順說絲飼蘇訴肅雖隨歲" +
+		"孫損鎖態談嘆湯燙濤討題體條鐵聽廳頭圖團襪彎灣萬網為違圍偉衛緯穩問聞無務霧誤戲細蝦" +
+		"嚇鮮閒顯險縣現線憲獻鄉詳響項協脅寫謝興許續選學尋訓訊壓鴨亞煙嚴鹽顏驗陽養樣藥爺頁" +
+		"業葉醫儀億憶藝義議異譯陰銀飲隱應嬰營贏擁傭優憂郵猶魚與語譽園員圓遠願約躍閱雲運暈" +
+		"雜災載贊責則澤賊贈閘戰張漲帳賬趙這針偵陣鎮爭睜證鄭織職執紙質鐘種眾豬燭囑築專轉賺" +
+		"裝狀壯準濁資綜總縱鄒組鑽鏈碼庫頻級績餓機統幾採彌閉闊頂須預顆額輪輯貢財賢販貪貫賤" +
+		"貼賭賜賴賽鍵鍋鋒銷錦悶鴻鵝請謹訂純納終繞絕維綠緣編緩腸膚腫騰艦萊薩慮蠻貞軌邁遺釣" +
+		"鋪厭疊啟吳吶嗚啞嘩喚囉壇壟壘夠誇夾奮妝娛寵塵堯屆豈莊廢徹徑惱悅懸憤戶揚擾撫拋攔擇" +
+		"掛擠擲攝搖撐數齋曠晝晉曉暫楊傑櫃欄檔測瀏潤漸溫濕滾灘潛爐爛煩牽獅狹瑪暢礦磚礎禍竊" +
+		"競籠糧糾纖紛艱蘆蒼繭蕩螢蟻襯趨踐蹤轟辭遜鑰鈴銳陝隸靚韓頑頓頒飽飾饒駛駐魯齡"
+
+	// t2sExtraChars 字表之外的繁体异体字，转简体时使用
+	t2sExtraChars = map[rune]rune{
+		'髮': '发', '乾': '干', '麵': '面', '裏': '里', '裡': '里', '複': '复', '曆': '历', '綫': '线',
+		'爲': '为', '隻': '只', '臺': '台', '颱': '台', '鍾': '钟', '沖': '冲', '讚': '赞', '週': '周',
+		'係': '系', '繫': '系', '鬆': '松', '範': '范', '穀': '谷', '瞭': '了', '慾': '欲', '製': '制',
+		'彙': '汇', '儘': '尽', '啓': '启', '衆': '众', '歎': '叹', '併': '并', '籤': '签', '罈': '坛',
+		'鬚': '须',
+	}
+
+	// s2tPhrases 逐字转换会出错的词，优先按最长匹配替换
+	s2tPhrases = map[string]string{
+		"头发": "頭髮", "理发": "理髮", "发型": "髮型", "白发": "白髮", "发廊": "髮廊",
+		"皇后": "皇后", "王后": "王后", "太后": "太后",
+		"干净": "乾淨", "干燥": "乾燥", "饼干": "餅乾", "干杯": "乾杯", "若干": "若干", "干脆": "乾脆",
+		"面条": "麵條", "方便面": "方便麵", "拉面": "拉麵", "面包": "麵包", "面粉": "麵粉",
+		"这里": "這裏", "那里": "那裏", "哪里": "哪裏", "里面": "裏面", "心里": "心裏", "家里": "家裏",
+		"复杂": "複雜", "重复": "重複", "复制": "複製", "复印": "複印", "复习": "複習",
+		"日历": "日曆", "农历": "農曆", "历法": "曆法",
+		"钟情": "鍾情", "冲洗": "沖洗", "冲澡": "沖澡", "词汇": "詞彙", "尽管": "儘管",
+		"茶几": "茶几", "风采": "風采", "神采": "神采", "文采": "文采", "小丑": "小丑",
+		"北斗": "北斗", "熨斗": "熨斗", "划船": "划船", "划算": "划算", "佣金": "佣金",
+	}
+
+	// twPhrases 台湾惯用词
+	twPhrases = map[string]string{
+		"软件": "軟體", "视频": "影片", "网络": "網路", "信息": "資訊", "质量": "品質", "默认": "預設",
+		"鼠标": "滑鼠", "打印": "列印", "服务器": "伺服器", "程序": "程式", "屏幕": "螢幕",
+		"出租车": "計程車", "短信": "簡訊",
+	}
+
+	// twChars 台湾用字
+	twChars = map[rune]rune{'裏': '裡'}
+
+	// hkPhrases 香港惯用词
+	hkPhrases = map[string]string{
+		"出租车": "的士", "冰淇淋": "雪糕", "网络": "網絡", "软件": "軟件", "信息": "資訊",
+	}
+
+	// hkChars 香港用字
+	hkChars = map[rune]rune{'線': '綫'}
+)
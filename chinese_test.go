@@ -0,0 +1,53 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestChineseConverter(t *testing.T) {
+	tests := []struct {
+		conversion ChineseConversion
+		in, want   string
+	}{
+		{ConvertS2T, "谢谢主播送的礼物，头发真好看", "謝謝主播送的禮物，頭髮真好看"},
+		{ConvertS2T, "皇后在这里吃方便面", "皇后在這裏吃方便麵"},
+		{ConvertS2TW, "这里的视频软件", "這裡的影片軟體"},
+		{ConvertS2HK, "坐出租车看直播线路", "坐的士看直播綫路"},
+		{ConvertT2S, "謝謝你們，頭髮這裡很亂", "谢谢你们，头发这里很乱"},
+		{ConvertT2S, "hello 666", "hello 666"},
+	}
+	for _, tt := range tests {
+		if got := NewChineseConverter(tt.conversion, nil).Convert(tt.in); got != tt.want {
+			t.Errorf("Convert(%d, %q) = %q, want %q", tt.conversion, tt.in, got, tt.want)
+		}
+	}
+
+	custom := NewChineseConverter(ConvertS2T, map[string]string{"老铁": "老鐵們"})
+	if got := custom.Convert("老铁"); got != "老鐵們" {
+		t.Errorf("自定义词表未生效: %q", got)
+	}
+}
+
+func TestChineseTransformer(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithClassifier(ScriptClassifier),
+		WithTransformer(NewChineseConverter(ConvertS2T, nil)))
+	var got *LiveEvent
+	dl.SubscribeEvent(func(e *LiveEvent) { got = e })
+	dl.deliver(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{
+		Content: "欢迎来到直播间",
+		User:    &new_douyin.Webcast_Data_User{Nickname: "小龙"},
+	}))
+
+	if got.Content() != "歡迎來到直播間" || got.Nickname() != "小龍" {
+		t.Fatalf("转换结果: %q %q", got.Content(), got.Nickname())
+	}
+	if got.Fields()[FieldContent] != "歡迎來到直播間" {
+		t.Fatalf("Fields 未反映转换结果: %v", got.Fields()[FieldContent])
+	}
+	// 分类器在转换前执行，标签反映原文
+	if got.Tags[TagScript] != "simplified" {
+		t.Fatalf("标签 = %v", got.Tags)
+	}
+}
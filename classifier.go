@@ -0,0 +1,129 @@
+package douyinLive
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 分类器输出的标签名
+const (
+	TagScript  = "script"  // 繁简：traditional / simplified
+	TagLang    = "lang"    // 语种：zh / ja / ko / en / ru / th / ar
+	TagDialect = "dialect" // 方言，如 粤语
+)
+
+// Classifier 事件分类器，返回的标签会合并到 LiveEvent.Tags，无结果时返回 nil
+type Classifier interface {
+	Classify(event *LiveEvent) map[string]string
+}
+
+// ClassifierFunc 函数形式的 Classifier
+type ClassifierFunc func(event *LiveEvent) map[string]string
+
+// Classify 实现 Classifier
+func (f ClassifierFunc) Classify(event *LiveEvent) map[string]string {
+	return f(event)
+}
+
+// WithClassifier 添加事件分类器，按添加顺序执行，后执行的同名标签会覆盖先前的结果
+func WithClassifier(classifiers ...Classifier) Option {
+	return func(dl *DouyinLive) {
+		dl.classifiers = append(dl.classifiers, classifiers...)
+	}
+}
+
+// classify 设置主题后执行全部分类器并附加标签
+func (dl *DouyinLive) classify(event *LiveEvent) {
+	dl.tagTopic(event)
+	for _, c := range dl.classifiers {
+		for k, v := range c.Classify(event) {
+			event.SetTag(k, v)
+		}
+	}
+}
+
+// traditionalOnly 与 simplifiedOnly 为常用字中繁简写法不同的字，用于粗略判断繁简
+var (
+	traditionalOnly = []rune("們這個說來會為時國對麼開點謝發聽還讓應學問題東車長門見頭體錢買賣讀寫愛覺關歡給兒電話氣號實現")
+	simplifiedOnly  = []rune("们这个说来会为时国对么开点谢发听还让应学问题东车长门见头体钱买卖读写爱觉关欢给儿电话气号实现")
+)
+
+// ScriptClassifier 根据繁体、简体特有字判断繁简，输出 TagScript
+var ScriptClassifier Classifier = ClassifierFunc(func(event *LiveEvent) map[string]string {
+	var traditional, simplified int
+	for _, r := range event.Content() {
+		if containsRune(traditionalOnly, r) {
+			traditional++
+		} else if containsRune(simplifiedOnly, r) {
+			simplified++
+		}
+	}
+	switch {
+	case traditional > simplified:
+		return map[string]string{TagScript: "traditional"}
+	case simplified > traditional:
+		return map[string]string{TagScript: "simplified"}
+	}
+	return nil
+})
+
+// LanguageClassifier 按文字所属的 Unicode 书写系统判断语种，输出 TagLang。
+// 含假名判为日语，含谚文判为韩语，其余取字符数最多的书写系统
+var LanguageClassifier Classifier = ClassifierFunc(func(event *LiveEvent) map[string]string {
+	counts := make(map[string]int)
+	for _, r := range event.Content() {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			return map[string]string{TagLang: "ja"}
+		case unicode.Is(unicode.Hangul, r):
+			return map[string]string{TagLang: "ko"}
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["en"]++
+		}
+	}
+	lang, best := "", 0
+	for _, l := range []string{"zh", "en", "ru", "th", "ar"} {
+		if counts[l] > best {
+			lang, best = l, counts[l]
+		}
+	}
+	if lang == "" {
+		return nil
+	}
+	return map[string]string{TagLang: lang}
+})
+
+// CantoneseKeywords 粤语常用字词
+var CantoneseKeywords = []string{"嘅", "咗", "唔", "冇", "佢", "喺", "咁", "嘢", "乜", "點解", "靚", "睇"}
+
+// NewKeywordClassifier 创建关键词分类器，内容包含 keywords 中任一词时输出 tag=value，
+// 可用于方言等没有通用规则的分类
+func NewKeywordClassifier(tag, value string, keywords []string) Classifier {
+	return ClassifierFunc(func(event *LiveEvent) map[string]string {
+		content := event.Content()
+		for _, keyword := range keywords {
+			if keyword != "" && strings.Contains(content, keyword) {
+				return map[string]string{tag: value}
+			}
+		}
+		return nil
+	})
+}
+
+// containsRune 判断 r 是否在 runes 中
+func containsRune(runes []rune, r rune) bool {
+	for _, c := range runes {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
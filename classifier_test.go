@@ -0,0 +1,39 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestClassifiers(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithClassifier(
+		ScriptClassifier,
+		LanguageClassifier,
+		NewKeywordClassifier(TagDialect, "粤语", CantoneseKeywords),
+	))
+	var tags []map[string]string
+	dl.SubscribeEvent(func(e *LiveEvent) { tags = append(tags, e.Tags) })
+
+	for _, content := range []string{"你們說這個好唔好", "我们这个很好", "hello world", "ありがとう", "666"} {
+		dl.deliver(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: content}))
+	}
+
+	want := []map[string]string{
+		{TagScript: "traditional", TagLang: "zh", TagDialect: "粤语"},
+		{TagScript: "simplified", TagLang: "zh"},
+		{TagLang: "en"},
+		{TagLang: "ja"},
+		nil,
+	}
+	for i := range want {
+		if len(tags[i]) != len(want[i]) {
+			t.Fatalf("第 %d 条标签 = %v, want %v", i, tags[i], want[i])
+		}
+		for k, v := range want[i] {
+			if tags[i][k] != v {
+				t.Fatalf("第 %d 条标签 = %v, want %v", i, tags[i], want[i])
+			}
+		}
+	}
+}
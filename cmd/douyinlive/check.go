@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// runCheck 实现 check 子命令：douyinlive check <直播间号>，未开播时返回错误
+func runCheck(args []string) error {
+	fs := pflag.NewFlagSet("check", pflag.ContinueOnError)
+	timeout := fs.Duration("timeout", 15*time.Second, "请求超时")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	liveID := fs.Arg(0)
+	if liveID == "" {
+		return usageError("用法: douyinlive check <直播间号>")
+	}
+
+	dl, err := douyinLive.NewDouyinLive(liveID, nil)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	err = dl.CheckLive(ctx)
+	switch {
+	case err == nil:
+		fmt.Printf("%s: 直播中\n", liveID)
+	case errors.Is(err, douyinLive.ErrRoomOffline):
+		fmt.Printf("%s: 未开播\n", liveID)
+	}
+	return err
+}
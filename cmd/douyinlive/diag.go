@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive/diag"
+)
+
+// setupDiag 开启诊断包，此后的日志同时进入诊断包；dir 为空时返回 nil。
+// 需要在创建直播实例之前调用，命令参数作为配置脱敏后写入诊断包
+func setupDiag(dir string, fs *pflag.FlagSet) *diag.Collector {
+	if dir == "" {
+		return nil
+	}
+	config := make(map[string]string)
+	fs.VisitAll(func(f *pflag.Flag) { config[f.Name] = f.Value.String() })
+	c := diag.New(diag.Options{Dir: dir, Config: config})
+	slog.SetDefault(slog.New(c.Handler(slog.NewTextHandler(os.Stderr, nil))))
+	return c
+}
+
+// finishDiag 命令以错误结束时导出诊断包，原样返回 err
+func finishDiag(c *diag.Collector, err error) error {
+	if c == nil || err == nil {
+		return err
+	}
+	path, exportErr := c.Export(err)
+	if exportErr != nil {
+		slog.Warn("导出诊断包失败", "error", exportErr)
+		return err
+	}
+	slog.Info("诊断包已导出，报障时请附上该文件", "path", path)
+	return err
+}
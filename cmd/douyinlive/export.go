@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive/danmaku"
+)
+
+// runExport 实现 export 子命令：将 record 录制的 JSON Lines 导出为 ASS 字幕或 B 站弹幕 XML
+func runExport(args []string) error {
+	fs := pflag.NewFlagSet("export", pflag.ContinueOnError)
+	in := fs.StringP("in", "i", "douyinlive.jsonl", "record 录制的 JSON Lines 文件")
+	out := fs.StringP("out", "o", "", "输出文件，默认与输入同名，扩展名按格式替换")
+	format := fs.String("format", "ass", "输出格式: ass/xml")
+	start := fs.String("start", "", "录像开始时间，如 \"2024-06-01 20:00:00\"，默认为第一条弹幕的时间")
+	width := fs.Int("width", 1920, "ASS 画面宽度")
+	height := fs.Int("height", 1080, "ASS 画面高度")
+	fontSize := fs.Int("font-size", 0, "字号，ASS 默认 48，XML 默认 25")
+	nickname := fs.Bool("nickname", false, "ASS 中在弹幕前显示昵称")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "ass" && *format != "xml" {
+		return fmt.Errorf("不支持的格式: %s", *format)
+	}
+	startTime, err := parseTime(*start)
+	if err != nil {
+		return fmt.Errorf("--start: %w", err)
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	comments, err := danmaku.ReadJSONL(f, startTime)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		*out = strings.TrimSuffix(*in, ".jsonl") + "." + *format
+	}
+	w, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	err = writeDanmaku(w, *format, comments, danmaku.ASSOptions{Width: *width, Height: *height, FontSize: *fontSize, ShowNickname: *nickname}, *fontSize)
+	if err := errors.Join(err, w.Close()); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "已导出 %d 条弹幕到 %s\n", len(comments), *out)
+	return nil
+}
+
+// writeDanmaku 按格式输出弹幕
+func writeDanmaku(w io.Writer, format string, comments []danmaku.Comment, ass danmaku.ASSOptions, fontSize int) error {
+	if format == "xml" {
+		return danmaku.WriteXML(w, comments, danmaku.XMLOptions{FontSize: fontSize})
+	}
+	return danmaku.WriteASS(w, comments, ass)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+
+	"github.com/tiga210/douyinLive/grpcapi"
+)
+
+// runGRPC 实现 grpc 子命令：douyinlive grpc [--listen :50051]
+func runGRPC(args []string) error {
+	fs := pflag.NewFlagSet("grpc", pflag.ContinueOnError)
+	listen := fs.String("listen", ":50051", "监听地址")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+
+	g := grpc.NewServer()
+	grpcapi.New(grpcapi.Options{}).Register(g)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		g.GracefulStop()
+	}()
+	slog.Info("gRPC 服务已启动", "listen", lis.Addr().String())
+	return g.Serve(lis)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+// defaultListenMethods listen 默认输出的消息类型
+var defaultListenMethods = []string{
+	douyinLive.WebcastChatMessage,
+	douyinLive.WebcastGiftMessage,
+	douyinLive.WebcastMemberMessage,
+	douyinLive.WebcastLikeMessage,
+	douyinLive.WebcastSocialMessage,
+}
+
+// runListen 实现 listen 子命令：douyinlive listen <直播间号> [参数]
+func runListen(args []string) error {
+	fs := pflag.NewFlagSet("listen", pflag.ContinueOnError)
+	methods := fs.StringSlice("method", defaultListenMethods, "输出的消息类型，可多次指定")
+	asJSON := fs.Bool("json", false, "每条事件输出一行 JSON")
+	fields := fs.String("fields", "", "--json 时的输出字段白名单，逗号分隔")
+	demoMode := fs.Bool("demo", false, "不连接抖音，使用本地生成的演示数据")
+	timezone := fs.String("timezone", douyinLive.DefaultTimezone, "事件时间使用的时区")
+	diagDir := fs.String("diag-dir", "", "异常退出时导出诊断包（最近日志、状态快照、最近原始帧、脱敏配置）的目录")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	liveID := fs.Arg(0)
+	if liveID == "" && !*demoMode {
+		return usageError("用法: douyinlive listen <直播间号> [参数]")
+	}
+
+	collector := setupDiag(*diagDir, fs)
+	tzOpt, err := timezoneOption(*timezone)
+	if err != nil {
+		return err
+	}
+	opts := []douyinLive.Option{tzOpt}
+	if collector != nil {
+		defer collector.Recover()
+		opts = append(opts, collector.Option())
+	}
+	dl, err := newLive(liveID, *demoMode, opts...)
+	if err != nil {
+		return finishDiag(collector, err)
+	}
+	if collector != nil {
+		collector.Watch(dl)
+	}
+	if *asJSON {
+		encoder := sink.JSONEncoder{Fields: sink.ParseFields(*fields)}
+		dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+			if !slices.Contains(*methods, event.Method) {
+				return
+			}
+			if line, err := encoder.Encode(event); err == nil {
+				os.Stdout.Write(append(line, '\n'))
+			}
+		})
+	} else {
+		printEvents(dl, os.Stdout, *methods)
+	}
+	return finishDiag(collector, runUntilSignal(dl))
+}
+
+// printEvents 以易读的格式输出事件，礼物连击合并为一行
+func printEvents(dl *douyinLive.DouyinLive, w io.Writer, methods []string) {
+	if slices.Contains(methods, douyinLive.WebcastGiftMessage) {
+		dl.SubscribeGiftCombo(0, func(gift *douyinLive.GiftEvent) {
+			fmt.Fprintf(w, "%s [礼物] %s 送出 %s x%d\n", gift.Time.Format(time.TimeOnly), gift.Nickname, gift.GiftName, gift.Count)
+		})
+	}
+	dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if event.Method == douyinLive.WebcastGiftMessage || !slices.Contains(methods, event.Method) {
+			return
+		}
+		if line := formatEvent(event); line != "" {
+			fmt.Fprintf(w, "%s %s\n", event.Time.Format(time.TimeOnly), line)
+		}
+	})
+}
+
+// formatEvent 返回事件的单行描述，无法解码时返回空串
+func formatEvent(event *douyinLive.LiveEvent) string {
+	decoded, err := event.Decode()
+	if err != nil {
+		return ""
+	}
+	switch msg := decoded.(type) {
+	case *new_douyin.Webcast_Im_ChatMessage:
+		return fmt.Sprintf("[弹幕] %s: %s", event.Nickname(), msg.Content)
+	case *new_douyin.Webcast_Im_MemberMessage:
+		return fmt.Sprintf("[进场] %s 进入直播间", event.Nickname())
+	case *new_douyin.Webcast_Im_LikeMessage:
+		return fmt.Sprintf("[点赞] %s 点赞 x%d", event.Nickname(), msg.Count)
+	case *new_douyin.Webcast_Im_SocialMessage:
+		return fmt.Sprintf("[关注] %s 关注了主播", event.Nickname())
+	}
+	data, err := json.Marshal(event.Fields())
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("[%s] %s", event.Method, data)
+}
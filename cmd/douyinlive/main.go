@@ -0,0 +1,119 @@
+// douyinlive 命令行工具
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // 没有系统时区数据时也能解析 --timezone
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/demo"
+)
+
+// command 子命令
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "listen", usage: "在终端输出直播间的弹幕、礼物等事件", run: runListen},
+	{name: "record", usage: "将直播间事件录制为 JSON Lines 文件", run: runRecord},
+	{name: "export", usage: "将录制的弹幕导出为 ASS 字幕或 B 站弹幕 XML", run: runExport},
+	{name: "check", usage: "检查直播间是否开播", run: runCheck},
+	{name: "overlay", usage: "提供 OBS 浏览器源叠加层，显示弹幕与礼物提醒", run: runOverlay},
+	{name: "soak", usage: "长时间运行并输出稳定性报告（内存、goroutine、队列、速率）", run: runSoak},
+	{name: "serve", usage: "以 WebSocket 转发网关模式运行", run: runServe},
+	{name: "grpc", usage: "启动 gRPC 事件流服务（LiveService.ListenRoom）", run: runGRPC},
+	{name: "query", usage: "查询 SQLite 中落盘的事件", run: runQuery},
+	{name: "service", usage: "以系统服务方式运行采集器（安装、启停、开机自启）", run: runService},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: douyinlive <命令> [参数]")
+	fmt.Fprintln(os.Stderr, "\n命令:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\n使用 douyinlive <命令> --help 查看命令参数")
+}
+
+// usageError 参数错误，退出码为 douyinLive.ExitUsage
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// exitCode 返回命令错误对应的退出码，见 douyinLive.ExitCode
+func exitCode(err error) int {
+	var usage usageError
+	switch {
+	case errors.Is(err, pflag.ErrHelp):
+		return douyinLive.ExitOK
+	case errors.As(err, &usage):
+		return douyinLive.ExitUsage
+	}
+	return douyinLive.ExitCode(err)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(douyinLive.ExitUsage)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			err := c.run(os.Args[2:])
+			if err != nil && !errors.Is(err, pflag.ErrHelp) {
+				fmt.Fprintln(os.Stderr, "错误:", err)
+			}
+			os.Exit(exitCode(err))
+		}
+	}
+	usage()
+	os.Exit(douyinLive.ExitUsage)
+}
+
+// runUntilSignal 连接直播间直到连接结束或收到中断信号
+func runUntilSignal(dl *douyinLive.DouyinLive) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return runLive(ctx, dl)
+}
+
+// runLive 连接直播间直到连接结束或 ctx 结束，ctx 结束时关闭连接并等待退出
+func runLive(ctx context.Context, dl *douyinLive.DouyinLive) error {
+	done := make(chan error, 1)
+	go func() { done <- dl.Start() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		dl.Close()
+		return <-done
+	}
+}
+
+// newLive 创建直播实例，demoMode 时使用本地演示数据，不连接抖音
+func newLive(liveID string, demoMode bool, opts ...douyinLive.Option) (*douyinLive.DouyinLive, error) {
+	if demoMode {
+		return demo.NewFakeLive(opts...), nil
+	}
+	return douyinLive.NewDouyinLive(liveID, nil, opts...)
+}
+
+// timezoneOption 解析 --timezone 指定的时区
+func timezoneOption(name string) (douyinLive.Option, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, usageError(fmt.Sprintf("无效的时区 %q: %v", name, err))
+	}
+	return douyinLive.WithTimezone(loc), nil
+}
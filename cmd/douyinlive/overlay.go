@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive/overlay"
+)
+
+// runOverlay 实现 overlay 子命令：连接直播间并提供 OBS 浏览器源页面
+func runOverlay(args []string) error {
+	fs := pflag.NewFlagSet("overlay", pflag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8090", "监听地址")
+	lifetime := fs.Duration("lifetime", 30*time.Second, "消息在页面上的停留时间")
+	minDiamond := fs.Int64("min-diamond", 0, "礼物提醒的最低总价值（抖币）")
+	demoMode := fs.Bool("demo", false, "不连接抖音，使用本地生成的演示数据")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	liveID := fs.Arg(0)
+	if liveID == "" && !*demoMode {
+		return usageError("用法: douyinlive overlay <直播间号> [参数]")
+	}
+
+	dl, err := newLive(liveID, *demoMode)
+	if err != nil {
+		return err
+	}
+	o := overlay.New(overlay.Options{ChatLifetime: *lifetime, MinGiftDiamond: *minDiamond})
+	o.Watch(dl)
+
+	srv := &http.Server{Addr: *listen, Handler: o}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	defer srv.Close()
+	slog.Info("叠加层已启动，在 OBS 中添加浏览器源", "url", "http://"+*listen+"/")
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("叠加层服务退出", "error", err)
+			stop()
+		}
+	}()
+	return runLive(ctx, dl)
+}
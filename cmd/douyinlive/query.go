@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive/sink/sqlite"
+)
+
+// timeLayouts --from/--to 支持的时间格式，按本地时区解析
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// runQuery 实现 query 子命令
+func runQuery(args []string) error {
+	fs := pflag.NewFlagSet("query", pflag.ContinueOnError)
+	dbPath := fs.String("db", "douyinlive.db", "SQLite 数据库文件")
+	room := fs.String("room", "", "房间 roomID")
+	from := fs.String("from", "", "开始时间，如 \"2024-06-01 20:00\"")
+	to := fs.String("to", "", "结束时间（不含）")
+	keyword := fs.String("keyword", "", "弹幕内容或昵称包含的关键词")
+	user := fs.Uint64("user", 0, "用户 ID")
+	methods := fs.StringSlice("method", nil, "消息类型，可多次指定，如 WebcastChatMessage")
+	limit := fs.Int("limit", 100, "最多返回条数")
+	format := fs.String("format", "table", "输出格式: table/json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := sqlite.Query{
+		RoomID:  *room,
+		Keyword: *keyword,
+		UserID:  *user,
+		Methods: *methods,
+		Limit:   *limit,
+	}
+	var err error
+	if q.From, err = parseTime(*from); err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	if q.To, err = parseTime(*to); err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		return err
+	}
+
+	s, err := sqlite.Open(*dbPath, sqlite.Options{})
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	records, err := sqlite.Find(context.Background(), s.DB(), q)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "table":
+		return printTable(os.Stdout, records)
+	default:
+		return fmt.Errorf("未知的输出格式: %s", *format)
+	}
+}
+
+// parseTime 按 timeLayouts 解析时间，空串返回零值
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间 %q，支持的格式: %s", s, strings.Join(timeLayouts, " | "))
+}
+
+// printTable 以对齐的表格输出
+func printTable(w io.Writer, records []sqlite.Record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "时间\t房间\t类型\t用户\t内容")
+	for _, r := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.Time.Format("2006-01-02 15:04:05"), r.RoomID,
+			strings.TrimPrefix(r.Method, "Webcast"), r.Nickname, summary(r))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "共 %d 条\n", len(records))
+	return nil
+}
+
+// summary 按消息类型生成一列可读内容
+func summary(r sqlite.Record) string {
+	switch {
+	case r.GiftName != "":
+		return fmt.Sprintf("%s x%d (%d 抖币)", r.GiftName, r.GiftCount, r.GiftCount*r.DiamondCount)
+	case r.LikeCount != 0:
+		return fmt.Sprintf("点赞 x%d", r.LikeCount)
+	}
+	return r.Content
+}
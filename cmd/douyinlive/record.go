@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/control"
+	"github.com/tiga210/douyinLive/hotpatch"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+// runRecord 实现 record 子命令：douyinlive record <直播间号> [参数]
+func runRecord(args []string) error {
+	fs := pflag.NewFlagSet("record", pflag.ContinueOnError)
+	out := fs.StringP("out", "o", "douyinlive.jsonl", "输出的 JSON Lines 文件")
+	methods := fs.StringSlice("method", nil, "只录制这些消息类型，可多次指定，为空时录制全部")
+	topics := fs.StringSlice("topic", nil, "只录制这些主题的事件，如 revenue、interaction.chat，可多次指定")
+	fields := fs.String("fields", "", "输出字段白名单，逗号分隔")
+	maxSize := fs.Int64("max-size", 0, "单个文件的最大字节数，0 表示不滚动")
+	interval := fs.Duration("interval", 0, "按时间滚动的间隔，如 1h")
+	compress := fs.Bool("compress", false, "滚动后的文件用 gzip 压缩")
+	controlFile := fs.String("control-file", "", "采集开关配置文件（JSON），修改后自动热加载")
+	diagDir := fs.String("diag-dir", "", "异常退出时导出诊断包（最近日志、状态快照、最近原始帧、脱敏配置）的目录")
+	timezone := fs.String("timezone", douyinLive.DefaultTimezone, "事件时间使用的时区，输出中同时带有 time_utc 与 time_local")
+	patchSource := fs.String("patch", "", "热补丁清单的本地路径或 http(s) 地址，定期检查并加载新的签名脚本与协议参数")
+	patchKey := fs.String("patch-key", "", "验证热补丁签名脚本的 ed25519 公钥（base64），清单包含签名脚本时必填")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	liveID := fs.Arg(0)
+	if liveID == "" {
+		return usageError("用法: douyinlive record <直播间号> [参数]")
+	}
+
+	collector := setupDiag(*diagDir, fs)
+	tzOpt, err := timezoneOption(*timezone)
+	if err != nil {
+		return err
+	}
+	opts := []douyinLive.Option{tzOpt}
+	if collector != nil {
+		defer collector.Recover()
+		opts = append(opts, collector.Option())
+	}
+
+	recorder, err := sink.NewRecorder(*out, sink.RecorderOptions{
+		RotateOptions: sink.RotateOptions{MaxSize: *maxSize, Interval: *interval, Compress: *compress},
+		Fields:        sink.ParseFields(*fields),
+		Methods:       *methods,
+		Topics:        *topics,
+	})
+	if err != nil {
+		return err
+	}
+	buf := sink.NewBuffer(recorder, sink.BufferOptions{
+		FlushInterval: time.Second,
+		OnError:       func(err error) { slog.Warn("写入文件失败", "error", err) },
+	})
+	defer buf.Close()
+
+	if *patchSource != "" {
+		var patchOpts hotpatch.Options
+		if *patchKey != "" {
+			key, err := hotpatch.ParsePublicKey(*patchKey)
+			if err != nil {
+				return usageError(err.Error())
+			}
+			patchOpts.PublicKey = key
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := hotpatch.Watch(ctx, *patchSource, patchOpts); err != nil {
+			return err
+		}
+	}
+	dl, err := douyinLive.NewDouyinLive(liveID, nil, opts...)
+	if err != nil {
+		return finishDiag(collector, err)
+	}
+	if collector != nil {
+		collector.Watch(dl)
+	}
+	if *controlFile != "" {
+		plane := control.New(control.Config{}, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := plane.WatchFile(ctx, *controlFile, 0); err != nil {
+			return err
+		}
+		plane.Register(dl)
+	}
+	sink.Attach(dl, buf, func(err error) { slog.Warn("写入缓冲失败", "error", err) })
+	slog.Info("开始录制", "live_id", liveID, "out", *out)
+	return finishDiag(collector, runUntilSignal(dl))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive/relay"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+// runServe 实现 serve 子命令：以转发网关模式运行，客户端连接 ws://<listen>/ws/<直播间号>
+func runServe(args []string) error {
+	fs := pflag.NewFlagSet("serve", pflag.ContinueOnError)
+	listen := fs.String("listen", ":8080", "监听地址")
+	path := fs.String("path", "/ws/", "WebSocket 路径前缀")
+	fields := fs.String("fields", "", "输出字段白名单，逗号分隔")
+	buffer := fs.Int("client-buffer", 0, "每个客户端的缓冲事件数，溢出时断开该客户端")
+	origins := fs.StringSlice("allow-origin", nil, "允许跨域连接的页面来源，如 https://example.com，可多次指定，* 允许任意来源；默认只允许同源")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(*path, relay.New(relay.Options{
+		Fields:       sink.ParseFields(*fields),
+		ClientBuffer: *buffer,
+		CheckOrigin:  originChecker(*origins),
+	}))
+	srv := &http.Server{Addr: *listen, Handler: mux}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	slog.Info("转发网关已启动", "listen", *listen, "path", *path)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// originChecker 按允许的来源检查 WebSocket 握手，列表为空时返回 nil，即只允许同源
+func originChecker(allowed []string) func(*http.Request) bool {
+	if len(allowed) == 0 {
+		return nil
+	}
+	if slices.Contains(allowed, "*") {
+		return func(*http.Request) bool { return true }
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || slices.Contains(allowed, origin)
+	}
+}
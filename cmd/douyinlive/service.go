@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/daemon"
+	"github.com/tiga210/douyinLive/sink"
+	"github.com/tiga210/douyinLive/sink/sqlite"
+)
+
+// runService 实现 service 子命令：douyinlive service <install|uninstall|start|stop|restart|status|run> [参数]
+func runService(args []string) error {
+	actions := append(daemon.Actions(), "status", "run")
+	if len(args) == 0 || !slices.Contains(actions, args[0]) {
+		return usageError(fmt.Sprintf("用法: douyinlive service <%s> [参数]", strings.Join(actions, "|")))
+	}
+	action := args[0]
+
+	fs := pflag.NewFlagSet("service", pflag.ContinueOnError)
+	name := fs.String("name", "douyinlive", "服务名，同一台机器采集多个房间时需区分")
+	room := fs.String("room", "", "抖音直播房间号")
+	dbPath := fs.String("db", "douyinlive.db", "SQLite 数据库文件")
+	logFile := fs.String("log", "douyinlive.log", "日志文件，为空时输出到标准错误")
+	logMaxSize := fs.Int64("log-max-size", 50<<20, "单个日志文件的最大字节数")
+	logCompress := fs.Bool("log-compress", true, "轮转后的日志用 gzip 压缩")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if (action == "install" || action == "run") && *room == "" {
+		return fmt.Errorf("%s 需要指定 --room", action)
+	}
+
+	// 服务管理器的工作目录不确定，相对路径按安装时的目录展开
+	for _, p := range []*string{dbPath, logFile} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			abs, err := filepath.Abs(*p)
+			if err != nil {
+				return err
+			}
+			*p = abs
+		}
+	}
+
+	svc, err := daemon.New(daemon.Config{
+		Name:        *name,
+		DisplayName: "抖音直播采集器 (" + *name + ")",
+		Description: "采集抖音直播间弹幕并写入 SQLite",
+		Arguments: []string{"service", "run", "--name", *name, "--room", *room, "--db", *dbPath, "--log", *logFile,
+			fmt.Sprintf("--log-max-size=%d", *logMaxSize), fmt.Sprintf("--log-compress=%t", *logCompress)},
+		LogFile: *logFile,
+		Rotate:  sink.RotateOptions{MaxSize: *logMaxSize, Interval: 24 * time.Hour, Compress: *logCompress},
+	}, func(ctx context.Context, log *slog.Logger) error {
+		return collect(ctx, log, *room, *dbPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "run":
+		return svc.Run()
+	case "status":
+		fmt.Printf("%s: %s\n", *name, svc.Status())
+		return nil
+	}
+	if err := svc.Control(action); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s: %s 完成\n", *name, action)
+	return nil
+}
+
+// collect 采集单个房间写入 SQLite，直到 ctx 结束或连接终止
+func collect(ctx context.Context, log *slog.Logger, room, dbPath string) error {
+	store, err := sqlite.Open(dbPath, sqlite.Options{})
+	if err != nil {
+		return err
+	}
+	buf := sink.NewBuffer(store, sink.BufferOptions{
+		OnError: func(err error) { log.Warn("写入 SQLite 失败", "error", err) },
+	})
+	defer buf.Close()
+
+	dl, err := douyinLive.NewDouyinLive(room, nil, douyinLive.WithSlog(log))
+	if err != nil {
+		return err
+	}
+	sink.Attach(dl, buf, func(err error) { log.Warn("写入缓冲失败", "error", err) })
+	return runLive(ctx, dl)
+}
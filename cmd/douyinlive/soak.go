@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/daemon"
+	"github.com/tiga210/douyinLive/soak"
+)
+
+// runSoak 实现 soak 子命令：持续连接直播间，周期性采样，结束时输出稳定性报告，未通过时返回错误
+func runSoak(args []string) error {
+	fs := pflag.NewFlagSet("soak", pflag.ContinueOnError)
+	duration := fs.Duration("duration", 24*time.Hour, "运行时长")
+	interval := fs.Duration("interval", time.Minute, "采样间隔")
+	queue := fs.Int("queue", 256, "异步分发队列容量，0 表示同步分发")
+	reportPath := fs.String("report", "", "完整报告（含全部采样）的 JSON 输出文件")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	liveID := fs.Arg(0)
+	if liveID == "" {
+		return usageError("用法: douyinlive soak <直播间号> [参数]")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	monitor := soak.New(soak.Options{Interval: *interval})
+	go daemon.Supervise(ctx, func(ctx context.Context, log *slog.Logger) error {
+		var opts []douyinLive.Option
+		if *queue > 0 {
+			opts = append(opts, douyinLive.WithAsyncDispatch(*queue))
+		}
+		dl, err := douyinLive.NewDouyinLive(liveID, nil, opts...)
+		if err != nil {
+			return err
+		}
+		defer monitor.Watch(dl)()
+		err = runLive(ctx, dl)
+		if ctx.Err() == nil {
+			monitor.RecordRestart(err)
+		}
+		return err
+	}, slog.Default(), 0, 0)
+
+	report := monitor.Run(ctx)
+	if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			return err
+		}
+		if err := errors.Join(report.WriteJSON(f), f.Close()); err != nil {
+			return err
+		}
+	}
+	if !report.Passed {
+		return errors.New("稳定性自检未通过")
+	}
+	return nil
+}
@@ -28,7 +28,7 @@ func (c *WsHandler) OnOpen(socket *gws.Conn) {
 	// 如果是第一个连接到该房间的客户端，初始化抖音直播实例
 	if !loaded {
 		// 新创建的房间组，初始化抖音直播实例
-		d, err := douyinLive.NewDouyinLive(c.RoomID, logger)
+		d, err := douyinLive.NewDouyinLive(c.RoomID, logger, douyinLive.WithLogLevel(logLevel))
 		if err != nil {
 			// 发送通知给客户端
 			if err := socket.WriteString(`{"type":"system","message":"直播间未开播"}`); err != nil {
@@ -49,7 +49,11 @@ func (c *WsHandler) OnOpen(socket *gws.Conn) {
 		})
 
 		// 开始处理
-		go d.Start()
+		go func() {
+			if err := d.Start(); err != nil {
+				logger.Printf("房间 %s 连接结束: %v", c.RoomID, err)
+			}
+		}()
 
 		// 更新组中的抖音直播实例
 
@@ -25,6 +25,8 @@ func initConfig() {
 	viper.SetDefault("room", "****")
 	viper.SetDefault("unknown", false)
 	viper.SetDefault("key", "")
+	viper.SetDefault("fields", "")
+	viper.SetDefault("log_level", "info")
 
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
@@ -7,11 +7,14 @@ import (
 	"github.com/spf13/cast"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/tiga210/douyinLive"
 	"github.com/tiga210/douyinLive/generated"
 	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"github.com/tiga210/douyinLive/sink"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -26,6 +29,8 @@ var (
 	port       string
 	room       string // 抖音直播房间号
 	key        string
+	fields     sink.Fields // 输出字段白名单
+	logLevel   slog.Level  // 直播实例日志级别
 	logger     *log.Logger
 	wsHandler  WsHandler // 创建 WebSocket 处理器实例
 	roomGroups sync.Map
@@ -38,6 +43,8 @@ func cmdArgs() {
 	pflag.String("room", viper.GetString("room"), "抖音直播房间号")
 	pflag.Bool("unknown", viper.GetBool("unknown"), "是否输出未知源的pb消息")
 	pflag.String("key", viper.GetString("key"), "tikhub key")
+	pflag.String("fields", viper.GetString("fields"), "输出字段白名单，逗号分隔，如 time,user_id,content")
+	pflag.String("log_level", viper.GetString("log_level"), "日志级别: debug/info/warn/error")
 	configFile := *pflag.String("config", "", "指定配置文件路径")
 	// 解析命令行参数
 	pflag.Parse()
@@ -64,6 +71,10 @@ func cmdArgs() {
 	}
 	unknown = viper.GetBool("unknown")
 	key = viper.GetString("key")
+	fields = sink.ParseFields(viper.GetString("fields"))
+	if err := logLevel.UnmarshalText([]byte(viper.GetString("log_level"))); err != nil {
+		logger.Fatalf("无效的日志级别: %v", err)
+	}
 }
 func main() {
 	logger = log.Default()
@@ -145,6 +156,19 @@ func routeEventToRoomClients(roomID string, eventData *new_douyin.Webcast_Im_Mes
 		return
 	}
 
+	// 配置了字段白名单时按统一的事件字段输出
+	if len(fields) > 0 {
+		finalJSON, err := sink.JSONEncoder{Fields: fields}.Encode(douyinLive.NewLiveEvent(roomID, n, eventData))
+		if err != nil {
+			logger.Printf("JSON 序列化失败: %v\n", err)
+			return
+		}
+		if group, ok := roomGroups.Load(roomID); ok {
+			broadcastMessage(group.(*RoomGroup).connections, roomID, finalJSON)
+		}
+		return
+	}
+
 	if msg != nil {
 		if err := proto.Unmarshal(eventData.Payload, msg); err != nil {
 			logger.Printf("反序列化失败: %v, 方法: %s\n", err, eventData.Method)
@@ -0,0 +1,221 @@
+// Package commerce 电商直播的商品讲解时间轴：按讲解切换消息把直播划分为商品讲解区间，
+// 并把区间内的弹幕与成交播报关联到对应商品，用于复盘各商品的互动与成交
+package commerce
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// 电商相关的消息类型
+const (
+	MethodShopExplain   = "WebcastAwemeShopExplainMessage"    // 开始或结束讲解商品
+	MethodProductChange = "WebcastProductChangeMessage"       // 购物车商品变化，带商品序号
+	MethodGoodsOrder    = "WebcastVideoLiveGoodsOrderMessage" // 成交播报
+	MethodLiveEcom      = "WebcastLiveEcomMessage"            // "xx 正在购买" 等电商播报
+)
+
+// Chat 讲解区间内的一条弹幕
+type Chat struct {
+	UserID   uint64    `json:"user_id"`
+	Nickname string    `json:"nickname"`
+	Content  string    `json:"content"`
+	Time     time.Time `json:"time"`
+}
+
+// Segment 一次商品讲解
+type Segment struct {
+	Index      int       `json:"index"`      // 第几次讲解，从 1 开始
+	ProductID  uint64    `json:"product_id"` // 商品的 promotion_id
+	CartIndex  int       `json:"cart_index"` // 商品在购物车中的序号，未知时为 0
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"` // 讲解仍在进行时为零值
+	ChatCount  int       `json:"chat_count"`
+	Orders     int       `json:"orders"`      // 成交播报条数
+	Purchases  uint64    `json:"purchases"`   // 播报中的购买件数合计
+	OrderMoney uint64    `json:"order_money"` // 播报中的成交金额合计（分），部分播报不含金额
+	Chats      []Chat    `json:"chats,omitempty"`
+}
+
+// Duration 讲解时长，进行中的讲解计算到 now
+func (s Segment) Duration(now time.Time) time.Duration {
+	if s.End.IsZero() {
+		return now.Sub(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+// ChatRate 每分钟弹幕数，用于比较时长不同的讲解
+func (s Segment) ChatRate(now time.Time) float64 {
+	minutes := s.Duration(now).Minutes()
+	if minutes <= 0 {
+		return 0
+	}
+	return float64(s.ChatCount) / minutes
+}
+
+// Options 时间轴配置
+type Options struct {
+	KeepChats int // 每个讲解区间保留的弹幕条数上限，0 表示只计数
+}
+
+// Timeline 商品讲解时间轴
+type Timeline struct {
+	opts Options
+
+	mu        sync.Mutex
+	segments  []*Segment
+	current   *Segment
+	cartIndex map[uint64]int
+}
+
+// New 创建时间轴
+func New(opts Options) *Timeline {
+	return &Timeline{opts: opts, cartIndex: make(map[uint64]int)}
+}
+
+// Watch 监听直播间的讲解切换、弹幕与成交播报，返回取消监听的函数
+func (t *Timeline) Watch(dl *douyinLive.DouyinLive) (unwatch func()) {
+	id := dl.SubscribeEvent(t.Observe)
+	return func() { dl.Unsubscribe(id) }
+}
+
+// Observe 处理单个事件，与讲解时间轴无关的消息忽略
+func (t *Timeline) Observe(event *douyinLive.LiveEvent) {
+	switch event.Method {
+	case MethodShopExplain, MethodProductChange, MethodGoodsOrder, MethodLiveEcom, douyinLive.WebcastChatMessage:
+	default:
+		return
+	}
+	decoded, err := event.Decode()
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch msg := decoded.(type) {
+	case *new_douyin.Webcast_Im_AwemeShopExplainMessage:
+		if msg.Extra != nil {
+			t.explain(msg.Extra.PromotionId, msg.Extra.Active, event.Time)
+		}
+	case *new_douyin.Webcast_Im_ProductChangeMessage:
+		for _, product := range msg.UpdateProductInfo {
+			t.cartIndex[product.PromotionId] = int(product.Index)
+		}
+	case *new_douyin.Webcast_Im_VideoLiveGoodsOrderMessage:
+		if t.current != nil && msg.GoodsOrder != nil {
+			t.current.Orders++
+			t.current.Purchases += msg.GoodsOrder.OrderNum
+			t.current.OrderMoney += msg.GoodsOrder.OrderMoney
+		}
+	case *new_douyin.Webcast_Im_LiveEcomMessage:
+		if t.current != nil && msg.PurchaseCnt > 0 {
+			t.current.Orders++
+			t.current.Purchases += msg.PurchaseCnt
+		}
+	case *new_douyin.Webcast_Im_ChatMessage:
+		if t.current == nil {
+			return
+		}
+		t.current.ChatCount++
+		if len(t.current.Chats) < t.opts.KeepChats {
+			chat := Chat{Content: msg.Content, Time: event.Time}
+			if msg.User != nil {
+				chat.UserID, chat.Nickname = msg.User.Id, msg.User.Nickname
+			}
+			t.current.Chats = append(t.current.Chats, chat)
+		}
+	}
+}
+
+// explain 处理讲解切换：开始讲解新商品时结束上一个讲解
+func (t *Timeline) explain(productID uint64, active bool, at time.Time) {
+	if t.current != nil && (active || t.current.ProductID == productID) {
+		if active && t.current.ProductID == productID {
+			return // 重复的开始讲解消息
+		}
+		t.current.End = at
+		t.current = nil
+	}
+	if !active || productID == 0 {
+		return
+	}
+	t.current = &Segment{
+		Index:     len(t.segments) + 1,
+		ProductID: productID,
+		CartIndex: t.cartIndex[productID],
+		Start:     at,
+	}
+	t.segments = append(t.segments, t.current)
+}
+
+// Segments 返回按时间排列的全部讲解区间
+func (t *Timeline) Segments() []Segment {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	segments := make([]Segment, len(t.segments))
+	for i, s := range t.segments {
+		segments[i] = *s
+		if segments[i].CartIndex == 0 {
+			segments[i].CartIndex = t.cartIndex[s.ProductID]
+		}
+		segments[i].Chats = append([]Chat(nil), s.Chats...)
+	}
+	return segments
+}
+
+// Current 返回正在进行的讲解
+func (t *Timeline) Current() (Segment, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current == nil {
+		return Segment{}, false
+	}
+	return *t.current, true
+}
+
+// Hottest 返回每分钟弹幕数最高的讲解区间，没有讲解时返回 false
+func (t *Timeline) Hottest(now time.Time) (Segment, bool) {
+	var hottest Segment
+	found := false
+	for _, s := range t.Segments() {
+		if !found || s.ChatRate(now) > hottest.ChatRate(now) {
+			hottest, found = s, true
+		}
+	}
+	return hottest, found
+}
+
+// WriteCSV 以 CSV 输出时间轴，每个讲解区间一行
+func (t *Timeline) WriteCSV(w io.Writer, now time.Time) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"index", "product_id", "cart_index", "start", "end", "duration_seconds", "chats", "chats_per_minute", "orders", "purchases", "order_money"})
+	for _, s := range t.Segments() {
+		end := ""
+		if !s.End.IsZero() {
+			end = s.End.Format(time.RFC3339)
+		}
+		cw.Write([]string{
+			strconv.Itoa(s.Index),
+			strconv.FormatUint(s.ProductID, 10),
+			strconv.Itoa(s.CartIndex),
+			s.Start.Format(time.RFC3339),
+			end,
+			strconv.FormatFloat(s.Duration(now).Seconds(), 'f', 0, 64),
+			strconv.Itoa(s.ChatCount),
+			strconv.FormatFloat(s.ChatRate(now), 'f', 2, 64),
+			strconv.Itoa(s.Orders),
+			strconv.FormatUint(s.Purchases, 10),
+			strconv.FormatUint(s.OrderMoney, 10),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
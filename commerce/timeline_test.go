@@ -0,0 +1,89 @@
+package commerce
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func event(t *testing.T, method string, msg proto.Message, at time.Time) *douyinLive.LiveEvent {
+	t.Helper()
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := douyinLive.NewLiveEvent("1", "", &new_douyin.Webcast_Im_Message{Method: method, Payload: payload})
+	e.Time = at
+	return e
+}
+
+func explain(t *testing.T, id uint64, active bool, at time.Time) *douyinLive.LiveEvent {
+	return event(t, MethodShopExplain, &new_douyin.Webcast_Im_AwemeShopExplainMessage{
+		Extra: &new_douyin.Webcast_Im_AwemeShopExplainMessage_Extra{PromotionId: id, Active: active},
+	}, at)
+}
+
+func chat(t *testing.T, content string, at time.Time) *douyinLive.LiveEvent {
+	return event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: content}, at)
+}
+
+func TestTimeline(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	at := func(minutes float64) time.Time { return start.Add(time.Duration(minutes * float64(time.Minute))) }
+	tl := New(Options{KeepChats: 1})
+
+	for _, e := range []*douyinLive.LiveEvent{
+		event(t, MethodProductChange, &new_douyin.Webcast_Im_ProductChangeMessage{
+			UpdateProductInfo: []*new_douyin.Webcast_Im_ProductInfo{{PromotionId: 11, Index: 3}},
+		}, at(0)),
+		chat(t, "开场前", at(0)),
+		explain(t, 11, true, at(1)),
+		chat(t, "多少钱", at(2)),
+		chat(t, "链接呢", at(3)),
+		event(t, MethodGoodsOrder, &new_douyin.Webcast_Im_VideoLiveGoodsOrderMessage{
+			GoodsOrder: &new_douyin.Webcast_Im_VideoLiveGoodsOrderMessage_GoodsOrder{OrderNum: 2, OrderMoney: 9900},
+		}, at(4)),
+		explain(t, 11, true, at(4)), // 重复的开始讲解
+		explain(t, 22, true, at(11)),
+		chat(t, "好看", at(12)),
+		chat(t, "要了", at(12)),
+		chat(t, "上链接", at(12)),
+		event(t, MethodLiveEcom, &new_douyin.Webcast_Im_LiveEcomMessage{PurchaseCnt: 1}, at(13)),
+		explain(t, 22, false, at(13)),
+		chat(t, "讲解结束后", at(14)),
+	} {
+		tl.Observe(e)
+	}
+
+	segments := tl.Segments()
+	if len(segments) != 2 {
+		t.Fatalf("segments = %+v", segments)
+	}
+	first, second := segments[0], segments[1]
+	if first.ProductID != 11 || first.CartIndex != 3 || first.ChatCount != 2 || first.Orders != 1 ||
+		first.Purchases != 2 || first.OrderMoney != 9900 || !first.End.Equal(at(11)) || len(first.Chats) != 1 {
+		t.Fatalf("first = %+v", first)
+	}
+	if second.Index != 2 || second.ChatCount != 3 || second.Purchases != 1 || !second.End.Equal(at(13)) {
+		t.Fatalf("second = %+v", second)
+	}
+	if _, ok := tl.Current(); ok {
+		t.Fatal("讲解结束后不应有进行中的讲解")
+	}
+	if hottest, _ := tl.Hottest(at(20)); hottest.ProductID != 22 {
+		t.Fatalf("Hottest = %d, want 22", hottest.ProductID)
+	}
+
+	var b strings.Builder
+	if err := tl.WriteCSV(&b, at(20)); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(b.String(), "\n"); lines != 3 {
+		t.Fatalf("CSV 行数 = %d, want 3:\n%s", lines, b.String())
+	}
+}
@@ -0,0 +1,147 @@
+package douyinLive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 消息帧的压缩方式，即 PushFrame 头中的 compress_type 与连接参数 compress 的取值
+const (
+	CompressGzip   = "gzip"
+	CompressZstd   = "zstd"
+	CompressBrotli = "br"
+	CompressNone   = "none"
+)
+
+// 常见压缩格式的魔数，消息头中没有 compress_type 时据此识别
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+const (
+	// maxPooledBufferSize 放回池中的解压缓冲区的最大容量
+	maxPooledBufferSize = 1 << 20
+	// maxFrameSize 单个消息帧解压后的最大字节数，防止解压炸弹耗尽内存
+	maxFrameSize = 16 << 20
+)
+
+// zstdDecoder 进程内共享的 zstd 解码器，DecodeAll 可并发调用
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxFrameSize))
+})
+
+// WithCompression 设置连接时请求的压缩方式（连接参数 compress），默认 gzip。
+// 无论请求哪种方式，收到的消息帧都按其 compress_type 头解压，支持 gzip、zstd、br 与未压缩
+func WithCompression(codec string) Option {
+	return func(dl *DouyinLive) {
+		dl.compression = codec
+	}
+}
+
+// Compression 返回连接时请求的压缩方式，未指定时开启 zstd 特性则为 zstd，否则为 gzip
+func (dl *DouyinLive) Compression() string {
+	switch {
+	case dl.compression != "":
+		return dl.compression
+	case dl.FeatureEnabled(FeatureZstd):
+		return CompressZstd
+	default:
+		return CompressGzip
+	}
+}
+
+// decompress 按压缩方式解压消息帧，压缩的内容解压到 buf 中且不超过 maxFrameSize，encoding 为空时按魔数识别，
+// 都不匹配时视为未压缩。返回的切片在 buf 放回池中之前有效
+func (dl *DouyinLive) decompress(encoding string, data []byte, buf *bytes.Buffer) ([]byte, error) {
+	if encoding == "" {
+		switch {
+		case bytes.HasPrefix(data, gzipMagic):
+			encoding = CompressGzip
+		case bytes.HasPrefix(data, zstdMagic):
+			encoding = CompressZstd
+		default:
+			encoding = CompressNone
+		}
+	}
+	switch encoding {
+	case CompressGzip:
+		if err := gunzip(buf, data); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(data, buf.Bytes()[:0])
+	case CompressBrotli, "brotli":
+		if err := readFrame(buf, brotli.NewReader(bytes.NewReader(data))); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressNone, "identity":
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCompression, encoding)
+	}
+}
+
+// gzipReaders 复用的 gzip.Reader，避免每帧重新分配解压窗口与哈夫曼表
+var gzipReaders = sync.Pool{New: func() any { return new(gzipReader) }}
+
+// gzipReader 可复用的 gzip.Reader 及其输入
+type gzipReader struct {
+	src bytes.Reader
+	zr  gzip.Reader
+}
+
+// gunzip 用复用的 gzip.Reader 把 data 解压并追加到 out
+func gunzip(out *bytes.Buffer, data []byte) error {
+	r := gzipReaders.Get().(*gzipReader)
+	defer gzipReaders.Put(r)
+	r.src.Reset(data)
+	if err := r.zr.Reset(&r.src); err != nil {
+		return err
+	}
+	out.Grow(len(data) * 4)
+	return readFrame(out, &r.zr)
+}
+
+// readFrame 把解压后的内容追加到 out，超过 maxFrameSize 时返回错误
+func readFrame(out *bytes.Buffer, r io.Reader) error {
+	n, err := out.ReadFrom(io.LimitReader(r, maxFrameSize+1))
+	if err != nil {
+		return err
+	}
+	if n > maxFrameSize {
+		return fmt.Errorf("消息帧解压后超过 %d 字节", maxFrameSize)
+	}
+	return nil
+}
+
+// GzipUnzipReset 用复用的 gzip.Reader 与缓冲区解压 GZIP 数据，返回的切片归调用方所有
+func (dl *DouyinLive) GzipUnzipReset(data []byte) ([]byte, error) {
+	buf := dl.bufferPool.Get().(*bytes.Buffer)
+	defer dl.putBuffer(buf)
+	if err := gunzip(buf, data); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// putBuffer 把解压缓冲区放回池中，过大的缓冲区直接丢弃，避免个别大帧长期占用内存
+func (dl *DouyinLive) putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	dl.bufferPool.Put(buf)
+}
@@ -0,0 +1,136 @@
+package douyinLive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDecompress(t *testing.T) {
+	body := []byte("hello douyin")
+	var gz, br bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(body)
+	zw.Close()
+	bw := brotli.NewWriter(&br)
+	bw.Write(body)
+	bw.Close()
+	enc, _ := zstd.NewWriter(nil)
+	zst := enc.EncodeAll(body, nil)
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	cases := []struct {
+		encoding string
+		data     []byte
+	}{
+		{CompressGzip, gz.Bytes()},
+		{CompressZstd, zst},
+		{CompressBrotli, br.Bytes()},
+		{CompressNone, body},
+		{"", gz.Bytes()},
+		{"", zst},
+		{"", body},
+	}
+	for _, c := range cases {
+		got, err := dl.decompress(c.encoding, c.data, new(bytes.Buffer))
+		if err != nil || !bytes.Equal(got, body) {
+			t.Fatalf("decompress(%q) = %q, %v", c.encoding, got, err)
+		}
+	}
+	if _, err := dl.decompress("lz4", body, new(bytes.Buffer)); !errors.Is(err, ErrUnsupportedCompression) {
+		t.Fatalf("未知压缩方式返回 %v", err)
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	bomb := make([]byte, maxFrameSize+1)
+	var gz, br bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(bomb)
+	zw.Close()
+	bw := brotli.NewWriter(&br)
+	bw.Write(bomb)
+	bw.Close()
+	// 不在帧头中声明内容大小，解码器只能在解压过程中发现超限
+	enc, _ := zstd.NewWriter(nil, zstd.WithSingleSegment(false))
+	var zst bytes.Buffer
+	enc.Reset(&zst)
+	enc.Write(bomb)
+	enc.Close()
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	for encoding, data := range map[string][]byte{CompressGzip: gz.Bytes(), CompressZstd: zst.Bytes(), CompressBrotli: br.Bytes()} {
+		if _, err := dl.decompress(encoding, data, new(bytes.Buffer)); err == nil {
+			t.Fatalf("%s 解压后超过上限应返回错误", encoding)
+		}
+	}
+}
+
+func TestZstdFrame(t *testing.T) {
+	chat := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+	body, _ := proto.Marshal(&new_douyin.Webcast_Im_Response{Messages: []*new_douyin.Webcast_Im_Message{chat}})
+	enc, _ := zstd.NewWriter(nil)
+	raw, _ := proto.Marshal(&new_douyin.Webcast_Im_PushFrame{
+		PayloadType: "msg",
+		Headers:     []*new_douyin.Webcast_Im_PushHeader{{Key: "compress_type", Value: CompressZstd}},
+		Payload:     enc.EncodeAll(body, nil),
+	})
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithCompression(CompressZstd))
+	var got int
+	dl.Subscribe(func(*new_douyin.Webcast_Im_Message) { got++ })
+	var pushFrame new_douyin.Webcast_Im_PushFrame
+	dl.handleFrame(&pushFrame, raw, nil)
+	if got != 1 {
+		t.Fatalf("收到 %d 条, want 1", got)
+	}
+	if dl.Compression() != CompressZstd {
+		t.Fatalf("Compression = %s", dl.Compression())
+	}
+}
+
+func BenchmarkDecompressGzip(b *testing.B) {
+	messages := make([]*new_douyin.Webcast_Im_Message, 50)
+	for i := range messages {
+		payload, _ := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{Content: "主播好，来自远方的问候"})
+		messages[i] = &new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: uint64(i + 1), Payload: payload}
+	}
+	body, _ := proto.Marshal(&new_douyin.Webcast_Im_Response{Messages: messages})
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(body)
+	zw.Close()
+	data := gz.Bytes()
+
+	b.Run("pooled", func(b *testing.B) {
+		dl := NewDouyinLive2("1", "2", "test", "", nil)
+		b.ReportAllocs()
+		for b.Loop() {
+			buf := dl.bufferPool.Get().(*bytes.Buffer)
+			if _, err := dl.decompress(CompressGzip, data, buf); err != nil {
+				b.Fatal(err)
+			}
+			dl.putBuffer(buf)
+		}
+	})
+	b.Run("new_reader", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			zr, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := io.ReadAll(zr); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package douyinLive
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConnectTimings 一次接入各阶段的耗时，未经过的阶段为 0
+type ConnectTimings struct {
+	StartedAt time.Time
+	Page      time.Duration // 抓取直播间页面（开播检查与房间信息，可能多次）
+	TTWID     time.Duration // 获取 ttwid
+	Signature time.Duration // 计算 WebSocket 签名
+	Handshake time.Duration // WebSocket 握手
+	Total     time.Duration // 从开始接入到握手完成
+}
+
+// String 输出 "页面→ttwid→签名→握手 (总计)" 形式的耗时
+func (t ConnectTimings) String() string {
+	return fmt.Sprintf("页面 %s → ttwid %s → 签名 %s → 握手 %s (总计 %s)",
+		t.Page.Round(time.Millisecond), t.TTWID.Round(time.Millisecond),
+		t.Signature.Round(time.Millisecond), t.Handshake.Round(time.Millisecond),
+		t.Total.Round(time.Millisecond))
+}
+
+// connectPhase 接入阶段
+type connectPhase int
+
+const (
+	phasePage connectPhase = iota
+	phaseTTWID
+	phaseSignature
+	phaseHandshake
+)
+
+// ConnectTimings 返回最近一次接入（含重连）的各阶段耗时
+func (dl *DouyinLive) ConnectTimings() ConnectTimings {
+	dl.timingMu.Lock()
+	defer dl.timingMu.Unlock()
+	return dl.timings
+}
+
+// beginTimings 开始记录新的一次接入
+func (dl *DouyinLive) beginTimings() {
+	dl.timingMu.Lock()
+	defer dl.timingMu.Unlock()
+	dl.timings = ConnectTimings{StartedAt: time.Now()}
+}
+
+// observePhase 累加阶段耗时，用法：defer dl.observePhase(phaseX, time.Now())
+func (dl *DouyinLive) observePhase(phase connectPhase, start time.Time) {
+	d := time.Since(start)
+	dl.timingMu.Lock()
+	defer dl.timingMu.Unlock()
+	switch phase {
+	case phasePage:
+		dl.timings.Page += d
+	case phaseTTWID:
+		dl.timings.TTWID += d
+	case phaseSignature:
+		dl.timings.Signature += d
+	case phaseHandshake:
+		dl.timings.Handshake += d
+	}
+}
+
+// finishTimings 接入完成，记录总耗时并输出日志
+func (dl *DouyinLive) finishTimings() {
+	dl.timingMu.Lock()
+	dl.timings.Total = time.Since(dl.timings.StartedAt)
+	t := dl.timings
+	dl.timingMu.Unlock()
+	dl.log().Info("连接耗时",
+		"page", t.Page, "ttwid", t.TTWID, "signature", t.Signature,
+		"handshake", t.Handshake, "total", t.Total)
+}
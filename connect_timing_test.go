@@ -0,0 +1,24 @@
+package douyinLive
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnectTimings(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	dl.beginTimings()
+	dl.observePhase(phasePage, time.Now().Add(-100*time.Millisecond))
+	dl.observePhase(phasePage, time.Now().Add(-100*time.Millisecond))
+	dl.observePhase(phaseHandshake, time.Now().Add(-50*time.Millisecond))
+	dl.finishTimings()
+
+	got := dl.ConnectTimings()
+	if got.Page < 200*time.Millisecond || got.Handshake < 50*time.Millisecond || got.TTWID != 0 {
+		t.Fatalf("阶段耗时错误: %+v", got)
+	}
+	if !strings.HasPrefix(got.String(), "页面 2") {
+		t.Fatalf("String() = %s", got)
+	}
+}
@@ -0,0 +1,171 @@
+// Package control 采集开关的控制面：集中管理多个直播实例按消息类型的采集开关，
+// 通过 HTTP 接口或配置文件热加载下发，即时生效，适合大促期间快速降载
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// defaultWatchInterval 配置文件的默认检查间隔
+const defaultWatchInterval = 2 * time.Second
+
+// Config 采集开关配置，Rooms 中的房间（按 live_id 或 room_id）整体覆盖 Default
+type Config struct {
+	Default douyinLive.MethodFilter            `json:"default"`
+	Rooms   map[string]douyinLive.MethodFilter `json:"rooms,omitempty"`
+}
+
+// FilterFor 返回某个直播间生效的开关
+func (c Config) FilterFor(liveID, roomID string) douyinLive.MethodFilter {
+	if f, ok := c.Rooms[liveID]; ok && liveID != "" {
+		return f
+	}
+	if f, ok := c.Rooms[roomID]; ok && roomID != "" {
+		return f
+	}
+	return c.Default
+}
+
+// LoadFile 读取 JSON 格式的配置文件
+func LoadFile(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Plane 控制面，保存当前配置并下发到已登记的直播实例
+type Plane struct {
+	mu     sync.Mutex
+	cfg    Config
+	lives  map[*douyinLive.DouyinLive]struct{}
+	logger *slog.Logger
+}
+
+// New 创建控制面，logger 为空时使用 slog.Default()
+func New(cfg Config, logger *slog.Logger) *Plane {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Plane{cfg: cfg, lives: make(map[*douyinLive.DouyinLive]struct{}), logger: logger}
+}
+
+// Register 登记直播实例并立即应用当前配置，连接时解析出 room_id 后会再应用一次，
+// 使按 room_id 配置的开关在首次连接时即生效。返回的函数用于取消登记
+func (p *Plane) Register(dl *douyinLive.DouyinLive) func() {
+	p.mu.Lock()
+	p.lives[dl] = struct{}{}
+	p.mu.Unlock()
+	dl.SetMethodFilterSource(p.filterFor)
+	return func() {
+		p.mu.Lock()
+		delete(p.lives, dl)
+		p.mu.Unlock()
+		dl.SetMethodFilterSource(nil)
+	}
+}
+
+// filterFor 按当前配置返回直播间的开关
+func (p *Plane) filterFor(liveID, roomID string) douyinLive.MethodFilter {
+	return p.Config().FilterFor(liveID, roomID)
+}
+
+// Apply 替换配置并下发到全部已登记的实例
+func (p *Plane) Apply(cfg Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+	for dl := range p.lives {
+		dl.SetMethodFilter(cfg.FilterFor(dl.LiveID(), dl.RoomID()))
+	}
+}
+
+// Config 返回当前配置
+func (p *Plane) Config() Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cfg
+}
+
+// ServeHTTP GET 返回当前配置，PUT/POST 以请求体中的 JSON 替换配置
+func (p *Plane) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var cfg Config
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, "配置格式错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		p.Apply(cfg)
+		p.logger.Info("采集开关已更新", "source", "http", "remote", r.RemoteAddr)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "不支持的方法", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(p.Config())
+}
+
+// WatchFile 先加载一次配置文件，之后按 interval 检查修改时间，变化时重新加载，直到 ctx 结束。
+// 首次加载失败时直接返回错误，之后的失败只记录日志并保留原配置
+func (p *Plane) WatchFile(ctx context.Context, path string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	p.Apply(cfg)
+	modTime := fileModTime(path)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			mt := fileModTime(path)
+			if mt.Equal(modTime) {
+				continue
+			}
+			modTime = mt
+			cfg, err := LoadFile(path)
+			if err != nil {
+				p.logger.Warn("重新加载采集开关失败", "path", path, "error", err)
+				continue
+			}
+			p.Apply(cfg)
+			p.logger.Info("采集开关已更新", "source", "file", "path", path)
+		}
+	}()
+	return nil
+}
+
+// fileModTime 返回文件修改时间，文件不存在时为零值
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,78 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestPlane(t *testing.T) {
+	dl, _ := douyinLive.NewDouyinLive("123", nil)
+	other, _ := douyinLive.NewDouyinLive("456", nil)
+
+	p := New(Config{Default: douyinLive.MethodFilter{Disable: []string{douyinLive.WebcastLikeMessage}}}, nil)
+	p.Register(dl)
+	unregister := p.Register(other)
+	if dl.MethodFilter().Allows(douyinLive.WebcastLikeMessage) {
+		t.Fatal("登记时未应用默认配置")
+	}
+
+	unregister()
+	body := `{"rooms":{"123":{"only":["WebcastGiftMessage","WebcastChatMessage"]}}}`
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("状态码 = %d: %s", rec.Code, rec.Body)
+	}
+	f := dl.MethodFilter()
+	if !slices.Equal(f.Only, []string{douyinLive.WebcastGiftMessage, douyinLive.WebcastChatMessage}) {
+		t.Fatalf("房间配置未下发: %+v", f)
+	}
+	if f.Allows(douyinLive.WebcastMemberMessage) || !f.Allows(douyinLive.WebcastControlMessage) {
+		t.Fatalf("过滤结果错误: %+v", f)
+	}
+	if other.MethodFilter().IsZero() {
+		t.Fatal("取消登记后不应再被修改，但应保留之前的配置")
+	}
+
+	rec = httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", bytes.NewBufferString("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("错误的配置应返回 400: %d", rec.Code)
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.json")
+	os.WriteFile(path, []byte(`{"default":{"disable":["WebcastLikeMessage"]}}`), 0o644)
+
+	dl, _ := douyinLive.NewDouyinLive("123", nil)
+	p := New(Config{}, nil)
+	p.Register(dl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := p.WatchFile(ctx, path, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if dl.MethodFilter().Allows(douyinLive.WebcastLikeMessage) {
+		t.Fatal("首次加载未生效")
+	}
+
+	os.WriteFile(path, []byte(`{"default":{"disable":["WebcastMemberMessage"]}}`), 0o644)
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for dl.MethodFilter().Allows(douyinLive.WebcastMemberMessage) {
+		if time.Now().After(deadline) {
+			t.Fatal("修改配置文件后未重新加载")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
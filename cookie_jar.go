@@ -0,0 +1,208 @@
+package douyinLive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// CookieStore 持久化 HTTP 客户端 cookie jar 的存储，见 WithCookieStore
+type CookieStore interface {
+	Load(ctx context.Context) ([]StoredCookie, error)
+	Save(ctx context.Context, cookies []StoredCookie) error
+}
+
+// StoredCookie 持久化的 cookie，Host 为下发该 cookie 的主机
+type StoredCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Host     string    `json:"host"`
+	Domain   string    `json:"domain,omitempty"` // 为空时只对 Host 生效
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"` // 零值为会话 cookie
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"http_only,omitempty"`
+}
+
+// expired 是否已过期
+func (c StoredCookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && now.After(c.Expires)
+}
+
+// WithCookieStore 持久化 HTTP 客户端的 cookie jar（ttwid、__ac_nonce、msToken、odin_tt 等），
+// 启动时加载，服务端每次下发 cookie 后保存，使进程重启后仍像回访的浏览器。
+// jar 中有未过期的 ttwid 时不再请求首页获取
+func WithCookieStore(store CookieStore) Option {
+	return func(dl *DouyinLive) {
+		dl.cookieStore = store
+	}
+}
+
+// persistentJar 记录写入的 cookie 并在变化时保存的 cookie jar
+type persistentJar struct {
+	http.CookieJar
+	store   CookieStore
+	onError func(error)
+
+	saveMu  sync.Mutex // 串行保存，使较旧的快照不会覆盖较新的
+	mu      sync.Mutex
+	cookies map[string]StoredCookie // name|host|domain|path → cookie
+}
+
+// initCookieJar 为 HTTP 客户端挂载持久化的 cookie jar 并加载已保存的 cookie
+func (dl *DouyinLive) initCookieJar() {
+	if dl.cookieStore == nil {
+		return
+	}
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	pj := &persistentJar{
+		CookieJar: jar,
+		store:     dl.cookieStore,
+		onError:   func(err error) { dl.log().Warn("保存cookie失败", "error", err) },
+		cookies:   make(map[string]StoredCookie),
+	}
+	saved, err := dl.cookieStore.Load(context.Background())
+	if err != nil {
+		dl.log().Warn("加载cookie失败", "error", err)
+	}
+	pj.restore(saved)
+	dl.client.SetCookieJar(pj)
+	dl.jar = pj
+}
+
+// restore 把保存的 cookie 放回 jar，跳过已过期的
+func (j *persistentJar) restore(saved []StoredCookie) {
+	now := time.Now()
+	for _, c := range saved {
+		if c.expired(now) || c.Host == "" {
+			continue
+		}
+		u := &url.URL{Scheme: "https", Host: c.Host, Path: c.Path}
+		j.CookieJar.SetCookies(u, []*http.Cookie{{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}})
+		j.cookies[cookieKey(c)] = c
+	}
+}
+
+// SetCookies 实现 http.CookieJar，写入后保存到 store
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(u, cookies)
+	now := time.Now()
+	j.saveMu.Lock()
+	defer j.saveMu.Unlock()
+	j.mu.Lock()
+	for _, c := range cookies {
+		stored := StoredCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Host:     u.Hostname(),
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		if c.MaxAge > 0 {
+			stored.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+		key := cookieKey(stored)
+		if c.MaxAge < 0 || stored.expired(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		j.cookies[key] = stored
+	}
+	snapshot := j.snapshot(now)
+	j.mu.Unlock()
+	if err := j.store.Save(context.Background(), snapshot); err != nil {
+		j.onError(err)
+	}
+}
+
+// snapshot 返回未过期的全部 cookie，调用方持有 mu
+func (j *persistentJar) snapshot(now time.Time) []StoredCookie {
+	cookies := make([]StoredCookie, 0, len(j.cookies))
+	for key, c := range j.cookies {
+		if c.expired(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		cookies = append(cookies, c)
+	}
+	return cookies
+}
+
+// lookup 返回 jar 中对 rawURL 生效的同名 cookie
+func (j *persistentJar) lookup(rawURL, name string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	for _, c := range j.CookieJar.Cookies(u) {
+		if c.Name == name && c.Value != "" {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
+
+// cookieKey 区分 cookie 的键
+func cookieKey(c StoredCookie) string {
+	return c.Name + "|" + c.Host + "|" + c.Domain + "|" + c.Path
+}
+
+// FileCookieStore 把 cookie 保存为本地 JSON 文件
+type FileCookieStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileCookieStore 创建基于文件的 cookie 存储
+func NewFileCookieStore(path string) *FileCookieStore {
+	return &FileCookieStore{Path: path}
+}
+
+// Load 实现 CookieStore，文件不存在时返回空
+func (s *FileCookieStore) Load(context.Context) ([]StoredCookie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cookies []StoredCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// Save 实现 CookieStore
+func (s *FileCookieStore) Save(_ context.Context, cookies []StoredCookie) error {
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeFileAtomic(s.Path, data)
+}
@@ -0,0 +1,104 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCookieStore(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("odin_tt"); err == nil {
+			got = append(got, c.Value)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "ttwid", Value: "from-jar", MaxAge: 3600})
+		http.SetCookie(w, &http.Cookie{Name: "odin_tt", Value: "returning", MaxAge: 3600})
+		http.SetCookie(w, &http.Cookie{Name: "stale", Value: "x", MaxAge: -1})
+	}))
+	defer srv.Close()
+	old := ttwidURL
+	ttwidURL = srv.URL
+	defer func() { ttwidURL = old }()
+
+	store := NewFileCookieStore(filepath.Join(t.TempDir(), "cookies.json"))
+	first, _ := NewDouyinLive("1", nil, WithCookieStore(store))
+	if err := first.fetchTTWID(context.Background()); err != nil || first.ttwid != "from-jar" {
+		t.Fatalf("ttwid = %q, %v", first.ttwid, err)
+	}
+	saved, err := store.Load(context.Background())
+	if err != nil || len(saved) != 2 {
+		t.Fatalf("保存的 cookie = %+v, %v", saved, err)
+	}
+
+	// 重启后从文件恢复：ttwid 不再请求首页，其他 cookie 随请求发出
+	second, _ := NewDouyinLive("1", nil, WithCookieStore(store))
+	if err := second.fetchTTWID(context.Background()); err != nil || second.ttwid != "from-jar" {
+		t.Fatalf("ttwid = %q, %v", second.ttwid, err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("恢复的 ttwid 不应再请求首页: %v", got)
+	}
+	if _, err := second.client.R().Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != "returning" {
+		t.Fatalf("恢复的 cookie 未随请求发出: %v", got)
+	}
+}
+
+// blockingCookieStore 第一次保存阻塞到 release 关闭，记录最后一次保存的内容
+type blockingCookieStore struct {
+	entered chan struct{}
+	release chan struct{}
+	mu      sync.Mutex
+	calls   int
+	last    []StoredCookie
+}
+
+func (s *blockingCookieStore) Load(context.Context) ([]StoredCookie, error) { return nil, nil }
+
+func (s *blockingCookieStore) Save(_ context.Context, cookies []StoredCookie) error {
+	s.mu.Lock()
+	s.calls++
+	first := s.calls == 1
+	s.mu.Unlock()
+	if first {
+		close(s.entered)
+		<-s.release
+	}
+	s.mu.Lock()
+	s.last = cookies
+	s.mu.Unlock()
+	return nil
+}
+
+func TestCookieJarSaveOrder(t *testing.T) {
+	store := &blockingCookieStore{entered: make(chan struct{}), release: make(chan struct{})}
+	dl, _ := NewDouyinLive("1", nil, WithCookieStore(store))
+	u, _ := url.Parse("https://www.douyin.com/")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		dl.jar.SetCookies(u, []*http.Cookie{{Name: "odin_tt", Value: "old", MaxAge: 3600}})
+	}()
+	<-store.entered
+	go func() {
+		defer wg.Done()
+		dl.jar.SetCookies(u, []*http.Cookie{{Name: "odin_tt", Value: "new", MaxAge: 3600}})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(store.release)
+	wg.Wait()
+
+	if len(store.last) != 1 || store.last[0].Value != "new" {
+		t.Fatalf("最后保存的 cookie = %+v", store.last)
+	}
+}
@@ -0,0 +1,197 @@
+// Package daemon 以系统服务/守护进程方式运行采集器：
+// 支持安装为 Windows 服务、systemd、launchd 等并开机自启，运行中 panic 或出错时自动重启，日志按大小/时间轮转
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kardianos/service"
+
+	"github.com/tiga210/douyinLive/sink"
+)
+
+const (
+	defaultRestartDelay    = time.Second
+	defaultMaxRestartDelay = time.Minute
+	// stopTimeout 服务管理器要求 Stop 尽快返回
+	stopTimeout = 10 * time.Second
+)
+
+// RunFunc 采集器主体，ctx 结束时应尽快返回
+type RunFunc func(ctx context.Context, log *slog.Logger) error
+
+// Config 服务配置
+type Config struct {
+	Name        string   // 服务名，不建议包含空格
+	DisplayName string   // 显示名称
+	Description string   // 服务描述
+	Arguments   []string // 服务管理器启动程序时传入的参数
+
+	LogFile string             // 日志文件，为空时输出到标准错误
+	Rotate  sink.RotateOptions // 日志轮转配置
+
+	RestartDelay    time.Duration // 首次重启前的等待时间，默认 1 秒，之后逐次翻倍
+	MaxRestartDelay time.Duration // 重启等待时间的上限，默认 1 分钟
+}
+
+// Service 可安装、启停的系统服务
+type Service struct {
+	cfg    Config
+	run    RunFunc
+	svc    service.Service
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+// New 创建服务
+func New(cfg Config, run RunFunc) (*Service, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("服务名不能为空")
+	}
+	s := &Service{cfg: cfg, run: run}
+	svc, err := service.New(s, &service.Config{
+		Name:        cfg.Name,
+		DisplayName: cfg.DisplayName,
+		Description: cfg.Description,
+		Arguments:   cfg.Arguments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建系统服务失败: %w", err)
+	}
+	s.svc = svc
+	return s, nil
+}
+
+// Actions 支持的控制命令
+func Actions() []string {
+	return service.ControlAction[:]
+}
+
+// Control 执行 install/uninstall/start/stop/restart
+func (s *Service) Control(action string) error {
+	return service.Control(s.svc, action)
+}
+
+// Status 返回服务当前状态
+func (s *Service) Status() string {
+	status, err := s.svc.Status()
+	if errors.Is(err, service.ErrNotInstalled) {
+		return "未安装"
+	}
+	if err != nil {
+		return fmt.Sprintf("未知 (%v)", err)
+	}
+	switch status {
+	case service.StatusRunning:
+		return "运行中"
+	case service.StatusStopped:
+		return "已停止"
+	default:
+		return "未知"
+	}
+}
+
+// Run 运行服务，由服务管理器启动时受其控制，交互运行时阻塞到收到中断信号
+func (s *Service) Run() error {
+	return s.svc.Run()
+}
+
+// Start 实现 service.Interface，在后台启动采集器
+func (s *Service) Start(service.Service) error {
+	out, closeOut, err := s.openLog()
+	if err != nil {
+		return err
+	}
+	log := slog.New(slog.NewJSONHandler(out, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	done := s.done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		defer closeOut()
+		err := Supervise(ctx, s.run, log, s.cfg.RestartDelay, s.cfg.MaxRestartDelay)
+		log.Info("采集器已退出", "error", err)
+	}()
+	return nil
+}
+
+// Stop 实现 service.Interface，通知采集器退出并等待其结束
+func (s *Service) Stop(service.Service) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(stopTimeout):
+		return errors.New("等待采集器退出超时")
+	}
+}
+
+// openLog 打开日志输出
+func (s *Service) openLog() (io.Writer, func(), error) {
+	if s.cfg.LogFile == "" {
+		return os.Stderr, func() {}, nil
+	}
+	f, err := sink.OpenRotatingFile(s.cfg.LogFile, s.cfg.Rotate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// Supervise 运行 run，panic 或返回错误时按指数退避重启，直到 ctx 结束。
+// run 正常返回 nil 视为主动退出，不再重启；持续运行超过 maxDelay 后退避时间重置
+func Supervise(ctx context.Context, run RunFunc, log *slog.Logger, delay, maxDelay time.Duration) error {
+	if delay <= 0 {
+		delay = defaultRestartDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRestartDelay
+	}
+	wait := delay
+	for restarts := 0; ; restarts++ {
+		start := time.Now()
+		err := runSafe(ctx, run, log)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if time.Since(start) > maxDelay {
+			wait = delay
+		}
+		log.Error("采集器异常退出，准备重启", "error", err, "restarts", restarts, "wait", wait)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxDelay)
+	}
+}
+
+// runSafe 运行 run 并将 panic 转为错误
+func runSafe(ctx context.Context, run RunFunc, log *slog.Logger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx, log)
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSuperviseRestartsAfterPanic(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runs := 0
+	err := Supervise(context.Background(), func(ctx context.Context, _ *slog.Logger) error {
+		runs++
+		switch runs {
+		case 1:
+			panic("连接崩溃")
+		case 2:
+			return errors.New("连接断开")
+		}
+		return nil
+	}, log, time.Millisecond, 10*time.Millisecond)
+	if err != nil || runs != 3 {
+		t.Fatalf("应在 panic 与出错后重启，直到正常退出: runs=%d err=%v", runs, err)
+	}
+}
+
+func TestSuperviseStopsOnCancel(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Supervise(ctx, func(ctx context.Context, _ *slog.Logger) error {
+			return errors.New("一直失败")
+		}, log, time.Hour, time.Hour)
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("取消时应返回最后一次错误")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("取消后未退出")
+	}
+}
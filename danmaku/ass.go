@@ -0,0 +1,148 @@
+package danmaku
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ASSOptions ASS 字幕配置
+type ASSOptions struct {
+	Width        int           // 画面宽度，默认 1920
+	Height       int           // 画面高度，默认 1080
+	FontName     string        // 字体，默认 Microsoft YaHei
+	FontSize     int           // 字号，默认 48
+	Duration     time.Duration // 单条弹幕横穿画面的时长，默认 8 秒
+	Area         float64       // 弹幕占用画面高度的比例，默认 0.8
+	Alpha        uint8         // 透明度，0 不透明，255 全透明，默认 0
+	ShowNickname bool          // 在弹幕前显示昵称
+}
+
+// defaults 填充默认值
+func (o *ASSOptions) defaults() {
+	if o.Width <= 0 {
+		o.Width = 1920
+	}
+	if o.Height <= 0 {
+		o.Height = 1080
+	}
+	if o.FontName == "" {
+		o.FontName = "Microsoft YaHei"
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 48
+	}
+	if o.Duration <= 0 {
+		o.Duration = 8 * time.Second
+	}
+	if o.Area <= 0 || o.Area > 1 {
+		o.Area = 0.8
+	}
+}
+
+// lane 一条弹幕轨道上最后一条弹幕的时间与宽度
+type lane struct {
+	start time.Duration
+	width float64
+	used  bool
+}
+
+// WriteASS 以从右向左滚动的方式输出 ASS 字幕，自动分配轨道避免弹幕重叠，轨道占满时复用最早空出的轨道
+func WriteASS(w io.Writer, comments []Comment, opts ASSOptions) error {
+	opts.defaults()
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `[Script Info]
+ScriptType: v4.00+
+PlayResX: %d
+PlayResY: %d
+WrapStyle: 2
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Danmaku,%s,%d,&H%02XFFFFFF,&H%02XFFFFFF,&H%02X000000,&H%02X000000,0,0,0,0,100,100,0,0,1,1,0,7,0,0,0,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`, opts.Width, opts.Height, opts.FontName, opts.FontSize, opts.Alpha, opts.Alpha, opts.Alpha, opts.Alpha)
+
+	lineHeight := opts.FontSize + opts.FontSize/4
+	lanes := make([]lane, max(int(float64(opts.Height)*opts.Area)/lineHeight, 1))
+	for _, c := range comments {
+		text := c.Content
+		if opts.ShowNickname && c.Nickname != "" {
+			text = c.Nickname + ": " + text
+		}
+		width := textWidth(text, opts.FontSize)
+		i := pickLane(lanes, c.Offset, width, opts)
+		lanes[i] = lane{start: c.Offset, width: width, used: true}
+
+		y := i * lineHeight
+		color := ""
+		if c.Color != 0 && c.Color != 0xffffff {
+			// ASS 颜色为 BGR 顺序
+			color = fmt.Sprintf(`\c&H%02X%02X%02X&`, c.Color&0xff, c.Color>>8&0xff, c.Color>>16&0xff)
+		}
+		fmt.Fprintf(bw, "Dialogue: 0,%s,%s,Danmaku,,0,0,0,,{\\move(%d,%d,%d,%d)%s}%s\n",
+			assTime(c.Offset), assTime(c.Offset+opts.Duration),
+			opts.Width, y, -int(width), y, color, escapeASS(text))
+	}
+	return bw.Flush()
+}
+
+// pickLane 选择不会与前一条弹幕重叠的轨道：前一条已完全进入画面，且新弹幕到达左边缘前不会追上它
+func pickLane(lanes []lane, start time.Duration, width float64, opts ASSOptions) int {
+	screen := float64(opts.Width)
+	d := opts.Duration.Seconds()
+	best, bestFree := 0, time.Duration(1<<62)
+	for i, l := range lanes {
+		if !l.used {
+			return i
+		}
+		speed := (screen + l.width) / d
+		entered := l.start + seconds(l.width/speed)
+		caught := start+seconds(d*screen/(screen+width)) < l.start+opts.Duration
+		if start >= entered && !caught {
+			return i
+		}
+		if free := max(entered, l.start+opts.Duration-seconds(d*screen/(screen+width))); free < bestFree {
+			best, bestFree = i, free
+		}
+	}
+	return best
+}
+
+// seconds 将秒数转为 time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// textWidth 估算文字宽度，全角字符按一个字号计算，半角字符按半个字号计算
+func textWidth(text string, fontSize int) float64 {
+	var width float64
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			width += float64(fontSize) / 2
+		} else {
+			width += float64(fontSize)
+		}
+	}
+	return width
+}
+
+// assTime 格式化为 ASS 的时间 h:mm:ss.cc
+func assTime(d time.Duration) string {
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// assReplacer 会被解释为样式代码的字符换成全角，换行换成空格
+var assReplacer = strings.NewReplacer("{", "｛", "}", "｝", `\`, "＼", "\r", " ", "\n", " ")
+
+// escapeASS 转义弹幕文本
+func escapeASS(text string) string {
+	return assReplacer.Replace(text)
+}
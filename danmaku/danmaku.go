@@ -0,0 +1,98 @@
+// Package danmaku 将录制的弹幕导出为 ASS 字幕或 B 站弹幕 XML，时间轴相对直播开始时间，
+// 用于回放录像时叠加原始弹幕
+package danmaku
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// Comment 一条带时间轴的弹幕
+type Comment struct {
+	Offset   time.Duration // 相对直播开始的时间
+	Time     time.Time     // 接收时间
+	UserID   string
+	Nickname string
+	Content  string
+	Color    uint32 // RGB 颜色，0 时使用白色
+}
+
+// isChat 是否为弹幕类消息
+func isChat(method string) bool {
+	return method == douyinLive.WebcastChatMessage || method == douyinLive.WebcastEmojiChatMessage
+}
+
+// Collector 从直播实例或回放中收集弹幕
+type Collector struct {
+	mu       sync.Mutex
+	start    time.Time
+	comments []Comment
+}
+
+// NewCollector 创建收集器，start 为直播（录像）开始时间，为零值时以第一条弹幕的时间为准
+func NewCollector(start time.Time) *Collector {
+	return &Collector{start: start}
+}
+
+// Watch 收集直播实例的弹幕，返回的订阅 ID 可用于 Unsubscribe。配合 ReplayJSONL 可导出历史录制
+func (c *Collector) Watch(dl *douyinLive.DouyinLive) string {
+	return dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if !isChat(event.Method) {
+			return
+		}
+		userID, _ := event.Fields()[douyinLive.FieldUserID].(string)
+		c.Add(Comment{Time: event.Time, UserID: userID, Nickname: event.Nickname(), Content: event.Content()})
+	})
+}
+
+// Add 添加一条弹幕，Offset 由开始时间计算
+func (c *Collector) Add(comment Comment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.start.IsZero() {
+		c.start = comment.Time
+	}
+	comment.Offset = max(comment.Time.Sub(c.start), 0)
+	c.comments = append(c.comments, comment)
+}
+
+// Comments 返回按时间排序的弹幕
+func (c *Collector) Comments() []Comment {
+	c.mu.Lock()
+	out := append([]Comment(nil), c.comments...)
+	c.mu.Unlock()
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Offset < out[j].Offset })
+	return out
+}
+
+// record JSON Lines 录制中用到的字段，见 sink.Recorder
+type record struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	UserID   string    `json:"user_id"`
+	Nickname string    `json:"nickname"`
+	Content  string    `json:"content"`
+}
+
+// ReadJSONL 读取 sink.Recorder 录制的 JSON Lines，只保留弹幕，start 含义同 NewCollector
+func ReadJSONL(r io.Reader, start time.Time) ([]Comment, error) {
+	c := NewCollector(start)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if isChat(rec.Method) && rec.Content != "" {
+			c.Add(Comment{Time: rec.Time, UserID: rec.UserID, Nickname: rec.Nickname, Content: rec.Content})
+		}
+	}
+	return c.Comments(), scanner.Err()
+}
@@ -0,0 +1,64 @@
+package danmaku
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recording = `{"time":"2024-06-01T20:00:00+08:00","method":"WebcastChatMessage","user_id":"1","nickname":"观众","content":"开始了"}
+{"time":"2024-06-01T20:00:01.5+08:00","method":"WebcastGiftMessage","nickname":"观众"}
+{"time":"2024-06-01T20:01:02.25+08:00","method":"WebcastChatMessage","user_id":"2","nickname":"路人","content":"a<b & {\\pos}"}
+`
+
+func TestReadJSONLAndWrite(t *testing.T) {
+	comments, err := ReadJSONL(strings.NewReader(recording), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[1].Offset != 62250*time.Millisecond {
+		t.Fatalf("弹幕 = %+v", comments)
+	}
+
+	var ass strings.Builder
+	if err := WriteASS(&ass, comments, ASSOptions{ShowNickname: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ass.String(), "Dialogue: 0,0:01:02.25,0:01:10.25,Danmaku,,0,0,0,,{\\move(1920,0,") ||
+		!strings.Contains(ass.String(), "路人: a<b & ｛＼pos｝") {
+		t.Fatalf("ASS = %s", ass.String())
+	}
+
+	var out strings.Builder
+	if err := WriteXML(&out, comments, XMLOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		D []struct {
+			P    string `xml:"p,attr"`
+			Text string `xml:",chardata"`
+		} `xml:"d"`
+	}
+	if err := xml.Unmarshal([]byte(out.String()), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.D) != 2 || doc.D[1].Text != `a<b & {\pos}` || !strings.HasPrefix(doc.D[1].P, "62.250,1,25,16777215,") {
+		t.Fatalf("XML = %s", out.String())
+	}
+}
+
+func TestPickLaneAvoidsOverlap(t *testing.T) {
+	opts := ASSOptions{}
+	opts.defaults()
+	lanes := make([]lane, 3)
+	lanes[0] = lane{start: 0, width: 480, used: true}
+	// 前一条尚未完全进入画面，应换到下一条轨道
+	if i := pickLane(lanes, time.Second, 480, opts); i != 1 {
+		t.Fatalf("pickLane = %d, want 1", i)
+	}
+	// 足够久之后可以复用第一条轨道
+	if i := pickLane(lanes, 5*time.Second, 480, opts); i != 0 {
+		t.Fatalf("pickLane = %d, want 0", i)
+	}
+}
@@ -0,0 +1,50 @@
+package danmaku
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// B 站弹幕的显示模式
+const (
+	ModeScroll = 1 // 滚动
+	ModeBottom = 4 // 底部
+	ModeTop    = 5 // 顶部
+)
+
+// XMLOptions B 站弹幕 XML 配置
+type XMLOptions struct {
+	Mode     int // 显示模式，默认 ModeScroll
+	FontSize int // 字号，默认 25
+}
+
+// WriteXML 输出 B 站格式的弹幕 XML，可被 DanmakuFactory、弹弹play 等工具和播放器加载
+func WriteXML(w io.Writer, comments []Comment, opts XMLOptions) error {
+	if opts.Mode <= 0 {
+		opts.Mode = ModeScroll
+	}
+	if opts.FontSize <= 0 {
+		opts.FontSize = 25
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, xml.Header)
+	fmt.Fprint(bw, "<i>\n<chatserver>chat.bilibili.com</chatserver>\n<chatid>0</chatid>\n<mission>0</mission>\n<maxlimit>", len(comments), "</maxlimit>\n<source>douyinLive</source>\n")
+	for i, c := range comments {
+		color := c.Color
+		if color == 0 {
+			color = 0xffffff
+		}
+		// p 属性：出现时间(秒),模式,字号,颜色,发送时间戳,弹幕池,用户哈希,弹幕 ID
+		fmt.Fprintf(bw, `<d p="%.3f,%d,%d,%d,%d,0,%08x,%d">`,
+			c.Offset.Seconds(), opts.Mode, opts.FontSize, color, c.Time.Unix(), crc32.ChecksumIEEE([]byte(c.UserID)), i+1)
+		if err := xml.EscapeText(bw, []byte(c.Content)); err != nil {
+			return err
+		}
+		fmt.Fprint(bw, "</d>\n")
+	}
+	fmt.Fprint(bw, "</i>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,109 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated"
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// OnDeadLetter 订阅死信：消息体解码失败，或全部处理器都 panic 时回调，
+// reason 包装了 ErrDecodeFailed 或 ErrHandlerPanic，可用 errors.Is 区分。返回的 ID 可用于 Unsubscribe
+func (dl *DouyinLive) OnDeadLetter(handler func(event *LiveEvent, reason error)) string {
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:                id,
+		DeadLetterHandler: handler,
+	})
+	return id
+}
+
+// WithDeadLetterFile 将死信以 JSON Lines 追加写入文件，原始消息体以 base64 保存在 payload 字段，便于事后重新解码
+func WithDeadLetterFile(path string) Option {
+	return func(dl *DouyinLive) {
+		dl.deadLetterFile = path
+	}
+}
+
+// callHandler 调用处理器，panic 时记录堆栈并返回包装了 ErrHandlerPanic 的错误
+func (dl *DouyinLive) callHandler(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+			dl.log().Error("事件处理器 panic", "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	fn()
+	return nil
+}
+
+// deadLetterEnabled 是否有死信订阅或死信文件，没有时跳过额外的解码检查
+func (dl *DouyinLive) deadLetterEnabled(handlers []EventHandler) bool {
+	if dl.deadLetterFile != "" {
+		return true
+	}
+	for _, handler := range handlers {
+		if handler.DeadLetterHandler != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDecode 已知消息类型解码失败时返回包装了 ErrDecodeFailed 的错误，未知消息类型不算死信
+func checkDecode(event *LiveEvent) error {
+	_, err := event.Decode()
+	if err == nil {
+		return nil
+	}
+	if _, unknown := generated.GetMessageInstance(event.Method); unknown != nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrDecodeFailed, err)
+}
+
+// deadLetter 通知死信订阅者并写入死信文件
+func (dl *DouyinLive) deadLetter(handlers []EventHandler, event *LiveEvent, reason error) {
+	dl.log().Warn("事件进入死信", "method", event.Method, "msg_id", event.MsgID, "reason", reason)
+	for _, handler := range handlers {
+		if handler.DeadLetterHandler != nil {
+			dl.callHandler(func() { handler.DeadLetterHandler(event, reason) })
+		}
+	}
+	if dl.deadLetterFile == "" {
+		return
+	}
+	if err := dl.writeDeadLetter(event, reason); err != nil {
+		dl.log().Warn("写入死信文件失败", "path", dl.deadLetterFile, "error", err)
+	}
+}
+
+// writeDeadLetter 追加一行死信记录，死信很少，每次写入时打开文件
+func (dl *DouyinLive) writeDeadLetter(event *LiveEvent, reason error) error {
+	record := event.Fields()
+	record["reason"] = reason.Error()
+	record["dead_at"] = time.Now()
+	if payload := event.Payload(); payload != nil {
+		record["payload"] = payload
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	dl.deadLetterMu.Lock()
+	defer dl.deadLetterMu.Unlock()
+	f, err := os.OpenFile(dl.deadLetterFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
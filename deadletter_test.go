@@ -0,0 +1,52 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDeadLetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	dl, _ := NewDouyinLive("1", nil, WithDeadLetterFile(path))
+
+	var reasons []error
+	dl.OnDeadLetter(func(event *LiveEvent, reason error) {
+		reasons = append(reasons, reason)
+	})
+	dl.SubscribeEvent(func(event *LiveEvent) {
+		if event.MsgID == 3 {
+			panic("boom")
+		}
+	})
+
+	dl.deliver(&new_douyin.Webcast_Im_Message{Method: WebcastGiftMessage, MsgId: 1, Payload: []byte{0xff}})
+	dl.deliver(&new_douyin.Webcast_Im_Message{Method: "WebcastUnknownMessage", MsgId: 2, Payload: []byte{0xff}})
+	dl.deliver(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 3})
+	dl.deliver(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 4})
+
+	if len(reasons) != 2 || !errors.Is(reasons[0], ErrDecodeFailed) || !errors.Is(reasons[1], ErrHandlerPanic) {
+		t.Fatalf("死信原因 = %v", reasons)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("死信文件行数 = %d", len(lines))
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record[FieldMsgID] != "1" || record["payload"] != "/w==" || !strings.Contains(record["reason"].(string), ErrDecodeFailed.Error()) {
+		t.Fatalf("死信记录 = %v", record)
+	}
+}
@@ -0,0 +1,99 @@
+package douyinLive
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultControlTimeout ping、close 等控制帧的默认写入超时
+const defaultControlTimeout = 3 * time.Second
+
+// WithReadDeadline 超过 d 未从连接读到任何数据（包括 pong）即视为连接已断开并重连，
+// 同时每 d/2 发送一次 ping，使空闲但正常的连接不会超时。<=0 时不限制（默认），
+// 半开的连接可能长时间阻塞在读取中
+func WithReadDeadline(d time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.readDeadline = d
+	}
+}
+
+// WithWriteDeadline 设置单次写入（如 ack）的超时，<=0 时不限制
+func WithWriteDeadline(d time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.writeDeadline = d
+	}
+}
+
+// WithControlTimeout 设置 ping、close 等控制帧的写入超时，<=0 时为 3 秒
+func WithControlTimeout(d time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.controlTimeout = d
+	}
+}
+
+// controlDeadline 返回控制帧的写入截止时间
+func (dl *DouyinLive) controlDeadline() time.Time {
+	d := dl.controlTimeout
+	if d <= 0 {
+		d = defaultControlTimeout
+	}
+	return time.Now().Add(d)
+}
+
+// setConn 使用新建立的连接，设置读超时、pong 处理并启动 ping
+func (dl *DouyinLive) setConn(conn *websocket.Conn) {
+	dl.mu.Lock()
+	dl.conn = conn
+	dl.mu.Unlock()
+	if dl.readDeadline <= 0 {
+		return
+	}
+	dl.extendReadDeadline(conn)
+	conn.SetPongHandler(func(string) error {
+		dl.extendReadDeadline(conn)
+		return nil
+	})
+	go dl.keepAlive(conn)
+}
+
+// currentConn 返回当前连接，连接会在重连与关闭时被其他 goroutine 替换
+func (dl *DouyinLive) currentConn() *websocket.Conn {
+	dl.mu.RLock()
+	defer dl.mu.RUnlock()
+	return dl.conn
+}
+
+// extendReadDeadline 收到数据后顺延读超时
+func (dl *DouyinLive) extendReadDeadline(conn *websocket.Conn) {
+	if dl.readDeadline > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(dl.readDeadline))
+	}
+}
+
+// keepAlive 定期发送 ping，连接关闭后写入失败即退出
+func (dl *DouyinLive) keepAlive(conn *websocket.Conn) {
+	ticker := time.NewTicker(dl.readDeadline / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := conn.WriteControl(websocket.PingMessage, nil, dl.controlDeadline()); err != nil {
+			return
+		}
+	}
+}
+
+// writeMessage 在写超时内向连接写入一条消息
+func (dl *DouyinLive) writeMessage(conn *websocket.Conn, messageType int, data []byte) error {
+	if dl.writeDeadline > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(dl.writeDeadline))
+	}
+	return conn.WriteMessage(messageType, data)
+}
+
+// isTimeout 判断读取错误是否由读超时引起，websocket 库会把超时错误包装为自己的 net.Error
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
@@ -0,0 +1,72 @@
+package douyinLive
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// silentServer 接受连接后不发送任何数据，reply 为 true 时读取连接以自动回复 pong
+func silentServer(t *testing.T, reply bool) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if !reply {
+			time.Sleep(time.Second)
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestReadDeadline(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		reply   bool
+		timeout bool
+	}{
+		{"dead", false, true},
+		{"pong", true, false},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			conn, _, err := websocket.DefaultDialer.Dial(silentServer(t, c.reply), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			dl := NewDouyinLive2("1", "2", "test", "", nil, WithReadDeadline(100*time.Millisecond))
+			dl.setConn(conn)
+
+			errs := make(chan error, 1)
+			go func() {
+				_, _, _, err := dl.readMessage()
+				errs <- err
+			}()
+			select {
+			case err := <-errs:
+				if !c.timeout || !isTimeout(err) {
+					t.Fatalf("读取返回 %v", err)
+				}
+			case <-time.After(400 * time.Millisecond):
+				if c.timeout {
+					t.Fatal("无响应的连接应在读超时后返回")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package douyinLive
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultDedupSize WithDedup 默认记住的消息 ID 数量
+const defaultDedupSize = 10000
+
+// WithDedup 按 msgId 去重：重连或切换到 HTTP 轮询后服务端会重发最近的消息，
+// 最近 size 个出现过的 msgId 再次出现时直接丢弃，不计入统计也不分发，丢弃数计入 Stats().Duplicates。
+// size<=0 时为 10000。msgId 为 0 的消息不去重
+func WithDedup(size int) Option {
+	return func(dl *DouyinLive) {
+		if size <= 0 {
+			size = defaultDedupSize
+		}
+		dl.dedup = newMsgDedup(size)
+	}
+}
+
+// msgDedup 按最近最少使用淘汰的 msgId 集合，跨重连保留
+type msgDedup struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List // 最近出现的在前
+	seen  map[uint64]*list.Element
+}
+
+// newMsgDedup 创建容量为 size 的去重集合
+func newMsgDedup(size int) *msgDedup {
+	return &msgDedup{size: size, order: list.New(), seen: make(map[uint64]*list.Element, size)}
+}
+
+// duplicate 记录 id，此前出现过时返回 true
+func (d *msgDedup) duplicate(id uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e, ok := d.seen[id]; ok {
+		d.order.MoveToFront(e)
+		return true
+	}
+	d.seen[id] = d.order.PushFront(id)
+	if d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.seen, oldest.Value.(uint64))
+	}
+	return false
+}
+
+// isDuplicate 判断消息是否重复，重复的消息计入 Stats().Duplicates
+func (dl *DouyinLive) isDuplicate(msgID uint64) bool {
+	if dl.dedup == nil || msgID == 0 || !dl.dedup.duplicate(msgID) {
+		return false
+	}
+	dl.duplicates.Add(1)
+	return true
+}
@@ -0,0 +1,40 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDedup(t *testing.T) {
+	d := newMsgDedup(2)
+	if d.duplicate(1) || d.duplicate(2) || !d.duplicate(1) {
+		t.Fatal("重复的 msgId 未被识别")
+	}
+	// 1 刚被访问过，插入 3 时淘汰 2
+	if d.duplicate(3) || !d.duplicate(1) || d.duplicate(2) {
+		t.Fatal("应按最近最少使用淘汰")
+	}
+}
+
+func TestWithDedup(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithDedup(0))
+	var got int
+	dl.SubscribeEvent(func(*LiveEvent) { got++ })
+	msg := func(id uint64) *new_douyin.Webcast_Im_Message {
+		m := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+		m.MsgId = id
+		return m
+	}
+	ctx := context.Background()
+	for _, id := range []uint64{1, 2, 1, 0, 0} {
+		dl.handleSingleMessage(ctx, msg(id))
+	}
+	if got != 4 {
+		t.Fatalf("收到 %d 条, want 4", got)
+	}
+	if s := dl.Stats(); s.Duplicates != 1 {
+		t.Fatalf("Duplicates = %d, want 1", s.Duplicates)
+	}
+}
@@ -0,0 +1,205 @@
+// Package demo 本地演示数据生成器：不连接抖音，周期性产生仿真的弹幕、礼物、进房、点赞与在线人数消息，
+// 返回的实例与真实实例接口一致，供前端与下游联调使用
+package demo
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	defaultRoomID   = "7000000000000000000"
+	defaultLiveName = "演示直播间"
+	defaultRate     = 5
+	defaultViewers  = 1200
+)
+
+// Options 生成器配置
+type Options struct {
+	RoomID   string  // 默认 7000000000000000000
+	LiveName string  // 默认 "演示直播间"
+	Rate     float64 // 平均每秒的消息数，默认 5
+	Viewers  uint64  // 初始在线人数，默认 1200
+	Seed     int64   // 随机种子，0 时使用当前时间
+}
+
+// gift 演示用礼物
+type gift struct {
+	id      uint64
+	name    string
+	diamond int32
+}
+
+var (
+	nicknames = []string{"小明", "阿花", "夜猫子", "路过的", "老铁666", "Momo", "追光者", "一只橘猫", "今天也要加油", "大梦想家"}
+	contents  = []string{"主播好", "666", "来了来了", "这个多少钱", "哈哈哈哈", "已关注", "点赞支持", "上链接", "好听", "晚上好"}
+	gifts     = []gift{
+		{id: 463, name: "玫瑰", diamond: 1},
+		{id: 685, name: "粉丝团灯牌", diamond: 1},
+		{id: 3389, name: "小心心", diamond: 1},
+		{id: 4213, name: "抖音", diamond: 1},
+		{id: 3243, name: "加油鸭", diamond: 15},
+		{id: 4209, name: "人气票", diamond: 1},
+		{id: 3800, name: "为你闪耀", diamond: 9},
+		{id: 3686, name: "嘉年华", diamond: 30000},
+	}
+)
+
+// Generator 演示消息生成器，实现 douyinLive.MessageSource，不能并发调用
+type Generator struct {
+	opts    Options
+	rnd     *rand.Rand
+	msgID   uint64
+	groupID uint64
+	likes   uint64
+	viewers uint64
+	total   uint64
+}
+
+// NewGenerator 创建演示消息生成器
+func NewGenerator(opts Options) *Generator {
+	if opts.RoomID == "" {
+		opts.RoomID = defaultRoomID
+	}
+	if opts.LiveName == "" {
+		opts.LiveName = defaultLiveName
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = defaultRate
+	}
+	if opts.Viewers == 0 {
+		opts.Viewers = defaultViewers
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g := &Generator{opts: opts, rnd: rand.New(rand.NewSource(seed)), viewers: opts.Viewers, total: opts.Viewers * 3}
+	g.msgID = uint64(seed) & 0xffffffff
+	return g
+}
+
+// NewFakeLive 创建使用默认配置的演示实例，调用 Start 后开始产生消息，Close 后停止
+func NewFakeLive(opts ...douyinLive.Option) *douyinLive.DouyinLive {
+	return NewFakeLiveWith(Options{}, opts...)
+}
+
+// NewFakeLiveWith 按生成器配置创建演示实例
+func NewFakeLiveWith(o Options, opts ...douyinLive.Option) *douyinLive.DouyinLive {
+	g := NewGenerator(o)
+	opts = append([]douyinLive.Option{douyinLive.WithMessageSource(g)}, opts...)
+	return douyinLive.NewDouyinLive2(g.opts.RoomID, "demo", g.opts.LiveName, "", nil, opts...)
+}
+
+// Run 实现 douyinLive.MessageSource，按泊松过程的间隔产生消息
+func (g *Generator) Run(ctx context.Context, emit func(*new_douyin.Webcast_Im_Message)) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if msg := g.Next(); msg != nil {
+			emit(msg)
+		}
+		timer.Reset(time.Duration(g.rnd.ExpFloat64() / g.opts.Rate * float64(time.Second)))
+	}
+}
+
+// Next 生成下一条消息，不等待
+func (g *Generator) Next() *new_douyin.Webcast_Im_Message {
+	user := g.user()
+	var (
+		method string
+		body   proto.Message
+	)
+	switch p := g.rnd.Float64(); {
+	case p < 0.45:
+		method = douyinLive.WebcastChatMessage
+		body = &new_douyin.Webcast_Im_ChatMessage{User: user, Content: contents[g.rnd.Intn(len(contents))]}
+	case p < 0.60:
+		method, body = douyinLive.WebcastGiftMessage, g.gift(user)
+	case p < 0.80:
+		g.viewers++
+		g.total++
+		method = douyinLive.WebcastMemberMessage
+		body = &new_douyin.Webcast_Im_MemberMessage{User: user, MemberCount: g.viewers}
+	case p < 0.95:
+		count := uint64(g.rnd.Intn(15) + 1)
+		g.likes += count
+		method = douyinLive.WebcastLikeMessage
+		body = &new_douyin.Webcast_Im_LikeMessage{User: user, Count: count, Total: g.likes}
+	default:
+		// 在线人数随机波动
+		delta := g.rnd.Intn(41) - 20
+		if int64(g.viewers)+int64(delta) > 0 {
+			g.viewers = uint64(int64(g.viewers) + int64(delta))
+		}
+		method = douyinLive.WebcastRoomUserSeqMessage
+		body = &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: g.viewers, TotalUser: g.total}
+	}
+	return g.wrap(method, body)
+}
+
+// wrap 补全 Common 并序列化为原始消息
+func (g *Generator) wrap(method string, body proto.Message) *new_douyin.Webcast_Im_Message {
+	g.msgID++
+	id := g.msgID
+	roomID, _ := strconv.ParseUint(g.opts.RoomID, 10, 64)
+	common := &new_douyin.Webcast_Im_Common{Method: method, MsgId: id, RoomId: roomID, CreateTime: uint64(time.Now().UnixMilli())}
+	switch m := body.(type) {
+	case *new_douyin.Webcast_Im_ChatMessage:
+		m.Common = common
+	case *new_douyin.Webcast_Im_GiftMessage:
+		m.Common = common
+	case *new_douyin.Webcast_Im_MemberMessage:
+		m.Common = common
+	case *new_douyin.Webcast_Im_LikeMessage:
+		m.Common = common
+	case *new_douyin.Webcast_Im_RoomUserSeqMessage:
+		m.Common = common
+	}
+	payload, err := proto.Marshal(body)
+	if err != nil {
+		return nil
+	}
+	return &new_douyin.Webcast_Im_Message{Method: method, MsgId: id, Payload: payload}
+}
+
+// user 随机生成观众
+func (g *Generator) user() *new_douyin.Webcast_Data_User {
+	i := g.rnd.Intn(len(nicknames))
+	return &new_douyin.Webcast_Data_User{Id: uint64(100000 + i), Nickname: nicknames[i]}
+}
+
+// gift 生成一次性送完的礼物消息，大额礼物更少见
+func (g *Generator) gift(user *new_douyin.Webcast_Data_User) *new_douyin.Webcast_Im_GiftMessage {
+	info := gifts[g.rnd.Intn(len(gifts)-1)]
+	if g.rnd.Intn(50) == 0 {
+		info = gifts[len(gifts)-1]
+	}
+	g.groupID++
+	repeat := uint64(1)
+	if info.diamond == 1 {
+		repeat = uint64(g.rnd.Intn(10) + 1)
+	}
+	return &new_douyin.Webcast_Im_GiftMessage{
+		GiftId:      info.id,
+		GroupId:     g.groupID,
+		GroupCount:  1,
+		RepeatCount: repeat,
+		ComboCount:  repeat,
+		RepeatEnd:   1,
+		User:        user,
+		Gift:        &new_douyin.Webcast_Data_GiftStruct{Id: info.id, Name: info.name, DiamondCount: info.diamond},
+	}
+}
@@ -0,0 +1,59 @@
+package demo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestFakeLive(t *testing.T) {
+	dl := NewFakeLiveWith(Options{Rate: 1000, Seed: 1})
+	if dl.RoomID() != defaultRoomID {
+		t.Fatalf("RoomID = %s", dl.RoomID())
+	}
+	methods := make(chan string, 1024)
+	dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if _, err := event.Decode(); err != nil {
+			t.Errorf("解码 %s 失败: %v", event.Method, err)
+		}
+		select {
+		case methods <- event.Method:
+		default:
+		}
+	})
+	gifts := make(chan *douyinLive.GiftEvent, 1024)
+	dl.SubscribeGift(func(g *douyinLive.GiftEvent) {
+		select {
+		case gifts <- g:
+		default:
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- dl.Start() }()
+
+	seen := make(map[string]bool)
+	deadline := time.After(5 * time.Second)
+	for len(seen) < 5 {
+		select {
+		case m := <-methods:
+			seen[m] = true
+		case <-deadline:
+			t.Fatalf("只收到 %v", seen)
+		}
+	}
+	if g := <-gifts; g.GiftName == "" || g.Count == 0 {
+		t.Fatalf("礼物事件不完整: %+v", g)
+	}
+
+	dl.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Close 后 Start 应返回 nil: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close 后 Start 未返回")
+	}
+}
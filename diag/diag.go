@@ -0,0 +1,230 @@
+// Package diag 采集进程的诊断包：在退出或致命错误时把最近日志、状态快照、
+// 最近的原始帧与脱敏后的配置导出到指定目录，便于远程用户报障
+package diag
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+const (
+	defaultMaxLogs   = 1000
+	defaultMaxFrames = 200
+)
+
+// Options 诊断包配置
+type Options struct {
+	Dir       string // 诊断包输出目录，不存在时自动创建
+	MaxLogs   int    // 保留的最近日志条数，默认 1000
+	MaxFrames int    // 保留的最近原始帧数，默认 200
+	Config    any    // 进程配置，导出时按字段名脱敏，见 Redact
+
+	LogLevel slog.Leveler // 进入诊断包的最低日志级别，nil 时为 Debug
+}
+
+// Collector 在运行期间收集诊断信息，Export 时写出诊断包
+type Collector struct {
+	opts   Options
+	logs   *lineRing
+	frames *frameRing
+
+	mu    sync.Mutex
+	lives []*douyinLive.DouyinLive
+}
+
+// New 创建诊断信息收集器
+func New(opts Options) *Collector {
+	if opts.MaxLogs <= 0 {
+		opts.MaxLogs = defaultMaxLogs
+	}
+	if opts.MaxFrames <= 0 {
+		opts.MaxFrames = defaultMaxFrames
+	}
+	return &Collector{
+		opts:   opts,
+		logs:   &lineRing{max: opts.MaxLogs},
+		frames: &frameRing{max: opts.MaxFrames},
+	}
+}
+
+// Option 返回记录最近原始帧的可选项，创建实例时传入；会占用 WithFrameRecorder
+func (c *Collector) Option() douyinLive.Option {
+	return douyinLive.WithFrameRecorder(douyinLive.NewFrameWriter(c.frames))
+}
+
+// Watch 登记需要导出状态快照的实例
+func (c *Collector) Watch(dl *douyinLive.DouyinLive) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lives = append(c.lives, dl)
+}
+
+// Export 写出诊断包 <Dir>/douyinlive-diag-<时间>.zip 并返回路径，reason 为导出原因，可为 nil
+func (c *Collector) Export(reason error) (string, error) {
+	if err := os.MkdirAll(c.opts.Dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(c.opts.Dir, "douyinlive-diag-"+time.Now().Format("20060102-150405.000")+".zip")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	zw := zip.NewWriter(f)
+	err = c.write(zw, reason)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("写入诊断包失败: %w", err)
+	}
+	return path, nil
+}
+
+// Recover 在 defer 中调用：发生 panic 时导出诊断包后继续 panic
+func (c *Collector) Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	reason := fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+	if path, err := c.Export(reason); err == nil {
+		fmt.Fprintln(os.Stderr, "诊断包已导出:", path)
+	}
+	panic(r)
+}
+
+// write 写入诊断包的各个文件
+func (c *Collector) write(zw *zip.Writer, reason error) error {
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"state.json", func(w io.Writer) error { return writeJSON(w, c.snapshot(reason)) }},
+		{"config.json", func(w io.Writer) error { return writeJSON(w, c.config()) }},
+		{"logs.jsonl", c.logs.writeTo},
+		// 与 WithFrameRecorder 的格式相同，可用 DouyinLive.ReplayFrames 回放
+		{"frames.bin", c.frames.writeTo},
+	}
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if err := file.write(w); err != nil {
+			return fmt.Errorf("%s: %w", file.name, err)
+		}
+	}
+	return nil
+}
+
+// State 诊断包中的状态快照
+type State struct {
+	Time      time.Time
+	Reason    string `json:",omitempty"`
+	ExitCode  int
+	Version   string
+	GoVersion string
+	OS        string
+	Arch      string
+
+	Goroutines int
+	HeapAlloc  uint64
+	Sys        uint64
+	NumGC      uint32
+
+	Capabilities douyinLive.CapabilityInfo
+	Lives        []LiveState
+}
+
+// LiveState 单个实例的状态
+type LiveState struct {
+	LiveID     string
+	RoomID     string
+	LiveName   string
+	IsLive     bool
+	Transport  string
+	PushHost   string
+	QueueDepth int
+	Stats      douyinLive.Stats
+	Timings    douyinLive.ConnectTimings
+	Protocol   douyinLive.ProtocolParams
+}
+
+// snapshot 采集当前状态
+func (c *Collector) snapshot(reason error) State {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	capabilities := douyinLive.Capabilities()
+	s := State{
+		Time:         time.Now(),
+		ExitCode:     douyinLive.ExitCode(reason),
+		Version:      capabilities.Version,
+		GoVersion:    runtime.Version(),
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		Capabilities: capabilities,
+	}
+	if reason != nil {
+		s.Reason = reason.Error()
+	}
+	c.mu.Lock()
+	lives := append([]*douyinLive.DouyinLive(nil), c.lives...)
+	c.mu.Unlock()
+	for _, dl := range lives {
+		s.Lives = append(s.Lives, LiveState{
+			LiveID:     dl.LiveID(),
+			RoomID:     dl.RoomID(),
+			LiveName:   dl.LiveName,
+			IsLive:     dl.IsLive(),
+			Transport:  dl.Transport(),
+			PushHost:   dl.PushHost(),
+			QueueDepth: dl.QueueDepth(),
+			Stats:      dl.Stats(),
+			Timings:    dl.ConnectTimings(),
+			Protocol:   dl.ProtocolParams(),
+		})
+	}
+	return s
+}
+
+// config 返回脱敏后的配置与相关环境变量
+func (c *Collector) config() map[string]any {
+	env := make(map[string]any)
+	for _, kv := range os.Environ() {
+		if name, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, "DOUYINLIVE_") {
+			env[name] = value
+		}
+	}
+	return map[string]any{
+		"args":   Redact(os.Args),
+		"env":    Redact(env),
+		"config": Redact(c.opts.Config),
+	}
+}
+
+// writeJSON 以缩进格式写入 JSON
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,80 @@
+package diag
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted 脱敏后的占位值
+const redacted = "***"
+
+// sensitiveKeys 字段名包含这些片段时整体脱敏，不区分大小写
+var sensitiveKeys = []string{"cookie", "token", "session", "password", "passwd", "secret", "authorization", "ttwid", "apikey", "api_key"}
+
+// cookiePairRegex 字符串中形如 sessionid=xxx 的敏感键值对
+var cookiePairRegex = regexp.MustCompile(`(?i)\b((?:[a-z_]*(?:session|sid|token|ttwid|passwd|password|secret)[a-z_]*))=([^;&\s]+)`)
+
+// Redact 返回 v 的脱敏副本：先转为 JSON 结构，字段名敏感的值替换为 ***，
+// 字符串中的 cookie 键值对与 URL 中的用户名密码同样替换
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return redacted
+	}
+	return redactValue(generic)
+}
+
+// redactValue 递归脱敏 JSON 结构
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if sensitiveKey(k) {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(item)
+			}
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+		return v
+	case string:
+		return redactString(v)
+	}
+	return v
+}
+
+// sensitiveKey 判断字段名是否敏感
+func sensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString 脱敏字符串中的 cookie 键值对与 URL 凭据
+func redactString(s string) string {
+	s = cookiePairRegex.ReplaceAllString(s, "$1="+redacted)
+	if strings.Contains(s, "://") && strings.Contains(s, "@") {
+		if u, err := url.Parse(s); err == nil && u.User != nil {
+			u.User = url.User(redacted)
+			s = u.String()
+		}
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+package diag
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// frameHeaderSize 与 douyinLive.FrameWriter 的帧头一致：8 字节时间 + 4 字节长度
+const frameHeaderSize = 12
+
+// lineRing 保留最近 max 行
+type lineRing struct {
+	mu    sync.Mutex
+	max   int
+	lines [][]byte
+}
+
+// Write 每次写入视为一行，slog 的 JSONHandler 每条记录只调用一次 Write
+func (r *lineRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.lines) >= r.max {
+		r.lines = r.lines[1:]
+	}
+	r.lines = append(r.lines, bytes.Clone(p))
+	return len(p), nil
+}
+
+// writeTo 按时间顺序写出
+func (r *lineRing) writeTo(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range r.lines {
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// frameRing 作为 FrameWriter 的输出，按帧头切分并保留最近 max 帧
+type frameRing struct {
+	mu      sync.Mutex
+	max     int
+	pending []byte
+	frames  [][]byte // 每项为帧头 + 帧内容
+}
+
+// Write 实现 io.Writer，FrameWriter 会先写帧头再写帧内容
+func (r *frameRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, p...)
+	for len(r.pending) >= frameHeaderSize {
+		size := frameHeaderSize + int(binary.BigEndian.Uint32(r.pending[8:frameHeaderSize]))
+		if len(r.pending) < size {
+			break
+		}
+		if len(r.frames) >= r.max {
+			r.frames = r.frames[1:]
+		}
+		r.frames = append(r.frames, bytes.Clone(r.pending[:size]))
+		r.pending = r.pending[size:]
+	}
+	if len(r.pending) == 0 {
+		r.pending = nil
+	}
+	return len(p), nil
+}
+
+// writeTo 按时间顺序写出完整的帧
+func (r *frameRing) writeTo(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, frame := range r.frames {
+		if _, err := w.Write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler 返回记录最近日志的 slog.Handler，不低于 Options.LogLevel 的日志脱敏后进入诊断包，
+// next 启用的级别同时转交给 next。next 不能是 slog.Default() 的 Handler
+func (c *Collector) Handler(next slog.Handler) slog.Handler {
+	level := c.opts.LogLevel
+	if level == nil {
+		level = slog.LevelDebug
+	}
+	return &logHandler{
+		next: next,
+		ring: slog.NewJSONHandler(c.logs, &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}),
+	}
+}
+
+// redactAttr 脱敏日志中的字段，规则与 Redact 相同
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if sensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, redactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, redactString(err.Error()))
+		}
+		return slog.Any(a.Key, Redact(a.Value.Any()))
+	}
+	return a
+}
+
+// logHandler 同时写入环形缓冲与下游 Handler
+type logHandler struct {
+	next slog.Handler
+	ring slog.Handler
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.ring.Enabled(ctx, level) || h.next.Enabled(ctx, level)
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.ring.Enabled(ctx, r.Level) {
+		h.ring.Handle(ctx, r)
+	}
+	if h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{next: h.next.WithAttrs(attrs), ring: h.ring.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{next: h.next.WithGroup(name), ring: h.ring.WithGroup(name)}
+}
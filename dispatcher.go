@@ -0,0 +1,191 @@
+package douyinLive
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// defaultDispatchQueueSize 每种消息类型队列的默认容量
+const defaultDispatchQueueSize = 256
+
+// WithAsyncDispatch 开启按消息类型的异步分发：
+// 同一 method 的消息由独立的 goroutine 按到达顺序处理，不同 method 之间并行。
+// queueSize 为每个 method 队列的容量，队列满时的处理见 WithDropPolicy，<=0 时使用默认值
+func WithAsyncDispatch(queueSize int) Option {
+	return func(dl *DouyinLive) {
+		if queueSize <= 0 {
+			queueSize = defaultDispatchQueueSize
+		}
+		dl.dispatchQueueSize = queueSize
+	}
+}
+
+// DropPolicy 异步分发队列满时的处理方式，见 WithDropPolicy
+type DropPolicy int
+
+const (
+	DropBlock  DropPolicy = iota // 读取循环等待队列腾出空间，不丢消息
+	DropOldest                   // 丢弃队列中最早的消息，放入新消息
+	DropNewest                   // 丢弃新到的消息
+)
+
+// String 返回策略名称
+func (p DropPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	default:
+		return "block"
+	}
+}
+
+// ParseDropPolicy 解析 block、drop-oldest、drop-newest
+func ParseDropPolicy(s string) (DropPolicy, error) {
+	for _, p := range []DropPolicy{DropBlock, DropOldest, DropNewest} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return DropBlock, fmt.Errorf("未知的队列丢弃策略: %s", s)
+}
+
+// WithDropPolicy 设置异步分发队列满时的处理方式，默认 DropBlock。
+// 处理器或 sink 跟不上时，丢弃策略让读取循环不被阻塞，被丢弃的消息计入 Stats().Dropped。
+// 未开启 WithAsyncDispatch 时以默认容量开启
+func WithDropPolicy(policy DropPolicy) Option {
+	return func(dl *DouyinLive) {
+		dl.dropPolicy = policy
+		if dl.dispatchQueueSize <= 0 {
+			dl.dispatchQueueSize = defaultDispatchQueueSize
+		}
+	}
+}
+
+// dispatcher 按 method 划分的保序队列
+type dispatcher struct {
+	mu      sync.Mutex
+	queues  map[string]chan *new_douyin.Webcast_Im_Message
+	size    int
+	handle  func(*new_douyin.Webcast_Im_Message)
+	policy  DropPolicy
+	drop    func(*new_douyin.Webcast_Im_Message) // 按 policy 丢弃消息时调用
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+// newDispatcher 创建分发器，handle 在各 method 自己的 goroutine 中被调用
+func newDispatcher(size int, handle func(*new_douyin.Webcast_Im_Message)) *dispatcher {
+	return &dispatcher{
+		queues: make(map[string]chan *new_douyin.Webcast_Im_Message),
+		size:   size,
+		handle: handle,
+	}
+}
+
+// dispatch 将消息放入对应 method 的队列，首次出现的 method 会创建新的队列，已停止时返回 false
+func (d *dispatcher) dispatch(msg *new_douyin.Webcast_Im_Message) bool {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return false
+	}
+	queue, ok := d.queues[msg.Method]
+	if !ok {
+		queue = make(chan *new_douyin.Webcast_Im_Message, d.size)
+		d.queues[msg.Method] = queue
+		d.wg.Add(1)
+		go d.run(queue)
+	}
+	// 在锁内发送，保证 stop 关闭队列时不会有并发写入
+	d.enqueue(queue, msg)
+	d.mu.Unlock()
+	return true
+}
+
+// enqueue 按丢弃策略把消息放入队列
+func (d *dispatcher) enqueue(queue chan *new_douyin.Webcast_Im_Message, msg *new_douyin.Webcast_Im_Message) {
+	switch d.policy {
+	case DropNewest:
+		select {
+		case queue <- msg:
+		default:
+			d.dropped(msg)
+		}
+	case DropOldest:
+		for {
+			select {
+			case queue <- msg:
+				return
+			default:
+			}
+			// 处理 goroutine 可能已取走了最早的消息，此时直接重试
+			select {
+			case old := <-queue:
+				d.dropped(old)
+			default:
+			}
+		}
+	default:
+		queue <- msg
+	}
+}
+
+// dropped 通知被丢弃的消息
+func (d *dispatcher) dropped(msg *new_douyin.Webcast_Im_Message) {
+	if d.drop != nil {
+		d.drop(msg)
+	}
+}
+
+// run 顺序处理单个队列中的消息
+func (d *dispatcher) run(queue chan *new_douyin.Webcast_Im_Message) {
+	defer d.wg.Done()
+	for msg := range queue {
+		d.handle(msg)
+	}
+}
+
+// stop 停止接收新消息，并等待已入队的消息处理完毕
+func (d *dispatcher) stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// startDispatcher 按配置创建异步分发器
+func (dl *DouyinLive) startDispatcher() {
+	dl.dispatcher = newDispatcher(dl.dispatchQueueSize, dl.deliverQueued)
+	dl.dispatcher.policy = dl.dropPolicy
+	dl.dispatcher.drop = dl.dropQueued
+}
+
+// dropQueued 记录按丢弃策略被丢弃的消息
+func (dl *DouyinLive) dropQueued(msg *new_douyin.Webcast_Im_Message) {
+	dl.queued.Add(-1)
+	dl.dropped.Add(1)
+	dl.takeTiming(msg)
+	dl.releaseMessage(msg)
+}
+
+// deliverQueued 处理异步分发队列中取出的消息
+func (dl *DouyinLive) deliverQueued(msg *new_douyin.Webcast_Im_Message) {
+	dl.queued.Add(-1)
+	dl.deliverWithTimeout(msg)
+}
+
+// QueueDepth 返回异步分发队列中等待处理的消息数，未开启 WithAsyncDispatch 时为 0
+func (dl *DouyinLive) QueueDepth() int {
+	return int(dl.queued.Load())
+}
@@ -0,0 +1,99 @@
+package douyinLive
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDispatcherKeepsOrderPerMethod(t *testing.T) {
+	var mu sync.Mutex
+	got := make(map[string][]uint64)
+	d := newDispatcher(4, func(msg *new_douyin.Webcast_Im_Message) {
+		mu.Lock()
+		got[msg.Method] = append(got[msg.Method], msg.MsgId)
+		mu.Unlock()
+	})
+
+	methods := []string{WebcastGiftMessage, WebcastControlMessage, WebcastChatMessage}
+	const perMethod = 100
+	for i := 0; i < perMethod; i++ {
+		for _, method := range methods {
+			d.dispatch(&new_douyin.Webcast_Im_Message{Method: method, MsgId: uint64(i)})
+		}
+	}
+	d.stop()
+
+	for _, method := range methods {
+		ids := got[method]
+		if len(ids) != perMethod {
+			t.Fatalf("%s: 收到 %d 条消息, want %d", method, len(ids), perMethod)
+		}
+		for i, id := range ids {
+			if id != uint64(i) {
+				t.Fatalf("%s: 第 %d 条消息 msgId=%d, 顺序被打乱", method, i, id)
+			}
+		}
+	}
+
+	// stop 之后的消息应被丢弃而不是 panic
+	d.dispatch(&new_douyin.Webcast_Im_Message{Method: WebcastGiftMessage})
+}
+
+func TestDispatcherDropPolicy(t *testing.T) {
+	for _, c := range []struct {
+		policy DropPolicy
+		want   []uint64 // 处理器解除阻塞后收到的 msgId
+	}{
+		{DropNewest, []uint64{1, 2, 3}},
+		{DropOldest, []uint64{1, 4, 5}},
+	} {
+		t.Run(c.policy.String(), func(t *testing.T) {
+			release := make(chan struct{})
+			var mu sync.Mutex
+			var got, dropped []uint64
+			d := newDispatcher(2, func(msg *new_douyin.Webcast_Im_Message) {
+				if msg.MsgId == 1 {
+					<-release
+				}
+				mu.Lock()
+				got = append(got, msg.MsgId)
+				mu.Unlock()
+			})
+			d.policy = c.policy
+			d.drop = func(msg *new_douyin.Webcast_Im_Message) { dropped = append(dropped, msg.MsgId) }
+
+			d.dispatch(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 1})
+			// 等待 1 被取出并阻塞在处理器中，队列中只剩 2 个空位
+			for len(d.queues[WebcastChatMessage]) != 0 {
+				time.Sleep(time.Millisecond)
+			}
+			for id := uint64(2); id <= 5; id++ {
+				d.dispatch(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: id})
+			}
+			close(release)
+			d.stop()
+
+			if !slices.Equal(got, c.want) {
+				t.Fatalf("处理了 %v, want %v", got, c.want)
+			}
+			if len(dropped) != 2 {
+				t.Fatalf("丢弃了 %v, want 2 条", dropped)
+			}
+		})
+	}
+}
+
+func TestParseDropPolicy(t *testing.T) {
+	for _, p := range []DropPolicy{DropBlock, DropOldest, DropNewest} {
+		if got, err := ParseDropPolicy(p.String()); err != nil || got != p {
+			t.Fatalf("ParseDropPolicy(%q) = %v, %v", p, got, err)
+		}
+	}
+	if _, err := ParseDropPolicy("drop-all"); err == nil {
+		t.Fatal("未知策略应返回错误")
+	}
+}
@@ -0,0 +1,124 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Resolver 把主机名解析为 IP 地址，*net.Resolver 与 DoHResolver 都实现了该接口
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// WithHosts 把主机名固定解析到指定的 IP，类似 /etc/hosts，如
+// {"webcast5-ws-web-lf.douyin.com": {"1.2.3.4"}}。多个 IP 按顺序尝试，优先于 WithResolver
+func WithHosts(hosts map[string][]string) Option {
+	return func(dl *DouyinLive) {
+		dl.hosts = make(map[string][]string, len(hosts))
+		for host, ips := range hosts {
+			dl.hosts[host] = append([]string(nil), ips...)
+		}
+	}
+}
+
+// WithResolver 用 r 解析 HTTP 接口与 WebSocket 的主机名，用于 DNS 被污染或很慢的网络，
+// 如 DoHResolver 或指定了 DNS 服务器的 *net.Resolver。使用 WithTLSDialer 时由其自行解析
+func WithResolver(r Resolver) Option {
+	return func(dl *DouyinLive) {
+		dl.resolver = r
+	}
+}
+
+// customDial 是否需要自定义解析
+func (dl *DouyinLive) customDial() bool {
+	return len(dl.hosts) > 0 || dl.resolver != nil
+}
+
+// dialContext 按 WithHosts 与 WithResolver 解析后建立连接，依次尝试各个 IP
+func (dl *DouyinLive) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	if net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	ips, ok := dl.hosts[host]
+	if !ok {
+		if dl.resolver == nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		if ips, err = dl.resolver.LookupHost(ctx, host); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", host, err)
+		}
+	}
+	var errs []error
+	for _, ip := range ips {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("%s 没有可用的 IP", host)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// DoHResolver 通过 DNS over HTTPS 的 JSON 接口解析主机名，兼容 Cloudflare、Google、阿里等
+// 提供 application/dns-json 格式的服务，如 https://dns.alidns.com/resolve
+type DoHResolver struct {
+	URL    string
+	Client *http.Client // 为空时使用 10 秒超时的默认客户端
+}
+
+// dohClient 默认的 DoH 请求客户端
+var dohClient = &http.Client{Timeout: 10 * time.Second}
+
+// LookupHost 实现 Resolver，返回 A 记录
+func (r *DoHResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	query := url.Values{"name": {host}, "type": {"A"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/dns-json")
+	client := r.Client
+	if client == nil {
+		client = dohClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH 请求失败，状态码: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	result := gjson.ParseBytes(body)
+	var ips []string
+	for _, answer := range result.Get("Answer").Array() {
+		// 1 为 A 记录，CNAME 等其他记录跳过
+		if answer.Get("type").Int() == 1 {
+			ips = append(ips, answer.Get("data").String())
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("DoH 未返回 %s 的 A 记录 (status=%d)", host, result.Get("Status").Int())
+	}
+	return ips, nil
+}
@@ -0,0 +1,65 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestWithHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	target := "http://webcast.invalid:" + u.Port() + "/"
+
+	// 第一个 IP 不可用时尝试下一个
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithHosts(map[string][]string{"webcast.invalid": {"127.0.0.2", "127.0.0.1"}}))
+	resp, err := dl.client.R().Get(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.String(); got != "webcast.invalid:"+u.Port() {
+		t.Fatalf("Host = %s, 应保留原主机名", got)
+	}
+	if dl.wsDialer().NetDialContext == nil {
+		t.Fatal("WebSocket Dialer 未使用自定义解析")
+	}
+
+	plain := NewDouyinLive2("1", "2", "test", "", nil)
+	if _, err := plain.client.R().Get(target); err == nil {
+		t.Fatal("未设置 WithHosts 时 .invalid 域名不应解析成功")
+	}
+}
+
+func TestDoHResolver(t *testing.T) {
+	doh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "webcast.invalid" || r.Header.Get("Accept") != "application/dns-json" {
+			w.Write([]byte(`{"Status":3}`))
+			return
+		}
+		w.Write([]byte(`{"Status":0,"Answer":[{"type":5,"data":"alias.invalid."},{"type":1,"data":"127.0.0.1"}]}`))
+	}))
+	defer doh.Close()
+
+	r := &DoHResolver{URL: doh.URL}
+	ips, err := r.LookupHost(context.Background(), "webcast.invalid")
+	if err != nil || !slices.Equal(ips, []string{"127.0.0.1"}) {
+		t.Fatalf("LookupHost = %v, %v", ips, err)
+	}
+	if _, err := r.LookupHost(context.Background(), "other.invalid"); err == nil {
+		t.Fatal("没有 A 记录时应返回错误")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithResolver(r))
+	if _, err := dl.client.R().Get("http://webcast.invalid:" + u.Port() + "/"); err != nil {
+		t.Fatal(err)
+	}
+}
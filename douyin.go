@@ -2,13 +2,14 @@ package douyinLive
 
 import (
 	"bytes"
-	"compress/gzip"
+	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
-	"github.com/tidwall/gjson"
-	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -16,29 +17,38 @@ import (
 	"github.com/avast/retry-go"
 	"github.com/gorilla/websocket"
 	"github.com/imroc/req/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/tiga210/douyinLive/generated/douyin"
 	"github.com/tiga210/douyinLive/generated/new_douyin"
-	"github.com/tiga210/douyinLive/jsScript"
 	"github.com/tiga210/douyinLive/utils"
 )
 
 const (
+	protocolVersionCode     = "180800"              // WebSocket 连接参数 version_code
+	webcastSDKVersion       = "1.0.14-beta.0"       // WebSocket 连接参数 webcast_sdk_version
+	wrdsVersion             = "7382620942951772256" // internal_ext 中的 wrds_v
+	webcastAid              = "6383"                // 抖音直播 Web 端的 aid
 	defaultMaxRetries       = 5
 	websocketConnectTimeout = 10 * time.Second
 	gzipBufferSize          = 1024 * 4
-	wssURLTemplate          = "wss://webcast5-ws-web-lf.douyin.com/webcast/im/push/v2/" +
-		"?app_name=douyin_web&version_code=180800&webcast_sdk_version=1.0.14-beta.0" +
-		"&update_version_code=1.0.14-beta.0&compress=gzip&device_platform=web" +
+	errorsBufferSize        = 8
+	wssURLTemplate          = "wss://%s/webcast/im/push/v2/" +
+		"?app_name=douyin_web&version_code=%s&webcast_sdk_version=%s" +
+		"&update_version_code=%s&compress=%s&device_platform=web" +
 		"&cookie_enabled=true&screen_width=1920&screen_height=1080&browser_language=zh-CN" +
 		"&browser_platform=Win32&browser_name=Mozilla&browser_version=%s&browser_online=true" +
-		"&tz_name=Asia/Shanghai&cursor=d-1_u-1_fh-7383731312643626035_t-1719159695790_r-1" +
-		"&internal_ext=internal_src:dim|wss_push_room_id:%s|wss_push_did:%s|first_req_ms:%d" +
-		"|fetch_time:%d|seq:1|wss_info:0-%d-0-0|wrds_v:7382620942951772256&host=https://live.douyin.com" +
-		"&aid=6383&live_id=1&did_rule=3&endpoint=live_pc&support_wrds=1&user_unique_id=%s" +
+		"&tz_name=Asia/Shanghai&cursor=%s" +
+		"&internal_ext=%s&host=https://live.douyin.com" +
+		"&aid=" + webcastAid + "&live_id=1&did_rule=3&endpoint=live_pc&support_wrds=1&user_unique_id=%s" +
 		"&im_path=/webcast/im/fetch/&identity=audience&need_persist_msg_count=15" +
 		"&insert_task_id=&live_reason=&room_id=%s&heartbeatDuration=0&signature=%s"
+
+	// 首次连接时使用的 cursor 与 internal_ext，续连时替换为服务端返回的值
+	defaultCursor       = "d-1_u-1_fh-7383731312643626035_t-1719159695790_r-1"
+	internalExtTemplate = "internal_src:dim|wss_push_room_id:%s|wss_push_did:%s|first_req_ms:%d" +
+		"|fetch_time:%d|seq:1|wss_info:0-%d-0-0|wrds_v:%s"
 )
 
 var (
@@ -48,46 +58,79 @@ var (
 	emptyStrings = [][]string{{"", "", "", "", ""}}
 )
 
-// NewDouyinLive 创建一个新的 DouyinLive 实例
-func NewDouyinLive(liveID string, logger logger) (*DouyinLive, error) {
+// NewDouyinLive 创建一个新的 DouyinLive 实例，liveID 也可以是直播间链接、v.douyin.com 短链或 App 分享文案，
+// 此时会先解析出直播间号（最多等待 10 秒），解析失败时返回包装了 ErrRoomNotFound 的错误
+func NewDouyinLive(liveID string, logger logger, opts ...Option) (*DouyinLive, error) {
+	return NewDouyinLiveContext(context.Background(), liveID, logger, opts...)
+}
+
+// NewDouyinLiveContext 同 NewDouyinLive，解析链接时使用 ctx，可随请求取消
+func NewDouyinLiveContext(ctx context.Context, liveID string, logger logger, opts ...Option) (*DouyinLive, error) {
 	//log.SetOutput(os.Stdout)
 	dl := &DouyinLive{
 		liveID:     liveID,
-		userAgent:  utils.RandomUserAgent(),
-		client:     req.C().SetUserAgent(utils.RandomUserAgent()),
+		client:     req.C(),
 		bufferPool: &sync.Pool{New: func() interface{} { return bytes.NewBuffer(make([]byte, 0, gzipBufferSize)) }},
 		headers:    make(http.Header),
-		logger:     logger,
+		tracer:     defaultTracer(),
+		errs:       make(chan error, errorsBufferSize),
+		chatGuard:  &ChatGuard{},
+	}
+	dl.applyOptions(opts)
+	dl.initUserAgent(liveID)
+	dl.initLogger(logger)
+	dl.initTransport()
+	dl.initCookieJar()
+	dl.initRiskParams()
+	if liveID != "" && !liveIDRegex.MatchString(liveID) {
+		id, err := dl.resolveLiveID(ctx, liveID)
+		if err != nil {
+			return nil, err
+		}
+		dl.log().Info("已解析直播间链接", "input", liveID, "live_id", id)
+		dl.liveID = id
 	}
 	return dl, nil
 }
 
-func NewDouyinLive2(roomId, pushId, liveName, ttwid string, logger logger) *DouyinLive {
-	return &DouyinLive{
+func NewDouyinLive2(roomId, pushId, liveName, ttwid string, logger logger, opts ...Option) *DouyinLive {
+	dl := &DouyinLive{
 		roomID:     roomId,
 		pushID:     pushId,
 		LiveName:   liveName,
 		ttwid:      ttwid,
-		userAgent:  utils.RandomUserAgent(),
+		client:     req.C(),
 		bufferPool: &sync.Pool{New: func() interface{} { return bytes.NewBuffer(make([]byte, 0, gzipBufferSize)) }},
-		logger:     logger,
 		headers:    make(http.Header),
-		isLiving:   true,
+		tracer:     defaultTracer(),
+		errs:       make(chan error, errorsBufferSize),
+		chatGuard:  &ChatGuard{},
 	}
+	dl.isLiving.Store(true)
+	dl.applyOptions(opts)
+	dl.initUserAgent(roomId)
+	dl.initLogger(logger)
+	dl.initTransport()
+	dl.initCookieJar()
+	dl.initRiskParams()
+	return dl
 }
 
 // Close 关闭抖音直播连接，确保资源正确释放
 func (dl *DouyinLive) Close() {
 	// 原子性地设置直播状态为关闭
 	dl.setLiveStatus(false)
-	dl.manualClose = true
+	dl.manualClose.Store(true)
+	dl.stopSource()
+	dl.stopPolling()
+	dl.releaseProxy()
 	// 获取锁，防止并发操作
 	dl.mu.Lock()
 	defer dl.mu.Unlock()
 
 	// 检查连接是否已经关闭
 	if dl.conn == nil {
-		dl.logger.Println("连接已关闭或未初始化")
+		dl.log().Debug("连接已关闭或未初始化")
 		return
 	}
 
@@ -106,8 +149,8 @@ func (dl *DouyinLive) Close() {
 		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "closing connection")
 
 		// 先尝试正常关闭
-		if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(2*time.Second)); err != nil {
-			dl.logger.Printf("发送关闭消息失败: %v\n", err)
+		if err := conn.WriteControl(websocket.CloseMessage, msg, dl.controlDeadline()); err != nil {
+			dl.log().Warn("发送关闭消息失败", "error", err)
 		}
 
 		// 等待一段时间，让对方有机会响应
@@ -115,199 +158,429 @@ func (dl *DouyinLive) Close() {
 
 		// 确保连接最终关闭
 		if err := conn.Close(); err != nil {
-			dl.logger.Printf("关闭连接失败: %v\n", err)
+			dl.log().Warn("关闭连接失败", "error", err)
 		}
 	}()
 
 	// 等待关闭操作完成或超时
 	select {
 	case <-done:
-		dl.logger.Println("连接已成功关闭")
+		dl.log().Info("连接已成功关闭")
 	case <-time.After(3 * time.Second):
-		dl.logger.Println("关闭连接超时")
+		dl.log().Warn("关闭连接超时")
 	}
 }
 
 // initialize 初始化 DouyinLive 实例
 func (dl *DouyinLive) initialize() error {
 
-	if err := jsScript.LoadGoja(dl.userAgent); err != nil {
-		return fmt.Errorf("加载JavaScript脚本失败: %w", err)
+	if err := dl.prepareSigner(); err != nil {
+		return fmt.Errorf("%w: 初始化签名实现失败: %w", ErrSignature, err)
 	}
 
 	dl.headers.Set("User-Agent", dl.userAgent)
-	dl.headers.Set("Cookie", fmt.Sprintf("ttwid=%s", dl.ttwid))
+	dl.headers.Set("Cookie", dl.cookieHeader())
+	return nil
+}
+
+// fetchTTWID 获取 TTWID，WithCookies 提供了 ttwid 时直接使用，
+// 持久化的 cookie jar 中有 ttwid 或设置了 TTWIDStore 时优先复用
+func (dl *DouyinLive) fetchTTWID(ctx context.Context) (err error) {
+	if dl.ttwidFixed {
+		return nil
+	}
+	ctx, span := dl.startSpan(ctx, "douyinLive.fetchTTWID")
+	defer func() { endSpan(span, err) }()
+	defer dl.observePhase(phaseTTWID, time.Now())
+
+	if dl.jar != nil {
+		if ttwid, ok := dl.jar.lookup(ttwidURL, "ttwid"); ok {
+			dl.ttwid = ttwid
+			return nil
+		}
+	}
+	var ttwid string
+	if dl.ttwidStore != nil {
+		ttwid, err = dl.cachedTTWID(ctx)
+	} else {
+		ttwid, err = dl.requestTTWID(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	dl.ttwid = ttwid
 	return nil
 }
 
-// fetchTTWID 获取 TTWID
-func (dl *DouyinLive) fetchTTWID() error {
-	resp, err := dl.client.R().Get("https://live.douyin.com/")
+// requestTTWID 请求首页，从响应的 cookie 中取出 ttwid
+func (dl *DouyinLive) requestTTWID(ctx context.Context) (string, error) {
+	resp, err := dl.client.R().SetContext(ctx).Get(ttwidURL)
 	if err != nil {
-		return fmt.Errorf("请求TTWID失败: %w", err)
+		return "", fmt.Errorf("请求TTWID失败: %w", err)
 	}
 
 	for _, c := range resp.Cookies() {
 		if c.Name == "ttwid" {
-			dl.ttwid = c.Value
-			return nil
+			return c.Value, nil
 		}
 	}
-	return errors.New("未找到TTWID cookie")
+	return "", ErrTTWIDNotFound
 }
 
 // fetchRoomInfo 获取房间信息
-func (dl *DouyinLive) fetchRoomInfo() error {
-	body, err := dl.getPageContent()
-	//log.Println("获取直播间页面内容:", string(body))
+func (dl *DouyinLive) fetchRoomInfo(ctx context.Context) (err error) {
+	ctx, span := dl.startSpan(ctx, "douyinLive.fetchRoomInfo")
+	defer func() { endSpan(span, err) }()
+
+	page, err := dl.getPageContent(ctx)
 	if err != nil {
 		return err
 	}
+	// 页面未变化且已解析过时跳过重复解析
+	if dl.pageUnchanged(&dl.roomInfoHash, page.Hash) && dl.roomID != "" && dl.pushID != "" {
+		dl.log().Debug("直播间页面未变化，跳过解析")
+		return nil
+	}
+	body := page.Body
 
 	dl.roomID = extractString(roomIDRegex, body, 1)
 	dl.pushID = extractString(pushIDRegex, body, 1)
-	name := extractString(regexp.MustCompile(`data-anchor-info="([\s\S]*?)" data-room-info="`), body, 1)
-	cleanJSON := strings.ReplaceAll(name, `&quot;`, `"`)
-	result := gjson.Get(cleanJSON, "nickname")
-	dl.LiveName = result.String()
-	//log.Println("直播间信息:", dl.roomID, dl.pushID, result.String())
+	info := parseRoomInfo(body)
+	info.LiveID = dl.liveID
+	dl.LiveName = info.AnchorNickname
+	span.SetAttributes(attribute.String("douyin.room_id", dl.roomID))
 	if dl.roomID == "" || dl.pushID == "" {
-		return errors.New("无法提取房间信息")
+		return fmt.Errorf("%w: 页面中缺少 roomId 或 user_unique_id", ErrRoomInfoParse)
 	}
+	dl.setRoomInfo(*info)
+	dl.refreshMethodFilter()
+	dl.updateProtocolParams(body)
+	dl.setPageHash(&dl.roomInfoHash, page.Hash)
 	return nil
 }
 
-// getPageContent 获取直播间页面内容
-func (dl *DouyinLive) getPageContent() (string, error) {
+// ttwidCookie 返回当前的 ttwid cookie
+func (dl *DouyinLive) ttwidCookie() *http.Cookie {
+	return &http.Cookie{Name: "ttwid", Value: dl.ttwid}
+}
+
+// getPageContent 获取直播间页面内容，携带条件请求头，页面未变化时返回缓存内容
+func (dl *DouyinLive) getPageContent(ctx context.Context) (page *cachedResponse, err error) {
+	ctx, span := dl.startSpan(ctx, "douyinLive.getPageContent")
+	defer func() { endSpan(span, err) }()
+	defer dl.observePhase(phasePage, time.Now())
+
 	cookies := []*http.Cookie{
 		{Name: "ttwid", Value: "ttwid=" + dl.ttwid},
 		{Name: "__ac_nonce", Value: "0123407cc00a9e438deb4"},
 	}
 
-	resp, err := dl.client.R().
-		SetCookies(cookies...).
-		Get(fmt.Sprintf("https://live.douyin.com/%s", dl.liveID))
-
+	page, err = dl.conditionalGet(ctx, dl.client.R().SetCookies(cookies...), fmt.Sprintf("https://live.douyin.com/%s", dl.liveID))
 	if err != nil {
-		return "", fmt.Errorf("请求直播间页面失败: %w", err)
+		return nil, fmt.Errorf("请求直播间页面失败: %w", err)
 	}
-	return resp.String(), nil
+	span.SetAttributes(
+		attribute.Int("http.status_code", page.StatusCode),
+		attribute.Bool("http.not_modified", page.NotModified),
+	)
+	if page.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrRoomNotFound, dl.liveID)
+	}
+	return page, nil
+}
+
+// pageUnchanged 判断页面哈希是否与某个解析步骤上次处理时相同
+func (dl *DouyinLive) pageUnchanged(last *[sha256.Size]byte, hash [sha256.Size]byte) bool {
+	dl.pageMu.Lock()
+	defer dl.pageMu.Unlock()
+	return *last == hash
+}
+
+// setPageHash 记录解析步骤处理过的页面哈希
+func (dl *DouyinLive) setPageHash(last *[sha256.Size]byte, hash [sha256.Size]byte) {
+	dl.pageMu.Lock()
+	defer dl.pageMu.Unlock()
+	*last = hash
 }
 
 // IsLive 检查直播间是否开播
 func (dl *DouyinLive) IsLive() bool {
-	content, err := dl.getPageContent()
+	return dl.isLive(context.Background())
+}
+
+// CheckLive 检查直播间是否开播，未开播时返回包装了 ErrRoomOffline 的错误，
+// 直播间不存在或页面无法解析时返回对应的哨兵错误
+func (dl *DouyinLive) CheckLive(ctx context.Context) error {
+	return dl.checkLive(ctx)
+}
+
+// isLive 检查直播间是否开播，携带上下文用于链路追踪
+func (dl *DouyinLive) isLive(ctx context.Context) bool {
+	return dl.checkLive(ctx) == nil
+}
+
+// checkLive 检查直播间状态，未开播时返回 ErrRoomOffline
+func (dl *DouyinLive) checkLive(ctx context.Context) error {
+	page, err := dl.getPageContent(ctx)
 	if err != nil {
 		dl.setLiveStatus(false)
-		return false
+		return err
 	}
 
-	matches := isLiveRegex.FindStringSubmatch(content)
-	if len(matches) < 3 {
-		return false
+	dl.pageMu.Lock()
+	status := dl.liveStatus
+	dl.pageMu.Unlock()
+	if !dl.pageUnchanged(&dl.liveStatusHash, page.Hash) || status == "" {
+		matches := isLiveRegex.FindStringSubmatch(page.Body)
+		if len(matches) < 3 {
+			return fmt.Errorf("%w: 页面中缺少直播状态", ErrRoomInfoParse)
+		}
+		status = matches[2]
+		dl.pageMu.Lock()
+		dl.liveStatus = status
+		dl.liveStatusHash = page.Hash
+		dl.pageMu.Unlock()
 	}
 
-	status := matches[2]
 	dl.setLiveStatus(status == "2")
-	return dl.isLiving
+	if !dl.isLiving.Load() {
+		return fmt.Errorf("%w: status=%s", ErrRoomOffline, status)
+	}
+	return nil
 }
 
 // setLiveStatus 设置直播间状态
 func (dl *DouyinLive) setLiveStatus(status bool) {
-	dl.isLiving = status
+	dl.isLiving.Store(status)
 }
 
-// Start 启动直播间连接
-func (dl *DouyinLive) Start() {
-	defer dl.cleanup()
+// Start 启动直播间连接，阻塞直到连接结束。
+// 手动 Close 时返回 nil，其余情况返回包装了哨兵错误的原因，同时推送到 Errors()
+func (dl *DouyinLive) Start() error {
+	return dl.fail(dl.start())
+}
 
-	if !dl.IsLive() {
-		dl.logger.Println("直播间未开播或连接失败")
-		return
-	}
-	if err := dl.fetchTTWID(); err != nil {
-		dl.logger.Printf("初始化获取ttwid失败: %v\n", err)
-		return
+// start 完整的连接流程
+func (dl *DouyinLive) start() error {
+	if dl.source != nil {
+		return dl.runSource()
 	}
+	defer dl.cleanup()
 
-	if err := dl.fetchRoomInfo(); err != nil {
-		dl.logger.Printf("初始化获取rome_info失败: %v\n", err)
-		return
+	dl.beginTimings()
+	ctx, span := dl.startSpan(context.Background(), "douyinLive.connect")
+	if dl.canFastStart() {
+		err := dl.fastConnect(ctx)
+		if err == nil {
+			endSpan(span, nil)
+			dl.finishTimings()
+			return dl.processMessages()
+		}
+		dl.log().Warn("快速接入失败，回退到完整流程", "error", err)
 	}
-	if err := dl.initialize(); err != nil {
-		dl.logger.Printf("初始化失败: %v\n", err)
-		return
+	if err := dl.checkLive(ctx); err != nil {
+		dl.log().Info("直播间未开播或连接失败", "error", err)
+		endSpan(span, err)
+		return fmt.Errorf("检查直播状态失败: %w", err)
+	}
+	if err := dl.fetchTTWID(ctx); err != nil {
+		dl.log().Error("初始化获取ttwid失败", "error", err)
+		endSpan(span, err)
+		return fmt.Errorf("初始化获取ttwid失败: %w", err)
 	}
 
-	if err := dl.startWebSocket(); err != nil {
-		dl.logger.Printf("WebSocket连接失败: %v\n", err)
-		return
+	if err := dl.fetchRoomInfo(ctx); err != nil {
+		dl.log().Error("初始化获取room_info失败", "error", err)
+		endSpan(span, err)
+		return fmt.Errorf("初始化获取room_info失败: %w", err)
+	}
+	if err := dl.dial(ctx); err != nil {
+		endSpan(span, err)
+		return err
 	}
+	endSpan(span, nil)
+	dl.finishTimings()
 
-	dl.processMessages()
+	return dl.processMessages()
 }
 
+// Start2 使用已知的 roomID/pushID/ttwid 直接连接，返回值与 Start 一致
 func (dl *DouyinLive) Start2() error {
+	return dl.fail(dl.start2())
+}
+
+// start2 跳过页面解析的连接流程
+func (dl *DouyinLive) start2() error {
+	if dl.source != nil {
+		return dl.runSource()
+	}
 	defer dl.cleanup()
-	if err := dl.initialize(); err != nil {
-		dl.logger.Printf("初始化失败: %v\n", err)
+	dl.beginTimings()
+	ctx, span := dl.startSpan(context.Background(), "douyinLive.connect")
+	if err := dl.dial(ctx); err != nil {
+		endSpan(span, err)
 		return err
 	}
-	if err := dl.startWebSocket(); err != nil {
-		dl.logger.Printf("WebSocket连接失败: %v\n", err)
-		return err
+	endSpan(span, nil)
+	dl.finishTimings()
+	return dl.processMessages()
+}
+
+// dial 凭已知的 roomID/pushID 初始化并握手，握手失败且开启了 HTTP 轮询时交给 processMessages 轮询
+func (dl *DouyinLive) dial(ctx context.Context) error {
+	if err := dl.initialize(); err != nil {
+		dl.log().Error("初始化失败", "error", err)
+		return fmt.Errorf("初始化失败: %w", err)
+	}
+	if err := dl.startWebSocket(ctx); err != nil {
+		dl.log().Error("WebSocket连接失败", "error", err)
+		if !dl.pollingEnabled() {
+			return fmt.Errorf("WebSocket连接失败: %w", err)
+		}
 	}
-	dl.processMessages()
 	return nil
 }
 
+// Errors 返回终止性错误的通道，适合以 go dl.Start() 方式运行时监听，
+// 通道有缓冲且不会被关闭，无人接收时多余的错误会被丢弃
+func (dl *DouyinLive) Errors() <-chan error {
+	return dl.errs
+}
+
+// fail 将终止性错误推送到 Errors()，并原样返回
+func (dl *DouyinLive) fail(err error) error {
+	if err == nil {
+		return nil
+	}
+	select {
+	case dl.errs <- err:
+	default:
+	}
+	return err
+}
+
 // connectWebSocket 连接 WebSocket
-func (dl *DouyinLive) startWebSocket() error {
-	dialer := websocket.DefaultDialer
-	dialer.HandshakeTimeout = websocketConnectTimeout
-	url := dl.makeURL()
-	conn, resp, err := dialer.Dial(url, dl.headers)
+func (dl *DouyinLive) startWebSocket(ctx context.Context) (err error) {
+	dialer := dl.wsDialer()
+	url, err := dl.makeURL(ctx)
 	if err != nil {
-		return fmt.Errorf("连接失败 (状态码: %d): %w", resp.StatusCode, err)
+		return err
 	}
-	dl.logger.Printf("直播间连接成功(状态码):[%d] 直播间名称:[%s]\n", resp.StatusCode, dl.LiveName)
-	dl.conn = conn
+
+	ctx, span := dl.startSpan(ctx, "douyinLive.dial")
+	defer func() { endSpan(span, err) }()
+
+	dialStart := time.Now()
+	conn, resp, err := dialer.DialContext(ctx, url, dl.headers)
+	dl.observePhase(phaseHandshake, dialStart)
+	dl.reportDial(err)
+	dl.reportProxyDial(resp, err)
+	if err == nil || resp != nil {
+		// 只回报服务端给出响应的握手，纯网络错误与签名无关
+		dl.reportHandshake(err)
+	}
+	if err != nil {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+			return fmt.Errorf("连接失败 (状态码: %d): %w", resp.StatusCode, err)
+		}
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	dl.log().Info("直播间连接成功", "status_code", resp.StatusCode)
+	dl.setConn(conn)
 	return nil
 }
 
 // makeURL 构建 WebSocket URL
-func (dl *DouyinLive) makeURL() string {
+func (dl *DouyinLive) makeURL(ctx context.Context) (string, error) {
 	fetchTime := time.Now().UnixNano() / int64(time.Millisecond)
 	browserInfo := strings.SplitN(dl.userAgent, "Mozilla", 2)[1]
 	parsedBrowser := strings.ReplaceAll(browserInfo, " ", "%20")
 
-	signature := jsScript.ExecuteJS(utils.GetxMSStub(
-		utils.NewOrderedMap(dl.roomID, dl.pushID),
-	))
+	signCtx, span := dl.startSpan(ctx, "douyinLive.signature")
+	signStart := time.Now()
+	signer := dl.signer()
+	if signer == nil {
+		err := fmt.Errorf("%w: 未配置签名实现", ErrSignature)
+		endSpan(span, err)
+		return "", err
+	}
+	signature, err := signer.Sign(signCtx, SignRequest{RoomID: dl.roomID, PushID: dl.pushID, UserAgent: dl.userAgent})
+	dl.observePhase(phaseSignature, signStart)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrSignature, err)
+		endSpan(span, err)
+		return "", err
+	}
+	endSpan(span, nil)
+	dl.lastSignature = signature
+
+	// 有续连状态时沿用上次的 cursor 与 internal_ext，服务端会补发断开期间的消息
+	protocol := dl.ProtocolParams()
+	cursor, internalExt := dl.resumeState()
+	if cursor == "" {
+		cursor = defaultCursor
+	}
+	if internalExt == "" {
+		internalExt = fmt.Sprintf(internalExtTemplate, dl.roomID, dl.pushID, fetchTime, fetchTime, fetchTime, protocol.WrdsV)
+	}
 
-	return fmt.Sprintf(wssURLTemplate,
+	return dl.appendRiskParams(ctx, fmt.Sprintf(wssURLTemplate,
+		dl.PushHost(),
+		protocol.VersionCode,
+		protocol.WebcastSDKVersion,
+		protocol.UpdateVersionCode,
+		dl.Compression(),
 		parsedBrowser,
-		dl.roomID,
-		dl.pushID,
-		fetchTime,
-		fetchTime,
-		fetchTime,
+		// 续连状态来自服务端，可能含有 &、= 等字符
+		url.QueryEscape(cursor),
+		url.QueryEscape(internalExt),
 		dl.pushID,
 		dl.roomID,
 		signature,
-	)
+	))
 }
 
-// processMessages 处理消息
-func (dl *DouyinLive) processMessages() {
-	var pushFrame new_douyin.Webcast_Im_PushFrame
+// processMessages 处理消息，返回导致消息循环结束的原因，手动关闭时返回 nil。
+// 开启 http_polling 特性时，WebSocket 不可用期间改用 HTTP 轮询
+func (dl *DouyinLive) processMessages() error {
+	if dl.dispatchQueueSize > 0 {
+		dl.startDispatcher()
+	}
+	for {
+		var err error
+		if dl.currentConn() == nil && dl.pollingEnabled() {
+			err = dl.pollMessages()
+		} else {
+			err = dl.readMessages()
+		}
+		if !errors.Is(err, errSwitchTransport) {
+			return err
+		}
+	}
+}
 
-	for dl.isLiving {
-		messageType, data, err := dl.conn.ReadMessage()
+// readMessages 读取 WebSocket 消息，重连失败且允许轮询时返回 errSwitchTransport
+func (dl *DouyinLive) readMessages() error {
+	var pushFrame new_douyin.Webcast_Im_PushFrame
+	var pipeline *decodePipeline
+	if dl.decodeWorkers > 1 {
+		pipeline = dl.startPipeline()
+		defer pipeline.stop()
+	}
+	for dl.isLiving.Load() {
+		conn, messageType, data, err := dl.readMessage()
 		if err != nil {
-			dl.logger.Printf("读取消息失败:%v\n", err)
-			if !dl.handleReadError(err) {
-				break
+			dl.log().Warn("读取消息失败", "error", err)
+			if err := dl.handleReadError(err); err != nil {
+				if errors.Is(err, errManualClose) {
+					return nil
+				}
+				if errors.Is(err, ErrReconnectFailed) && dl.pollingEnabled() {
+					return errSwitchTransport
+				}
+				return err
 			}
 			continue
 		}
@@ -315,73 +588,109 @@ func (dl *DouyinLive) processMessages() {
 		if messageType != websocket.BinaryMessage || len(data) == 0 {
 			continue
 		}
-
-		if err := proto.Unmarshal(data, &pushFrame); err != nil {
-			dl.logger.Printf("解析PushFrame失败: %v\n", err)
-			continue
+		if dl.frameRecorder != nil {
+			if err := dl.frameRecorder.WriteFrame(time.Now(), data); err != nil {
+				dl.log().Warn("录制PushFrame失败", "error", err)
+			}
 		}
-
-		if pushFrame.PayloadType == "msg" && utils.HasGzipEncoding(pushFrame.Headers) {
-			dl.handleGzipMessage(&pushFrame)
+		if pipeline != nil {
+			pipeline.submit(data, conn)
+		} else {
+			dl.handleFrame(&pushFrame, data, conn)
 		}
 	}
+	if dl.manualClose.Load() {
+		return nil
+	}
+	return ErrLiveEnded
 }
 
-// readMessage 读取消息
-func (dl *DouyinLive) readMessage() (int, []byte, error) {
-	if dl.conn == nil {
-		return 0, nil, errors.New("连接已关闭")
+// handleFrame 解析一帧 PushFrame 并处理其中的消息，pushFrame 可复用。
+// conn 为收到该帧的连接，ack 只发回该连接，回放时为 nil
+func (dl *DouyinLive) handleFrame(pushFrame *new_douyin.Webcast_Im_PushFrame, data []byte, conn *websocket.Conn) {
+	f := &decodedFrame{frame: pushFrame, data: data, conn: conn}
+	dl.decodeFrame(f)
+	dl.processFrame(f)
+}
+
+// readMessage 从当前连接读取消息，同时返回该连接
+func (dl *DouyinLive) readMessage() (*websocket.Conn, int, []byte, error) {
+	conn := dl.currentConn()
+	if conn == nil {
+		return nil, 0, nil, errors.New("连接已关闭")
+	}
+	messageType, data, err := conn.ReadMessage()
+	if err == nil {
+		dl.extendReadDeadline(conn)
 	}
-	return dl.conn.ReadMessage()
+	return conn, messageType, data, err
 }
 
-// handleGzipMessage 处理 GZIP 消息
-func (dl *DouyinLive) handleGzipMessage(pushFrame *new_douyin.Webcast_Im_PushFrame) {
-	uncompressed, err := dl.decompressGzip(pushFrame.Payload)
+// handleMessageFrame 处理消息帧
+func (dl *DouyinLive) handleMessageFrame(pushFrame *new_douyin.Webcast_Im_PushFrame) {
+	f := &decodedFrame{frame: pushFrame}
+	dl.decodeResponse(f)
+	dl.deliverResponse(f)
+}
+
+// decodeResponse 解压并解码消息帧中的 Response，不依赖连接状态，可并行执行
+func (dl *DouyinLive) decodeResponse(f *decodedFrame) {
+	f.received = time.Now()
+	f.ctx, f.span = dl.startSpan(context.Background(), "douyinLive.handleFrame",
+		attribute.Int64("douyin.log_id", int64(f.frame.LogID)),
+	)
+
+	// 解压结果只在解码期间使用，Response 解码时会复制其中的字段
+	buf := dl.bufferPool.Get().(*bytes.Buffer)
+	defer dl.putBuffer(buf)
+	encoding := utils.PayloadEncoding(f.frame.Headers)
+	uncompressed, err := dl.decompress(encoding, f.frame.Payload, buf)
 	if err != nil {
-		dl.logger.Printf("GZIP解压失败: %v\n", err)
+		dl.log().Warn("消息帧解压失败", "compress_type", encoding, "error", err)
+		f.err = err
 		return
 	}
 
-	var response new_douyin.Webcast_Im_Response
-	if err := proto.Unmarshal(uncompressed, &response); err != nil {
-		dl.logger.Printf("解析Response失败: %v\n", err)
+	response, err := dl.unmarshalResponse(uncompressed)
+	if err != nil {
+		dl.log().Warn("解析Response失败", "error", err)
+		f.err = err
 		return
 	}
+	f.span.SetAttributes(attribute.Int("douyin.message_count", len(response.Messages)))
+	f.response = response
+}
 
-	if response.NeedAck {
-		dl.sendAck(pushFrame.LogID, response.InternalExt)
+// deliverResponse 确认并分发解码后的 Response，需按收到的顺序执行
+func (dl *DouyinLive) deliverResponse(f *decodedFrame) {
+	defer func() { endSpan(f.span, f.err) }()
+	response := f.response
+	if response == nil {
+		return
 	}
+	deliver := dl.interceptResponse(response, ResponseInfo{LogID: f.frame.LogID})
+	dl.saveResumeState(response.Cursor, response.InternalExt)
 
-	for _, msg := range response.Messages {
-		dl.handleSingleMessage(msg)
+	if response.NeedAck {
+		dl.sendAck(f.conn, f.frame.LogID, response.InternalExt)
 	}
-}
-
-// decompressGzip 解压 GZIP 数据
-func (dl *DouyinLive) decompressGzip(data []byte) ([]byte, error) {
-	buf := dl.bufferPool.Get().(*bytes.Buffer)
-	defer func() {
-		buf.Reset()
-		dl.bufferPool.Put(buf)
-	}()
-
-	buf.Write(data)
-	gz, err := gzip.NewReader(buf)
-	if err != nil {
-		return nil, err
+	if !deliver {
+		dl.discardResponse(response)
+		return
 	}
-	defer gz.Close()
 
-	result := bytes.NewBuffer(make([]byte, 0, len(data)*2))
-	if _, err = io.Copy(result, gz); err != nil {
-		return nil, err
+	ctx := dl.withResponseTiming(f.ctx, response, f.received)
+	for _, msg := range response.Messages {
+		dl.handleSingleMessage(ctx, msg)
 	}
-	return result.Bytes(), nil
+	dl.releaseResponse(response)
 }
 
-// sendAck 发送 ACK 消息
-func (dl *DouyinLive) sendAck(logID uint64, internalExt string) {
+// sendAck 在 conn 上发送 ACK 消息，conn 为 nil 时不发送
+func (dl *DouyinLive) sendAck(conn *websocket.Conn, logID uint64, internalExt string) {
+	if conn == nil {
+		return
+	}
 	ackFrame := &new_douyin.Webcast_Im_PushFrame{
 		LogID:       logID,
 		PayloadType: "ack",
@@ -390,88 +699,132 @@ func (dl *DouyinLive) sendAck(logID uint64, internalExt string) {
 
 	data, err := proto.Marshal(ackFrame)
 	if err != nil {
-		dl.logger.Printf("心跳包序列化失败: %v\n", err)
+		dl.log().Error("心跳包序列化失败", "error", err)
 		return
 	}
 
-	if dl.conn != nil {
-		err := dl.conn.WriteMessage(websocket.BinaryMessage, data)
-		if err != nil {
-			dl.logger.Printf("发送心跳包失败: %v\n", err)
-		}
+	if err := dl.writeMessage(conn, websocket.BinaryMessage, data); err != nil {
+		dl.log().Warn("发送心跳包失败", "error", err)
 	}
 }
 
 // handleSingleMessage 处理单条消息
-func (dl *DouyinLive) handleSingleMessage(msg *new_douyin.Webcast_Im_Message) {
-	dl.emitEvent(msg)
-
-	if msg.Method == "WebcastControlMessage" {
-		var controlMsg douyin.ControlMessage
-		if err := proto.Unmarshal(msg.Payload, &controlMsg); err != nil {
-			dl.logger.Printf("解析控制消息失败: %v\n", err)
-			return
-		}
-		if controlMsg.Status == 3 {
-			dl.logger.Printf("[%s]直播间已关闭", dl.LiveName)
-			dl.setLiveStatus(false)
+func (dl *DouyinLive) handleSingleMessage(ctx context.Context, msg *new_douyin.Webcast_Im_Message) {
+	if !dl.methodAllowed(msg.Method) || dl.isDuplicate(msg.MsgId) {
+		dl.releaseMessage(msg)
+		return
+	}
+	_, span := dl.startSpan(ctx, "douyinLive.handleMessage",
+		attribute.String("douyin.method", msg.Method),
+		attribute.Int64("douyin.msg_id", int64(msg.MsgId)),
+	)
+	defer span.End()
+
+	dl.updateStats(msg)
+	dl.countTopic(msg.Method)
+	dl.trackSummary(msg)
+	dl.handleUnknown(msg)
+	// 控制消息在分发前解析，分发后消息可能已被回收
+	closed := msg.Method == WebcastControlMessage && dl.roomClosed(msg)
+	// 统计基于全部消息，采样只影响分发
+	if dl.sampled(msg.Method, msg.MsgId) {
+		if dl.eventTiming {
+			dl.storeTiming(ctx, msg)
 		}
+		dl.emitEvent(msg)
+	} else {
+		dl.releaseMessage(msg)
+	}
+
+	if closed {
+		dl.log().Info("直播间已关闭")
+		dl.setLiveStatus(false)
+		dl.emitSummary()
 	}
 }
 
-// 修改 handleReadError 方法，使用库自带方法判断错误
-func (dl *DouyinLive) handleReadError(err error) bool {
-	// 如果是手动关闭，不进行重连
-	if dl.manualClose {
-		dl.logger.Println("连接被手动关闭，不进行重连")
+// roomClosed 判断控制消息是否表示直播结束
+func (dl *DouyinLive) roomClosed(msg *new_douyin.Webcast_Im_Message) bool {
+	var controlMsg douyin.ControlMessage
+	if err := proto.Unmarshal(msg.Payload, &controlMsg); err != nil {
+		dl.log().Warn("解析控制消息失败", "error", err)
 		return false
 	}
+	return controlMsg.Status == 3
+}
+
+// handleReadError 使用库自带方法判断错误，重连成功时返回 nil，否则返回终止原因
+func (dl *DouyinLive) handleReadError(err error) error {
+	// 如果是手动关闭，不进行重连
+	if dl.manualClose.Load() {
+		dl.log().Info("连接被手动关闭，不进行重连")
+		return errManualClose
+	}
+	if isTimeout(err) {
+		dl.log().Warn("读取超时，连接可能已断开，尝试重连", "error", err)
+		return dl.reconnect(defaultMaxRetries)
+	}
 	// 使用 websocket.IsUnexpectedCloseError 判断特定关闭码
 	if !websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure) {
-		dl.logger.Printf("正常关闭: %v\n", err)
-		return false // 不需要重连
+		dl.log().Info("正常关闭", "error", err)
+		return fmt.Errorf("%w: %w", ErrConnectionClosed, err) // 不需要重连
 	}
-	dl.logger.Printf("检测到非正常关闭，尝试重连...错误代码:%v\n", err)
+	dl.log().Warn("检测到非正常关闭，尝试重连", "error", err)
 	// 处理非正常关闭错误
 	var closeErr *websocket.CloseError
 	if errors.As(err, &closeErr) {
-		dl.logger.Printf("WebSocket关闭错误: code=%d, reason=%s\n", closeErr.Code, closeErr.Text)
+		dl.log().Warn("WebSocket关闭错误", "code", closeErr.Code, "reason", closeErr.Text)
 
 		// 针对特定错误码处理
 		switch closeErr.Code {
 		case websocket.CloseAbnormalClosure: // 1006 异常关闭
-			dl.logger.Println("检测到异常关闭，尝试重连...")
+			dl.log().Debug("检测到异常关闭，尝试重连")
 			return dl.reconnect(defaultMaxRetries)
 		case websocket.CloseTryAgainLater: // 1013 临时不可用
-			dl.logger.Println("服务端要求稍后重试...")
+			dl.log().Debug("服务端要求稍后重试")
 			time.Sleep(5 * time.Second)
 			return dl.reconnect(defaultMaxRetries)
 		}
 	}
 
 	// 处理其他网络错误
-	dl.logger.Printf("网络错误: %v\n", err)
+	dl.log().Warn("网络错误", "error", err)
 	return dl.reconnect(defaultMaxRetries)
 }
 
-// 优化后的 reconnect 方法
-func (dl *DouyinLive) reconnect(attempts int) bool {
+// reconnect 重新建立连接，成功时返回 nil
+func (dl *DouyinLive) reconnect(attempts int) error {
 	// 如果是手动关闭，不进行重连
-	if dl.manualClose {
-		dl.logger.Println("连接被手动关闭，不进行重连")
-		return false
+	if dl.manualClose.Load() {
+		dl.log().Info("连接被手动关闭，不进行重连")
+		return errManualClose
 	}
-	if dl.conn != nil {
+	dl.mu.Lock()
+	conn := dl.conn
+	dl.conn = nil
+	dl.mu.Unlock()
+	if conn != nil {
 		// 使用标准方法发送关闭帧
 		msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "reconnecting")
-		_ = dl.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(3*time.Second))
-		dl.conn.Close()
-		dl.conn = nil
+		_ = conn.WriteControl(websocket.CloseMessage, msg, dl.controlDeadline())
+		conn.Close()
 	}
 
 	retryable := func() error {
-		url := dl.makeURL()
-		conn, _, err := websocket.DefaultDialer.Dial(url, dl.headers)
+		dl.beginTimings()
+		url, err := dl.makeURL(context.Background())
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+		dialStart := time.Now()
+		conn, resp, err := dl.wsDialer().Dial(url, dl.headers)
+		dl.observePhase(phaseHandshake, dialStart)
+		dl.reportDial(err)
+		dl.reportProxyDial(resp, err)
+		if err == nil || resp != nil {
+			// 只回报服务端给出响应的握手，纯网络错误与签名无关
+			dl.reportHandshake(err)
+		}
 		if err != nil {
 			// 处理不可恢复错误
 			if websocket.IsCloseError(err,
@@ -487,7 +840,7 @@ func (dl *DouyinLive) reconnect(attempts int) bool {
 			}
 			return err
 		}
-		dl.conn = conn
+		dl.setConn(conn)
 		return nil
 	}
 
@@ -504,15 +857,16 @@ func (dl *DouyinLive) reconnect(attempts int) bool {
 			)
 		}),
 		retry.OnRetry(func(n uint, err error) {
-			dl.logger.Printf("第%d次重试连接: %v\n", n+1, err)
+			dl.log().Warn("重试连接", "attempt", n+1, "error", err)
 		}),
 	)
 	if err != nil {
-		dl.logger.Printf("连接最终失败: %v", err)
-	} else {
-		dl.logger.Println("连接成功，未触发重试")
+		dl.log().Error("连接最终失败", "error", err)
+		return fmt.Errorf("%w: %w", ErrReconnectFailed, err)
 	}
-	return err == nil
+	dl.log().Info("重连成功")
+	dl.finishTimings()
+	return nil
 }
 
 // 使用库方法判断意外关闭
@@ -526,21 +880,104 @@ func isUnexpectedClose(err error) bool {
 
 // cleanup 清理资源
 func (dl *DouyinLive) cleanup() {
-	if dl.conn != nil {
-		dl.conn.Close()
+	if conn := dl.currentConn(); conn != nil {
+		conn.Close()
+	}
+	// 等待异步队列中剩余的消息处理完毕
+	if dl.dispatcher != nil {
+		dl.dispatcher.stop()
+		dl.dispatcher = nil
 	}
+	dl.stopDeliveryLanes()
 }
 
-// emitEvent 触发事件，遍历处理所有有效处理器
+// emitEvent 触发事件，开启异步分发时放入对应 method 的队列，否则直接处理
 func (dl *DouyinLive) emitEvent(msg *new_douyin.Webcast_Im_Message) {
-	for _, handler := range dl.eventHandlers {
-		handler.Handler(msg)
+	if dl.dispatcher != nil {
+		dl.queued.Add(1)
+		if !dl.dispatcher.dispatch(msg) {
+			dl.queued.Add(-1)
+			dl.takeTiming(msg)
+			dl.releaseMessage(msg)
+		}
+		return
+	}
+	dl.deliverWithTimeout(msg)
+}
+
+// deliver 遍历处理所有有效处理器
+func (dl *DouyinLive) deliver(msg *new_douyin.Webcast_Im_Message) {
+	dl.handlersMu.RLock()
+	handlers := dl.eventHandlers
+	dl.handlersMu.RUnlock()
+
+	timing := dl.takeTiming(msg)
+	defer dl.releaseMessage(msg)
+	var event *LiveEvent
+	var prepared bool
+	newEvent := func() *LiveEvent {
+		if !prepared {
+			prepared = true
+			if event == nil {
+				event = NewLiveEvent(dl.roomID, dl.LiveName, dl.eventMessage(msg))
+			}
+			event.timing = timing
+			event.loc = dl.Location()
+			event.Time = event.Time.In(event.loc)
+			dl.classify(event)
+			dl.transform(event)
+		}
+		return event
+	}
+	// 名单在分类与转换之前判断，被屏蔽用户的消息不进入任何处理器
+	if dl.userList != nil {
+		event = NewLiveEvent(dl.roomID, dl.LiveName, dl.eventMessage(msg))
+		if !dl.userPermitted(event) {
+			return
+		}
+	}
+	// 记录处理器 panic 的次数，全部 panic 时该消息进入死信
+	var called, failed int
+	var reason error
+	for _, handler := range handlers {
+		if handler.Handler != nil {
+			called++
+			call := func() { handler.Handler(msg) }
+			if len(dl.middlewares) > 0 {
+				event, h := newEvent(), dl.wrap(func(*LiveEvent) { handler.Handler(msg) })
+				call = func() { h(event) }
+			}
+			if err := dl.callHandler(call); err != nil {
+				failed, reason = failed+1, err
+			}
+		}
+		if handler.EventHandler != nil {
+			called++
+			event, h := newEvent(), Handler(handler.EventHandler)
+			if len(dl.middlewares) > 0 {
+				h = dl.wrap(h)
+			}
+			if err := dl.callHandler(func() { h(event) }); err != nil {
+				failed, reason = failed+1, err
+			}
+		}
+	}
+
+	if !dl.deadLetterEnabled(handlers) {
+		return
+	}
+	if err := checkDecode(newEvent()); err != nil {
+		dl.deadLetter(handlers, event, err)
+	} else if called > 0 && failed == called {
+		dl.deadLetter(handlers, event, reason)
 	}
 }
 
 // Subscribe 订阅事件，生成唯一ID
 func (dl *DouyinLive) Subscribe(handler func(*new_douyin.Webcast_Im_Message)) string {
 	id := utils.GenerateUniqueID() // 假设这是一个生成唯一ID的函数
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
 	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
 		ID:      id,
 		Handler: handler,
@@ -548,14 +985,61 @@ func (dl *DouyinLive) Subscribe(handler func(*new_douyin.Webcast_Im_Message)) st
 	return id
 }
 
+// SubscribeEvent 订阅带房间上下文的事件，同一条消息的多个处理器共享解码结果
+func (dl *DouyinLive) SubscribeEvent(handler func(*LiveEvent)) string {
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:           id,
+		EventHandler: handler,
+	})
+	return id
+}
+
+// LiveID 返回直播间号（live.douyin.com/ 后面的部分）
+func (dl *DouyinLive) LiveID() string {
+	return dl.liveID
+}
+
+// RoomID 返回当前直播间的 roomID
+func (dl *DouyinLive) RoomID() string {
+	return dl.roomID
+}
+
 // Unsubscribe 取消订阅事件，通过ID查找并移除
 func (dl *DouyinLive) Unsubscribe(id string) {
+	var onRemove func()
+	dl.handlersMu.Lock()
 	for i, h := range dl.eventHandlers {
 		if h.ID == id {
-			dl.eventHandlers = append(dl.eventHandlers[:i], dl.eventHandlers[i+1:]...)
+			// 复制而不是原地修改，避免影响正在遍历旧切片的分发协程
+			handlers := make([]EventHandler, 0, len(dl.eventHandlers)-1)
+			handlers = append(handlers, dl.eventHandlers[:i]...)
+			dl.eventHandlers = append(handlers, dl.eventHandlers[i+1:]...)
+			onRemove = h.onRemove
 			break
 		}
 	}
+	dl.handlersMu.Unlock()
+	// 在锁外调用，回调中可以再次订阅或取消订阅
+	if onRemove != nil {
+		onRemove()
+	}
+}
+
+// onUnsubscribe 设置订阅被取消时的清理函数
+func (dl *DouyinLive) onUnsubscribe(id string, fn func()) {
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	for i, h := range dl.eventHandlers {
+		if h.ID == id {
+			handlers := slices.Clone(dl.eventHandlers)
+			handlers[i].onRemove = fn
+			dl.eventHandlers = handlers
+			return
+		}
+	}
 }
 
 // extractString 辅助函数，从正则匹配中提取字符串
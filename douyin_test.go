@@ -1,6 +1,7 @@
 package douyinLive
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -45,8 +46,9 @@ func TestNewDouyinLive(t *testing.T) {
 		// }
 	})
 
-	d.Start()
-
+	if err := d.Start(); err != nil {
+		t.Logf("启动失败: %v", err)
+	}
 }
 
 func TestNewDouyinLive2(t *testing.T) {
@@ -89,10 +91,19 @@ func TestNewDouyinLive2(t *testing.T) {
 }
 
 func TestConstructWSSURL(t *testing.T) {
+	if defaultSignerName == "none" {
+		t.Skip("没有内置签名实现")
+	}
 	d, err := NewDouyinLive("483379663830", log.Default())
 	if err != nil {
 		t.Fatalf("创建 DouyinLive 实例失败: %v", err)
 	}
-	wssURL := d.makeURL()
+	if err := d.initialize(); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	wssURL, err := d.makeURL(context.Background())
+	if err != nil {
+		t.Fatalf("构建 WSS URL 失败: %v", err)
+	}
 	t.Logf("构建的 WSS URL: %s", wssURL)
 }
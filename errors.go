@@ -0,0 +1,38 @@
+package douyinLive
+
+import "errors"
+
+// 连接过程中的哨兵错误，返回的错误会包装这些值，调用方可以通过 errors.Is 区分失败原因
+var (
+	// ErrRoomNotFound 直播间不存在
+	ErrRoomNotFound = errors.New("直播间不存在")
+	// ErrRoomOffline 直播间存在但未开播
+	ErrRoomOffline = errors.New("直播间未开播")
+	// ErrTTWIDNotFound 响应中没有 ttwid cookie
+	ErrTTWIDNotFound = errors.New("未找到TTWID cookie")
+	// ErrRoomInfoParse 页面结构变化导致无法解析房间信息
+	ErrRoomInfoParse = errors.New("无法解析房间信息")
+	// ErrSignature 签名脚本加载或执行失败
+	ErrSignature = errors.New("签名生成失败")
+	// ErrLiveEnded 收到直播结束的控制消息
+	ErrLiveEnded = errors.New("直播已结束")
+	// ErrConnectionClosed 服务端正常关闭了连接
+	ErrConnectionClosed = errors.New("连接已被服务端关闭")
+	// ErrReconnectFailed 连接异常断开且重连失败
+	ErrReconnectFailed = errors.New("重连失败")
+	// ErrNotAuthenticated 需要登录的操作未提供有效的登录 cookie，见 WithCookies
+	ErrNotAuthenticated = errors.New("未登录或登录已失效")
+	// ErrDuplicateChat 短时间内重复发送相同内容的弹幕，见 ChatGuard
+	ErrDuplicateChat = errors.New("重复的弹幕内容")
+	// ErrSensitiveChat 弹幕命中敏感词，见 ChatGuard
+	ErrSensitiveChat = errors.New("弹幕包含敏感词")
+	// ErrDecodeFailed 已知类型的消息体解码失败，见 OnDeadLetter
+	ErrDecodeFailed = errors.New("消息体解码失败")
+	// ErrHandlerPanic 事件处理器 panic，见 OnDeadLetter
+	ErrHandlerPanic = errors.New("事件处理器 panic")
+	// ErrUnsupportedCompression 消息帧使用了无法解压的压缩方式
+	ErrUnsupportedCompression = errors.New("不支持的压缩方式")
+)
+
+// errManualClose 调用 Close 后读取循环结束的内部标记
+var errManualClose = errors.New("连接被手动关闭")
@@ -0,0 +1,288 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"maps"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/tiga210/douyinLive/generated"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// 事件导出时的标准字段名
+const (
+	FieldTime      = "time"
+	FieldTimeUTC   = "time_utc"
+	FieldTimeLocal = "time_local"
+	FieldRoomID    = "room_id"
+	FieldLiveName  = "live_name"
+	FieldMethod    = "method"
+	FieldMsgID     = "msg_id"
+	FieldUserID    = "user_id"
+	FieldNickname  = "nickname"
+	FieldContent   = "content"
+	FieldData      = "data"
+	FieldTags      = "tags"
+	FieldExt       = "ext"
+)
+
+// LiveEvent 带有房间上下文的直播事件，消息体按需解码并缓存
+type LiveEvent struct {
+	RoomID   string
+	LiveName string
+	Method   string
+	MsgID    uint64
+	Time     time.Time // 本地接收时间
+	Message  *new_douyin.Webcast_Im_Message
+	Tags     map[string]string // 分类器等附加的标签
+	Ext      map[string]any    // 中间件附加的扩展字段，所有 Sink 原样输出，见 SetExt
+
+	topic     string         // 按实例的主题映射得到的主题，见 Topic
+	loc       *time.Location // 实例的时区，见 LocalTime
+	timing    frameTiming    // 下发与接收时间，见 Timing
+	payload   []byte         // 消息体，Message 被 WithMessagePool 回收后仍然有效
+	decoded   protoreflect.ProtoMessage
+	decodeErr error
+	data      map[string]interface{}
+}
+
+// NewLiveEvent 使用原始消息创建事件
+func NewLiveEvent(roomID, liveName string, msg *new_douyin.Webcast_Im_Message) *LiveEvent {
+	return &LiveEvent{
+		RoomID:   roomID,
+		LiveName: liveName,
+		Method:   msg.Method,
+		MsgID:    msg.MsgId,
+		Time:     time.Now(),
+		Message:  msg,
+		payload:  msg.Payload,
+	}
+}
+
+// Clone 复制事件，标签、扩展字段与已解码的消息体为深拷贝（扩展字段只复制一层），原始消息共享
+func (e *LiveEvent) Clone() *LiveEvent {
+	c := &LiveEvent{
+		RoomID:    e.RoomID,
+		LiveName:  e.LiveName,
+		Method:    e.Method,
+		MsgID:     e.MsgID,
+		Time:      e.Time,
+		Message:   e.Message,
+		topic:     e.topic,
+		loc:       e.loc,
+		timing:    e.timing,
+		payload:   e.payload,
+		decodeErr: e.decodeErr,
+	}
+	if e.Tags != nil {
+		c.Tags = make(map[string]string, len(e.Tags))
+		for k, v := range e.Tags {
+			c.Tags[k] = v
+		}
+	}
+	if e.Ext != nil {
+		c.Ext = maps.Clone(e.Ext)
+	}
+	if e.decoded != nil {
+		c.decoded = proto.Clone(e.decoded)
+	}
+	return c
+}
+
+// Payload 返回原始消息体，开启 WithMessagePool 时在回调返回后仍然有效
+func (e *LiveEvent) Payload() []byte {
+	return e.payload
+}
+
+// Decode 解码消息体，结果会被缓存，未知消息类型返回错误
+func (e *LiveEvent) Decode() (protoreflect.ProtoMessage, error) {
+	if e.decoded != nil || e.decodeErr != nil {
+		return e.decoded, e.decodeErr
+	}
+	msg, err := generated.GetMessageInstance(e.Method)
+	if err != nil {
+		e.decodeErr = err
+		return nil, err
+	}
+	if err := proto.Unmarshal(e.payload, msg); err != nil {
+		e.decodeErr = err
+		return nil, err
+	}
+	e.decoded = msg
+	return msg, nil
+}
+
+// Data 返回消息体的 JSON 对象形式
+func (e *LiveEvent) Data() (map[string]interface{}, error) {
+	if e.data != nil {
+		return e.data, nil
+	}
+	msg, err := e.Decode()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	e.data = data
+	return data, nil
+}
+
+// Fields 返回用于导出的扁平字段，消息体放在 data 字段中，无法解码时省略，
+// 标签与扩展字段分别放在 tags 与 ext 中
+func (e *LiveEvent) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		FieldTime:      e.Time,
+		FieldTimeUTC:   e.UTCTime(),
+		FieldTimeLocal: e.LocalTime(),
+		FieldRoomID:    e.RoomID,
+		FieldLiveName:  e.LiveName,
+		FieldMethod:    e.Method,
+		FieldMsgID:     strconv.FormatUint(e.MsgID, 10),
+	}
+	if len(e.Tags) > 0 {
+		fields[FieldTags] = e.Tags
+	}
+	if len(e.Ext) > 0 {
+		fields[FieldExt] = e.Ext
+	}
+	msg, err := e.Decode()
+	if err != nil {
+		return fields
+	}
+	if user := messageField(msg.ProtoReflect(), "user"); user != nil {
+		if id, ok := scalarField(user, "id", protoreflect.Uint64Kind); ok && id.Uint() != 0 {
+			fields[FieldUserID] = strconv.FormatUint(id.Uint(), 10)
+		}
+		if nickname, ok := scalarField(user, "nickname", protoreflect.StringKind); ok && nickname.String() != "" {
+			fields[FieldNickname] = nickname.String()
+		}
+	}
+	if content, ok := scalarField(msg.ProtoReflect(), "content", protoreflect.StringKind); ok {
+		fields[FieldContent] = content.String()
+	}
+	if data, err := e.Data(); err == nil {
+		fields[FieldData] = data
+	}
+	return fields
+}
+
+// SetExt 附加一个扩展字段，如主播所属团队，值需能编码为 JSON
+func (e *LiveEvent) SetExt(key string, value any) {
+	if e.Ext == nil {
+		e.Ext = make(map[string]any)
+	}
+	e.Ext[key] = value
+}
+
+// Content 返回消息中的文本内容（如弹幕），没有 content 字段或无法解码时返回空串
+func (e *LiveEvent) Content() string {
+	msg, err := e.Decode()
+	if err != nil {
+		return ""
+	}
+	if content, ok := scalarField(msg.ProtoReflect(), "content", protoreflect.StringKind); ok {
+		return content.String()
+	}
+	return ""
+}
+
+// UserID 返回消息中用户的 ID，没有 user 字段或无法解码时返回 0
+func (e *LiveEvent) UserID() uint64 {
+	msg, err := e.Decode()
+	if err != nil {
+		return 0
+	}
+	if user := messageField(msg.ProtoReflect(), "user"); user != nil {
+		if id, ok := scalarField(user, "id", protoreflect.Uint64Kind); ok {
+			return id.Uint()
+		}
+	}
+	return 0
+}
+
+// Nickname 返回消息中用户的昵称，没有 user 字段或无法解码时返回空串
+func (e *LiveEvent) Nickname() string {
+	msg, err := e.Decode()
+	if err != nil {
+		return ""
+	}
+	if user := messageField(msg.ProtoReflect(), "user"); user != nil {
+		if nickname, ok := scalarField(user, "nickname", protoreflect.StringKind); ok {
+			return nickname.String()
+		}
+	}
+	return ""
+}
+
+// SetContent 修改已解码消息中的文本内容，消息没有 content 字段时返回 false
+func (e *LiveEvent) SetContent(content string) bool {
+	msg, err := e.Decode()
+	if err != nil {
+		return false
+	}
+	return e.setString(msg.ProtoReflect(), "content", content)
+}
+
+// SetNickname 修改已解码消息中用户的昵称，消息没有 user 字段时返回 false
+func (e *LiveEvent) SetNickname(nickname string) bool {
+	msg, err := e.Decode()
+	if err != nil {
+		return false
+	}
+	user := messageField(msg.ProtoReflect(), "user")
+	if user == nil {
+		return false
+	}
+	return e.setString(user, "nickname", nickname)
+}
+
+// setString 修改字符串字段，并清除 Data 的缓存
+func (e *LiveEvent) setString(m protoreflect.Message, name protoreflect.Name, value string) bool {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return false
+	}
+	m.Set(fd, protoreflect.ValueOfString(value))
+	e.data = nil
+	return true
+}
+
+// SetTag 设置标签
+func (e *LiveEvent) SetTag(key, value string) {
+	if e.Tags == nil {
+		e.Tags = make(map[string]string)
+	}
+	e.Tags[key] = value
+}
+
+// messageField 按名称获取子消息字段，不存在时返回 nil
+func messageField(m protoreflect.Message, name protoreflect.Name) protoreflect.Message {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+		return nil
+	}
+	if !m.Has(fd) {
+		return nil
+	}
+	return m.Get(fd).Message()
+}
+
+// scalarField 按名称和类型获取标量字段
+func scalarField(m protoreflect.Message, name protoreflect.Name, kind protoreflect.Kind) (protoreflect.Value, bool) {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != kind || fd.IsList() {
+		return protoreflect.Value{}, false
+	}
+	return m.Get(fd), true
+}
@@ -0,0 +1,48 @@
+package douyinLive
+
+import "errors"
+
+// 进程退出码，命令行工具与采集服务据此区分失败原因，便于脚本与监控处理
+const (
+	ExitOK               = 0  // 正常结束或手动关闭
+	ExitFailure          = 1  // 其他错误
+	ExitUsage            = 2  // 参数错误
+	ExitRoomNotFound     = 10 // ErrRoomNotFound
+	ExitRoomOffline      = 11 // ErrRoomOffline
+	ExitTTWIDNotFound    = 12 // ErrTTWIDNotFound
+	ExitRoomInfoParse    = 13 // ErrRoomInfoParse
+	ExitSignature        = 14 // ErrSignature
+	ExitNotAuthenticated = 15 // ErrNotAuthenticated
+	ExitLiveEnded        = 20 // ErrLiveEnded
+	ExitConnectionClosed = 21 // ErrConnectionClosed
+	ExitReconnectFailed  = 22 // ErrReconnectFailed
+)
+
+// exitCodes 哨兵错误与退出码的对应关系，按顺序匹配
+var exitCodes = []struct {
+	err  error
+	code int
+}{
+	{ErrRoomNotFound, ExitRoomNotFound},
+	{ErrRoomOffline, ExitRoomOffline},
+	{ErrTTWIDNotFound, ExitTTWIDNotFound},
+	{ErrRoomInfoParse, ExitRoomInfoParse},
+	{ErrSignature, ExitSignature},
+	{ErrNotAuthenticated, ExitNotAuthenticated},
+	{ErrLiveEnded, ExitLiveEnded},
+	{ErrConnectionClosed, ExitConnectionClosed},
+	{ErrReconnectFailed, ExitReconnectFailed},
+}
+
+// ExitCode 返回错误对应的进程退出码，nil 时为 ExitOK，未识别的错误为 ExitFailure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	for _, c := range exitCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ExitFailure
+}
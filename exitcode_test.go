@@ -0,0 +1,21 @@
+package douyinLive
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := map[error]int{
+		nil: ExitOK,
+		fmt.Errorf("检查直播状态失败: %w", ErrRoomOffline):                ExitRoomOffline,
+		fmt.Errorf("%w: %w", ErrReconnectFailed, errors.New("x")): ExitReconnectFailed,
+		errors.New("其他"): ExitFailure,
+	}
+	for err, want := range cases {
+		if got := ExitCode(err); got != want {
+			t.Fatalf("ExitCode(%v) = %d, want %d", err, got, want)
+		}
+	}
+}
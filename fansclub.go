@@ -0,0 +1,129 @@
+package douyinLive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// WebcastFansclubStatisticsMessage 粉丝团人数变化
+const WebcastFansclubStatisticsMessage = "WebcastFansclubStatisticsMessage"
+
+// 粉丝团事件类型，见 FansclubEvent.Type
+const (
+	FansclubJoin    = "join"    // 加入粉丝团
+	FansclubUpgrade = "upgrade" // 亲密度提升导致粉丝团升级
+	FansclubTask    = "task"    // 完成粉丝团任务
+	FansclubGift    = "gift"    // 赠送粉丝团专属礼物
+	FansclubCount   = "count"   // 粉丝团总人数变化
+	FansclubOther   = "other"   // 其他粉丝团通知
+)
+
+// FansclubMessage.Action 的取值
+const (
+	fansclubActionUpgrade = 1
+	fansclubActionJoin    = 2
+)
+
+// FansclubEvent 粉丝团相关的结构化事件
+type FansclubEvent struct {
+	Type      string
+	RoomID    string
+	Time      time.Time
+	MsgID     uint64
+	UserID    uint64
+	Nickname  string
+	ClubName  string // 粉丝团名称
+	Level     int    // 用户当前的粉丝团等级
+	Content   string // 消息原文，如 "恭喜 xx 成为第 n 位粉丝团成员"
+	Privilege string // 升级后解锁的权益说明
+	GiftID    uint64 // 专属礼物的 ID，仅 FansclubGift
+	GiftName  string
+	MinLevel  int    // 专属礼物要求的粉丝团等级，仅 FansclubGift
+	FansCount uint64 // 粉丝团总人数，仅 FansclubCount
+}
+
+// DecodeFansclub 把粉丝团消息、粉丝团人数变化与专属礼物解码为粉丝团事件，
+// 其他消息（包括普通礼物）返回错误
+func (dl *DouyinLive) DecodeFansclub(event *LiveEvent) (*FansclubEvent, error) {
+	switch event.Method {
+	case WebcastFansclubMessage, WebcastFansclubStatisticsMessage, WebcastGiftMessage:
+	default:
+		return nil, fmt.Errorf("不是粉丝团消息: %s", event.Method)
+	}
+	decoded, err := event.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	fe := &FansclubEvent{RoomID: event.RoomID, Time: event.Time, MsgID: event.MsgID}
+	switch msg := decoded.(type) {
+	case *new_douyin.Webcast_Im_FansclubMessage:
+		fe.Content = msg.Content
+		fe.setUser(msg.User)
+		switch {
+		case msg.Action == fansclubActionJoin:
+			fe.Type = FansclubJoin
+		case msg.Action == fansclubActionUpgrade:
+			fe.Type = FansclubUpgrade
+		case strings.Contains(msg.Content, "任务"):
+			fe.Type = FansclubTask
+		default:
+			fe.Type = FansclubOther
+		}
+		if p := msg.UpgradePrivilege; p != nil {
+			fe.Privilege = strings.TrimSpace(p.Content + " " + p.Description)
+		}
+	case *new_douyin.Webcast_Im_FansclubStatisticsMessage:
+		fe.Type = FansclubCount
+		fe.ClubName = msg.Name
+		fe.FansCount = msg.FansCount
+	case *new_douyin.Webcast_Im_GiftMessage:
+		gift := msg.Gift
+		if gift == nil || !(gift.ForFansclub || gift.FansclubInfo.GetMinLevel() > 0) {
+			return nil, fmt.Errorf("不是粉丝团专属礼物: %d", msg.GiftId)
+		}
+		fe.Type = FansclubGift
+		fe.setUser(msg.User)
+		fe.GiftID = gift.Id
+		fe.GiftName = gift.Name
+		fe.MinLevel = int(gift.FansclubInfo.GetMinLevel())
+	default:
+		return nil, fmt.Errorf("粉丝团消息类型不匹配: %T", decoded)
+	}
+	return fe, nil
+}
+
+// setUser 填充用户与其粉丝团信息
+func (fe *FansclubEvent) setUser(user *new_douyin.Webcast_Data_User) {
+	if user == nil {
+		return
+	}
+	fe.UserID = user.Id
+	fe.Nickname = user.Nickname
+	if data := user.GetFansClub().GetData(); data != nil {
+		fe.ClubName = data.ClubName
+		fe.Level = int(data.Level)
+	}
+}
+
+// SubscribeFansclub 订阅粉丝团事件：入团、升级、任务完成、专属礼物与粉丝团人数变化
+func (dl *DouyinLive) SubscribeFansclub(handler func(*FansclubEvent)) string {
+	return dl.SubscribeEvent(func(event *LiveEvent) {
+		switch event.Method {
+		case WebcastFansclubMessage, WebcastFansclubStatisticsMessage, WebcastGiftMessage:
+		default:
+			return
+		}
+		fe, err := dl.DecodeFansclub(event)
+		if err != nil {
+			if event.Method != WebcastGiftMessage {
+				dl.log().Warn("解析粉丝团消息失败", "error", err)
+			}
+			return
+		}
+		handler(fe)
+	})
+}
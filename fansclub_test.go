@@ -0,0 +1,56 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestSubscribeFansclub(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil)
+	var events []*FansclubEvent
+	dl.SubscribeFansclub(func(e *FansclubEvent) { events = append(events, e) })
+
+	member := &new_douyin.Webcast_Data_User{
+		Id:       7,
+		Nickname: "小明",
+		FansClub: &new_douyin.Webcast_Data_User_FansClub{Data: &new_douyin.Webcast_Data_User_FansClub_FansClubData{ClubName: "星星团", Level: 5}},
+	}
+	for _, msg := range []*new_douyin.Webcast_Im_Message{
+		statsMessage(t, WebcastFansclubMessage, &new_douyin.Webcast_Im_FansclubMessage{Action: 2, Content: "欢迎加入粉丝团", User: member}),
+		statsMessage(t, WebcastFansclubMessage, &new_douyin.Webcast_Im_FansclubMessage{
+			Action: 1, User: member,
+			UpgradePrivilege: &new_douyin.Webcast_Im_FansclubMessage_UpgradePrivilege{Content: "解锁", Description: "专属表情"},
+		}),
+		statsMessage(t, WebcastFansclubMessage, &new_douyin.Webcast_Im_FansclubMessage{Action: 3, Content: "完成了今日粉丝团任务", User: member}),
+		statsMessage(t, WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{User: member, Gift: &new_douyin.Webcast_Data_GiftStruct{Id: 1, Name: "小心心"}}),
+		statsMessage(t, WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{User: member, Gift: &new_douyin.Webcast_Data_GiftStruct{
+			Id: 2, Name: "粉丝团灯牌", FansclubInfo: &new_douyin.Webcast_Data_GiftStruct_GiftStructFansClubInfo{MinLevel: 3},
+		}}),
+		statsMessage(t, WebcastFansclubStatisticsMessage, &new_douyin.Webcast_Im_FansclubStatisticsMessage{Name: "星星团", FansCount: 1024}),
+	} {
+		dl.deliver(msg)
+	}
+
+	want := []string{FansclubJoin, FansclubUpgrade, FansclubTask, FansclubGift, FansclubCount}
+	if len(events) != len(want) {
+		t.Fatalf("收到 %d 个粉丝团事件, want %d", len(events), len(want))
+	}
+	for i, typ := range want {
+		if events[i].Type != typ {
+			t.Fatalf("第 %d 个事件类型 = %s, want %s", i, events[i].Type, typ)
+		}
+	}
+	if e := events[0]; e.UserID != 7 || e.ClubName != "星星团" || e.Level != 5 {
+		t.Fatalf("入团事件 = %+v", e)
+	}
+	if events[1].Privilege != "解锁 专属表情" {
+		t.Fatalf("Privilege = %q", events[1].Privilege)
+	}
+	if e := events[3]; e.GiftName != "粉丝团灯牌" || e.MinLevel != 3 {
+		t.Fatalf("专属礼物事件 = %+v", e)
+	}
+	if events[4].FansCount != 1024 {
+		t.Fatalf("FansCount = %d", events[4].FansCount)
+	}
+}
@@ -0,0 +1,54 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithFastStart 开启快速接入：已知 roomID/pushID 时 Start 跳过页面解析，按 roomID 查询开播状态后直接握手，
+// 未开播或握手失败时回退到完整流程。roomID/pushID 为空时沿用上一次完整流程解析出的值，
+// 适合重复连接已知正在直播的房间
+func WithFastStart(roomID, pushID string) Option {
+	return func(dl *DouyinLive) {
+		dl.fastStart = true
+		if roomID != "" && pushID != "" {
+			dl.roomID, dl.pushID = roomID, pushID
+		}
+	}
+}
+
+// canFastStart 判断本次连接能否走快速接入
+func (dl *DouyinLive) canFastStart() bool {
+	return dl.fastStart && dl.roomID != "" && dl.pushID != ""
+}
+
+// fastConnect 确认开播后凭已知的 roomID/pushID 直接握手，缺少 ttwid 时先获取，
+// 握手部分与 Start2 相同
+func (dl *DouyinLive) fastConnect(ctx context.Context) error {
+	if err := dl.checkRoomStatus(ctx); err != nil {
+		return err
+	}
+	if dl.ttwid == "" {
+		if err := dl.fetchTTWID(ctx); err != nil {
+			return fmt.Errorf("获取ttwid失败: %w", err)
+		}
+	}
+	return dl.dial(ctx)
+}
+
+// checkRoomStatus 按 roomID 查询开播状态，比解析直播间页面轻量，未开播时返回 ErrRoomOffline
+func (dl *DouyinLive) checkRoomStatus(ctx context.Context) error {
+	result, err := dl.reflowInfo(ctx, map[string]string{"room_id": dl.roomID})
+	if err != nil {
+		return err
+	}
+	status := result.Get("data.room.status")
+	if !status.Exists() {
+		return fmt.Errorf("%w: 接口中缺少直播状态", ErrRoomInfoParse)
+	}
+	dl.setLiveStatus(status.Int() == 2)
+	if !dl.isLiving.Load() {
+		return fmt.Errorf("%w: status=%d", ErrRoomOffline, status.Int())
+	}
+	return nil
+}
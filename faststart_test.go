@@ -0,0 +1,75 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestFastConnect(t *testing.T) {
+	var status atomic.Int32
+	var dials atomic.Int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			dials.Add(1)
+			if conn, err := upgrader.Upgrade(w, r, nil); err == nil {
+				conn.Close()
+			}
+			return
+		}
+		if r.URL.Query().Get("room_id") != "7380" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"room":{"status":%d}}}`, status.Load())
+	}))
+	defer srv.Close()
+	old := reflowInfoURL
+	reflowInfoURL = srv.URL + "/webcast/room/reflow/info/"
+	defer func() { reflowInfoURL = old }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	newLive := func() *DouyinLive {
+		dl, err := NewDouyinLive("1", nil,
+			WithFastStart("7380", "9"),
+			WithCookieString("ttwid=t"),
+			WithTLSConfig(&tls.Config{RootCAs: roots}),
+			WithPushHosts(strings.TrimPrefix(srv.URL, "https://")),
+			WithSigner(SignerFunc(func(context.Context, SignRequest) (string, error) { return "sig", nil })))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dl
+	}
+
+	// 未开播时不握手，也不标记为直播中
+	status.Store(4)
+	dl := newLive()
+	if err := dl.fastConnect(context.Background()); !errors.Is(err, ErrRoomOffline) {
+		t.Fatalf("未开播时 err = %v", err)
+	}
+	if dl.isLiving.Load() || dials.Load() != 0 {
+		t.Fatalf("未开播时 isLiving = %v, dials = %d", dl.isLiving.Load(), dials.Load())
+	}
+
+	status.Store(2)
+	dl = newLive()
+	if err := dl.fastConnect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !dl.isLiving.Load() || dials.Load() != 1 || dl.currentConn() == nil {
+		t.Fatalf("开播时 isLiving = %v, dials = %d", dl.isLiving.Load(), dials.Load())
+	}
+	dl.Close()
+}
@@ -0,0 +1,138 @@
+package douyinLive
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// FeaturesEnv 通过环境变量切换特性，逗号分隔，前缀 - 表示关闭，如 DOUYINLIVE_FEATURES=zstd,-http_polling
+const FeaturesEnv = "DOUYINLIVE_FEATURES"
+
+// Feature 实验性特性的名称
+type Feature string
+
+// 已知的实验性特性
+const (
+	FeatureHTTPPolling Feature = "http_polling" // WebSocket 不可用时降级为 HTTP 轮询
+	FeatureZstd        Feature = "zstd"         // 未指定 WithCompression 时请求 zstd 压缩的推送数据
+)
+
+// FeatureInfo 特性说明
+type FeatureInfo struct {
+	Name        Feature
+	Description string
+	Default     bool
+}
+
+// knownFeatures 已知特性及默认值，新协议路径先以关闭状态加入，灰度稳定后再改默认值
+var knownFeatures = []FeatureInfo{
+	{Name: FeatureHTTPPolling, Description: "WebSocket 不可用时降级为 HTTP 轮询"},
+	{Name: FeatureZstd, Description: "未指定 WithCompression 时请求 zstd 压缩的推送数据"},
+}
+
+// KnownFeatures 返回已知特性及默认值
+func KnownFeatures() []FeatureInfo {
+	return append([]FeatureInfo(nil), knownFeatures...)
+}
+
+// ParseFeatures 解析 "a,-b" 形式的特性开关，前缀 - 表示关闭，+ 或无前缀表示开启
+func ParseFeatures(spec string) map[Feature]bool {
+	out := make(map[Feature]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		switch {
+		case item == "":
+		case strings.HasPrefix(item, "-"):
+			out[Feature(item[1:])] = false
+		default:
+			out[Feature(strings.TrimPrefix(item, "+"))] = true
+		}
+	}
+	return out
+}
+
+// featureFlags 实例的特性开关，未显式设置的特性使用默认值
+type featureFlags struct {
+	mu     sync.RWMutex
+	values map[Feature]bool
+}
+
+// set 设置开关
+func (f *featureFlags) set(values map[Feature]bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.values == nil {
+		f.values = make(map[Feature]bool)
+	}
+	for name, enabled := range values {
+		f.values[name] = enabled
+	}
+}
+
+// enabled 返回特性是否开启
+func (f *featureFlags) enabled(name Feature) bool {
+	f.mu.RLock()
+	enabled, ok := f.values[name]
+	f.mu.RUnlock()
+	if ok {
+		return enabled
+	}
+	for _, info := range knownFeatures {
+		if info.Name == name {
+			return info.Default
+		}
+	}
+	return false
+}
+
+// snapshot 返回全部已知特性与显式设置的特性的当前值
+func (f *featureFlags) snapshot() map[Feature]bool {
+	out := make(map[Feature]bool, len(knownFeatures))
+	for _, info := range knownFeatures {
+		out[info.Name] = info.Default
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for name, enabled := range f.values {
+		out[name] = enabled
+	}
+	return out
+}
+
+// applyFeaturesEnv 读取 FeaturesEnv，在可选项之前应用，可被 WithFeature 覆盖
+func (dl *DouyinLive) applyFeaturesEnv() {
+	if spec := os.Getenv(FeaturesEnv); spec != "" {
+		dl.features.set(ParseFeatures(spec))
+	}
+}
+
+// WithFeature 开启或关闭特性，优先于环境变量
+func WithFeature(name Feature, enabled bool) Option {
+	return func(dl *DouyinLive) {
+		dl.features.set(map[Feature]bool{name: enabled})
+	}
+}
+
+// WithFeatures 按 ParseFeatures 的格式批量设置特性，便于从配置文件读取
+func WithFeatures(spec string) Option {
+	return func(dl *DouyinLive) {
+		dl.features.set(ParseFeatures(spec))
+	}
+}
+
+// FeatureEnabled 返回特性是否开启
+func (dl *DouyinLive) FeatureEnabled(name Feature) bool {
+	return dl.features.enabled(name)
+}
+
+// SetFeature 运行时切换特性，连接级的特性在下次连接时生效
+func (dl *DouyinLive) SetFeature(name Feature, enabled bool) {
+	dl.features.set(map[Feature]bool{name: enabled})
+	dl.log().Info("切换特性", "feature", name, "enabled", enabled)
+}
+
+// Features 返回全部特性的当前值
+func (dl *DouyinLive) Features() map[Feature]bool {
+	return dl.features.snapshot()
+}
@@ -0,0 +1,24 @@
+package douyinLive
+
+import "testing"
+
+func TestFeatureFlags(t *testing.T) {
+	t.Setenv(FeaturesEnv, "zstd, http_polling, +custom, -other")
+	dl, _ := NewDouyinLive("1", nil, WithFeature(FeatureHTTPPolling, false))
+
+	if !dl.FeatureEnabled(FeatureZstd) || !dl.FeatureEnabled("custom") {
+		t.Fatal("环境变量开启的特性未生效")
+	}
+	if dl.Compression() != CompressZstd {
+		t.Fatalf("开启 zstd 特性后 Compression = %s", dl.Compression())
+	}
+	if dl.FeatureEnabled(FeatureHTTPPolling) {
+		t.Fatal("WithFeature 应覆盖环境变量")
+	}
+
+	dl.SetFeature(FeatureHTTPPolling, true)
+	features := dl.Stats().Features
+	if !features[FeatureHTTPPolling] || features["other"] || !features["custom"] {
+		t.Fatalf("Stats 中的特性 = %v", features)
+	}
+}
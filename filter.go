@@ -0,0 +1,156 @@
+package douyinLive
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// Filter 声明式的事件过滤条件，不同条件之间为"且"，同一条件的多个取值之间为"或"。
+// 消息类型在解码之前判断，不匹配的消息不会被解码。构建完成后只读，可并发使用
+type Filter struct {
+	methods       map[string]bool
+	users         map[uint64]bool
+	nicknames     map[string]bool
+	minFansLevel  int
+	keywords      []string
+	patterns      []*regexp.Regexp
+	needsDecoding bool
+}
+
+// NewFilter 创建不含任何条件（匹配全部事件）的过滤器
+func NewFilter() *Filter {
+	return &Filter{}
+}
+
+// Methods 只匹配这些消息类型
+func (f *Filter) Methods(methods ...string) *Filter {
+	if f.methods == nil {
+		f.methods = make(map[string]bool, len(methods))
+	}
+	for _, m := range methods {
+		f.methods[m] = true
+	}
+	return f
+}
+
+// Users 只匹配这些用户 ID 的消息
+func (f *Filter) Users(ids ...uint64) *Filter {
+	if f.users == nil {
+		f.users = make(map[uint64]bool, len(ids))
+	}
+	for _, id := range ids {
+		f.users[id] = true
+	}
+	f.needsDecoding = true
+	return f
+}
+
+// Nicknames 只匹配这些昵称的用户的消息
+func (f *Filter) Nicknames(names ...string) *Filter {
+	if f.nicknames == nil {
+		f.nicknames = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		f.nicknames[name] = true
+	}
+	f.needsDecoding = true
+	return f
+}
+
+// MinFansclubLevel 只匹配粉丝团等级不低于 level 的用户的消息
+func (f *Filter) MinFansclubLevel(level int) *Filter {
+	f.minFansLevel = level
+	f.needsDecoding = true
+	return f
+}
+
+// Keywords 只匹配文本内容（如弹幕）包含任一关键词的消息
+func (f *Filter) Keywords(words ...string) *Filter {
+	f.keywords = append(f.keywords, words...)
+	f.needsDecoding = true
+	return f
+}
+
+// Regexp 只匹配文本内容满足任一正则表达式的消息
+func (f *Filter) Regexp(patterns ...*regexp.Regexp) *Filter {
+	f.patterns = append(f.patterns, patterns...)
+	f.needsDecoding = true
+	return f
+}
+
+// Match 事件是否满足全部条件，需要解码的条件在消息无法解码时视为不满足
+func (f *Filter) Match(event *LiveEvent) bool {
+	if f.methods != nil && !f.methods[event.Method] {
+		return false
+	}
+	if !f.needsDecoding {
+		return true
+	}
+	msg, err := event.Decode()
+	if err != nil {
+		return false
+	}
+	if f.users != nil || f.nicknames != nil || f.minFansLevel > 0 {
+		field := messageField(msg.ProtoReflect(), "user")
+		if field == nil {
+			return false
+		}
+		user, ok := field.Interface().(*new_douyin.Webcast_Data_User)
+		if !ok {
+			return false
+		}
+		if f.users != nil && !f.users[user.Id] {
+			return false
+		}
+		if f.nicknames != nil && !f.nicknames[user.Nickname] {
+			return false
+		}
+		if f.minFansLevel > 0 && int(user.GetFansClub().GetData().GetLevel()) < f.minFansLevel {
+			return false
+		}
+	}
+	if len(f.keywords) > 0 || len(f.patterns) > 0 {
+		return f.matchContent(event.Content())
+	}
+	return true
+}
+
+// matchContent 文本内容是否包含任一关键词或满足任一正则表达式
+func (f *Filter) matchContent(content string) bool {
+	if content == "" {
+		return false
+	}
+	for _, word := range f.keywords {
+		if strings.Contains(content, word) {
+			return true
+		}
+	}
+	for _, re := range f.patterns {
+		if re.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware 把过滤器作为中间件，不匹配的事件跳过后续处理器，见 WithMiddleware
+func (f *Filter) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(event *LiveEvent) {
+			if f.Match(event) {
+				next(event)
+			}
+		}
+	}
+}
+
+// SubscribeFiltered 订阅满足过滤条件的事件，handler 只在匹配时调用
+func (dl *DouyinLive) SubscribeFiltered(f *Filter, handler func(*LiveEvent)) string {
+	return dl.SubscribeEvent(func(event *LiveEvent) {
+		if f.Match(event) {
+			handler(event)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package douyinLive
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestFilter(t *testing.T) {
+	fan := &new_douyin.Webcast_Data_User{
+		Id: 42, Nickname: "老粉",
+		FansClub: &new_douyin.Webcast_Data_User_FansClub{Data: &new_douyin.Webcast_Data_User_FansClub_FansClubData{Level: 5}},
+	}
+	chat := func(user *new_douyin.Webcast_Data_User, content string) *LiveEvent {
+		return NewLiveEvent("1", "", statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: content}))
+	}
+
+	f := NewFilter().Methods(WebcastChatMessage).MinFansclubLevel(3).Keywords("抽奖").Regexp(regexp.MustCompile(`^\d+$`))
+	cases := []struct {
+		event *LiveEvent
+		want  bool
+	}{
+		{chat(fan, "来抽奖了"), true},
+		{chat(fan, "666"), true},
+		{chat(fan, "你好"), false},
+		{chat(&new_douyin.Webcast_Data_User{Id: 1}, "抽奖"), false},
+	}
+	for i, c := range cases {
+		if got := f.Match(c.event); got != c.want {
+			t.Errorf("case %d: Match = %v, want %v", i, got, c.want)
+		}
+	}
+
+	like := NewLiveEvent("1", "", statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{User: fan}))
+	if f.Match(like) || like.decoded != nil {
+		t.Fatal("消息类型不匹配时不应解码")
+	}
+	if !NewFilter().Users(42).Nicknames("老粉").Match(like) || NewFilter().Users(7).Match(like) {
+		t.Fatal("用户条件匹配错误")
+	}
+
+	dl, _ := NewDouyinLive("1", nil)
+	var got int
+	dl.SubscribeFiltered(NewFilter().Keywords("抽奖"), func(*LiveEvent) { got++ })
+	dl.deliver(chat(fan, "抽奖").Message)
+	dl.deliver(chat(fan, "你好").Message)
+	if got != 1 {
+		t.Fatalf("SubscribeFiltered 收到 %d 条, want 1", got)
+	}
+}
@@ -0,0 +1,33 @@
+package douyinLive
+
+import (
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// SubscribeFrame 订阅 WebSocket 收到的原始 PushFrame，在 gzip 解压与 Response 解析之前回调，
+// 用于归档原始流量、自行解码或排查协议变化；回放 ReplayFrames 时同样回调，HTTP 轮询没有 PushFrame。
+// frame 在回调返回后会被复用，需要保留时请 proto.Clone；raw 为该帧的 protobuf 字节，不会被复用。
+// 回调在读取循环中同步执行，返回的 ID 可用于 Unsubscribe
+func (dl *DouyinLive) SubscribeFrame(handler func(frame *new_douyin.Webcast_Im_PushFrame, raw []byte)) string {
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:           id,
+		FrameHandler: handler,
+	})
+	return id
+}
+
+// emitFrame 通知 SubscribeFrame 订阅者
+func (dl *DouyinLive) emitFrame(frame *new_douyin.Webcast_Im_PushFrame, raw []byte) {
+	dl.handlersMu.RLock()
+	handlers := dl.eventHandlers
+	dl.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		if handler.FrameHandler != nil {
+			_ = dl.callHandler(func() { handler.FrameHandler(frame, raw) })
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package game 弹幕互动游戏的基础组件：玩家注册、指令映射、积分账本与排行榜，
+// 游戏逻辑只需处理解析好的指令与积分变动
+package game
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// Player 玩家
+type Player struct {
+	UserID   uint64
+	Nickname string
+	JoinedAt time.Time
+	LastSeen time.Time
+}
+
+// Command 从弹幕解析出的指令
+type Command struct {
+	Name    string   // 指令名，别名已映射为注册时的名称
+	Args    []string // 指令名之后按空白切分的参数
+	Content string   // 原始弹幕
+	Player  Player
+	Time    time.Time
+}
+
+// CommandFunc 指令处理函数
+type CommandFunc func(g *Game, cmd Command)
+
+// Standing 排行榜中的一项
+type Standing struct {
+	Rank   int
+	Player Player
+	Points int64
+}
+
+// Options 游戏配置
+type Options struct {
+	// JoinCommand 加入游戏的指令，如 "加入"；为空时玩家发出第一条弹幕即自动注册。
+	// 设置后未注册用户的其他指令、点赞与礼物均被忽略
+	JoinCommand      string
+	PointsPerDiamond int64         // 礼物每抖币计入的积分，0 表示不计
+	PointsPerLike    int64         // 每个点赞计入的积分，0 表示不计
+	ComboTimeout     time.Duration // 礼物连击合并的超时，连击结束后一次性计分
+	OnJoin           func(Player)  // 新玩家注册时回调
+	OnUnknown        func(Command) // 已注册玩家发出未映射的弹幕时回调，可用于自由聊天
+}
+
+// Game 弹幕互动游戏
+type Game struct {
+	opts   Options
+	ledger *Ledger
+
+	mu       sync.RWMutex
+	players  map[uint64]*Player
+	commands map[string]CommandFunc
+	aliases  map[string]string
+}
+
+// New 创建游戏
+func New(opts Options) *Game {
+	g := &Game{
+		opts:     opts,
+		ledger:   NewLedger(0),
+		players:  make(map[uint64]*Player),
+		commands: make(map[string]CommandFunc),
+		aliases:  make(map[string]string),
+	}
+	if opts.JoinCommand != "" {
+		// 注册在 Observe 中完成，这里只保证指令被识别
+		g.Handle(opts.JoinCommand, func(*Game, Command) {})
+	}
+	return g
+}
+
+// Handle 注册指令，匹配弹幕的第一个词，不区分大小写；aliases 为别名，如 "1" 映射到 "左"
+func (g *Game) Handle(name string, fn CommandFunc, aliases ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := strings.ToLower(name)
+	g.commands[key] = fn
+	g.aliases[key] = name
+	for _, alias := range aliases {
+		g.aliases[strings.ToLower(alias)] = name
+	}
+}
+
+// Ledger 返回积分账本
+func (g *Game) Ledger() *Ledger {
+	return g.ledger
+}
+
+// Watch 监听直播间的弹幕、点赞与礼物，返回取消监听的函数
+func (g *Game) Watch(dl *douyinLive.DouyinLive) (unwatch func()) {
+	events := dl.SubscribeEvent(g.Observe)
+	gifts := dl.SubscribeGiftCombo(g.opts.ComboTimeout, g.ObserveGift)
+	return func() {
+		dl.Unsubscribe(events)
+		dl.Unsubscribe(gifts)
+	}
+}
+
+// Observe 处理弹幕与点赞事件，其他消息忽略
+func (g *Game) Observe(event *douyinLive.LiveEvent) {
+	decoded, err := event.Decode()
+	if err != nil {
+		return
+	}
+	switch msg := decoded.(type) {
+	case *new_douyin.Webcast_Im_ChatMessage:
+		if msg.User != nil {
+			g.chat(msg.User.Id, msg.User.Nickname, msg.Content, event.Time)
+		}
+	case *new_douyin.Webcast_Im_LikeMessage:
+		if msg.User != nil && g.opts.PointsPerLike != 0 {
+			if _, ok := g.touch(msg.User.Id, msg.User.Nickname, event.Time, false); ok {
+				g.ledger.Add(msg.User.Id, int64(msg.Count)*g.opts.PointsPerLike, "点赞")
+			}
+		}
+	}
+}
+
+// ObserveGift 按礼物总价值计分，连击礼物应先经过 GiftAggregator 合并
+func (g *Game) ObserveGift(gift *douyinLive.GiftEvent) {
+	if g.opts.PointsPerDiamond == 0 {
+		return
+	}
+	if _, ok := g.touch(gift.UserID, gift.Nickname, gift.Time, false); ok {
+		g.ledger.Add(gift.UserID, gift.TotalDiamond()*g.opts.PointsPerDiamond, "礼物:"+gift.GiftName)
+	}
+}
+
+// chat 注册玩家并分发指令
+func (g *Game) chat(userID uint64, nickname, content string, at time.Time) {
+	fields := strings.Fields(content)
+	var name string
+	var fn CommandFunc
+	if len(fields) > 0 {
+		g.mu.RLock()
+		name = g.aliases[strings.ToLower(fields[0])]
+		fn = g.commands[strings.ToLower(name)]
+		g.mu.RUnlock()
+	}
+
+	join := g.opts.JoinCommand == "" || (fn != nil && strings.EqualFold(name, g.opts.JoinCommand))
+	player, ok := g.touch(userID, nickname, at, join)
+	if !ok {
+		return
+	}
+	cmd := Command{Name: name, Content: content, Player: player, Time: at}
+	if len(fields) > 1 {
+		cmd.Args = fields[1:]
+	}
+	switch {
+	case fn != nil:
+		fn(g, cmd)
+	case g.opts.OnUnknown != nil:
+		cmd.Name = ""
+		g.opts.OnUnknown(cmd)
+	}
+}
+
+// touch 更新玩家的昵称与活跃时间，join 为 true 时注册新玩家；返回玩家是否已注册
+func (g *Game) touch(userID uint64, nickname string, at time.Time, join bool) (Player, bool) {
+	if userID == 0 {
+		return Player{}, false
+	}
+	if g.opts.JoinCommand == "" {
+		join = true
+	}
+	g.mu.Lock()
+	p, ok := g.players[userID]
+	if !ok && !join {
+		g.mu.Unlock()
+		return Player{}, false
+	}
+	if !ok {
+		p = &Player{UserID: userID, JoinedAt: at}
+		g.players[userID] = p
+	}
+	if nickname != "" {
+		p.Nickname = nickname
+	}
+	p.LastSeen = at
+	player := *p
+	g.mu.Unlock()
+
+	if !ok && g.opts.OnJoin != nil {
+		g.opts.OnJoin(player)
+	}
+	return player, true
+}
+
+// Player 按用户 ID 查询玩家
+func (g *Game) Player(userID uint64) (Player, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	p, ok := g.players[userID]
+	if !ok {
+		return Player{}, false
+	}
+	return *p, true
+}
+
+// Players 返回全部玩家，按注册时间先后排列
+func (g *Game) Players() []Player {
+	g.mu.RLock()
+	players := make([]Player, 0, len(g.players))
+	for _, p := range g.players {
+		players = append(players, *p)
+	}
+	g.mu.RUnlock()
+	sort.Slice(players, func(i, j int) bool {
+		if !players[i].JoinedAt.Equal(players[j].JoinedAt) {
+			return players[i].JoinedAt.Before(players[j].JoinedAt)
+		}
+		return players[i].UserID < players[j].UserID
+	})
+	return players
+}
+
+// Leaderboard 返回积分前 n 名，n<=0 时返回全部；积分相同的按注册先后排名并列
+func (g *Game) Leaderboard(n int) []Standing {
+	balances := g.ledger.Balances()
+	players := g.Players()
+	standings := make([]Standing, 0, len(players))
+	for _, p := range players {
+		standings = append(standings, Standing{Player: p, Points: balances[p.UserID]})
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Points > standings[j].Points
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Points == standings[i-1].Points {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	if n > 0 && len(standings) > n {
+		standings = standings[:n]
+	}
+	return standings
+}
+
+// Reset 开始新的一局：清空积分，可选择同时清空玩家
+func (g *Game) Reset(players bool) {
+	g.ledger.Reset()
+	if players {
+		g.mu.Lock()
+		g.players = make(map[uint64]*Player)
+		g.mu.Unlock()
+	}
+}
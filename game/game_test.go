@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func chat(t *testing.T, userID uint64, nickname, content string) *douyinLive.LiveEvent {
+	t.Helper()
+	payload, err := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{
+		Content: content,
+		User:    &new_douyin.Webcast_Data_User{Id: userID, Nickname: nickname},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return douyinLive.NewLiveEvent("1", "", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage, Payload: payload})
+}
+
+func TestGameCommands(t *testing.T) {
+	var joined []string
+	var moves []Command
+	g := New(Options{
+		JoinCommand:      "加入",
+		PointsPerDiamond: 10,
+		OnJoin:           func(p Player) { joined = append(joined, p.Nickname) },
+	})
+	g.Handle("左", func(g *Game, cmd Command) {
+		moves = append(moves, cmd)
+		g.Ledger().Add(cmd.Player.UserID, 1, "移动")
+	}, "1", "L")
+
+	g.Observe(chat(t, 1, "小明", "1")) // 未加入，忽略
+	g.Observe(chat(t, 1, "小明", "加入"))
+	g.Observe(chat(t, 2, "小红", "加入"))
+	g.Observe(chat(t, 1, "小明", "l 3"))
+	g.Observe(chat(t, 2, "小红", "左"))
+	g.ObserveGift(&douyinLive.GiftEvent{UserID: 2, DiamondCount: 1, Count: 5, Time: time.Now()})
+	g.ObserveGift(&douyinLive.GiftEvent{UserID: 3, DiamondCount: 1, Count: 5, Time: time.Now()}) // 未加入
+
+	if len(joined) != 2 || len(moves) != 2 {
+		t.Fatalf("joined=%v moves=%d", joined, len(moves))
+	}
+	if moves[0].Name != "左" || len(moves[0].Args) != 1 || moves[0].Args[0] != "3" {
+		t.Fatalf("指令解析错误: %+v", moves[0])
+	}
+
+	board := g.Leaderboard(0)
+	if len(board) != 2 || board[0].Player.Nickname != "小红" || board[0].Points != 51 || board[1].Points != 1 {
+		t.Fatalf("排行榜 = %+v", board)
+	}
+	if !g.Ledger().Spend(1, 1, "兑换") || g.Ledger().Spend(1, 1, "兑换") {
+		t.Fatal("余额不足时不应扣除")
+	}
+}
+
+func TestGameAutoJoinAndTies(t *testing.T) {
+	g := New(Options{})
+	g.Observe(chat(t, 1, "a", "你好"))
+	g.Observe(chat(t, 2, "b", "你好"))
+	if len(g.Players()) != 2 {
+		t.Fatalf("首次弹幕应自动注册: %+v", g.Players())
+	}
+	board := g.Leaderboard(1)
+	if len(board) != 1 || board[0].Rank != 1 || board[0].Player.UserID != 1 {
+		t.Fatalf("积分相同应按注册先后排列: %+v", board)
+	}
+	if all := g.Leaderboard(0); all[1].Rank != 1 {
+		t.Fatalf("积分相同应并列: %+v", all)
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultMaxEntries 账本默认保留的流水条数
+const defaultMaxEntries = 10000
+
+// Entry 一条积分流水
+type Entry struct {
+	UserID  uint64
+	Delta   int64
+	Balance int64 // 变动后的余额
+	Reason  string
+	Time    time.Time
+}
+
+// Ledger 积分账本，记录余额与最近的流水，可并发使用
+type Ledger struct {
+	mu         sync.Mutex
+	balances   map[uint64]int64
+	entries    []Entry
+	maxEntries int
+}
+
+// NewLedger 创建账本，maxEntries 为保留的流水条数，<=0 时使用默认值
+func NewLedger(maxEntries int) *Ledger {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &Ledger{balances: make(map[uint64]int64), maxEntries: maxEntries}
+}
+
+// Add 变动积分并返回余额，delta 可为负
+func (l *Ledger) Add(userID uint64, delta int64, reason string) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.add(userID, delta, reason)
+}
+
+// add 在持有锁时变动积分
+func (l *Ledger) add(userID uint64, delta int64, reason string) int64 {
+	balance := l.balances[userID] + delta
+	l.balances[userID] = balance
+	l.entries = append(l.entries, Entry{UserID: userID, Delta: delta, Balance: balance, Reason: reason, Time: time.Now()})
+	if len(l.entries) > l.maxEntries {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-l.maxEntries:]...)
+	}
+	return balance
+}
+
+// Spend 余额足够时扣除积分，返回是否成功
+func (l *Ledger) Spend(userID uint64, amount int64, reason string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.balances[userID] < amount {
+		return false
+	}
+	l.add(userID, -amount, reason)
+	return true
+}
+
+// Balance 返回用户的积分余额
+func (l *Ledger) Balance(userID uint64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.balances[userID]
+}
+
+// Balances 返回全部用户的余额副本
+func (l *Ledger) Balances() map[uint64]int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make(map[uint64]int64, len(l.balances))
+	for id, balance := range l.balances {
+		out[id] = balance
+	}
+	return out
+}
+
+// Entries 返回用户最近的流水，userID 为 0 时返回全部，按时间先后排列
+func (l *Ledger) Entries(userID uint64) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []Entry
+	for _, e := range l.entries {
+		if userID == 0 || e.UserID == userID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Reset 清空余额与流水，用于开始新的一局
+func (l *Ledger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.balances = make(map[uint64]int64)
+	l.entries = nil
+}
@@ -0,0 +1,289 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.2
+// source: live_service.proto
+
+package liveservice
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListenRoomRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LiveId         string                 `protobuf:"bytes,1,opt,name=live_id,json=liveId,proto3" json:"live_id,omitempty"`
+	Methods        []string               `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"`
+	IncludePayload bool                   `protobuf:"varint,3,opt,name=include_payload,json=includePayload,proto3" json:"include_payload,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListenRoomRequest) Reset() {
+	*x = ListenRoomRequest{}
+	mi := &file_live_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListenRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListenRoomRequest) ProtoMessage() {}
+
+func (x *ListenRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_live_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListenRoomRequest.ProtoReflect.Descriptor instead.
+func (*ListenRoomRequest) Descriptor() ([]byte, []int) {
+	return file_live_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ListenRoomRequest) GetLiveId() string {
+	if x != nil {
+		return x.LiveId
+	}
+	return ""
+}
+
+func (x *ListenRoomRequest) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *ListenRoomRequest) GetIncludePayload() bool {
+	if x != nil {
+		return x.IncludePayload
+	}
+	return false
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LiveName      string                 `protobuf:"bytes,2,opt,name=live_name,json=liveName,proto3" json:"live_name,omitempty"`
+	Method        string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	MsgId         uint64                 `protobuf:"varint,4,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	TimeUnixMs    int64                  `protobuf:"varint,5,opt,name=time_unix_ms,json=timeUnixMs,proto3" json:"time_unix_ms,omitempty"`
+	UserId        uint64                 `protobuf:"varint,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname      string                 `protobuf:"bytes,7,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Content       string                 `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`
+	DataJson      string                 `protobuf:"bytes,9,opt,name=data_json,json=dataJson,proto3" json:"data_json,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	Tags          map[string]string      `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_live_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_live_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_live_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Event) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *Event) GetLiveName() string {
+	if x != nil {
+		return x.LiveName
+	}
+	return ""
+}
+
+func (x *Event) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Event) GetMsgId() uint64 {
+	if x != nil {
+		return x.MsgId
+	}
+	return 0
+}
+
+func (x *Event) GetTimeUnixMs() int64 {
+	if x != nil {
+		return x.TimeUnixMs
+	}
+	return 0
+}
+
+func (x *Event) GetUserId() uint64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Event) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *Event) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *Event) GetDataJson() string {
+	if x != nil {
+		return x.DataJson
+	}
+	return ""
+}
+
+func (x *Event) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_live_service_proto protoreflect.FileDescriptor
+
+const file_live_service_proto_rawDesc = "" +
+	"\n" +
+	"\x12live_service.proto\x12\rdouyinlive.v1\"o\n" +
+	"\x11ListenRoomRequest\x12\x17\n" +
+	"\alive_id\x18\x01 \x01(\tR\x06liveId\x12\x18\n" +
+	"\amethods\x18\x02 \x03(\tR\amethods\x12'\n" +
+	"\x0finclude_payload\x18\x03 \x01(\bR\x0eincludePayload\"\x81\x03\n" +
+	"\x05Event\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tlive_name\x18\x02 \x01(\tR\bliveName\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12\x15\n" +
+	"\x06msg_id\x18\x04 \x01(\x04R\x05msgId\x12 \n" +
+	"\ftime_unix_ms\x18\x05 \x01(\x03R\n" +
+	"timeUnixMs\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\x04R\x06userId\x12\x1a\n" +
+	"\bnickname\x18\a \x01(\tR\bnickname\x12\x18\n" +
+	"\acontent\x18\b \x01(\tR\acontent\x12\x1b\n" +
+	"\tdata_json\x18\t \x01(\tR\bdataJson\x12\x18\n" +
+	"\apayload\x18\n" +
+	" \x01(\fR\apayload\x122\n" +
+	"\x04tags\x18\v \x03(\v2\x1e.douyinlive.v1.Event.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012U\n" +
+	"\vLiveService\x12F\n" +
+	"\n" +
+	"ListenRoom\x12 .douyinlive.v1.ListenRoomRequest\x1a\x14.douyinlive.v1.Event0\x01B\x18Z\x16generated/liveservice/b\x06proto3"
+
+var (
+	file_live_service_proto_rawDescOnce sync.Once
+	file_live_service_proto_rawDescData []byte
+)
+
+func file_live_service_proto_rawDescGZIP() []byte {
+	file_live_service_proto_rawDescOnce.Do(func() {
+		file_live_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_live_service_proto_rawDesc), len(file_live_service_proto_rawDesc)))
+	})
+	return file_live_service_proto_rawDescData
+}
+
+var file_live_service_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_live_service_proto_goTypes = []any{
+	(*ListenRoomRequest)(nil), // 0: douyinlive.v1.ListenRoomRequest
+	(*Event)(nil),             // 1: douyinlive.v1.Event
+	nil,                       // 2: douyinlive.v1.Event.TagsEntry
+}
+var file_live_service_proto_depIdxs = []int32{
+	2, // 0: douyinlive.v1.Event.tags:type_name -> douyinlive.v1.Event.TagsEntry
+	0, // 1: douyinlive.v1.LiveService.ListenRoom:input_type -> douyinlive.v1.ListenRoomRequest
+	1, // 2: douyinlive.v1.LiveService.ListenRoom:output_type -> douyinlive.v1.Event
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_live_service_proto_init() }
+func file_live_service_proto_init() {
+	if File_live_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_live_service_proto_rawDesc), len(file_live_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_live_service_proto_goTypes,
+		DependencyIndexes: file_live_service_proto_depIdxs,
+		MessageInfos:      file_live_service_proto_msgTypes,
+	}.Build()
+	File_live_service_proto = out.File
+	file_live_service_proto_goTypes = nil
+	file_live_service_proto_depIdxs = nil
+}
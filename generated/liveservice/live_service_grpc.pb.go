@@ -0,0 +1,124 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.2
+// source: live_service.proto
+
+package liveservice
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LiveService_ListenRoom_FullMethodName = "/douyinlive.v1.LiveService/ListenRoom"
+)
+
+// LiveServiceClient is the client API for LiveService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LiveServiceClient interface {
+	ListenRoom(ctx context.Context, in *ListenRoomRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type liveServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLiveServiceClient(cc grpc.ClientConnInterface) LiveServiceClient {
+	return &liveServiceClient{cc}
+}
+
+func (c *liveServiceClient) ListenRoom(ctx context.Context, in *ListenRoomRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LiveService_ServiceDesc.Streams[0], LiveService_ListenRoom_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListenRoomRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LiveService_ListenRoomClient = grpc.ServerStreamingClient[Event]
+
+// LiveServiceServer is the server API for LiveService service.
+// All implementations must embed UnimplementedLiveServiceServer
+// for forward compatibility.
+type LiveServiceServer interface {
+	ListenRoom(*ListenRoomRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedLiveServiceServer()
+}
+
+// UnimplementedLiveServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLiveServiceServer struct{}
+
+func (UnimplementedLiveServiceServer) ListenRoom(*ListenRoomRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method ListenRoom not implemented")
+}
+func (UnimplementedLiveServiceServer) mustEmbedUnimplementedLiveServiceServer() {}
+func (UnimplementedLiveServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeLiveServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LiveServiceServer will
+// result in compilation errors.
+type UnsafeLiveServiceServer interface {
+	mustEmbedUnimplementedLiveServiceServer()
+}
+
+func RegisterLiveServiceServer(s grpc.ServiceRegistrar, srv LiveServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLiveServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LiveService_ServiceDesc, srv)
+}
+
+func _LiveService_ListenRoom_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListenRoomRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LiveServiceServer).ListenRoom(m, &grpc.GenericServerStream[ListenRoomRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LiveService_ListenRoomServer = grpc.ServerStreamingServer[Event]
+
+// LiveService_ServiceDesc is the grpc.ServiceDesc for LiveService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LiveService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "douyinlive.v1.LiveService",
+	HandlerType: (*LiveServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListenRoom",
+			Handler:       _LiveService_ListenRoom_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "live_service.proto",
+}
@@ -5,6 +5,7 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"log"
 	"os"
+	"sort"
 	"sync"
 )
 
@@ -53,3 +54,20 @@ func GetMessageInstance(name string) (protoreflect.ProtoMessage, error) {
 	}
 	return nil, errors.New("未知消息: " + name)
 }
+
+// Known 消息类型是否已注册
+func Known(name string) bool {
+	_, ok := NewMessageSync.Load(name)
+	return ok
+}
+
+// MessageNames 返回已注册的全部消息类型名，按字母序排列
+func MessageNames() []string {
+	var names []string
+	NewMessageSync.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
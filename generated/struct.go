@@ -83,6 +83,12 @@ var newMessage = map[string]func() protoreflect.ProtoMessage{
 	// Fans club related messages (joining the club, upgrading, fan task notifications)
 	"WebcastFansclubMessage": func() protoreflect.ProtoMessage { return &new_douyin.Webcast_Im_FansclubMessage{} },
 
+	// 粉丝团人数变化消息
+	// Fans club statistics message (total member count of the fans club)
+	"WebcastFansclubStatisticsMessage": func() protoreflect.ProtoMessage {
+		return &new_douyin.Webcast_Im_FansclubStatisticsMessage{}
+	},
+
 	// 直播控制消息（禁言、清屏、设置管理员等操作）
 	// Live broadcast control message (operations such as muting, clearing the screen, setting administrators)
 	"WebcastControlMessage": func() protoreflect.ProtoMessage { return &new_douyin.Webcast_Im_ControlMessage{} },
@@ -600,4 +606,16 @@ var newMessage = map[string]func() protoreflect.ProtoMessage{
 	"WebcastRankListAwardMessage": func() protoreflect.ProtoMessage {
 		return &new_douyin.Webcast_Im_RankListAwardMessage{}
 	},
+
+	// 商品讲解切换消息（开始或结束讲解某个商品）
+	// Shop explain message (start or stop explaining a product)
+	"WebcastAwemeShopExplainMessage": func() protoreflect.ProtoMessage {
+		return &new_douyin.Webcast_Im_AwemeShopExplainMessage{}
+	},
+
+	// 商品成交播报消息（直播间内的下单播报）
+	// Goods order message (order broadcast in the live room)
+	"WebcastVideoLiveGoodsOrderMessage": func() protoreflect.ProtoMessage {
+		return &new_douyin.Webcast_Im_VideoLiveGoodsOrderMessage{}
+	},
 }
@@ -0,0 +1,267 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	giftListURL            = "https://live.douyin.com/webcast/gift/list/"
+	defaultGiftCatalogTTL  = 30 * time.Minute
+	giftCatalogRetryPeriod = time.Minute
+)
+
+// GiftInfo 礼物字典中的单个礼物
+type GiftInfo struct {
+	ID           uint64
+	Name         string
+	DiamondCount int64 // 单价（抖币）
+	IconURL      string
+}
+
+// GiftCatalog 直播间礼物字典
+type GiftCatalog struct {
+	mu        sync.RWMutex
+	gifts     map[uint64]GiftInfo
+	fetchedAt time.Time
+	hash      [sha256.Size]byte // 礼物列表响应的内容哈希
+}
+
+// Lookup 按礼物 ID 查询
+func (c *GiftCatalog) Lookup(id uint64) (GiftInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.gifts[id]
+	return info, ok
+}
+
+// Len 返回字典中的礼物数量
+func (c *GiftCatalog) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.gifts)
+}
+
+// FetchedAt 返回字典的拉取时间
+func (c *GiftCatalog) FetchedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fetchedAt
+}
+
+// GiftEvent 解码并用礼物字典补全后的礼物事件
+type GiftEvent struct {
+	RoomID       string
+	Time         time.Time
+	MsgID        uint64
+	UserID       uint64
+	Nickname     string
+	GiftID       uint64
+	GiftName     string
+	DiamondCount int64 // 单价（抖币）
+	IconURL      string
+	GroupID      uint64 // 连击组 ID，同一次连击的消息相同
+	GroupCount   uint64 // 每次赠送的个数
+	RepeatCount  uint64 // 连击累计次数
+	ComboCount   uint64
+	RepeatEnd    bool   // 是否为连击的最后一条消息
+	Count        uint64 // 本事件代表的礼物总个数，GroupCount*RepeatCount
+	Message      *new_douyin.Webcast_Im_GiftMessage
+}
+
+// TotalDiamond 返回本事件代表的总价值（抖币）
+func (g *GiftEvent) TotalDiamond() int64 {
+	return g.DiamondCount * int64(g.Count)
+}
+
+// WithGiftCatalogTTL 设置礼物字典的缓存时间，默认 30 分钟
+func WithGiftCatalogTTL(ttl time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.giftCatalogTTL = ttl
+	}
+}
+
+// GiftCatalog 返回缓存的礼物字典，过期或尚未拉取时重新拉取
+func (dl *DouyinLive) GiftCatalog(ctx context.Context) (*GiftCatalog, error) {
+	dl.giftMu.Lock()
+	catalog := dl.giftCatalog
+	dl.giftMu.Unlock()
+
+	if catalog != nil && time.Since(catalog.FetchedAt()) < dl.giftTTL() {
+		return catalog, nil
+	}
+	return dl.FetchGiftCatalog(ctx)
+}
+
+// giftTTL 返回礼物字典的缓存时间
+func (dl *DouyinLive) giftTTL() time.Duration {
+	if dl.giftCatalogTTL <= 0 {
+		return defaultGiftCatalogTTL
+	}
+	return dl.giftCatalogTTL
+}
+
+// FetchGiftCatalog 从 webcast gift/list 接口拉取礼物字典并更新缓存
+func (dl *DouyinLive) FetchGiftCatalog(ctx context.Context) (*GiftCatalog, error) {
+	r := dl.client.R().
+		SetHeader("User-Agent", dl.userAgent).
+		SetHeader("Referer", fmt.Sprintf("https://live.douyin.com/%s", dl.liveID)).
+		SetCookies(dl.ttwidCookie()).
+		SetQueryParams(map[string]string{
+			"aid":             webcastAid,
+			"app_name":        "douyin_web",
+			"device_platform": "web",
+			"browser_name":    "Mozilla",
+			"room_id":         dl.roomID,
+		})
+	resp, err := dl.conditionalGet(ctx, r, giftListURL)
+	if err != nil {
+		return nil, fmt.Errorf("请求礼物列表失败: %w", err)
+	}
+
+	// 内容未变化时沿用已有字典，只刷新拉取时间
+	dl.giftMu.Lock()
+	current := dl.giftCatalog
+	dl.giftMu.Unlock()
+	if current != nil && current.hash == resp.Hash {
+		current.mu.Lock()
+		current.fetchedAt = time.Now()
+		current.mu.Unlock()
+		return current, nil
+	}
+
+	gifts := gjson.Get(resp.Body, "data.gifts")
+	if !gifts.IsArray() {
+		return nil, fmt.Errorf("%w: 礼物列表响应中缺少 data.gifts (状态码: %d)", ErrRoomInfoParse, resp.StatusCode)
+	}
+
+	catalog := &GiftCatalog{
+		gifts:     make(map[uint64]GiftInfo),
+		fetchedAt: time.Now(),
+		hash:      resp.Hash,
+	}
+	gifts.ForEach(func(_, g gjson.Result) bool {
+		info := GiftInfo{
+			ID:           g.Get("id").Uint(),
+			Name:         g.Get("name").String(),
+			DiamondCount: g.Get("diamond_count").Int(),
+			IconURL:      g.Get("image.url_list.0").String(),
+		}
+		if info.ID != 0 {
+			catalog.gifts[info.ID] = info
+		}
+		return true
+	})
+
+	dl.giftMu.Lock()
+	dl.giftCatalog = catalog
+	dl.giftMu.Unlock()
+	return catalog, nil
+}
+
+// DecodeGift 解码礼物消息，消息中缺失的名称、价值、图标由已缓存的礼物字典补全
+func (dl *DouyinLive) DecodeGift(event *LiveEvent) (*GiftEvent, error) {
+	if event.Method != WebcastGiftMessage {
+		return nil, fmt.Errorf("不是礼物消息: %s", event.Method)
+	}
+	decoded, err := event.Decode()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := decoded.(*new_douyin.Webcast_Im_GiftMessage)
+	if !ok {
+		return nil, fmt.Errorf("礼物消息类型不匹配: %T", decoded)
+	}
+
+	gift := &GiftEvent{
+		RoomID:      event.RoomID,
+		Time:        event.Time,
+		MsgID:       event.MsgID,
+		GiftID:      msg.GiftId,
+		GroupID:     msg.GroupId,
+		GroupCount:  msg.GroupCount,
+		RepeatCount: msg.RepeatCount,
+		ComboCount:  msg.ComboCount,
+		RepeatEnd:   msg.RepeatEnd == 1,
+		Message:     msg,
+	}
+	if msg.User != nil {
+		gift.UserID = msg.User.Id
+		gift.Nickname = msg.User.Nickname
+	}
+	if g := msg.Gift; g != nil {
+		if gift.GiftID == 0 {
+			gift.GiftID = g.Id
+		}
+		gift.GiftName = g.Name
+		gift.DiamondCount = int64(g.DiamondCount)
+		if g.Image != nil && len(g.Image.UrlList) > 0 {
+			gift.IconURL = g.Image.UrlList[0]
+		}
+	}
+	gift.Count = max(gift.GroupCount, 1) * max(gift.RepeatCount, 1)
+
+	dl.giftMu.Lock()
+	catalog := dl.giftCatalog
+	dl.giftMu.Unlock()
+	if catalog != nil {
+		if info, ok := catalog.Lookup(gift.GiftID); ok {
+			if gift.GiftName == "" {
+				gift.GiftName = info.Name
+			}
+			if gift.DiamondCount == 0 {
+				gift.DiamondCount = info.DiamondCount
+			}
+			if gift.IconURL == "" {
+				gift.IconURL = info.IconURL
+			}
+		}
+	}
+	return gift, nil
+}
+
+// SubscribeGift 订阅礼物事件，首次收到礼物时在后台拉取礼物字典用于补全
+func (dl *DouyinLive) SubscribeGift(handler func(*GiftEvent)) string {
+	return dl.SubscribeEvent(func(event *LiveEvent) {
+		if event.Method != WebcastGiftMessage {
+			return
+		}
+		dl.ensureGiftCatalog()
+		gift, err := dl.DecodeGift(event)
+		if err != nil {
+			dl.log().Warn("解析礼物消息失败", "error", err)
+			return
+		}
+		handler(gift)
+	})
+}
+
+// ensureGiftCatalog 字典缺失或过期时在后台拉取，失败后间隔一段时间再重试
+func (dl *DouyinLive) ensureGiftCatalog() {
+	dl.giftMu.Lock()
+	defer dl.giftMu.Unlock()
+	fresh := dl.giftCatalog != nil && time.Since(dl.giftCatalog.FetchedAt()) < dl.giftTTL()
+	if fresh || dl.giftFetching || time.Since(dl.giftFetchFailedAt) < giftCatalogRetryPeriod {
+		return
+	}
+	dl.giftFetching = true
+	go func() {
+		_, err := dl.FetchGiftCatalog(context.Background())
+		dl.giftMu.Lock()
+		dl.giftFetching = false
+		if err != nil {
+			dl.giftFetchFailedAt = time.Now()
+		}
+		dl.giftMu.Unlock()
+		if err != nil {
+			dl.log().Warn("拉取礼物字典失败", "error", err)
+		}
+	}()
+}
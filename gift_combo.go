@@ -0,0 +1,147 @@
+package douyinLive
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultComboTimeout 连击消息之间的默认最长间隔
+const defaultComboTimeout = 5 * time.Second
+
+// comboKey 连击的唯一标识：用户 + 礼物 + 连击组
+type comboKey struct {
+	userID  uint64
+	giftID  uint64
+	groupID uint64
+}
+
+// GiftAggregator 将同一次连击的多条礼物消息合并为一条最终事件。
+// 连击结束（RepeatEnd）或超过 timeout 未收到后续消息时输出，Count 为整次连击的礼物总数
+type GiftAggregator struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	emit    func(*GiftEvent)
+	pending map[comboKey]*pendingCombo
+	closed  bool
+}
+
+// pendingCombo 进行中的连击
+type pendingCombo struct {
+	last  *GiftEvent
+	timer *time.Timer
+}
+
+// NewGiftAggregator 创建连击合并器，timeout<=0 时使用默认值
+func NewGiftAggregator(timeout time.Duration, emit func(*GiftEvent)) *GiftAggregator {
+	if timeout <= 0 {
+		timeout = defaultComboTimeout
+	}
+	return &GiftAggregator{
+		timeout: timeout,
+		emit:    emit,
+		pending: make(map[comboKey]*pendingCombo),
+	}
+}
+
+// Add 加入一条礼物事件，非连击礼物立即输出
+func (a *GiftAggregator) Add(gift *GiftEvent) {
+	combo := gift.Message != nil && gift.Message.Gift != nil && gift.Message.Gift.Combo
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return
+	}
+	if !combo && !gift.RepeatEnd && gift.GroupID == 0 {
+		a.mu.Unlock()
+		a.output(gift)
+		return
+	}
+
+	key := comboKey{userID: gift.UserID, giftID: gift.GiftID, groupID: gift.GroupID}
+	p, ok := a.pending[key]
+	if !ok {
+		p = &pendingCombo{}
+		a.pending[key] = p
+	}
+	// 消息可能乱序，只保留累计次数最大的一条
+	if p.last == nil || gift.RepeatCount >= p.last.RepeatCount {
+		p.last = gift
+	}
+	if gift.RepeatEnd {
+		if p.timer != nil {
+			p.timer.Stop()
+		}
+		delete(a.pending, key)
+		final := p.last
+		a.mu.Unlock()
+		a.output(final)
+		return
+	}
+	if p.timer == nil {
+		p.timer = time.AfterFunc(a.timeout, func() { a.expire(key, p) })
+	} else {
+		p.timer.Reset(a.timeout)
+	}
+	a.mu.Unlock()
+}
+
+// expire 连击超时后输出
+func (a *GiftAggregator) expire(key comboKey, p *pendingCombo) {
+	a.mu.Lock()
+	if a.pending[key] != p {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.pending, key)
+	a.mu.Unlock()
+	a.output(p.last)
+}
+
+// Flush 立即输出所有进行中的连击
+func (a *GiftAggregator) Flush() {
+	a.mu.Lock()
+	pending := a.takePending()
+	a.mu.Unlock()
+	a.outputAll(pending)
+}
+
+// Close 输出所有进行中的连击，之后加入的事件会被忽略
+func (a *GiftAggregator) Close() {
+	a.mu.Lock()
+	a.closed = true
+	pending := a.takePending()
+	a.mu.Unlock()
+	a.outputAll(pending)
+}
+
+// takePending 取出进行中的连击，调用方需持有锁
+func (a *GiftAggregator) takePending() map[comboKey]*pendingCombo {
+	pending := a.pending
+	a.pending = make(map[comboKey]*pendingCombo)
+	return pending
+}
+
+// outputAll 停止计时器并输出取出的连击
+func (a *GiftAggregator) outputAll(pending map[comboKey]*pendingCombo) {
+	for _, p := range pending {
+		p.timer.Stop()
+		a.output(p.last)
+	}
+}
+
+// output 计算总数后输出
+func (a *GiftAggregator) output(gift *GiftEvent) {
+	final := *gift
+	final.RepeatEnd = true
+	final.Count = max(final.GroupCount, 1) * max(final.RepeatCount, 1)
+	a.emit(&final)
+}
+
+// SubscribeGiftCombo 订阅合并后的礼物事件，每次连击只回调一次。
+// Unsubscribe 时输出进行中的连击，之后不再回调
+func (dl *DouyinLive) SubscribeGiftCombo(timeout time.Duration, handler func(*GiftEvent)) string {
+	aggregator := NewGiftAggregator(timeout, handler)
+	id := dl.SubscribeGift(aggregator.Add)
+	dl.onUnsubscribe(id, aggregator.Close)
+	return id
+}
@@ -0,0 +1,108 @@
+package douyinLive
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func comboGift(repeat uint64, end bool) *GiftEvent {
+	return &GiftEvent{
+		UserID:      1,
+		GiftID:      463,
+		GroupID:     99,
+		GroupCount:  1,
+		RepeatCount: repeat,
+		RepeatEnd:   end,
+		Message:     &new_douyin.Webcast_Im_GiftMessage{Gift: &new_douyin.Webcast_Data_GiftStruct{Combo: true}},
+	}
+}
+
+func TestGiftAggregator(t *testing.T) {
+	var mu sync.Mutex
+	var got []*GiftEvent
+	a := NewGiftAggregator(50*time.Millisecond, func(g *GiftEvent) {
+		mu.Lock()
+		got = append(got, g)
+		mu.Unlock()
+	})
+
+	// 连击以 RepeatEnd 结束
+	a.Add(comboGift(1, false))
+	a.Add(comboGift(3, false))
+	a.Add(comboGift(2, false))
+	a.Add(comboGift(3, true))
+	// 非连击礼物立即输出
+	a.Add(&GiftEvent{GiftID: 1, GroupCount: 1, RepeatCount: 1})
+	// 连击超时
+	timeout := comboGift(5, false)
+	timeout.GroupID = 100
+	a.Add(timeout)
+	time.Sleep(200 * time.Millisecond)
+	a.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 3 {
+		t.Fatalf("输出 %d 条事件, want 3", len(got))
+	}
+	if got[0].Count != 3 || got[1].Count != 1 || got[2].Count != 5 {
+		t.Fatalf("连击计数错误: %d %d %d", got[0].Count, got[1].Count, got[2].Count)
+	}
+}
+
+func TestGiftAggregatorIgnoresAddAfterClose(t *testing.T) {
+	var mu sync.Mutex
+	var got int
+	a := NewGiftAggregator(20*time.Millisecond, func(*GiftEvent) {
+		mu.Lock()
+		got++
+		mu.Unlock()
+	})
+	a.Add(comboGift(1, false))
+	a.Close()
+	a.Add(comboGift(2, false))
+	a.Add(&GiftEvent{GiftID: 1, GroupCount: 1, RepeatCount: 1})
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got != 1 {
+		t.Fatalf("Close 后仍有输出: %d 条, want 1", got)
+	}
+}
+
+func TestSubscribeGiftComboUnsubscribe(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	var mu sync.Mutex
+	var got []*GiftEvent
+	id := dl.SubscribeGiftCombo(20*time.Millisecond, func(g *GiftEvent) {
+		mu.Lock()
+		got = append(got, g)
+		mu.Unlock()
+	})
+	gift := giftLiveEvent(t, &new_douyin.Webcast_Im_GiftMessage{
+		GiftId:      463,
+		GroupId:     99,
+		GroupCount:  1,
+		RepeatCount: 3,
+		Gift:        &new_douyin.Webcast_Data_GiftStruct{Combo: true},
+	})
+	dl.deliver(gift.Message)
+	dl.Unsubscribe(id)
+
+	mu.Lock()
+	if len(got) != 1 || got[0].Count != 3 {
+		mu.Unlock()
+		t.Fatalf("取消订阅时应输出进行中的连击: %v", got)
+	}
+	mu.Unlock()
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 {
+		t.Fatalf("取消订阅后仍有回调: %d 条", len(got))
+	}
+}
@@ -0,0 +1,48 @@
+package douyinLive
+
+import (
+	"time"
+
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// GiftCorrection 外部对账系统回写的礼物修正，如撤回、退款或与官方后台对账后的差额
+type GiftCorrection struct {
+	MsgID        uint64    // 被修正的礼物消息 ID，未知时为 0
+	UserID       uint64    // 送礼用户
+	GiftID       uint64    // 礼物 ID
+	Time         time.Time // 原礼物事件的时间，用于定位所属的统计窗口
+	DiamondDelta int64     // 价值修正量（抖币），撤回/退款为负数
+	Reason       string    // 修正原因，如 "refund"、"reconcile"
+}
+
+// CorrectGift 提交一条礼物修正：开启汇总统计时计入 Summary 的总价值与送礼榜，
+// 并同步通知全部修正订阅者，由其级联更新各自的聚合结果
+func (dl *DouyinLive) CorrectGift(c GiftCorrection) {
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+	if dl.summaryOn.Load() {
+		dl.summary.correct(c)
+	}
+	dl.handlersMu.RLock()
+	handlers := dl.eventHandlers
+	dl.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		if handler.CorrectionHandler != nil {
+			handler.CorrectionHandler(c)
+		}
+	}
+}
+
+// SubscribeGiftCorrection 订阅礼物修正，返回的 ID 可用于 Unsubscribe
+func (dl *DouyinLive) SubscribeGiftCorrection(handler func(GiftCorrection)) string {
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:                id,
+		CorrectionHandler: handler,
+	})
+	return id
+}
@@ -0,0 +1,51 @@
+package douyinLive
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// giftLiveEvent 构造礼物消息事件
+func giftLiveEvent(t *testing.T, gift *new_douyin.Webcast_Im_GiftMessage) *LiveEvent {
+	t.Helper()
+	payload, err := proto.Marshal(gift)
+	if err != nil {
+		t.Fatalf("序列化礼物消息失败: %v", err)
+	}
+	return NewLiveEvent("1", "test", &new_douyin.Webcast_Im_Message{Method: WebcastGiftMessage, Payload: payload})
+}
+
+func TestDecodeGiftEnrichesFromCatalog(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", log.Default())
+	dl.giftCatalog = &GiftCatalog{
+		gifts: map[uint64]GiftInfo{
+			463: {ID: 463, Name: "小心心", DiamondCount: 1, IconURL: "https://example.com/heart.png"},
+		},
+		fetchedAt: time.Now(),
+	}
+
+	event := giftLiveEvent(t, &new_douyin.Webcast_Im_GiftMessage{
+		GiftId:      463,
+		GroupCount:  10,
+		RepeatCount: 3,
+		User:        &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"},
+	})
+	gift, err := dl.DecodeGift(event)
+	if err != nil {
+		t.Fatalf("DecodeGift() error = %v", err)
+	}
+	if gift.GiftName != "小心心" || gift.DiamondCount != 1 || gift.IconURL == "" {
+		t.Fatalf("礼物信息未补全: %+v", gift)
+	}
+	if gift.Count != 30 || gift.TotalDiamond() != 30 {
+		t.Fatalf("Count=%d TotalDiamond=%d, want 30", gift.Count, gift.TotalDiamond())
+	}
+	if gift.UserID != 42 || gift.Nickname != "观众" {
+		t.Fatalf("用户信息错误: %+v", gift)
+	}
+}
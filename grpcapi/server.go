@@ -0,0 +1,143 @@
+// Package grpcapi gRPC 事件流服务：LiveService.ListenRoom 按直播间推送事件，
+// 每个直播间只保持一条上游连接，协议定义见 protobuf/live_service.proto，
+// 其他语言的客户端可直接用该文件生成代码
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/liveservice"
+)
+
+const defaultClientBuffer = 256
+
+// Upstream 直播间的上游连接，*douyinLive.SharedLive 实现了该接口
+type Upstream interface {
+	SubscribeEvent(handler func(*douyinLive.LiveEvent)) string
+	Unsubscribe(id string)
+	Done() <-chan struct{}
+	Release()
+}
+
+// Options 服务配置
+type Options struct {
+	// Acquire 获取直播间的上游连接，默认使用 douyinLive.NewRegistry 的共享连接
+	Acquire      func(ctx context.Context, liveID string) (Upstream, error)
+	ClientBuffer int // 每个流的缓冲事件数，溢出时以 ResourceExhausted 结束该流，默认 256
+	Logger       *slog.Logger
+}
+
+// Server LiveService 的实现
+type Server struct {
+	liveservice.UnimplementedLiveServiceServer
+	opts Options
+}
+
+// New 创建服务
+func New(opts Options) *Server {
+	if opts.Acquire == nil {
+		registry := douyinLive.NewRegistry()
+		opts.Acquire = func(ctx context.Context, liveID string) (Upstream, error) {
+			return registry.AcquireContext(ctx, liveID)
+		}
+	}
+	if opts.ClientBuffer <= 0 {
+		opts.ClientBuffer = defaultClientBuffer
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Server{opts: opts}
+}
+
+// Register 将服务注册到 gRPC 服务器
+func (s *Server) Register(g *grpc.Server) {
+	liveservice.RegisterLiveServiceServer(g, s)
+}
+
+// ListenRoom 订阅直播间事件，客户端取消时返回，直播间连接结束时正常结束流
+func (s *Server) ListenRoom(req *liveservice.ListenRoomRequest, stream liveservice.LiveService_ListenRoomServer) error {
+	if req.GetLiveId() == "" {
+		return status.Error(codes.InvalidArgument, "缺少直播间号")
+	}
+	upstream, err := s.opts.Acquire(stream.Context(), req.GetLiveId())
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "连接直播间失败: %v", err)
+	}
+	defer upstream.Release()
+
+	log := s.opts.Logger.With("live_id", req.GetLiveId())
+	log.Info("gRPC 客户端已订阅")
+	defer log.Info("gRPC 客户端已退订")
+
+	methods := make(map[string]bool, len(req.GetMethods()))
+	for _, m := range req.GetMethods() {
+		methods[m] = true
+	}
+	queue := make(chan *liveservice.Event, s.opts.ClientBuffer)
+	evicted := make(chan struct{})
+	var evictOnce sync.Once
+	id := upstream.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if len(methods) > 0 && !methods[event.Method] {
+			return
+		}
+		select {
+		case queue <- NewEvent(event, req.GetIncludePayload()):
+		default:
+			evictOnce.Do(func() { close(evicted) })
+		}
+	})
+	defer upstream.Unsubscribe(id)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case event := <-queue:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-evicted:
+			log.Warn("gRPC 客户端消费过慢，结束流")
+			return status.Error(codes.ResourceExhausted, "消费过慢")
+		case <-upstream.Done():
+			return nil
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// NewEvent 将事件转换为 gRPC 消息，includePayload 为 true 时附带原始消息体
+func NewEvent(event *douyinLive.LiveEvent, includePayload bool) *liveservice.Event {
+	out := &liveservice.Event{
+		RoomId:     event.RoomID,
+		LiveName:   event.LiveName,
+		Method:     event.Method,
+		MsgId:      event.MsgID,
+		TimeUnixMs: event.Time.UnixMilli(),
+		Nickname:   event.Nickname(),
+		Content:    event.Content(),
+		Tags:       event.Tags,
+	}
+	if userID, ok := event.Fields()[douyinLive.FieldUserID].(string); ok {
+		out.UserId, _ = strconv.ParseUint(userID, 10, 64)
+	}
+	if data, err := event.Data(); err == nil {
+		if b, err := json.Marshal(data); err == nil {
+			out.DataJson = string(b)
+		}
+	}
+	if includePayload {
+		out.Payload = event.Payload()
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/liveservice"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// fakeUpstream 手动推送事件的上游
+type fakeUpstream struct {
+	mu       sync.Mutex
+	handlers map[string]func(*douyinLive.LiveEvent)
+	nextID   int
+	done     chan struct{}
+}
+
+func (u *fakeUpstream) SubscribeEvent(handler func(*douyinLive.LiveEvent)) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.nextID++
+	id := strconv.Itoa(u.nextID)
+	u.handlers[id] = handler
+	return id
+}
+
+func (u *fakeUpstream) Unsubscribe(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.handlers, id)
+}
+
+func (u *fakeUpstream) Done() <-chan struct{} { return u.done }
+func (u *fakeUpstream) Release()              {}
+
+func (u *fakeUpstream) subscribers() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.handlers)
+}
+
+func (u *fakeUpstream) emit(method string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	event := douyinLive.NewLiveEvent("100", "", &new_douyin.Webcast_Im_Message{Method: method, Payload: []byte{1}})
+	for _, h := range u.handlers {
+		h(event)
+	}
+}
+
+func TestListenRoom(t *testing.T) {
+	up := &fakeUpstream{handlers: make(map[string]func(*douyinLive.LiveEvent)), done: make(chan struct{})}
+	lis := bufconn.Listen(1 << 16)
+	g := grpc.NewServer()
+	New(Options{Acquire: func(context.Context, string) (Upstream, error) { return up, nil }}).Register(g)
+	go g.Serve(lis)
+	defer g.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := liveservice.NewLiveServiceClient(conn)
+
+	if _, err := recvErr(client, &liveservice.ListenRoomRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("缺少直播间号时应返回 InvalidArgument，得到 %v", err)
+	}
+
+	stream, err := client.ListenRoom(context.Background(), &liveservice.ListenRoomRequest{
+		LiveId:         "933572413882",
+		Methods:        []string{douyinLive.WebcastGiftMessage},
+		IncludePayload: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for up.subscribers() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	up.emit(douyinLive.WebcastChatMessage)
+	up.emit(douyinLive.WebcastGiftMessage)
+	event, err := stream.Recv()
+	if err != nil || event.Method != douyinLive.WebcastGiftMessage || event.RoomId != "100" || len(event.Payload) != 1 {
+		t.Fatalf("收到 %v, %v", event, err)
+	}
+
+	close(up.done)
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("直播间结束后流应结束")
+	}
+	for up.subscribers() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// recvErr 发起订阅并返回首次接收的错误
+func recvErr(client liveservice.LiveServiceClient, req *liveservice.ListenRoomRequest) (*liveservice.Event, error) {
+	stream, err := client.ListenRoom(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	return stream.Recv()
+}
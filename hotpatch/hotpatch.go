@@ -0,0 +1,253 @@
+// Package hotpatch 在采集进程运行时加载新的签名脚本与协议参数，
+// 平台小改版时无需重启几百个连接即可适配
+package hotpatch
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+const (
+	// defaultInterval Watch 的默认检查间隔
+	defaultInterval = time.Minute
+	// maxPatchSize 清单与签名脚本的最大字节数
+	maxPatchSize = 8 << 20
+)
+
+// httpClient 读取远程补丁的客户端，不跟随到非 https 地址的跳转，测试中会被替换
+var httpClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" || len(via) >= 10 {
+			return fmt.Errorf("拒绝跳转到 %s", req.URL.Redacted())
+		}
+		return nil
+	},
+}
+
+// Manifest 补丁清单，JSON 格式，放在本地文件或 https 地址上
+type Manifest struct {
+	// Protocol 协议参数，非空字段覆盖页面解析值与内置值
+	Protocol douyinLive.ProtocolParams `json:"protocol"`
+	// SignScript 签名脚本（webmssdk.js）的位置，本地路径或 https 地址，相对路径按清单所在位置解析
+	SignScript string `json:"sign_script,omitempty"`
+	// SignScriptSignature 发布者私钥对签名脚本内容的 ed25519 签名（base64），指定 SignScript 时必填，
+	// 用调用方提供的 Options.PublicKey 验证，见 SignScript
+	SignScriptSignature string `json:"sign_script_signature,omitempty"`
+}
+
+// Options Load 与 Watch 的配置
+type Options struct {
+	// PublicKey 验证签名脚本的 ed25519 公钥，清单指定 sign_script 时必填。
+	// 公钥由调用方提供而不是来自清单，能修改清单的人无法伪造脚本
+	PublicKey ed25519.PublicKey
+	Interval  time.Duration // Watch 的检查间隔，默认 1 分钟
+	Logger    *slog.Logger  // Watch 的日志，为 nil 时使用 slog.Default()
+}
+
+// Patch 已加载的补丁
+type Patch struct {
+	Manifest Manifest
+	Script   string // 签名脚本内容，清单未指定时为空
+}
+
+// Load 读取 source（本地路径或 https 地址）指向的清单及其引用的签名脚本，签名脚本用 opts.PublicKey 验证
+func Load(ctx context.Context, source string, opts Options) (*Patch, error) {
+	data, err := fetch(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("读取补丁清单失败: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析补丁清单失败: %w", err)
+	}
+	patch := &Patch{Manifest: m}
+	if m.SignScript != "" {
+		script, err := fetch(ctx, resolve(source, m.SignScript))
+		if err != nil {
+			return nil, fmt.Errorf("读取签名脚本失败: %w", err)
+		}
+		if err := verify(opts.PublicKey, script, m.SignScriptSignature); err != nil {
+			return nil, err
+		}
+		patch.Script = string(script)
+	}
+	return patch, nil
+}
+
+// Apply 应用补丁：签名脚本替换失败时返回错误且不更新协议参数
+func Apply(p *Patch) error {
+	if p.Script != "" {
+		if err := douyinLive.PatchSignScript(p.Script); err != nil {
+			return fmt.Errorf("加载签名脚本失败: %w", err)
+		}
+	}
+	douyinLive.PatchProtocolParams(p.Manifest.Protocol)
+	return nil
+}
+
+// Watch 先加载并应用一次补丁，之后按 opts.Interval 重新读取，内容变化时应用，直到 ctx 结束。
+// 首次失败时直接返回错误，之后的失败只记录日志并保留当前补丁
+func Watch(ctx context.Context, source string, opts Options) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	patch, err := Load(ctx, source, opts)
+	if err != nil {
+		return err
+	}
+	if err := Apply(patch); err != nil {
+		return err
+	}
+	last := patch.hash()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			patch, err := Load(ctx, source, opts)
+			if err != nil {
+				logger.Warn("读取热补丁失败", "source", source, "error", err)
+				continue
+			}
+			if h := patch.hash(); h != last {
+				if err := Apply(patch); err != nil {
+					logger.Warn("应用热补丁失败", "source", source, "error", err)
+					continue
+				}
+				last = h
+				logger.Info("热补丁已更新", "source", source, "version_code", patch.Manifest.Protocol.VersionCode,
+					"sign_script", patch.Manifest.SignScript != "")
+			}
+		}
+	}()
+	return nil
+}
+
+// hash 返回补丁内容的哈希，用于判断是否变化
+func (p *Patch) hash() [sha256.Size]byte {
+	data, _ := json.Marshal(p.Manifest)
+	return sha256.Sum256(append(data, p.Script...))
+}
+
+// SignScript 用发布者私钥签名脚本内容，结果填入清单的 sign_script_signature
+func SignScript(key ed25519.PrivateKey, script []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, script))
+}
+
+// ParsePublicKey 解析 base64 编码的 ed25519 公钥
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("解析公钥失败: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("公钥长度应为 %d 字节: %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// verify 用调用方的公钥校验签名脚本，脚本会在进程内执行，必须来自持有私钥的发布者
+func verify(key ed25519.PublicKey, script []byte, signature string) error {
+	if len(key) != ed25519.PublicKeySize {
+		return errors.New("未配置验证签名脚本的公钥，拒绝加载")
+	}
+	if signature == "" {
+		return errors.New("清单缺少 sign_script_signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("解析 sign_script_signature 失败: %w", err)
+	}
+	if !ed25519.Verify(key, script, sig) {
+		return errors.New("签名脚本的 ed25519 签名无效")
+	}
+	return nil
+}
+
+// isURL 判断 source 是否为 http(s) 地址
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// resolve 按清单位置解析相对路径
+func resolve(base, ref string) string {
+	if isURL(ref) || filepath.IsAbs(ref) {
+		return ref
+	}
+	if isURL(base) {
+		u, err := url.Parse(base)
+		if err != nil {
+			return ref
+		}
+		u.Path = path.Join(path.Dir(u.Path), ref)
+		return u.String()
+	}
+	return filepath.Join(filepath.Dir(base), ref)
+}
+
+// fetch 读取本地文件或 https 地址的内容，不超过 maxPatchSize
+func fetch(ctx context.Context, source string) ([]byte, error) {
+	if !isURL(source) {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return readLimited(f)
+	}
+	if !strings.HasPrefix(source, "https://") {
+		return nil, fmt.Errorf("只支持 https 地址: %s", source)
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+	return readLimited(resp.Body)
+}
+
+// readLimited 读取全部内容，超过 maxPatchSize 时返回错误
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPatchSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPatchSize {
+		return nil, fmt.Errorf("内容超过 %d 字节", maxPatchSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,119 @@
+//go:build !douyinlive_nojs
+
+package hotpatch
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/jsScript"
+)
+
+func TestWatch(t *testing.T) {
+	var version atomic.Value
+	version.Store("190000")
+	const script = `function get_sign(s) { return "patched-" + s.length; }`
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	srv := patchServer(t, func() string {
+		return `{"protocol":{"version_code":"` + version.Load().(string) + `"},"sign_script":"sign.js","sign_script_signature":"` + SignScript(priv, []byte(script)) + `"}`
+	}, script)
+	defer jsScript.ResetScript()
+	defer douyinLive.PatchProtocolParams(douyinLive.ProtocolParams{})
+
+	signer := douyinLive.JSSigner{}
+	if err := signer.Prepare("Mozilla/5.0"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := Watch(ctx, srv.URL+"/patch/manifest.json", Options{PublicKey: pub, Interval: 10 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signer.Sign(ctx, douyinLive.SignRequest{RoomID: "1", PushID: "2", UserAgent: "Mozilla/5.0"})
+	if err != nil || !strings.HasPrefix(sig, "patched-") {
+		t.Fatalf("签名脚本未替换: %s, %v", sig, err)
+	}
+	dl := douyinLive.NewDouyinLive2("1", "2", "", "", nil)
+	if got := dl.ProtocolParams().VersionCode; got != "190000" {
+		t.Fatalf("version_code = %s", got)
+	}
+
+	// 远端更新后无需重启即生效
+	version.Store("190100")
+	deadline := time.Now().Add(2 * time.Second)
+	for dl.ProtocolParams().VersionCode != "190100" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := dl.ProtocolParams().VersionCode; got != "190100" {
+		t.Fatalf("热更新后 version_code = %s", got)
+	}
+}
+
+// patchServer 返回清单与签名脚本的 https 服务，测试期间 httpClient 信任其证书
+func patchServer(t *testing.T, manifest func() string, script string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/patch/manifest.json":
+			w.Write([]byte(manifest()))
+		case "/patch/sign.js":
+			w.Write([]byte(script))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	old := httpClient
+	httpClient = srv.Client()
+	t.Cleanup(func() { httpClient = old })
+	return srv
+}
+
+func TestLoadRejectsUnverifiedScript(t *testing.T) {
+	const script = `function get_sign(s) { return "evil"; }`
+	pub, _, _ := ed25519.GenerateKey(nil)
+	_, attacker, _ := ed25519.GenerateKey(nil)
+	// 能修改清单的人可以给出自己的签名，但无法通过调用方公钥的验证
+	manifest := `{"sign_script":"sign.js","sign_script_signature":"` + SignScript(attacker, []byte(script)) + `"}`
+	srv := patchServer(t, func() string { return manifest }, script)
+	source := srv.URL + "/patch/manifest.json"
+	if _, err := Load(context.Background(), source, Options{PublicKey: pub}); err == nil || !strings.Contains(err.Error(), "签名无效") {
+		t.Fatalf("其他私钥的签名应拒绝: %v", err)
+	}
+	if _, err := Load(context.Background(), source, Options{}); err == nil || !strings.Contains(err.Error(), "公钥") {
+		t.Fatalf("未配置公钥应拒绝: %v", err)
+	}
+	manifest = `{"sign_script":"sign.js"}`
+	if _, err := Load(context.Background(), source, Options{PublicKey: pub}); err == nil {
+		t.Fatal("缺少签名应拒绝")
+	}
+	if _, err := Load(context.Background(), "http://127.0.0.1:1/manifest.json", Options{PublicKey: pub}); err == nil || !strings.Contains(err.Error(), "https") {
+		t.Fatalf("http 地址应拒绝: %v", err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(pub))
+	if err != nil || !got.Equal(pub) {
+		t.Fatalf("ParsePublicKey = %x, %v", got, err)
+	}
+	if _, err := ParsePublicKey("YWJj"); err == nil {
+		t.Fatal("长度错误应返回错误")
+	}
+}
+
+func TestApplyInvalidScript(t *testing.T) {
+	if err := Apply(&Patch{Script: "var x = 1;"}); err == nil {
+		t.Fatal("缺少 get_sign 的脚本应返回错误")
+	}
+}
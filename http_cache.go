@@ -0,0 +1,81 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"sync"
+
+	"github.com/imroc/req/v3"
+)
+
+// httpCache 按 URL 记录 ETag/Last-Modified 与响应内容哈希，
+// 用于条件请求以及判断页面或接口响应是否变化
+type httpCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// cachedResponse 一次 GET 请求的结果
+type cachedResponse struct {
+	Body         string
+	Hash         [sha256.Size]byte // 响应内容的哈希，内容不变时哈希不变
+	StatusCode   int
+	NotModified  bool // 服务端返回 304，Body 来自缓存
+	etag         string
+	lastModified string
+}
+
+// get 返回 url 的缓存
+func (c *httpCache) get(url string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries[url]
+}
+
+// put 缓存 url 的响应
+func (c *httpCache) put(url string, resp *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*cachedResponse)
+	}
+	c.entries[url] = resp
+}
+
+// conditionalGet 携带上次的 ETag/Last-Modified 发起 GET 请求，
+// 服务端返回 304 时直接使用缓存内容，仅缓存 200 响应
+func (dl *DouyinLive) conditionalGet(ctx context.Context, r *req.Request, url string) (*cachedResponse, error) {
+	cached := dl.httpCache.get(url)
+	if cached != nil {
+		if cached.etag != "" {
+			r.SetHeader("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			r.SetHeader("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := r.SetContext(ctx).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		hit := *cached
+		hit.NotModified = true
+		return &hit, nil
+	}
+
+	body := resp.Bytes()
+	result := &cachedResponse{
+		Body:         string(body),
+		Hash:         sha256.Sum256(body),
+		StatusCode:   resp.StatusCode,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.StatusCode == http.StatusOK {
+		dl.httpCache.put(url, result)
+	}
+	return result, nil
+}
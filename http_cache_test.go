@@ -0,0 +1,38 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalGet(t *testing.T) {
+	var notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("page"))
+	}))
+	defer srv.Close()
+
+	dl, _ := NewDouyinLive("1", nil)
+	first, err := dl.conditionalGet(context.Background(), dl.client.R(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := dl.conditionalGet(context.Background(), dl.client.R(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NotModified || !second.NotModified || notModified != 1 {
+		t.Fatalf("条件请求未生效: first=%v second=%v 304次数=%d", first.NotModified, second.NotModified, notModified)
+	}
+	if second.Body != "page" || second.Hash != first.Hash {
+		t.Fatalf("304 时应返回缓存内容, got %q", second.Body)
+	}
+}
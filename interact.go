@@ -0,0 +1,84 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxLikesPerCall 单次点赞请求的最大个数，与网页端连续点击的合并上限一致
+	maxLikesPerCall = 15
+	// maxLikes 单次 SendLike 的最大点赞个数，避免账号因刷赞被风控
+	maxLikes = 300
+	// minPresenceInterval 在场保活的最短间隔
+	minPresenceInterval = 30 * time.Second
+)
+
+// 互动接口与点赞请求间隔，测试中会被替换
+var (
+	likeURL      = "https://live.douyin.com/webcast/room/like/"
+	enterURL     = "https://live.douyin.com/webcast/room/web/enter/"
+	likeInterval = time.Second
+)
+
+// SendLike 以登录账号的身份点赞，count 超过单次上限时拆成多次请求，请求之间间隔 1 秒，
+// 单次调用最多 300 个。需要登录且在连接后调用
+func (dl *DouyinLive) SendLike(ctx context.Context, count int) error {
+	if count <= 0 || count > maxLikes {
+		return fmt.Errorf("点赞个数必须在 1 到 %d 之间: %d", maxLikes, count)
+	}
+	for first := true; count > 0; first = false {
+		if !first {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(likeInterval):
+			}
+		}
+		n := min(count, maxLikesPerCall)
+		if err := dl.webcastCall(ctx, "点赞", http.MethodPost, likeURL, map[string]string{
+			"room_id": dl.roomID,
+			"count":   strconv.Itoa(n),
+		}); err != nil {
+			return err
+		}
+		count -= n
+	}
+	return nil
+}
+
+// EnterRoomPresence 以登录账号的身份进入直播间，使账号出现在观众列表中，需要登录且在连接后调用
+func (dl *DouyinLive) EnterRoomPresence(ctx context.Context) error {
+	return dl.webcastCall(ctx, "进入直播间", http.MethodGet, enterURL, map[string]string{
+		"web_rid":    dl.liveID,
+		"enter_from": "web_live",
+	})
+}
+
+// KeepPresence 按 interval 周期性调用 EnterRoomPresence 保持在场，直到 ctx 结束，
+// interval 小于 30 秒时按 30 秒计。单次失败只记录日志，登录失效时返回
+func (dl *DouyinLive) KeepPresence(ctx context.Context, interval time.Duration) error {
+	interval = max(interval, minPresenceInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := dl.EnterRoomPresence(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, ErrNotAuthenticated) {
+				return err
+			}
+			dl.log().Warn("在场保活失败", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
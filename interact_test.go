@@ -0,0 +1,56 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInteractions(t *testing.T) {
+	var likes []string
+	var entered int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/like/"):
+			r.ParseForm()
+			likes = append(likes, r.PostForm.Get("count"))
+		case r.URL.Query().Get("web_rid") == "live1":
+			entered++
+		}
+		w.Write([]byte(`{"status_code":0}`))
+	}))
+	defer srv.Close()
+	oldLike, oldEnter := likeURL, enterURL
+	likeURL, enterURL = srv.URL+"/webcast/room/like/", srv.URL+"/webcast/room/web/enter/"
+	oldInterval := likeInterval
+	likeInterval = 10 * time.Millisecond
+	defer func() { likeURL, enterURL, likeInterval = oldLike, oldEnter, oldInterval }()
+
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, WithCookieString("sessionid=abc"))
+	dl.liveID = "live1"
+	start := time.Now()
+	if err := dl.SendLike(context.Background(), 20); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(likes, ",") != "15,5" {
+		t.Fatalf("点赞应按上限拆分: %v", likes)
+	}
+	if elapsed := time.Since(start); elapsed < likeInterval {
+		t.Fatalf("拆分的请求之间应有间隔: %v", elapsed)
+	}
+	if err := dl.SendLike(context.Background(), maxLikes+1); err == nil || len(likes) != 2 {
+		t.Fatalf("超过上限应直接返回错误: %v, %v", err, likes)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := dl.SendLike(ctx, 20); !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消后应停止拆分的请求: %v", err)
+	}
+	if err := dl.EnterRoomPresence(context.Background()); err != nil || entered != 1 {
+		t.Fatalf("进入直播间: %v, %d", err, entered)
+	}
+}
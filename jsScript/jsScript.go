@@ -2,6 +2,7 @@ package jsScript
 
 import (
 	_ "embed"
+	"errors"
 	"github.com/dop251/goja"
 	"sync"
 )
@@ -9,20 +10,32 @@ import (
 // 嵌入的 JavaScript 文件来源于开源项目，感谢贡献者们的努力
 //
 //go:embed webmssdk.js
-var jsScript string
+var embeddedScript string
 
 var (
 	vm       *goja.Runtime
 	fGetSign func(string) string
 	mu       sync.Mutex
+	jsScript = embeddedScript // 当前使用的脚本，可通过 SetScript 热替换
+	lastUA   string           // 最近一次加载使用的 User-Agent
 )
 
 // LoadGoja 加载 JavaScript 到 Goja 运行时中，并设置必要的环境
 func LoadGoja(ua string) error {
 	mu.Lock()
 	defer mu.Unlock()
+	runtime, getSign, err := load(jsScript, ua)
+	if err != nil {
+		return err
+	}
+	vm, fGetSign, lastUA = runtime, getSign, ua
+	return nil
+}
+
+// load 在新的 Goja VM 中运行脚本并导出 get_sign
+func load(script, ua string) (*goja.Runtime, func(string) string, error) {
 	// 创建一个新的 Goja VM 实例
-	vm = goja.New()
+	runtime := goja.New()
 
 	// 构建 JavaScript 环境，模拟浏览器的 navigator 和 window 对象
 	jsdom := `
@@ -33,18 +46,60 @@ func LoadGoja(ua string) error {
 		setTimeout = function() {};
 	`
 
-	// 运行 JavaScript 环境设置和嵌入的 JavaScript 代码
-	if _, err := vm.RunString(jsdom + jsScript); err != nil {
+	// 运行 JavaScript 环境设置和脚本
+	if _, err := runtime.RunString(jsdom + script); err != nil {
+		return nil, nil, err
+	}
+
+	// 将 JavaScript 函数 get_sign 导出为 Go 函数
+	fn := runtime.Get("get_sign")
+	if fn == nil || goja.IsUndefined(fn) || goja.IsNull(fn) {
+		return nil, nil, errors.New("脚本中未定义 get_sign")
+	}
+	var getSign func(string) string
+	if err := runtime.ExportTo(fn, &getSign); err != nil {
+		return nil, nil, err
+	}
+	return runtime, getSign, nil
+}
+
+// SetScript 热替换签名脚本，新脚本必须定义 get_sign。
+// 先在新的 VM 中加载验证，失败时保留原脚本；已加载过时立即以上次的 User-Agent 切换
+func SetScript(script string) error {
+	if script == "" {
+		return errors.New("签名脚本为空")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	ua := lastUA
+	if ua == "" {
+		ua = "Mozilla/5.0"
+	}
+	runtime, getSign, err := load(script, ua)
+	if err != nil {
 		return err
 	}
+	if getSign("") == "" {
+		return errors.New("签名脚本的 get_sign 返回为空")
+	}
+	jsScript = script
+	if lastUA != "" {
+		vm, fGetSign = runtime, getSign
+	}
+	return nil
+}
 
-	// 将 JavaScript 函数 get_sign 导出为 Go 函数 fGetSign
-	return vm.ExportTo(vm.Get("get_sign"), &fGetSign)
+// ResetScript 恢复为内嵌的签名脚本
+func ResetScript() error {
+	return SetScript(embeddedScript)
 }
 
-// ExecuteJS 执行 JavaScript 中的 get_sign 函数
+// ExecuteJS 执行 JavaScript 中的 get_sign 函数，脚本未加载时返回空字符串
 func ExecuteJS(signature string) string {
 	mu.Lock()
 	defer mu.Unlock()
+	if fGetSign == nil {
+		return ""
+	}
 	return fGetSign(signature)
 }
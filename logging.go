@@ -0,0 +1,74 @@
+package douyinLive
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// WithSlog 使用 slog.Logger 输出日志，设置后忽略构造函数传入的 logger
+func WithSlog(l *slog.Logger) Option {
+	return func(dl *DouyinLive) {
+		if l != nil {
+			dl.slog = l
+		}
+	}
+}
+
+// WithLogLevel 设置兼容 logger 的最低输出级别，默认 slog.LevelInfo
+func WithLogLevel(level slog.Level) Option {
+	return func(dl *DouyinLive) {
+		dl.logLevel.Set(level)
+	}
+}
+
+// roomLogger 附带房间信息的 logger 及生成它时的房间信息
+type roomLogger struct {
+	liveID, roomID, liveName string
+	logger                   *slog.Logger
+}
+
+// log 返回附带房间信息的结构化 logger，房间信息变化时才重新生成
+func (dl *DouyinLive) log() *slog.Logger {
+	liveID, roomID, liveName := dl.liveID, dl.roomID, dl.LiveName
+	if c := dl.roomLog.Load(); c != nil && c.liveID == liveID && c.roomID == roomID && c.liveName == liveName {
+		return c.logger
+	}
+	l := dl.slog.With(
+		slog.String("live_id", liveID),
+		slog.String("room_id", roomID),
+		slog.String("live_name", liveName),
+	)
+	dl.roomLog.Store(&roomLogger{liveID: liveID, roomID: roomID, liveName: liveName, logger: l})
+	return l
+}
+
+// initLogger 根据构造参数初始化 slog，旧的 logger 接口通过 printfWriter 适配
+func (dl *DouyinLive) initLogger(l logger) {
+	if dl.slog != nil {
+		return
+	}
+	if l == nil {
+		dl.slog = slog.Default()
+		return
+	}
+	dl.slog = slog.New(slog.NewTextHandler(printfWriter{l}, &slog.HandlerOptions{
+		Level: &dl.logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// 时间由旧 logger 自行输出
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// printfWriter 将 slog 的输出转交给旧的 logger 接口
+type printfWriter struct {
+	l logger
+}
+
+func (w printfWriter) Write(p []byte) (int, error) {
+	w.l.Print(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,15 @@
+package douyinLive
+
+import "testing"
+
+func TestLogReusesRoomLogger(t *testing.T) {
+	dl := NewDouyinLive2("100", "200", "test", "", nil)
+	first := dl.log()
+	if dl.log() != first {
+		t.Fatal("房间信息未变时应复用 logger")
+	}
+	dl.roomID = "101"
+	if dl.log() == first {
+		t.Fatal("roomID 变化后应重新生成 logger")
+	}
+}
@@ -0,0 +1,86 @@
+package douyinLive
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMethodFilter(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithMethodFilter(MethodFilter{Disable: []string{WebcastLikeMessage}}))
+	var got []string
+	dl.SubscribeEvent(func(event *LiveEvent) { got = append(got, event.Method) })
+
+	for _, method := range []string{WebcastLikeMessage, WebcastChatMessage} {
+		dl.handleSingleMessage(context.Background(), &new_douyin.Webcast_Im_Message{Method: method})
+	}
+	dl.SetMethodFilter(MethodFilter{Only: []string{WebcastGiftMessage}})
+	for _, method := range []string{WebcastChatMessage, WebcastGiftMessage, WebcastControlMessage} {
+		dl.handleSingleMessage(context.Background(), &new_douyin.Webcast_Im_Message{Method: method})
+	}
+
+	want := []string{WebcastChatMessage, WebcastGiftMessage, WebcastControlMessage}
+	if !slices.Equal(got, want) {
+		t.Fatalf("收到 %v, 期望 %v", got, want)
+	}
+	if n := dl.Stats().Filtered; n != 2 {
+		t.Fatalf("Filtered = %d", n)
+	}
+}
+
+func TestMethodFilterSkipsDecode(t *testing.T) {
+	for _, pool := range []bool{false, true} {
+		dl, _ := NewDouyinLive("1", nil, WithMessagePool(pool), WithMethodFilter(MethodFilter{Disable: []string{WebcastLikeMessage}}))
+		data, _ := proto.Marshal(&new_douyin.Webcast_Im_Response{
+			Messages: []*new_douyin.Webcast_Im_Message{
+				{Method: WebcastLikeMessage, Payload: []byte{0xff}},
+				{Method: WebcastChatMessage},
+			},
+			Cursor: "c-1",
+		})
+		response, err := dl.unmarshalResponse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(response.Messages) != 1 || response.Messages[0].Method != WebcastChatMessage || response.Cursor != "c-1" {
+			t.Fatalf("pool=%v 解码结果错误: %v", pool, response)
+		}
+		if n := dl.Stats().Filtered; n != 1 {
+			t.Fatalf("pool=%v Filtered = %d", pool, n)
+		}
+		dl.discardResponse(response)
+	}
+}
+
+func TestMethodFilterSource(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil)
+	dl.SetMethodFilterSource(func(liveID, roomID string) MethodFilter {
+		if roomID == "7380" {
+			return MethodFilter{Only: []string{WebcastGiftMessage}}
+		}
+		return MethodFilter{}
+	})
+	if !dl.MethodFilter().IsZero() {
+		t.Fatal("roomID 未知时不应匹配房间配置")
+	}
+
+	dl.mu.Lock()
+	dl.roomID = "7380"
+	dl.mu.Unlock()
+	dl.refreshMethodFilter()
+	if dl.MethodFilter().Allows(WebcastChatMessage) {
+		t.Fatal("解析出 roomID 后未重新应用")
+	}
+
+	dl.SetMethodFilterSource(nil)
+	dl.mu.Lock()
+	dl.roomID = "1"
+	dl.mu.Unlock()
+	dl.refreshMethodFilter()
+	if dl.MethodFilter().Allows(WebcastChatMessage) {
+		t.Fatal("取消后应保留当前开关")
+	}
+}
@@ -0,0 +1,24 @@
+package douyinLive
+
+// Handler 事件处理函数，中间件包装的对象
+type Handler func(event *LiveEvent)
+
+// Middleware 处理器中间件，用于日志、指标、限流、过滤等对全部订阅生效的逻辑；
+// 不调用 next 即跳过该订阅者
+type Middleware func(next Handler) Handler
+
+// WithMiddleware 添加对全部订阅生效的中间件，先添加的在最外层。
+// Subscribe 注册的原始消息处理器同样经过中间件，收到的仍是原始消息
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(dl *DouyinLive) {
+		dl.middlewares = append(dl.middlewares, middlewares...)
+	}
+}
+
+// wrap 用全部中间件包装处理器
+func (dl *DouyinLive) wrap(h Handler) Handler {
+	for i := len(dl.middlewares) - 1; i >= 0; i-- {
+		h = dl.middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,44 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestMiddleware(t *testing.T) {
+	var trace []string
+	logging := func(next Handler) Handler {
+		return func(e *LiveEvent) {
+			trace = append(trace, "log:"+e.Method)
+			next(e)
+		}
+	}
+	dropLikes := func(next Handler) Handler {
+		return func(e *LiveEvent) {
+			if e.Method != WebcastLikeMessage {
+				next(e)
+			}
+		}
+	}
+	dl, _ := NewDouyinLive("1", nil, WithMiddleware(logging, dropLikes))
+	dl.SubscribeEvent(func(e *LiveEvent) { trace = append(trace, "event:"+e.Method) })
+	dl.Subscribe(func(m *new_douyin.Webcast_Im_Message) { trace = append(trace, "raw:"+m.Method) })
+
+	dl.handleSingleMessage(context.Background(), &new_douyin.Webcast_Im_Message{Method: WebcastChatMessage})
+	dl.handleSingleMessage(context.Background(), &new_douyin.Webcast_Im_Message{Method: WebcastLikeMessage})
+
+	want := []string{
+		"log:WebcastChatMessage", "event:WebcastChatMessage", "log:WebcastChatMessage", "raw:WebcastChatMessage",
+		"log:WebcastLikeMessage", "log:WebcastLikeMessage",
+	}
+	if len(trace) != len(want) {
+		t.Fatalf("trace = %v", trace)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("trace = %v, want %v", trace, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/tiga210/douyinLive"
+)
+
+const (
+	defaultDriftWindow      = 500
+	defaultDriftMinWindows  = 3
+	defaultDriftMaxDepth    = 2
+	defaultDriftMinBaseline = 0.9
+	defaultDriftMaxRate     = 0.1
+	driftBaselineAlpha      = 0.2 // 基线的指数移动平均系数
+)
+
+// DriftOptions 消息模式漂移检测配置
+type DriftOptions struct {
+	Window      int     // 每种消息类型每统计多少条评估一次，默认 500
+	MinWindows  int     // 基线至少经过多少个窗口后才开始告警，默认 3
+	MaxDepth    int     // 嵌套消息的展开深度，默认 2（如 gift.diamond_count）
+	MinBaseline float64 // 只对基线填充率不低于该值的字段告警，默认 0.9
+	MaxRate     float64 // 窗口填充率不高于该值时视为大面积为空，默认 0.1
+	OnAlert     func(DriftAlert)
+}
+
+// DriftAlert 字段填充率突变告警，Recovered 为 true 时表示字段已恢复
+type DriftAlert struct {
+	Time      time.Time
+	Method    string
+	Field     string  // 字段路径，如 gift.diamond_count
+	Baseline  float64 // 长期填充率
+	Rate      float64 // 本窗口填充率
+	Samples   int     // 本窗口中该字段的统计条数
+	Recovered bool
+}
+
+// String 输出可读的告警
+func (a DriftAlert) String() string {
+	if a.Recovered {
+		return fmt.Sprintf("%s.%s 填充率已恢复: %.0f%%", a.Method, a.Field, a.Rate*100)
+	}
+	return fmt.Sprintf("%s.%s 填充率骤降: 基线 %.0f%% → %.0f%%（%d 条），可能是平台改版", a.Method, a.Field, a.Baseline*100, a.Rate*100, a.Samples)
+}
+
+// fieldStat 单个字段的统计
+type fieldStat struct {
+	seen, filled int     // 当前窗口
+	baseline     float64 // 长期填充率
+	windows      int     // 已计入基线的窗口数
+	drifted      bool
+}
+
+// methodStat 单种消息类型的统计
+type methodStat struct {
+	count  int
+	fields map[string]*fieldStat
+}
+
+// DriftMonitor 长期统计各消息类型的字段填充率，字段突然大面积为空时告警
+type DriftMonitor struct {
+	opts DriftOptions
+
+	mu      sync.Mutex
+	methods map[string]*methodStat
+}
+
+// NewDriftMonitor 创建漂移检测
+func NewDriftMonitor(opts DriftOptions) *DriftMonitor {
+	if opts.Window <= 0 {
+		opts.Window = defaultDriftWindow
+	}
+	if opts.MinWindows <= 0 {
+		opts.MinWindows = defaultDriftMinWindows
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultDriftMaxDepth
+	}
+	if opts.MinBaseline <= 0 {
+		opts.MinBaseline = defaultDriftMinBaseline
+	}
+	if opts.MaxRate <= 0 {
+		opts.MaxRate = defaultDriftMaxRate
+	}
+	return &DriftMonitor{opts: opts, methods: make(map[string]*methodStat)}
+}
+
+// Watch 统计直播间的全部消息，返回的订阅 ID 可用于 Unsubscribe
+func (m *DriftMonitor) Watch(dl *douyinLive.DouyinLive) string {
+	return dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if decoded, err := event.Decode(); err == nil {
+			m.Observe(event.Method, decoded.ProtoReflect())
+		}
+	})
+}
+
+// Observe 统计一条已解码的消息，窗口结束时评估并回调告警
+func (m *DriftMonitor) Observe(method string, msg protoreflect.Message) {
+	m.mu.Lock()
+	stat, ok := m.methods[method]
+	if !ok {
+		stat = &methodStat{fields: make(map[string]*fieldStat)}
+		m.methods[method] = stat
+	}
+	m.walk(stat, "", msg, 1)
+	stat.count++
+	var alerts []DriftAlert
+	if stat.count >= m.opts.Window {
+		alerts = m.evaluate(method, stat)
+	}
+	m.mu.Unlock()
+
+	if m.opts.OnAlert != nil {
+		for _, alert := range alerts {
+			m.opts.OnAlert(alert)
+		}
+	}
+}
+
+// walk 记录每个字段是否有值，有值的嵌套消息继续展开，调用方需持有锁
+func (m *DriftMonitor) walk(stat *methodStat, prefix string, msg protoreflect.Message, depth int) {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := string(fd.Name())
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		f, ok := stat.fields[path]
+		if !ok {
+			f = &fieldStat{}
+			stat.fields[path] = f
+		}
+		f.seen++
+		if !msg.Has(fd) {
+			continue
+		}
+		f.filled++
+		if fd.Kind() == protoreflect.MessageKind && fd.Cardinality() != protoreflect.Repeated && depth < m.opts.MaxDepth {
+			m.walk(stat, path, msg.Get(fd).Message(), depth+1)
+		}
+	}
+}
+
+// evaluate 结束当前窗口：对比基线产生告警，再更新基线，调用方需持有锁
+func (m *DriftMonitor) evaluate(method string, stat *methodStat) []DriftAlert {
+	var alerts []DriftAlert
+	now := time.Now()
+	for path, f := range stat.fields {
+		if f.seen == 0 {
+			continue
+		}
+		rate := float64(f.filled) / float64(f.seen)
+		alert := DriftAlert{Time: now, Method: method, Field: path, Baseline: f.baseline, Rate: rate, Samples: f.seen}
+		switch {
+		case !f.drifted && f.windows >= m.opts.MinWindows && f.baseline >= m.opts.MinBaseline && rate <= m.opts.MaxRate:
+			f.drifted = true
+			alerts = append(alerts, alert)
+		case f.drifted && rate >= m.opts.MinBaseline:
+			f.drifted = false
+			alert.Recovered = true
+			alerts = append(alerts, alert)
+		}
+		// 漂移期间不更新基线，避免基线被异常数据拉低后不再告警
+		if !f.drifted {
+			if f.windows == 0 {
+				f.baseline = rate
+			} else {
+				f.baseline += driftBaselineAlpha * (rate - f.baseline)
+			}
+			f.windows++
+		}
+		f.seen, f.filled = 0, 0
+	}
+	stat.count = 0
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Field < alerts[j].Field })
+	return alerts
+}
+
+// FillRates 返回各消息类型各字段的长期填充率，尚未完成首个窗口的字段不包含在内
+func (m *DriftMonitor) FillRates() map[string]map[string]float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]map[string]float64, len(m.methods))
+	for method, stat := range m.methods {
+		rates := make(map[string]float64)
+		for path, f := range stat.fields {
+			if f.windows > 0 {
+				rates[path] = f.baseline
+			}
+		}
+		if len(rates) > 0 {
+			out[method] = rates
+		}
+	}
+	return out
+}
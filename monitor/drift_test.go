@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDriftMonitorAlerts(t *testing.T) {
+	var alerts []DriftAlert
+	m := NewDriftMonitor(DriftOptions{Window: 10, MinWindows: 2, OnAlert: func(a DriftAlert) { alerts = append(alerts, a) }})
+	observe := func(windows int, diamond int32) {
+		for i := 0; i < windows*10; i++ {
+			m.Observe("WebcastGiftMessage", (&new_douyin.Webcast_Im_GiftMessage{
+				GiftId: 1,
+				Gift:   &new_douyin.Webcast_Data_GiftStruct{Name: "小心心", DiamondCount: diamond},
+			}).ProtoReflect())
+		}
+	}
+
+	observe(3, 1)
+	if len(alerts) != 0 || m.FillRates()["WebcastGiftMessage"]["gift.diamond_count"] != 1 {
+		t.Fatalf("正常数据不应告警: %v, %v", alerts, m.FillRates())
+	}
+
+	observe(2, 0)
+	if len(alerts) != 1 || alerts[0].Field != "gift.diamond_count" || alerts[0].Recovered {
+		t.Fatalf("价值全为 0 时应告警一次: %v", alerts)
+	}
+
+	observe(1, 1)
+	if len(alerts) != 2 || !alerts[1].Recovered {
+		t.Fatalf("恢复后应发出恢复通知: %v", alerts)
+	}
+}
@@ -0,0 +1,228 @@
+// Package monitor 提供跨直播间的弹幕监控、消息模式漂移检测与端到端延迟 SLO 监控
+package monitor
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	defaultReportInterval = time.Minute
+	defaultMaxSamples     = 5
+)
+
+// KeywordOptions 关键词监控配置
+type KeywordOptions struct {
+	Keywords   map[string][]string  // 竞品名 → 关键词列表（含别名），匹配不区分大小写
+	Interval   time.Duration        // 报告周期，默认 1 分钟
+	MaxSamples int                  // 每个房间每个竞品保留的样例弹幕数，默认 5
+	OnReport   func(*KeywordReport) // 每个周期结束时回调
+}
+
+// KeywordSample 命中关键词的样例弹幕
+type KeywordSample struct {
+	Time     time.Time
+	UserID   uint64
+	Nickname string
+	Content  string
+	Keyword  string // 命中的关键词
+}
+
+// CompetitorMentions 单个竞品在某房间内的提及情况
+type CompetitorMentions struct {
+	Name    string
+	Count   int
+	Samples []KeywordSample
+}
+
+// RoomMentions 单个房间的竞品提及，按提及次数降序
+type RoomMentions struct {
+	RoomID      string
+	LiveName    string
+	Competitors []CompetitorMentions
+}
+
+// KeywordReport 一个周期的监控报告，只包含有提及的房间
+type KeywordReport struct {
+	Start time.Time
+	End   time.Time
+	Rooms []RoomMentions
+}
+
+// keywordRule 关键词规则，lower 用于匹配
+type keywordRule struct {
+	competitor string
+	keyword    string
+	lower      string
+}
+
+// roomCounter 房间在当前周期内的计数
+type roomCounter struct {
+	liveName    string
+	competitors map[string]*CompetitorMentions
+}
+
+// KeywordMonitor 统计各房间弹幕中提及竞品的频次与样例，并周期性输出报告
+type KeywordMonitor struct {
+	opts  KeywordOptions
+	rules []keywordRule
+
+	mu    sync.Mutex
+	start time.Time
+	rooms map[string]*roomCounter
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewKeywordMonitor 创建关键词监控，调用 Start 后开始周期性输出报告
+func NewKeywordMonitor(opts KeywordOptions) *KeywordMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultReportInterval
+	}
+	if opts.MaxSamples <= 0 {
+		opts.MaxSamples = defaultMaxSamples
+	}
+	m := &KeywordMonitor{
+		opts:  opts,
+		start: time.Now(),
+		rooms: make(map[string]*roomCounter),
+	}
+	for competitor, keywords := range opts.Keywords {
+		for _, keyword := range keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				m.rules = append(m.rules, keywordRule{competitor: competitor, keyword: keyword, lower: strings.ToLower(keyword)})
+			}
+		}
+	}
+	return m
+}
+
+// Watch 监控直播间的弹幕，返回的订阅 ID 可用于 Unsubscribe
+func (m *KeywordMonitor) Watch(dl *douyinLive.DouyinLive) string {
+	return dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if event.Method != douyinLive.WebcastChatMessage {
+			return
+		}
+		decoded, err := event.Decode()
+		if err != nil {
+			return
+		}
+		if chat, ok := decoded.(*new_douyin.Webcast_Im_ChatMessage); ok {
+			m.Observe(event.RoomID, event.LiveName, event.Time, chat)
+		}
+	})
+}
+
+// Observe 统计一条弹幕，同一竞品在一条弹幕中只计一次
+func (m *KeywordMonitor) Observe(roomID, liveName string, t time.Time, chat *new_douyin.Webcast_Im_ChatMessage) {
+	content := strings.ToLower(chat.Content)
+	matched := make(map[string]string)
+	for _, rule := range m.rules {
+		if _, ok := matched[rule.competitor]; !ok && strings.Contains(content, rule.lower) {
+			matched[rule.competitor] = rule.keyword
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	room, ok := m.rooms[roomID]
+	if !ok {
+		room = &roomCounter{competitors: make(map[string]*CompetitorMentions)}
+		m.rooms[roomID] = room
+	}
+	room.liveName = liveName
+	for competitor, keyword := range matched {
+		mentions, ok := room.competitors[competitor]
+		if !ok {
+			mentions = &CompetitorMentions{Name: competitor}
+			room.competitors[competitor] = mentions
+		}
+		mentions.Count++
+		if len(mentions.Samples) < m.opts.MaxSamples {
+			sample := KeywordSample{Time: t, Content: chat.Content, Keyword: keyword}
+			if chat.User != nil {
+				sample.UserID = chat.User.Id
+				sample.Nickname = chat.User.Nickname
+			}
+			mentions.Samples = append(mentions.Samples, sample)
+		}
+	}
+}
+
+// Report 生成当前周期的报告并开始新的周期
+func (m *KeywordMonitor) Report() *KeywordReport {
+	m.mu.Lock()
+	rooms := m.rooms
+	report := &KeywordReport{Start: m.start, End: time.Now()}
+	m.rooms = make(map[string]*roomCounter)
+	m.start = report.End
+	m.mu.Unlock()
+
+	for roomID, room := range rooms {
+		r := RoomMentions{RoomID: roomID, LiveName: room.liveName}
+		for _, mentions := range room.competitors {
+			r.Competitors = append(r.Competitors, *mentions)
+		}
+		sort.Slice(r.Competitors, func(i, j int) bool {
+			if r.Competitors[i].Count != r.Competitors[j].Count {
+				return r.Competitors[i].Count > r.Competitors[j].Count
+			}
+			return r.Competitors[i].Name < r.Competitors[j].Name
+		})
+		report.Rooms = append(report.Rooms, r)
+	}
+	sort.Slice(report.Rooms, func(i, j int) bool { return report.Rooms[i].RoomID < report.Rooms[j].RoomID })
+	return report
+}
+
+// Start 开始周期性输出报告
+func (m *KeywordMonitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.run(m.stop, m.done)
+}
+
+// Stop 停止周期性报告，不会输出未结束周期的数据
+func (m *KeywordMonitor) Stop() {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop = nil
+	m.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run 按周期输出报告
+func (m *KeywordMonitor) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			report := m.Report()
+			if m.opts.OnReport != nil {
+				m.opts.OnReport(report)
+			}
+		}
+	}
+}
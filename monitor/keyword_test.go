@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestKeywordMonitorReport(t *testing.T) {
+	m := NewKeywordMonitor(KeywordOptions{
+		Keywords:   map[string][]string{"竞品A": {"品牌a", "BrandA"}, "竞品B": {"品牌b"}},
+		MaxSamples: 1,
+	})
+	user := &new_douyin.Webcast_Data_User{Id: 1, Nickname: "观众"}
+	for _, content := range []string{"brandA 更便宜", "品牌A和品牌b都用过", "无关弹幕"} {
+		m.Observe("100", "主播", time.Now(), &new_douyin.Webcast_Im_ChatMessage{User: user, Content: content})
+	}
+	m.Observe("200", "另一个主播", time.Now(), &new_douyin.Webcast_Im_ChatMessage{Content: "品牌b"})
+
+	report := m.Report()
+	if len(report.Rooms) != 2 {
+		t.Fatalf("房间数 = %d, want 2", len(report.Rooms))
+	}
+	room := report.Rooms[0]
+	if room.RoomID != "100" || room.Competitors[0].Name != "竞品A" || room.Competitors[0].Count != 2 {
+		t.Fatalf("房间 100 统计错误: %+v", room)
+	}
+	if len(room.Competitors[0].Samples) != 1 || room.Competitors[0].Samples[0].Keyword != "BrandA" {
+		t.Fatalf("样例弹幕错误: %+v", room.Competitors[0].Samples)
+	}
+	if next := m.Report(); len(next.Rooms) != 0 {
+		t.Fatalf("Report 后应开始新的周期: %+v", next)
+	}
+}
@@ -0,0 +1,212 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+const (
+	defaultLatencyObjective  = 3 * time.Second
+	defaultLatencyPercentile = 0.99
+	defaultLatencyWindow     = 1000
+)
+
+// 端到端延迟的分段，依次相接
+const (
+	StageServer  = "server"  // 服务端生成消息 → 下发
+	StageNetwork = "network" // 下发 → 本地收到（含两端时钟偏差）
+	StageProcess = "process" // 收到 → 交给处理器（解压、解码、分发队列）
+	StageSink    = "sink"    // 交给处理器 → Sink 写出完成
+)
+
+// latencyStages 分段的输出顺序
+var latencyStages = []string{StageServer, StageNetwork, StageProcess, StageSink}
+
+// LatencyOptions 端到端延迟 SLO 配置
+type LatencyOptions struct {
+	Objective  time.Duration // 延迟目标，默认 3 秒
+	Percentile float64       // 按该分位数判断是否达标，默认 0.99
+	Window     int           // 每写出多少条事件评估一次，默认 1000
+	OnAlert    func(LatencyAlert)
+}
+
+// LatencyAlert 一个窗口的延迟评估，超标时回调，恢复后再回调一次 Recovered 为 true 的结果
+type LatencyAlert struct {
+	Time       time.Time
+	Objective  time.Duration
+	Percentile float64
+	Latency    time.Duration            // 窗口内端到端延迟的分位数
+	Samples    int                      // 窗口内的事件数
+	Stages     map[string]time.Duration // 各分段的平均耗时
+	Bottleneck string                   // 平均耗时最长的分段
+	Breached   bool                     // 是否超出目标
+	Recovered  bool
+}
+
+// String 输出可读的告警与瓶颈分解
+func (a LatencyAlert) String() string {
+	var parts []string
+	for _, stage := range latencyStages {
+		if d, ok := a.Stages[stage]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", stage, d.Round(time.Millisecond)))
+		}
+	}
+	breakdown := strings.Join(parts, " / ")
+	p := a.Percentile * 100
+	if a.Recovered {
+		return fmt.Sprintf("端到端延迟已恢复: P%g %s（目标 %s），%s", p, a.Latency.Round(time.Millisecond), a.Objective, breakdown)
+	}
+	return fmt.Sprintf("端到端延迟超标: P%g %s > 目标 %s（%d 条），瓶颈 %s；%s",
+		p, a.Latency.Round(time.Millisecond), a.Objective, a.Samples, a.Bottleneck, breakdown)
+}
+
+// LatencySLO 监控"服务端消息时间 → Sink 写出完成"的端到端延迟，超标时告警并给出各分段耗时
+type LatencySLO struct {
+	opts LatencyOptions
+
+	mu       sync.Mutex
+	totals   []time.Duration
+	sums     map[string]time.Duration
+	counts   map[string]int
+	breached bool
+	last     LatencyAlert
+}
+
+// NewLatencySLO 创建延迟监控，需配合 Option 开启事件时间记录，并用 Wrap 包装 Sink
+func NewLatencySLO(opts LatencyOptions) *LatencySLO {
+	if opts.Objective <= 0 {
+		opts.Objective = defaultLatencyObjective
+	}
+	if opts.Percentile <= 0 || opts.Percentile > 1 {
+		opts.Percentile = defaultLatencyPercentile
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultLatencyWindow
+	}
+	return &LatencySLO{opts: opts, sums: make(map[string]time.Duration), counts: make(map[string]int)}
+}
+
+// Option 返回开启事件时间记录的选项，创建直播实例时传入
+func (m *LatencySLO) Option() douyinLive.Option {
+	return douyinLive.WithEventTiming()
+}
+
+// Wrap 包装 Sink，每批事件写出成功后统计其端到端延迟
+func (m *LatencySLO) Wrap(s sink.Sink) sink.Sink {
+	return &latencySink{Sink: s, slo: m}
+}
+
+// latencySink 写出后统计延迟的 Sink
+type latencySink struct {
+	sink.Sink
+	slo *LatencySLO
+}
+
+// Write 实现 sink.Sink
+func (s *latencySink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	if err := s.Sink.Write(ctx, events); err != nil {
+		return err
+	}
+	written := time.Now()
+	for _, event := range events {
+		s.slo.Observe(event.Timing(), written)
+	}
+	return nil
+}
+
+// Observe 统计一条事件，written 为写出完成的时间，窗口结束时评估并回调告警
+func (m *LatencySLO) Observe(timing douyinLive.EventTiming, written time.Time) {
+	points := []time.Time{timing.Server, timing.Pushed, timing.Received, timing.Dispatched, written}
+	var start time.Time
+	for _, t := range points[:4] {
+		if !t.IsZero() {
+			start = t
+			break
+		}
+	}
+	if start.IsZero() {
+		return
+	}
+
+	m.mu.Lock()
+	m.totals = append(m.totals, nonNegative(written.Sub(start)))
+	for i, stage := range latencyStages {
+		from, to := points[i], points[i+1]
+		if from.IsZero() || to.IsZero() {
+			continue
+		}
+		m.sums[stage] += nonNegative(to.Sub(from))
+		m.counts[stage]++
+	}
+	var alert *LatencyAlert
+	if len(m.totals) >= m.opts.Window {
+		alert = m.evaluate()
+	}
+	m.mu.Unlock()
+
+	if alert != nil && m.opts.OnAlert != nil {
+		m.opts.OnAlert(*alert)
+	}
+}
+
+// evaluate 结束当前窗口，需要告警时返回告警，调用方需持有锁
+func (m *LatencySLO) evaluate() *LatencyAlert {
+	sort.Slice(m.totals, func(i, j int) bool { return m.totals[i] < m.totals[j] })
+	idx := int(float64(len(m.totals))*m.opts.Percentile+0.5) - 1
+	idx = max(0, min(idx, len(m.totals)-1))
+	a := LatencyAlert{
+		Time:       time.Now(),
+		Objective:  m.opts.Objective,
+		Percentile: m.opts.Percentile,
+		Latency:    m.totals[idx],
+		Samples:    len(m.totals),
+		Stages:     make(map[string]time.Duration, len(latencyStages)),
+	}
+	var slowest time.Duration
+	for _, stage := range latencyStages {
+		if m.counts[stage] == 0 {
+			continue
+		}
+		avg := m.sums[stage] / time.Duration(m.counts[stage])
+		a.Stages[stage] = avg
+		if a.Bottleneck == "" || avg > slowest {
+			a.Bottleneck, slowest = stage, avg
+		}
+	}
+	a.Breached = a.Latency > m.opts.Objective
+
+	m.totals = m.totals[:0]
+	clear(m.sums)
+	clear(m.counts)
+	m.last = a
+
+	switch {
+	case a.Breached && !m.breached:
+		m.breached = true
+		return &a
+	case !a.Breached && m.breached:
+		m.breached = false
+		a.Recovered = true
+		return &a
+	}
+	return nil
+}
+
+// Last 返回最近一个窗口的评估结果，尚未完成首个窗口时为零值
+func (m *LatencySLO) Last() LatencyAlert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+// nonNegative 时钟偏差可能导致分段为负，按 0 计
+func nonNegative(d time.Duration) time.Duration {
+	return max(d, 0)
+}
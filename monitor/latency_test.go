@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestLatencySLO(t *testing.T) {
+	var alerts []LatencyAlert
+	m := NewLatencySLO(LatencyOptions{Objective: time.Second, Window: 10, OnAlert: func(a LatencyAlert) { alerts = append(alerts, a) }})
+	base := time.Now()
+	observe := func(sinkDelay time.Duration) {
+		for i := 0; i < 10; i++ {
+			timing := douyinLive.EventTiming{
+				Server:     base,
+				Pushed:     base.Add(100 * time.Millisecond),
+				Received:   base.Add(200 * time.Millisecond),
+				Dispatched: base.Add(250 * time.Millisecond),
+			}
+			m.Observe(timing, timing.Dispatched.Add(sinkDelay))
+		}
+	}
+
+	observe(100 * time.Millisecond)
+	if len(alerts) != 0 || m.Last().Latency != 350*time.Millisecond {
+		t.Fatalf("未超标不应告警: %v, %+v", alerts, m.Last())
+	}
+
+	observe(2 * time.Second)
+	if len(alerts) != 1 || !alerts[0].Breached || alerts[0].Bottleneck != StageSink || alerts[0].Stages[StageNetwork] != 100*time.Millisecond {
+		t.Fatalf("Sink 变慢应告警并指出瓶颈: %+v", alerts)
+	}
+	if s := alerts[0].String(); !strings.Contains(s, "瓶颈 sink") {
+		t.Fatalf("告警文本 = %s", s)
+	}
+	observe(2 * time.Second)
+	if len(alerts) != 1 {
+		t.Fatal("持续超标不应重复告警")
+	}
+
+	observe(0)
+	if len(alerts) != 2 || !alerts[1].Recovered {
+		t.Fatalf("恢复后应通知: %+v", alerts)
+	}
+}
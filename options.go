@@ -0,0 +1,15 @@
+package douyinLive
+
+// Option 配置 DouyinLive 实例的可选项
+type Option func(*DouyinLive)
+
+// applyOptions 依次应用所有可选项，环境变量中的特性开关与远程签名服务先于可选项生效
+func (dl *DouyinLive) applyOptions(opts []Option) {
+	dl.applyFeaturesEnv()
+	dl.applySignerEnv()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(dl)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package overlay OBS 浏览器源叠加层：内置的 HTTP 页面以透明背景滚动显示最近的弹幕与礼物提醒。
+// 在 OBS 中添加“浏览器”源，URL 填 http://127.0.0.1:8090/ 即可，页面通过 EventSource 接收事件
+package overlay
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+const (
+	defaultHistory      = 20
+	defaultClientBuffer = 64
+	defaultChatLifetime = 30 * time.Second
+	heartbeatInterval   = 15 * time.Second
+)
+
+// 叠加层中的消息类型
+const (
+	KindChat = "chat"
+	KindGift = "gift"
+)
+
+//go:embed overlay.html
+var pageHTML string
+
+var pageTemplate = template.Must(template.New("overlay").Parse(pageHTML))
+
+// Item 叠加层中的一条消息
+type Item struct {
+	Kind     string    `json:"kind"`
+	Time     time.Time `json:"time"`
+	Nickname string    `json:"nickname"`
+	Content  string    `json:"content,omitempty"`
+	GiftName string    `json:"gift_name,omitempty"`
+	GiftIcon string    `json:"gift_icon,omitempty"`
+	Count    uint64    `json:"count,omitempty"`
+	Diamond  int64     `json:"diamond,omitempty"` // 总价值（抖币）
+}
+
+// Options 叠加层配置
+type Options struct {
+	History        int           // 页面打开时补发的最近消息数，默认 20
+	ChatLifetime   time.Duration // 消息在页面上的停留时间，默认 30 秒
+	MinGiftDiamond int64         // 礼物提醒的最低总价值（抖币），低于该值的礼物不显示
+	CSS            template.CSS  // 追加到页面的自定义样式
+	ClientBuffer   int           // 每个页面的缓冲消息数，页面跟不上时丢弃新消息，默认 64
+}
+
+// Server 叠加层，实现 http.Handler：
+// 路径以 /events 结尾时输出 SSE 消息流，以 /recent 结尾时返回最近消息的 JSON，其余路径返回页面
+type Server struct {
+	opts Options
+
+	mu      sync.Mutex
+	recent  []Item
+	clients map[chan Item]struct{}
+}
+
+// New 创建叠加层
+func New(opts Options) *Server {
+	if opts.History <= 0 {
+		opts.History = defaultHistory
+	}
+	if opts.ChatLifetime <= 0 {
+		opts.ChatLifetime = defaultChatLifetime
+	}
+	if opts.ClientBuffer <= 0 {
+		opts.ClientBuffer = defaultClientBuffer
+	}
+	return &Server{opts: opts, clients: make(map[chan Item]struct{})}
+}
+
+// Watch 订阅直播实例的弹幕与合并连击后的礼物，返回取消订阅的函数
+func (s *Server) Watch(dl *douyinLive.DouyinLive) func() {
+	chat := dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if event.Method != douyinLive.WebcastChatMessage && event.Method != douyinLive.WebcastEmojiChatMessage {
+			return
+		}
+		s.Push(Item{Kind: KindChat, Time: event.Time, Nickname: event.Nickname(), Content: event.Content()})
+	})
+	gift := dl.SubscribeGiftCombo(0, s.ObserveGift)
+	return func() {
+		dl.Unsubscribe(chat)
+		dl.Unsubscribe(gift)
+	}
+}
+
+// ObserveGift 显示礼物提醒，总价值低于 MinGiftDiamond 时忽略
+func (s *Server) ObserveGift(gift *douyinLive.GiftEvent) {
+	if gift.TotalDiamond() < s.opts.MinGiftDiamond {
+		return
+	}
+	s.Push(Item{
+		Kind:     KindGift,
+		Time:     gift.Time,
+		Nickname: gift.Nickname,
+		GiftName: gift.GiftName,
+		GiftIcon: gift.IconURL,
+		Count:    gift.Count,
+		Diamond:  gift.TotalDiamond(),
+	})
+}
+
+// Push 向全部页面推送一条消息，页面跟不上时丢弃，不阻塞
+func (s *Server) Push(item Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent = append(s.recent, item)
+	if len(s.recent) > s.opts.History {
+		s.recent = s.recent[len(s.recent)-s.opts.History:]
+	}
+	for ch := range s.clients {
+		select {
+		case ch <- item:
+		default:
+		}
+	}
+}
+
+// Recent 返回最近的消息
+func (s *Server) Recent() []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Item(nil), s.recent...)
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/events"):
+		s.serveEvents(w, r)
+	case strings.HasSuffix(r.URL.Path, "/recent"):
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(s.Recent())
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		pageTemplate.Execute(w, map[string]interface{}{
+			"Lifetime": s.opts.ChatLifetime.Milliseconds(),
+			"CSS":      s.opts.CSS,
+		})
+	}
+}
+
+// serveEvents 先补发最近消息，再持续推送新消息
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "不支持流式响应", http.StatusInternalServerError)
+		return
+	}
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ch := make(chan Item, s.opts.ClientBuffer)
+	s.mu.Lock()
+	recent := append([]Item(nil), s.recent...)
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	for _, item := range recent {
+		writeItem(w, item)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case item := <-ch:
+			writeItem(w, item)
+		}
+		flusher.Flush()
+	}
+}
+
+// writeItem 写入一条 SSE 消息，事件名为消息类型
+func writeItem(w http.ResponseWriter, item Item) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", item.Kind, data)
+}
@@ -0,0 +1,66 @@
+package overlay
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// nextData 读取下一条 SSE 消息的 data 行
+func nextData(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			return line
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := New(Options{History: 2, MinGiftDiamond: 10})
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	s.Push(Item{Kind: KindChat, Nickname: "a", Content: "1"})
+	s.Push(Item{Kind: KindChat, Nickname: "b", Content: "2"})
+	s.Push(Item{Kind: KindChat, Nickname: "c", Content: "3"})
+	s.ObserveGift(&douyinLive.GiftEvent{Nickname: "d", GiftName: "小心心", DiamondCount: 1, Count: 1})
+	if recent := s.Recent(); len(recent) != 2 || recent[0].Nickname != "b" {
+		t.Fatalf("最近消息 = %v", recent)
+	}
+
+	resp, err := http.Get(srv.URL + "/overlay/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(page), `new EventSource("events")`) {
+		t.Fatalf("页面内容 = %s", page)
+	}
+
+	resp, err = http.Get(srv.URL + "/overlay/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	if line := nextData(t, reader); !strings.Contains(line, `"nickname":"b"`) {
+		t.Fatalf("补发消息 = %s", line)
+	}
+	nextData(t, reader)
+
+	s.ObserveGift(&douyinLive.GiftEvent{Nickname: "e", GiftName: "嘉年华", DiamondCount: 3000, Count: 1})
+	if line := nextData(t, reader); !strings.Contains(line, `"gift_name":"嘉年华"`) || !strings.Contains(line, `"diamond":3000`) {
+		t.Fatalf("礼物消息 = %s", line)
+	}
+}
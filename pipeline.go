@@ -0,0 +1,112 @@
+package douyinLive
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// WithDecodeWorkers 开启分阶段的解码流水线：读取 → 解压 → 解码 → 分发。
+// PushFrame 解析、解压与 Response 解码由 n 个 goroutine 并行完成，
+// 之后按收到的顺序串行确认与分发，消息顺序与 ack 不受影响，单个繁忙的直播间也能用上多核。
+// n<=1 时在读取 goroutine 中串行处理
+func WithDecodeWorkers(n int) Option {
+	return func(dl *DouyinLive) {
+		dl.decodeWorkers = n
+	}
+}
+
+// decodedFrame 一帧 PushFrame 在各阶段之间传递的状态
+type decodedFrame struct {
+	data     []byte
+	frame    *new_douyin.Webcast_Im_PushFrame
+	ok       bool // PushFrame 是否解析成功
+	received time.Time
+	conn     *websocket.Conn // 收到该帧的连接，ack 发回该连接；重连后旧连接上的帧不会在新连接上确认
+
+	// 以下仅消息帧
+	ctx      context.Context
+	span     trace.Span
+	response *new_douyin.Webcast_Im_Response // 解压或解码失败时为 nil
+	err      error
+
+	done chan struct{} // 流水线中解码完成后关闭
+}
+
+// decodeFrame 解析 PushFrame，消息帧同时解压并解码其中的 Response
+func (dl *DouyinLive) decodeFrame(f *decodedFrame) {
+	if err := proto.Unmarshal(f.data, f.frame); err != nil {
+		dl.log().Warn("解析PushFrame失败", "error", err)
+		return
+	}
+	f.ok = true
+	if f.frame.PayloadType == "msg" {
+		dl.decodeResponse(f)
+	}
+}
+
+// processFrame 通知原始帧订阅者并确认、分发其中的消息
+func (dl *DouyinLive) processFrame(f *decodedFrame) {
+	if !f.ok {
+		return
+	}
+	dl.emitFrame(f.frame, f.data)
+	if f.span != nil {
+		dl.deliverResponse(f)
+	}
+}
+
+// decodePipeline 并行解码、按序分发的流水线
+type decodePipeline struct {
+	jobs    chan *decodedFrame // 等待解码
+	ordered chan *decodedFrame // 按收到的顺序等待分发
+	wg      sync.WaitGroup
+}
+
+// startPipeline 启动 decodeWorkers 个解码 goroutine 与一个分发 goroutine
+func (dl *DouyinLive) startPipeline() *decodePipeline {
+	n := dl.decodeWorkers
+	p := &decodePipeline{
+		jobs:    make(chan *decodedFrame, n),
+		ordered: make(chan *decodedFrame, n*4),
+	}
+	p.wg.Add(n + 1)
+	for range n {
+		go func() {
+			defer p.wg.Done()
+			for f := range p.jobs {
+				dl.decodeFrame(f)
+				close(f.done)
+			}
+		}()
+	}
+	go func() {
+		defer p.wg.Done()
+		for f := range p.ordered {
+			<-f.done
+			dl.processFrame(f)
+		}
+	}()
+	return p
+}
+
+// submit 放入从 conn 收到的一帧原始数据，流水线已满时等待
+func (p *decodePipeline) submit(data []byte, conn *websocket.Conn) {
+	f := &decodedFrame{data: data, conn: conn, frame: &new_douyin.Webcast_Im_PushFrame{}, done: make(chan struct{})}
+	// 先占分发顺序再交给解码 goroutine，保证分发顺序与收到的顺序一致
+	p.ordered <- f
+	p.jobs <- f
+}
+
+// stop 等待已提交的帧全部解码并分发
+func (p *decodePipeline) stop() {
+	close(p.jobs)
+	close(p.ordered)
+	p.wg.Wait()
+}
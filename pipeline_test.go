@@ -0,0 +1,117 @@
+package douyinLive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestDecodePipelineKeepsOrder(t *testing.T) {
+	var recorded bytes.Buffer
+	fw := NewFrameWriter(&recorded)
+	const frames = 200
+	for i := 1; i <= frames; i++ {
+		msg := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+		msg.MsgId = uint64(i)
+		fw.WriteFrame(time.Time{}, gzipFrame(t, msg))
+	}
+	// 非法帧应被跳过而不影响后续帧
+	fw.WriteFrame(time.Time{}, []byte{0xff})
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithDecodeWorkers(4))
+	var ids []uint64
+	var rawFrames int
+	dl.SubscribeFrame(func(*new_douyin.Webcast_Im_PushFrame, []byte) { rawFrames++ })
+	dl.Subscribe(func(msg *new_douyin.Webcast_Im_Message) { ids = append(ids, msg.MsgId) })
+	if err := dl.ReplayFrames(context.Background(), &recorded, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != frames || rawFrames != frames {
+		t.Fatalf("收到 %d 条消息、%d 帧, want %d", len(ids), rawFrames, frames)
+	}
+	for i, id := range ids {
+		if id != uint64(i+1) {
+			t.Fatalf("第 %d 条消息 msgId=%d, 顺序被打乱", i, id)
+		}
+	}
+}
+
+// ackServer 统计收到的 ack 帧数的 WebSocket 服务
+func ackServer(t *testing.T) (*websocket.Conn, *atomic.Int32) {
+	t.Helper()
+	var acks atomic.Int32
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var frame new_douyin.Webcast_Im_PushFrame
+			if proto.Unmarshal(data, &frame) == nil && frame.PayloadType == "ack" {
+				acks.Add(1)
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, &acks
+}
+
+func TestDecodePipelineAcksOnReceivingConn(t *testing.T) {
+	body, _ := proto.Marshal(&new_douyin.Webcast_Im_Response{NeedAck: true, InternalExt: "ext"})
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(body)
+	zw.Close()
+	frame, _ := proto.Marshal(&new_douyin.Webcast_Im_PushFrame{PayloadType: "msg", Payload: buf.Bytes()})
+
+	oldConn, oldAcks := ackServer(t)
+	newConn, newAcks := ackServer(t)
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithDecodeWorkers(4))
+	dl.setConn(oldConn)
+
+	// 分发 goroutine 确认期间读取 goroutine 重连，换成新连接
+	const frames = 100
+	p := dl.startPipeline()
+	swapped := make(chan struct{})
+	for i := range frames {
+		if i == frames/2 {
+			go func() {
+				dl.setConn(newConn)
+				close(swapped)
+			}()
+		}
+		p.submit(frame, oldConn)
+	}
+	p.stop()
+	<-swapped
+
+	deadline := time.Now().Add(2 * time.Second)
+	for oldAcks.Load() < frames && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if oldAcks.Load() != frames || newAcks.Load() != 0 {
+		t.Fatalf("旧连接收到 %d 个 ack，新连接收到 %d 个，want %d / 0", oldAcks.Load(), newAcks.Load(), frames)
+	}
+}
@@ -0,0 +1,171 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 传输方式，见 Transport()
+const (
+	TransportWebSocket = "websocket"
+	TransportPolling   = "polling"
+)
+
+const (
+	defaultPollInterval = time.Second // 服务端未给出 fetch_interval 时的轮询间隔
+	minPollInterval     = 200 * time.Millisecond
+	maxPollFailures     = 5               // 连续失败次数达到后结束轮询
+	websocketRetryEvery = 5 * time.Minute // 轮询期间尝试恢复 WebSocket 的间隔
+)
+
+// imFetchURL HTTP 轮询接口，即 WebSocket URL 中 im_path 指向的地址，测试中会被替换
+var imFetchURL = "https://live.douyin.com/webcast/im/fetch/"
+
+// errSwitchTransport 消息循环需要切换传输方式
+var errSwitchTransport = errors.New("切换传输方式")
+
+// Transport 返回当前使用的传输方式，未连接时为空
+func (dl *DouyinLive) Transport() string {
+	if dl.polling.Load() {
+		return TransportPolling
+	}
+	dl.mu.RLock()
+	defer dl.mu.RUnlock()
+	if dl.conn != nil {
+		return TransportWebSocket
+	}
+	return ""
+}
+
+// pollingEnabled 是否允许在 WebSocket 不可用时降级为 HTTP 轮询，见 FeatureHTTPPolling
+func (dl *DouyinLive) pollingEnabled() bool {
+	return dl.FeatureEnabled(FeatureHTTPPolling)
+}
+
+// pollMessages 轮询 im/fetch 接口代替 WebSocket，消息进入与 WebSocket 相同的处理流程，
+// 并定期尝试恢复 WebSocket，成功时返回 errSwitchTransport
+func (dl *DouyinLive) pollMessages() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	dl.mu.Lock()
+	dl.pollCancel = cancel
+	dl.mu.Unlock()
+	defer cancel()
+
+	dl.polling.Store(true)
+	defer dl.polling.Store(false)
+	dl.log().Warn("WebSocket不可用，降级为HTTP轮询")
+
+	failures := 0
+	lastTry := time.Now()
+	for dl.isLiving.Load() {
+		interval, err := dl.pollOnce(ctx)
+		if dl.manualClose.Load() {
+			return nil
+		}
+		if err != nil {
+			failures++
+			dl.log().Warn("HTTP轮询失败", "attempt", failures, "error", err)
+			if failures >= maxPollFailures {
+				return fmt.Errorf("%w: HTTP轮询连续失败: %w", ErrReconnectFailed, err)
+			}
+			interval = defaultPollInterval * time.Duration(failures)
+		} else {
+			failures = 0
+		}
+
+		if time.Since(lastTry) >= websocketRetryEvery {
+			lastTry = time.Now()
+			if err := dl.startWebSocket(ctx); err == nil {
+				dl.log().Info("WebSocket已恢复，停止HTTP轮询")
+				return errSwitchTransport
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+	}
+	if dl.manualClose.Load() {
+		return nil
+	}
+	return ErrLiveEnded
+}
+
+// pollOnce 请求一次 im/fetch，返回服务端建议的下次轮询间隔
+func (dl *DouyinLive) pollOnce(ctx context.Context) (interval time.Duration, err error) {
+	ctx, span := dl.startSpan(ctx, "douyinLive.poll")
+	defer func() { endSpan(span, err) }()
+
+	protocol := dl.ProtocolParams()
+	cursor, internalExt := dl.resumeState()
+	resp, err := dl.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", dl.userAgent).
+		SetHeader("Referer", fmt.Sprintf("https://live.douyin.com/%s", dl.liveID)).
+		SetHeader("Cookie", dl.cookieHeader()).
+		SetQueryParams(map[string]string{
+			"aid":                    webcastAid,
+			"app_name":               "douyin_web",
+			"device_platform":        "web",
+			"version_code":           protocol.VersionCode,
+			"webcast_sdk_version":    protocol.WebcastSDKVersion,
+			"update_version_code":    protocol.UpdateVersionCode,
+			"live_id":                "1",
+			"did_rule":               "3",
+			"endpoint":               "live_pc",
+			"identity":               "audience",
+			"support_wrds":           "1",
+			"need_persist_msg_count": "15",
+			"resp_content_type":      "protobuf",
+			"fetch_rule":             "1",
+			"room_id":                dl.roomID,
+			"user_unique_id":         dl.pushID,
+			"cursor":                 cursor,
+			"internal_ext":           internalExt,
+		}).
+		Get(imFetchURL)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+
+	response, err := dl.unmarshalResponse(resp.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("解析Response失败: %w", err)
+	}
+	span.SetAttributes(attribute.Int("douyin.message_count", len(response.Messages)))
+	deliver := dl.interceptResponse(response, ResponseInfo{Polling: true})
+	dl.saveResumeState(response.Cursor, response.InternalExt)
+	interval = time.Duration(response.FetchInterval) * time.Millisecond
+	if deliver {
+		ctx = dl.withResponseTiming(ctx, response, time.Now())
+		for _, msg := range response.Messages {
+			dl.handleSingleMessage(ctx, msg)
+		}
+		dl.releaseResponse(response)
+	} else {
+		dl.discardResponse(response)
+	}
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return max(interval, minPollInterval), nil
+}
+
+// stopPolling 结束 HTTP 轮询
+func (dl *DouyinLive) stopPolling() {
+	dl.mu.Lock()
+	cancel := dl.pollCancel
+	dl.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
@@ -0,0 +1,71 @@
+package douyinLive
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestHTTPPolling(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		n := len(cursors)
+		mu.Unlock()
+		data, _ := proto.Marshal(&new_douyin.Webcast_Im_Response{
+			Messages:      []*new_douyin.Webcast_Im_Message{{Method: WebcastChatMessage, MsgId: uint64(n)}},
+			Cursor:        "c" + string(rune('0'+n)),
+			FetchInterval: 10,
+		})
+		w.Write(data)
+	}))
+	defer srv.Close()
+	old := imFetchURL
+	imFetchURL = srv.URL + "/webcast/im/fetch/"
+	defer func() { imFetchURL = old }()
+
+	dl := NewDouyinLive2("100", "200", "test", "t", nil)
+	dl.SetFeature(FeatureHTTPPolling, true)
+	received := make(chan uint64, 8)
+	dl.Subscribe(func(msg *new_douyin.Webcast_Im_Message) { received <- msg.MsgId })
+
+	done := make(chan error, 1)
+	go func() { done <- dl.processMessages() }()
+	for want := uint64(1); want <= 2; want++ {
+		select {
+		case id := <-received:
+			if id != want {
+				t.Fatalf("msg_id = %d, want %d", id, want)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatal("未收到轮询消息")
+		}
+	}
+	if got := dl.Transport(); got != TransportPolling {
+		t.Fatalf("Transport() = %q", got)
+	}
+
+	dl.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("手动关闭应返回 nil: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close 后轮询未结束")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// 第二次请求沿用第一次响应中的 cursor
+	if len(cursors) < 2 || cursors[0] != "" || cursors[1] != "c1" {
+		t.Fatalf("cursors = %v", cursors)
+	}
+}
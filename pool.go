@@ -0,0 +1,164 @@
+package douyinLive
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	// responseMessagesField Webcast_Im_Response.messages 的字段编号
+	responseMessagesField = 1
+	// messageMethodField Webcast_Im_Message.method 的字段编号
+	messageMethodField = 1
+)
+
+var (
+	responsePool  = sync.Pool{New: func() any { return new(new_douyin.Webcast_Im_Response) }}
+	imMessagePool = sync.Pool{New: func() any { return new(new_douyin.Webcast_Im_Message) }}
+)
+
+// WithMessagePool 复用 Response 与 Message 对象，减少热门直播间的内存分配与 GC 压力。
+//
+// 开启后的所有权规则：Subscribe 处理器收到的 *Webcast_Im_Message、OnUnknown 的 payload
+// 以及 ResponseInterceptor 收到的 Response 只在回调期间有效，返回后会被回收复用，
+// 需要保留时应自行 proto.Clone。copyEvents 为 true 时 SubscribeEvent 等事件处理器收到的
+// LiveEvent 持有消息的副本，可以放心保留（如 sink 批量写入）；为 false 时 LiveEvent.Message
+// 同样只在回调期间有效，回调返回后会被清空复用。事件单独引用了消息体（回收时不会复用），
+// 因此保留的事件仍可调用 Decode、Data 与 Payload，只是不能再读取 Message
+func WithMessagePool(copyEvents bool) Option {
+	return func(dl *DouyinLive) {
+		dl.messagePool = true
+		dl.poolCopyEvents = copyEvents
+	}
+}
+
+// unmarshalResponse 解码 Response，开启 WithMessagePool 时 Response 与其中的 Message 取自对象池，
+// 设置了采集开关时跳过被关闭类型的消息
+func (dl *DouyinLive) unmarshalResponse(data []byte) (*new_douyin.Webcast_Im_Response, error) {
+	filter := dl.activeMethodFilter()
+	if !dl.messagePool && filter == nil {
+		response := new(new_douyin.Webcast_Im_Response)
+		if err := proto.Unmarshal(data, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+	response := new(new_douyin.Webcast_Im_Response)
+	if dl.messagePool {
+		response = responsePool.Get().(*new_douyin.Webcast_Im_Response)
+	}
+	if err := dl.unmarshalMessages(data, response, filter); err != nil {
+		dl.discardResponse(response)
+		return nil, err
+	}
+	return response, nil
+}
+
+// newMessage 返回用于解码的 Message，开启 WithMessagePool 时取自对象池
+func (dl *DouyinLive) newMessage() *new_douyin.Webcast_Im_Message {
+	if dl.messagePool {
+		return imMessagePool.Get().(*new_douyin.Webcast_Im_Message)
+	}
+	return new(new_douyin.Webcast_Im_Message)
+}
+
+// unmarshalMessages 逐个字段解码 Response：messages 中被 filter 关闭的类型只读取 method 即跳过，
+// 其余消息逐条解码，其他字段合并解码
+func (dl *DouyinLive) unmarshalMessages(data []byte, response *new_douyin.Webcast_Im_Response, filter *MethodFilter) error {
+	var rest []byte
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		if num == responseMessagesField && typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(data[n:])
+			if filter != nil && !filter.Allows(messageMethod(v)) {
+				dl.filtered.Add(1)
+				data = data[n+m:]
+				continue
+			}
+			msg := dl.newMessage()
+			if err := proto.Unmarshal(v, msg); err != nil {
+				dl.releaseMessage(msg)
+				return err
+			}
+			response.Messages = append(response.Messages, msg)
+		} else {
+			rest = append(rest, data[:n+m]...)
+		}
+		data = data[n+m:]
+	}
+	return proto.UnmarshalOptions{Merge: true}.Unmarshal(rest, response)
+}
+
+// messageMethod 不解码整条消息，只读取其 method 字段
+func messageMethod(data []byte) string {
+	var method string
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return method
+		}
+		m := protowire.ConsumeFieldValue(num, typ, data[n:])
+		if m < 0 {
+			return method
+		}
+		if num == messageMethodField && typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(data[n:])
+			// 重复出现时与 proto 解码一致，以最后一个为准
+			method = string(v)
+		}
+		data = data[n+m:]
+	}
+	return method
+}
+
+// releaseResponse 回收 Response，其中的消息已交给 handleSingleMessage，由其各自回收
+func (dl *DouyinLive) releaseResponse(response *new_douyin.Webcast_Im_Response) {
+	if !dl.messagePool || response == nil {
+		return
+	}
+	// 保留 Messages 的底层数组，下次解码时复用
+	messages := response.Messages
+	clear(messages)
+	response.Reset()
+	response.Messages = messages[:0]
+	responsePool.Put(response)
+}
+
+// discardResponse 回收未分发的 Response 及其中的全部消息
+func (dl *DouyinLive) discardResponse(response *new_douyin.Webcast_Im_Response) {
+	if !dl.messagePool || response == nil {
+		return
+	}
+	for _, msg := range response.Messages {
+		dl.releaseMessage(msg)
+	}
+	dl.releaseResponse(response)
+}
+
+// releaseMessage 在消息的生命周期结束（分发完毕或被丢弃）时回收
+func (dl *DouyinLive) releaseMessage(msg *new_douyin.Webcast_Im_Message) {
+	if !dl.messagePool || msg == nil {
+		return
+	}
+	msg.Reset()
+	imMessagePool.Put(msg)
+}
+
+// eventMessage 返回事件持有的消息，开启 WithMessagePool(true) 时为副本
+func (dl *DouyinLive) eventMessage(msg *new_douyin.Webcast_Im_Message) *new_douyin.Webcast_Im_Message {
+	if dl.messagePool && dl.poolCopyEvents {
+		return proto.Clone(msg).(*new_douyin.Webcast_Im_Message)
+	}
+	return msg
+}
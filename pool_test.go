@@ -0,0 +1,130 @@
+package douyinLive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestUnmarshalPooledResponse(t *testing.T) {
+	want := &new_douyin.Webcast_Im_Response{
+		Messages: []*new_douyin.Webcast_Im_Message{
+			{Method: WebcastChatMessage, MsgId: 1, Payload: []byte("a")},
+			{Method: WebcastLikeMessage, MsgId: 2, Payload: []byte("b")},
+		},
+		Cursor:      "c-1",
+		InternalExt: "ext",
+		NeedAck:     true,
+		RouteParams: map[string]string{"k": "v"},
+	}
+	data, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithMessagePool(false))
+	for range 3 {
+		got, err := dl.unmarshalResponse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, want) {
+			t.Fatalf("解码结果 %v, want %v", got, want)
+		}
+		dl.discardResponse(got)
+	}
+	if _, err := dl.unmarshalResponse([]byte{0x0a, 0xff}); err == nil {
+		t.Fatal("截断的数据应返回错误")
+	}
+}
+
+func TestMessagePoolCopyEvents(t *testing.T) {
+	var recorded bytes.Buffer
+	fw := NewFrameWriter(&recorded)
+	const frames = 50
+	for i := 1; i <= frames; i++ {
+		msg := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+		msg.MsgId = uint64(i)
+		fw.WriteFrame(time.Time{}, gzipFrame(t, msg, msg))
+	}
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithMessagePool(true))
+	var kept []*LiveEvent
+	var raw int
+	dl.Subscribe(func(msg *new_douyin.Webcast_Im_Message) {
+		if msg.MsgId == 0 || msg.Method != WebcastChatMessage {
+			t.Errorf("回调期间的消息不应被回收: %v", msg)
+		}
+		raw++
+	})
+	dl.SubscribeEvent(func(event *LiveEvent) { kept = append(kept, event) })
+	if err := dl.ReplayFrames(context.Background(), &recorded, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if raw != 2*frames || len(kept) != 2*frames {
+		t.Fatalf("收到 %d/%d 条, want %d", raw, len(kept), 2*frames)
+	}
+	for i, event := range kept {
+		if want := uint64(i/2 + 1); event.Message.MsgId != want || event.Content() != "你好" {
+			t.Fatalf("第 %d 个事件 msgId=%d content=%q, 保留的事件应持有消息副本", i, event.Message.MsgId, event.Content())
+		}
+	}
+}
+
+func TestMessagePoolKeptEventsDecode(t *testing.T) {
+	var recorded bytes.Buffer
+	fw := NewFrameWriter(&recorded)
+	const frames = 20
+	for i := 1; i <= frames; i++ {
+		msg := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: fmt.Sprint(i)})
+		msg.MsgId = uint64(i)
+		fw.WriteFrame(time.Time{}, gzipFrame(t, msg))
+	}
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithMessagePool(false))
+	var kept []*LiveEvent
+	dl.SubscribeEvent(func(event *LiveEvent) { kept = append(kept, event) })
+	if err := dl.ReplayFrames(context.Background(), &recorded, ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != frames {
+		t.Fatalf("收到 %d 条, want %d", len(kept), frames)
+	}
+	// 消息已被回收复用，保留的事件仍应解码出自己的消息体
+	for i, event := range kept {
+		if want := fmt.Sprint(i + 1); event.Content() != want {
+			t.Fatalf("第 %d 个事件 content=%q, want %q", i, event.Content(), want)
+		}
+	}
+}
+
+func BenchmarkHandleGzipMessage(b *testing.B) {
+	messages := make([]*new_douyin.Webcast_Im_Message, 20)
+	for i := range messages {
+		payload, _ := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+		messages[i] = &new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: uint64(i + 1), Payload: payload}
+	}
+	raw := gzipFrame(b, messages...)
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"pool", []Option{WithMessagePool(false)}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			dl := NewDouyinLive2("1", "2", "test", "", nil, bc.opts...)
+			dl.Subscribe(func(*new_douyin.Webcast_Im_Message) {})
+			var pushFrame new_douyin.Webcast_Im_PushFrame
+			b.ReportAllocs()
+			for b.Loop() {
+				dl.handleFrame(&pushFrame, raw, nil)
+			}
+		})
+	}
+}
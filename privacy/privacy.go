@@ -0,0 +1,276 @@
+// Package privacy 弹幕聚合统计的差分隐私发布：限制单个用户的贡献后对热词与人数加入拉普拉斯噪声，
+// 用于对外发布行业报告等研究用途。每次 Release 满足 (Epsilon, Delta)-差分隐私
+package privacy
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	defaultEpsilon         = 1.0
+	defaultDelta           = 1e-6
+	defaultMaxChatsPerUser = 20
+	defaultMaxWordsPerUser = 10
+	defaultMinCount        = 5
+	defaultTopWords        = 20
+)
+
+// Options 差分隐私聚合的配置
+type Options struct {
+	Epsilon         float64               // 每次发布的隐私预算，平均分给弹幕数、发言人数、进房人数与热词四项统计，默认 1
+	Delta           float64               // 热词发布允许的失败概率，决定发布阈值，默认 1e-6
+	MaxChatsPerUser int                   // 单个用户在一个周期内最多计入的弹幕数，默认 20
+	MaxWordsPerUser int                   // 单个用户在一个周期内最多贡献的不同热词数，默认 10
+	MinCount        float64               // 加噪后低于此值的热词不发布，默认 5；实际阈值不低于 Threshold 按 Delta 算出的值
+	TopWords        int                   // 发布的热词数，默认 20
+	Tokenize        func(string) []string // 分词，默认按字母数字连续段切分，中文切为相邻二字词
+	Rand            *rand.Rand            // 噪声的随机源，仅用于测试：可预测的随机源不提供隐私保证。默认使用 crypto/rand
+}
+
+// WordCount 加噪后的热词计数
+type WordCount struct {
+	Word  string  `json:"word"`
+	Count float64 `json:"count"`
+}
+
+// Report 一个周期内加噪后的聚合结果，不包含任何用户级数据
+type Report struct {
+	Start    time.Time   `json:"start"`
+	End      time.Time   `json:"end"`
+	Epsilon  float64     `json:"epsilon"`
+	Delta    float64     `json:"delta"`
+	Chats    float64     `json:"chats"`    // 弹幕条数
+	Chatters float64     `json:"chatters"` // 发言人数
+	Viewers  float64     `json:"viewers"`  // 进房人数
+	Words    []WordCount `json:"words"`    // 热词，按计数降序
+}
+
+// userState 单个用户在本周期的贡献
+type userState struct {
+	chats int
+	words map[string]bool
+}
+
+// Aggregator 按周期聚合弹幕并以差分隐私方式发布
+type Aggregator struct {
+	opts Options
+
+	mu      sync.Mutex
+	start   time.Time
+	users   map[uint64]*userState
+	viewers map[uint64]bool
+	words   map[string]int
+}
+
+// NewAggregator 创建差分隐私聚合器
+func NewAggregator(opts Options) *Aggregator {
+	if opts.Epsilon <= 0 {
+		opts.Epsilon = defaultEpsilon
+	}
+	if opts.Delta <= 0 || opts.Delta >= 1 {
+		opts.Delta = defaultDelta
+	}
+	if opts.MaxChatsPerUser <= 0 {
+		opts.MaxChatsPerUser = defaultMaxChatsPerUser
+	}
+	if opts.MaxWordsPerUser <= 0 {
+		opts.MaxWordsPerUser = defaultMaxWordsPerUser
+	}
+	if opts.MinCount <= 0 {
+		opts.MinCount = defaultMinCount
+	}
+	if opts.TopWords <= 0 {
+		opts.TopWords = defaultTopWords
+	}
+	if opts.Tokenize == nil {
+		opts.Tokenize = Tokenize
+	}
+	a := &Aggregator{opts: opts}
+	a.reset(time.Now())
+	return a
+}
+
+// reset 开始新的周期
+func (a *Aggregator) reset(start time.Time) {
+	a.start = start
+	a.users = make(map[uint64]*userState)
+	a.viewers = make(map[uint64]bool)
+	a.words = make(map[string]int)
+}
+
+// Watch 聚合直播间的弹幕与进房消息，返回的订阅 ID 可用于 Unsubscribe
+func (a *Aggregator) Watch(dl *douyinLive.DouyinLive) string {
+	return dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if event.Method != douyinLive.WebcastChatMessage && event.Method != douyinLive.WebcastMemberMessage {
+			return
+		}
+		decoded, err := event.Decode()
+		if err != nil {
+			return
+		}
+		switch msg := decoded.(type) {
+		case *new_douyin.Webcast_Im_ChatMessage:
+			if msg.User != nil {
+				a.ObserveChat(msg.User.Id, msg.Content)
+			}
+		case *new_douyin.Webcast_Im_MemberMessage:
+			if msg.User != nil {
+				a.ObserveViewer(msg.User.Id)
+			}
+		}
+	})
+}
+
+// ObserveChat 计入一条弹幕，超过单用户上限的部分被忽略
+func (a *Aggregator) ObserveChat(userID uint64, content string) {
+	tokens := a.opts.Tokenize(content)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	u, ok := a.users[userID]
+	if !ok {
+		u = &userState{words: make(map[string]bool)}
+		a.users[userID] = u
+	}
+	if u.chats >= a.opts.MaxChatsPerUser {
+		return
+	}
+	u.chats++
+	for _, word := range tokens {
+		if u.words[word] || len(u.words) >= a.opts.MaxWordsPerUser {
+			continue
+		}
+		u.words[word] = true
+		a.words[word]++
+	}
+}
+
+// ObserveViewer 计入一次进房，同一用户在一个周期内只计一次
+func (a *Aggregator) ObserveViewer(userID uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.viewers[userID] = true
+}
+
+// Release 对当前周期加噪后发布，并开始新的周期
+func (a *Aggregator) Release() *Report {
+	a.mu.Lock()
+	end := time.Now()
+	report := &Report{Start: a.start, End: end, Epsilon: a.opts.Epsilon, Delta: a.opts.Delta}
+	var chats int
+	for _, u := range a.users {
+		chats += u.chats
+	}
+	chatters, viewers, words := len(a.users), len(a.viewers), a.words
+	a.reset(end)
+	a.mu.Unlock()
+
+	// 四项统计平均分配预算，敏感度为单个用户最多能改变的 L1 距离
+	eps := a.opts.Epsilon / 4
+	report.Chats = a.noisy(float64(chats), float64(a.opts.MaxChatsPerUser)/eps)
+	report.Chatters = a.noisy(float64(chatters), 1/eps)
+	report.Viewers = a.noisy(float64(viewers), 1/eps)
+
+	// 候选词来自数据本身，只发布加噪后超过阈值的词，阈值按 Delta 计算，
+	// 只有个别用户说过的词被发布的概率不超过 Delta
+	wordScale := float64(a.opts.MaxWordsPerUser) / eps
+	threshold := math.Max(a.opts.MinCount, Threshold(wordScale, a.opts.MaxWordsPerUser, a.opts.Delta))
+	for word, n := range words {
+		if count := a.noisy(float64(n), wordScale); count >= threshold {
+			report.Words = append(report.Words, WordCount{Word: word, Count: count})
+		}
+	}
+	sort.Slice(report.Words, func(i, j int) bool {
+		if report.Words[i].Count != report.Words[j].Count {
+			return report.Words[i].Count > report.Words[j].Count
+		}
+		return report.Words[i].Word < report.Words[j].Word
+	})
+	if len(report.Words) > a.opts.TopWords {
+		report.Words = report.Words[:a.opts.TopWords]
+	}
+	return report
+}
+
+// noisy 加入拉普拉斯噪声并取整，结果不小于 0
+func (a *Aggregator) noisy(value, scale float64) float64 {
+	return math.Max(0, math.Round(value+Laplace(a.opts.Rand, scale)))
+}
+
+// Threshold 返回 (ε,δ) 阈值：每个用户最多新增 maxKeys 个词、每词计数加 1，
+// 加入尺度为 scale 的拉普拉斯噪声后，只有一个用户说过的词超过阈值的概率不超过 delta。
+// τ = 1 + scale·ln(maxKeys/(2δ))
+func Threshold(scale float64, maxKeys int, delta float64) float64 {
+	return 1 + scale*math.Log(float64(max(maxKeys, 1))/(2*delta))
+}
+
+// Laplace 返回尺度为 scale 的拉普拉斯噪声，r 为空时使用 crypto/rand
+func Laplace(r *rand.Rand, scale float64) float64 {
+	float := cryptoFloat64
+	if r != nil {
+		float = r.Float64
+	}
+	u := float() - 0.5
+	for u == -0.5 {
+		u = float() - 0.5
+	}
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
+
+// cryptoFloat64 用 crypto/rand 生成 [0, 1) 内均匀分布的随机数
+func cryptoFloat64() float64 {
+	var b [8]byte
+	_, _ = cryptorand.Read(b[:])
+	return float64(binary.BigEndian.Uint64(b[:])>>11) / (1 << 53)
+}
+
+// Tokenize 默认分词：字母数字连续段作为一个词（转小写），中文连续段切为相邻二字词
+func Tokenize(s string) []string {
+	var tokens []string
+	var latin strings.Builder
+	var han []rune
+	flushLatin := func() {
+		if latin.Len() > 0 {
+			tokens = append(tokens, strings.ToLower(latin.String()))
+			latin.Reset()
+		}
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushLatin()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			latin.WriteRune(r)
+		default:
+			flushLatin()
+			flushHan()
+		}
+	}
+	flushLatin()
+	flushHan()
+	return tokens
+}
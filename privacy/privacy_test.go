@@ -0,0 +1,73 @@
+package privacy
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("主播好 666 Hello!上链接")
+	want := []string{"主播", "播好", "666", "hello", "上链", "链接"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Tokenize = %v", got)
+	}
+}
+
+func TestAggregatorRelease(t *testing.T) {
+	a := NewAggregator(Options{})
+	for user := uint64(1); user <= 2000; user++ {
+		a.ObserveChat(user, "上链接")
+		a.ObserveViewer(user)
+	}
+	// 单个用户的刷屏被限制在上限内
+	for i := 0; i < 1000; i++ {
+		a.ObserveChat(9999, "刷屏刷屏")
+	}
+
+	r := a.Release()
+	if math.Abs(r.Chats-2020) > 1000 || math.Abs(r.Chatters-2001) > 100 || math.Abs(r.Viewers-2000) > 100 {
+		t.Fatalf("加噪后的计数偏差过大: %+v", r)
+	}
+	words := make([]string, 0, len(r.Words))
+	for _, w := range r.Words {
+		words = append(words, w.Word)
+	}
+	slices.Sort(words)
+	if !slices.Equal(words, []string{"上链", "链接"}) || r.Delta != defaultDelta {
+		t.Fatalf("只应发布多人提到的热词: %+v", r.Words)
+	}
+
+	next := a.Release()
+	if len(next.Words) != 0 {
+		t.Fatalf("Release 后应开始新的周期: %+v", next)
+	}
+}
+
+func TestAggregatorHidesRareWords(t *testing.T) {
+	// 默认参数下，只有一个用户（即使刷满上限）说过的词不应被发布
+	a := NewAggregator(Options{})
+	for i := 0; i < 2000; i++ {
+		a.ObserveChat(1, "秘密")
+		if r := a.Release(); len(r.Words) != 0 {
+			t.Fatalf("第 %d 次发布泄露了单个用户的词: %+v", i, r.Words)
+		}
+	}
+	if tau := Threshold(40, 10, 1e-6); tau < 600 {
+		t.Fatalf("默认阈值 = %f", tau)
+	}
+}
+
+func TestLaplaceScale(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	var sum float64
+	const n = 20000
+	for i := 0; i < n; i++ {
+		sum += math.Abs(Laplace(r, 3))
+	}
+	// 拉普拉斯分布绝对值的期望等于尺度
+	if mean := sum / n; math.Abs(mean-3) > 0.15 {
+		t.Fatalf("平均绝对噪声 = %f", mean)
+	}
+}
@@ -0,0 +1,2 @@
+@echo off
+protoc --go_out=.. --go-grpc_out=.. live_service.proto
\ No newline at end of file
@@ -0,0 +1,141 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync/atomic"
+)
+
+// ProtocolParams WebSocket URL 中随抖音版本变化的参数
+type ProtocolParams struct {
+	VersionCode       string `json:"version_code"`
+	WebcastSDKVersion string `json:"webcast_sdk_version"`
+	UpdateVersionCode string `json:"update_version_code"`
+	WrdsV             string `json:"wrds_v"`
+}
+
+// DefaultProtocolParams 返回内置的协议参数，页面中解析不到时使用
+func DefaultProtocolParams() ProtocolParams {
+	return ProtocolParams{
+		VersionCode:       protocolVersionCode,
+		WebcastSDKVersion: webcastSDKVersion,
+		UpdateVersionCode: webcastSDKVersion,
+		WrdsV:             wrdsVersion,
+	}
+}
+
+// 页面中协议参数的位置，兼容转义的 JSON（\"version_code\":\"180800\"）与查询串（version_code=180800）
+var (
+	versionCodeRegex       = protocolParamRegex("version_code")
+	webcastSDKVersionRegex = protocolParamRegex("webcast_sdk_version")
+	updateVersionCodeRegex = protocolParamRegex("update_version_code")
+	wrdsVRegex             = protocolParamRegex("wrds_v")
+)
+
+// protocolParamRegex 生成匹配页面中某个参数值的正则
+func protocolParamRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(`[\\"&?]` + name + `\\?"?\s*[:=]\s*\\?"?([0-9A-Za-z][0-9A-Za-z.\-]*)`)
+}
+
+// ParseProtocolParams 从直播间页面中解析协议参数，解析不到的字段为空
+func ParseProtocolParams(page string) ProtocolParams {
+	return ProtocolParams{
+		VersionCode:       extractString(versionCodeRegex, page, 1),
+		WebcastSDKVersion: extractString(webcastSDKVersionRegex, page, 1),
+		UpdateVersionCode: extractString(updateVersionCodeRegex, page, 1),
+		WrdsV:             extractString(wrdsVRegex, page, 1),
+	}
+}
+
+// merge 用 p 中的非空字段覆盖 base
+func (p ProtocolParams) merge(base ProtocolParams) ProtocolParams {
+	if p.VersionCode != "" {
+		base.VersionCode = p.VersionCode
+	}
+	if p.WebcastSDKVersion != "" {
+		base.WebcastSDKVersion = p.WebcastSDKVersion
+		// 页面没有单独给出 update_version_code 时与 SDK 版本保持一致
+		if p.UpdateVersionCode == "" {
+			base.UpdateVersionCode = p.WebcastSDKVersion
+		}
+	}
+	if p.UpdateVersionCode != "" {
+		base.UpdateVersionCode = p.UpdateVersionCode
+	}
+	if p.WrdsV != "" {
+		base.WrdsV = p.WrdsV
+	}
+	return base
+}
+
+// LoadProtocolParams 读取 JSON 格式的协议参数配置文件，用于在不升级程序的情况下下发新版本号
+func LoadProtocolParams(path string) (ProtocolParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProtocolParams{}, err
+	}
+	var p ProtocolParams
+	if err := json.Unmarshal(data, &p); err != nil {
+		return ProtocolParams{}, fmt.Errorf("解析协议参数失败: %w", err)
+	}
+	return p, nil
+}
+
+// WithProtocolParams 固定协议参数，非空字段覆盖内置值，且不再从直播间页面解析
+func WithProtocolParams(p ProtocolParams) Option {
+	return func(dl *DouyinLive) {
+		dl.protocol = p.merge(DefaultProtocolParams())
+		dl.protocolFixed = true
+	}
+}
+
+// protocolPatch 运行时下发的协议参数，见 PatchProtocolParams
+var protocolPatch atomic.Pointer[ProtocolParams]
+
+// PatchProtocolParams 运行时为进程内所有实例下发协议参数，非空字段优先于页面解析值与内置值，
+// 下次连接或重连时生效；WithProtocolParams 固定的实例不受影响。传入零值撤销补丁
+func PatchProtocolParams(p ProtocolParams) {
+	if p == (ProtocolParams{}) {
+		protocolPatch.Store(nil)
+		return
+	}
+	protocolPatch.Store(&p)
+}
+
+// ProtocolParams 返回实例当前使用的协议参数
+func (dl *DouyinLive) ProtocolParams() ProtocolParams {
+	p := dl.pageProtocolParams()
+	if patch := protocolPatch.Load(); patch != nil && !dl.protocolFixed {
+		p = patch.merge(p)
+	}
+	return p
+}
+
+// pageProtocolParams 返回页面解析或 WithProtocolParams 指定的协议参数，不含运行时补丁
+func (dl *DouyinLive) pageProtocolParams() ProtocolParams {
+	dl.pageMu.Lock()
+	defer dl.pageMu.Unlock()
+	if dl.protocol.VersionCode == "" {
+		return DefaultProtocolParams()
+	}
+	return dl.protocol
+}
+
+// updateProtocolParams 用页面中解析出的参数更新协议参数，WithProtocolParams 固定时不更新
+func (dl *DouyinLive) updateProtocolParams(page string) {
+	if dl.protocolFixed {
+		return
+	}
+	current := dl.pageProtocolParams()
+	parsed := ParseProtocolParams(page).merge(current)
+	if parsed == current {
+		return
+	}
+	dl.pageMu.Lock()
+	dl.protocol = parsed
+	dl.pageMu.Unlock()
+	dl.log().Info("协议参数已从页面更新", "version_code", parsed.VersionCode,
+		"webcast_sdk_version", parsed.WebcastSDKVersion, "wrds_v", parsed.WrdsV)
+}
@@ -0,0 +1,51 @@
+package douyinLive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProtocolParams(t *testing.T) {
+	page := `<script>self.__pace_f.push([1,"{\"app_name\":\"douyin_web\",\"version_code\":\"190500\",` +
+		`\"webcast_sdk_version\":\"1.0.15\"}"])</script><script src="/x.js?wrds_v=7400000000000000001&aid=6383"></script>`
+	parsed := ParseProtocolParams(page)
+	if parsed.VersionCode != "190500" || parsed.WebcastSDKVersion != "1.0.15" || parsed.WrdsV != "7400000000000000001" {
+		t.Fatalf("parsed = %+v", parsed)
+	}
+
+	sign := WithSigner(SignerFunc(func(context.Context, SignRequest) (string, error) { return "sig", nil }))
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, sign)
+	if dl.ProtocolParams() != DefaultProtocolParams() {
+		t.Fatalf("未解析页面时应使用内置参数: %+v", dl.ProtocolParams())
+	}
+	dl.updateProtocolParams(page)
+	url, err := dl.makeURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"version_code=190500&", "webcast_sdk_version=1.0.15&", "update_version_code=1.0.15&", "wrds_v%3A7400000000000000001"} {
+		if !strings.Contains(url, want) {
+			t.Fatalf("URL 缺少 %s: %s", want, url)
+		}
+	}
+	// 页面中没有的字段保留原值
+	dl.updateProtocolParams(`<html></html>`)
+	if dl.ProtocolParams().VersionCode != "190500" {
+		t.Fatalf("params = %+v", dl.ProtocolParams())
+	}
+
+	path := filepath.Join(t.TempDir(), "protocol.json")
+	os.WriteFile(path, []byte(`{"version_code":"200000"}`), 0o644)
+	fixed, err := LoadProtocolParams(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dl = NewDouyinLive2("100", "200", "test", "t", nil, WithProtocolParams(fixed))
+	dl.updateProtocolParams(page)
+	if p := dl.ProtocolParams(); p.VersionCode != "200000" || p.WebcastSDKVersion != webcastSDKVersion {
+		t.Fatalf("固定参数不应被页面覆盖: %+v", p)
+	}
+}
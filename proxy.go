@@ -0,0 +1,316 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+// ErrNoHealthyProxy 代理池中没有可用的代理
+var ErrNoHealthyProxy = errors.New("没有可用的代理")
+
+// proxySeq 为使用代理池的实例分配标识
+var proxySeq atomic.Uint64
+
+// 代理池的默认配置
+const (
+	defaultProxyCheckURL     = "https://live.douyin.com/"
+	defaultProxyCooldown     = time.Minute
+	defaultProxyCheckTimeout = 10 * time.Second
+)
+
+// ProxyPoolOptions 代理池配置
+type ProxyPoolOptions struct {
+	CheckURL      string        // 健康检查访问的地址，默认直播首页
+	CheckInterval time.Duration // Start 后定期检查的间隔，0 表示只在调用 Check 时检查
+	CheckTimeout  time.Duration // 单个代理检查的超时，默认 10 秒
+	Cooldown      time.Duration // 代理失败后暂停分配的时长，默认 1 分钟
+	MaxFailures   int           // 连续失败多少次后暂停分配，默认 1
+}
+
+// ProxyStatus 代理的当前状态，见 ProxyPool.Status
+type ProxyStatus struct {
+	URL       string    // 隐去密码的代理地址
+	Healthy   bool      // 当前是否可分配
+	Failures  int       // 连续失败次数
+	Rooms     int       // 固定使用该代理的实例数
+	LastError string    // 最近一次失败的原因
+	LastCheck time.Time // 最近一次健康检查的时间
+}
+
+// proxyState 代理池中的一个代理
+type proxyState struct {
+	url       *url.URL
+	failures  int
+	until     time.Time // 暂停分配到该时刻
+	rooms     int
+	lastErr   error
+	lastCheck time.Time
+}
+
+// available 是否可以分配
+func (s *proxyState) available(now time.Time) bool {
+	return !now.Before(s.until)
+}
+
+// ProxyPool 带健康检查与自动轮换的代理池，可被多个实例共享。
+// 每个实例固定使用一个代理，代理返回 403 或 WebSocket 连接失败时换到其他可用代理
+type ProxyPool struct {
+	opts ProxyPoolOptions
+
+	mu      sync.Mutex
+	proxies []*proxyState
+	pinned  map[string]*proxyState // 实例 → 固定使用的代理
+}
+
+// NewProxyPool 创建代理池，proxies 为 http://、https:// 或 socks5:// 代理地址
+func NewProxyPool(proxies []string, opts ProxyPoolOptions) (*ProxyPool, error) {
+	if len(proxies) == 0 {
+		return nil, errors.New("代理列表为空")
+	}
+	if opts.CheckURL == "" {
+		opts.CheckURL = defaultProxyCheckURL
+	}
+	if opts.CheckTimeout <= 0 {
+		opts.CheckTimeout = defaultProxyCheckTimeout
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultProxyCooldown
+	}
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = 1
+	}
+	p := &ProxyPool{opts: opts, pinned: make(map[string]*proxyState)}
+	for _, raw := range proxies {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("无效的代理地址 %q", raw)
+		}
+		p.proxies = append(p.proxies, &proxyState{url: u})
+	}
+	return p, nil
+}
+
+// Pick 返回 key 固定使用的代理，尚未固定或原代理不可用时选择使用者最少的可用代理
+func (p *ProxyPool) Pick(key string) (*url.URL, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if s, ok := p.pinned[key]; ok {
+		if s.available(now) {
+			return s.url, nil
+		}
+		s.rooms--
+		delete(p.pinned, key)
+	}
+	var best *proxyState
+	for _, s := range p.proxies {
+		if s.available(now) && (best == nil || s.rooms < best.rooms) {
+			best = s
+		}
+	}
+	if best == nil {
+		return nil, ErrNoHealthyProxy
+	}
+	best.rooms++
+	p.pinned[key] = best
+	return best.url, nil
+}
+
+// Report 报告 key 当前代理的请求结果，err 非空时计为一次失败，
+// 失败次数达到 MaxFailures 后该代理暂停分配 Cooldown，下次 Pick 时换到其他代理
+func (p *ProxyPool) Report(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.pinned[key]
+	if !ok {
+		return
+	}
+	p.record(s, err)
+}
+
+// Release 解除 key 对代理的固定，实例关闭时调用，重复调用无效果
+func (p *ProxyPool) Release(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.pinned[key]; ok {
+		s.rooms--
+		delete(p.pinned, key)
+	}
+}
+
+// record 记录代理的一次结果，调用方持有 mu
+func (p *ProxyPool) record(s *proxyState, err error) {
+	if err == nil {
+		s.failures, s.lastErr = 0, nil
+		return
+	}
+	s.failures++
+	s.lastErr = err
+	if s.failures >= p.opts.MaxFailures {
+		s.until = time.Now().Add(p.opts.Cooldown)
+	}
+}
+
+// Check 立即对所有代理做一次健康检查：经代理访问 CheckURL，状态码小于 400 视为可用
+func (p *ProxyPool) Check(ctx context.Context) {
+	p.mu.Lock()
+	proxies := append([]*proxyState(nil), p.proxies...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range proxies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.probe(ctx, s.url)
+			p.mu.Lock()
+			s.lastCheck = time.Now()
+			if err == nil {
+				// 检查通过的代理立即恢复分配
+				s.until = time.Time{}
+			}
+			p.record(s, err)
+			p.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// probe 经代理访问 CheckURL
+func (p *ProxyPool) probe(ctx context.Context, proxy *url.URL) error {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.CheckTimeout)
+	defer cancel()
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+	defer client.CloseIdleConnections()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.opts.CheckURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("健康检查状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Start 按 CheckInterval 定期健康检查，直到 ctx 结束，CheckInterval 为 0 时只检查一次
+func (p *ProxyPool) Start(ctx context.Context) {
+	p.Check(ctx)
+	if p.opts.CheckInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(p.opts.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.Check(ctx)
+			}
+		}
+	}()
+}
+
+// Status 返回各代理的当前状态
+func (p *ProxyPool) Status() []ProxyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	status := make([]ProxyStatus, 0, len(p.proxies))
+	for _, s := range p.proxies {
+		st := ProxyStatus{
+			URL:       s.url.Redacted(),
+			Healthy:   s.available(now),
+			Failures:  s.failures,
+			Rooms:     s.rooms,
+			LastCheck: s.lastCheck,
+		}
+		if s.lastErr != nil {
+			st.LastError = s.lastErr.Error()
+		}
+		status = append(status, st)
+	}
+	return status
+}
+
+// WithProxyPool HTTP 接口与 WebSocket 经 pool 中的代理访问，实例固定使用其中一个，Close 时释放。
+// HTTP 返回 403、请求失败或 WebSocket 连接失败时计为该代理的失败并自动换用其他代理
+func WithProxyPool(pool *ProxyPool) Option {
+	return func(dl *DouyinLive) {
+		dl.proxyPool = pool
+		dl.proxyID = strconv.FormatUint(proxySeq.Add(1), 10)
+	}
+}
+
+// proxyKey 实例在代理池中的标识，同一直播间的多个实例各自固定代理
+func (dl *DouyinLive) proxyKey() string {
+	return dl.proxyID
+}
+
+// releaseProxy 释放实例固定的代理
+func (dl *DouyinLive) releaseProxy() {
+	if dl.proxyPool != nil {
+		dl.proxyPool.Release(dl.proxyKey())
+	}
+}
+
+// proxy 返回当前使用的代理，实现 http.Transport.Proxy 与 websocket.Dialer.Proxy
+func (dl *DouyinLive) proxy(*http.Request) (*url.URL, error) {
+	return dl.proxyPool.Pick(dl.proxyKey())
+}
+
+// reportProxy 报告经代理的请求结果，调用方取消的请求不计入
+func (dl *DouyinLive) reportProxy(err error) {
+	if dl.proxyPool == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		dl.log().Warn("代理请求失败，换用其他代理", "error", err)
+	}
+	dl.proxyPool.Report(dl.proxyKey(), err)
+}
+
+// initProxy 为 HTTP 客户端设置代理并在 403 与请求失败时轮换
+func (dl *DouyinLive) initProxy() {
+	if dl.proxyPool == nil {
+		return
+	}
+	dl.client.SetProxy(dl.proxy)
+	dl.client.OnAfterResponse(func(_ *req.Client, resp *req.Response) error {
+		switch {
+		case resp.Err != nil:
+			dl.reportProxy(resp.Err)
+		case resp.Response != nil && resp.StatusCode == http.StatusForbidden:
+			dl.reportProxy(fmt.Errorf("状态码: %d", resp.StatusCode))
+		}
+		return nil
+	})
+}
+
+// reportProxyDial 报告 WebSocket 握手结果，只有网络错误与 403 计为代理失败，
+// 服务端给出的其他响应（如签名错误）与代理无关
+func (dl *DouyinLive) reportProxyDial(resp *http.Response, err error) {
+	switch {
+	case err == nil:
+		dl.reportProxy(nil)
+	case resp == nil:
+		dl.reportProxy(err)
+	case resp.StatusCode == http.StatusForbidden:
+		dl.reportProxy(fmt.Errorf("握手状态码: %d", resp.StatusCode))
+	}
+}
@@ -0,0 +1,125 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProxyPoolPick(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a.invalid:1", "http://b.invalid:1"}, ProxyPoolOptions{Cooldown: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 直播间分散到使用者最少的代理，之后固定使用
+	a, _ := pool.Pick("room1")
+	b, _ := pool.Pick("room2")
+	if a.Host == b.Host {
+		t.Fatalf("两个直播间都分配到了 %s", a.Host)
+	}
+	if again, _ := pool.Pick("room1"); again.Host != a.Host {
+		t.Fatalf("room1 未固定使用 %s，得到 %s", a.Host, again.Host)
+	}
+
+	// 失败后换到其他代理，冷却结束前不再分配
+	pool.Report("room1", errors.New("403"))
+	if got, _ := pool.Pick("room1"); got.Host != b.Host {
+		t.Fatalf("失败后应换到 %s，得到 %s", b.Host, got.Host)
+	}
+	pool.Report("room1", errors.New("403"))
+	if _, err := pool.Pick("room1"); !errors.Is(err, ErrNoHealthyProxy) {
+		t.Fatalf("全部失败时 err = %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, err := pool.Pick("room1"); err != nil {
+		t.Fatalf("冷却结束后应恢复分配: %v", err)
+	}
+
+	if _, err := NewProxyPool([]string{"not a proxy"}, ProxyPoolOptions{}); err == nil {
+		t.Fatal("无效的代理地址应返回错误")
+	}
+}
+
+// forwardProxy 模拟 HTTP 代理，对所有请求返回 status 与 body
+func forwardProxy(t *testing.T, status int, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProxyPoolCheck(t *testing.T) {
+	good := forwardProxy(t, http.StatusOK, "")
+	bad := forwardProxy(t, http.StatusForbidden, "")
+	pool, err := NewProxyPool([]string{bad.URL, good.URL}, ProxyPoolOptions{CheckURL: "http://live.invalid/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Check(context.Background())
+
+	status := pool.Status()
+	if status[0].Healthy || status[0].LastError == "" || status[0].LastCheck.IsZero() {
+		t.Fatalf("返回 403 的代理应不可用: %+v", status[0])
+	}
+	if !status[1].Healthy || status[1].Failures != 0 {
+		t.Fatalf("正常的代理应可用: %+v", status[1])
+	}
+	if u, _ := pool.Pick("room"); u.String() != good.URL {
+		t.Fatalf("应分配到健康的代理，得到 %s", u)
+	}
+}
+
+func TestWithProxyPool(t *testing.T) {
+	blocked := forwardProxy(t, http.StatusForbidden, "blocked")
+	good := forwardProxy(t, http.StatusOK, "ok")
+	pool, err := NewProxyPool([]string{blocked.URL, good.URL}, ProxyPoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithProxyPool(pool))
+	if dl.wsDialer().Proxy == nil {
+		t.Fatal("WebSocket Dialer 未使用代理池")
+	}
+
+	// 第一个代理返回 403 后换用第二个
+	resp, err := dl.client.R().Get("http://webcast.invalid/")
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("第一次请求 = %v, %v", resp, err)
+	}
+	resp, err = dl.client.R().Get("http://webcast.invalid/")
+	if err != nil || resp.String() != "ok" {
+		t.Fatalf("轮换后请求 = %v, %v", resp, err)
+	}
+	if status := pool.Status(); status[0].Healthy || status[1].Rooms != 1 {
+		t.Fatalf("Status = %+v", status)
+	}
+}
+
+func TestProxyPoolRelease(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a.invalid:1", "http://b.invalid:1"}, ProxyPoolOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 同一直播间的两个实例各自固定代理
+	first, _ := NewDouyinLive("1", nil, WithProxyPool(pool))
+	second, _ := NewDouyinLive("1", nil, WithProxyPool(pool))
+	a, _ := first.proxy(nil)
+	b, _ := second.proxy(nil)
+	if a.Host == b.Host {
+		t.Fatalf("两个实例共用了 %s", a.Host)
+	}
+
+	first.Close()
+	second.Close()
+	for _, s := range pool.Status() {
+		if s.Rooms != 0 {
+			t.Fatalf("Close 后未释放代理: %+v", s)
+		}
+	}
+	pool.Release("unknown")
+}
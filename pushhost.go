@@ -0,0 +1,62 @@
+package douyinLive
+
+import "strings"
+
+// hostRotateAfter 同一推送节点连续握手失败的次数达到后切换到下一个节点
+const hostRotateAfter = 2
+
+// DefaultPushHosts 抖音 Web 端的推送节点，按顺序轮换
+var DefaultPushHosts = []string{
+	"webcast5-ws-web-lf.douyin.com",
+	"webcast5-ws-web-hl.douyin.com",
+	"webcast5-ws-web-lq.douyin.com",
+}
+
+// WithPushHosts 覆盖推送节点列表，连续握手失败时按顺序切换到下一个节点，为空时使用 DefaultPushHosts
+func WithPushHosts(hosts ...string) Option {
+	return func(dl *DouyinLive) {
+		dl.pushHosts = nil
+		for _, host := range hosts {
+			host = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(host), "wss://"), "/")
+			if host != "" {
+				dl.pushHosts = append(dl.pushHosts, host)
+			}
+		}
+	}
+}
+
+// PushHost 返回当前使用的推送节点
+func (dl *DouyinLive) PushHost() string {
+	dl.hostMu.Lock()
+	defer dl.hostMu.Unlock()
+	return dl.currentHost()
+}
+
+// currentHost 返回当前节点，调用方持有 hostMu
+func (dl *DouyinLive) currentHost() string {
+	hosts := dl.pushHosts
+	if len(hosts) == 0 {
+		hosts = DefaultPushHosts
+	}
+	return hosts[dl.hostIndex%len(hosts)]
+}
+
+// reportDial 记录一次握手结果，同一节点连续失败 hostRotateAfter 次后切换到下一个节点
+func (dl *DouyinLive) reportDial(err error) {
+	dl.hostMu.Lock()
+	defer dl.hostMu.Unlock()
+	if err == nil {
+		dl.hostFailures = 0
+		return
+	}
+	dl.hostFailures++
+	if dl.hostFailures < hostRotateAfter {
+		return
+	}
+	from := dl.currentHost()
+	dl.hostIndex++
+	dl.hostFailures = 0
+	if to := dl.currentHost(); to != from {
+		dl.log().Warn("推送节点连续握手失败，切换节点", "from", from, "to", to)
+	}
+}
@@ -0,0 +1,49 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPushHostRotation(t *testing.T) {
+	sign := WithSigner(SignerFunc(func(context.Context, SignRequest) (string, error) { return "sig", nil }))
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, sign, WithPushHosts("a.example.com", " wss://b.example.com/ "))
+
+	if got := dl.PushHost(); got != "a.example.com" {
+		t.Fatalf("PushHost() = %s", got)
+	}
+	dialErr := errors.New("dial")
+	dl.reportDial(dialErr)
+	if got := dl.PushHost(); got != "a.example.com" {
+		t.Fatalf("失败一次不应切换: %s", got)
+	}
+	dl.reportDial(dialErr)
+	if got := dl.PushHost(); got != "b.example.com" {
+		t.Fatalf("连续失败后应切换: %s", got)
+	}
+	url, err := dl.makeURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(url, "wss://b.example.com/webcast/im/push/v2/?") {
+		t.Fatalf("url = %s", url)
+	}
+
+	// 成功后计数清零，循环回到第一个节点
+	dl.reportDial(dialErr)
+	dl.reportDial(nil)
+	dl.reportDial(dialErr)
+	if got := dl.PushHost(); got != "b.example.com" {
+		t.Fatalf("成功后应清零失败计数: %s", got)
+	}
+	dl.reportDial(dialErr)
+	if got := dl.PushHost(); got != "a.example.com" {
+		t.Fatalf("应循环回第一个节点: %s", got)
+	}
+
+	if got := NewDouyinLive2("1", "2", "", "", nil).PushHost(); got != DefaultPushHosts[0] {
+		t.Fatalf("默认节点 = %s", got)
+	}
+}
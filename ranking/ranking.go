@@ -0,0 +1,352 @@
+// Package ranking 跨直播间的实时营收与热度排行：接入多个直播实例后按礼物价值、热度、在线人数等指标排序，
+// 提供 HTTP 查询接口，并可定期把快照写入 SnapshotSink
+package ranking
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	defaultWindow           = 10 * time.Minute
+	defaultSnapshotInterval = time.Minute
+	bucketSize              = 10 * time.Second
+)
+
+// Metric 排序指标
+type Metric string
+
+// 支持的排序指标
+const (
+	MetricRevenue       Metric = "revenue"        // 累计礼物价值
+	MetricRecentRevenue Metric = "recent_revenue" // 最近窗口内的礼物价值
+	MetricHeat          Metric = "heat"           // 热度分
+	MetricViewers       Metric = "viewers"        // 当前在线人数
+	MetricChats         Metric = "chats"          // 最近窗口内的弹幕数
+)
+
+// HeatWeights 热度分的权重：热度 = 窗口内礼物价值×Diamond + 弹幕数×Chat + 点赞数×Like + 在线人数×Viewer
+type HeatWeights struct {
+	Diamond float64
+	Chat    float64
+	Like    float64
+	Viewer  float64
+}
+
+// DefaultHeatWeights 默认热度权重
+var DefaultHeatWeights = HeatWeights{Diamond: 1, Chat: 5, Like: 0.05, Viewer: 1}
+
+// RoomStats 单个直播间的统计
+type RoomStats struct {
+	RoomID         string    `json:"room_id"`
+	LiveID         string    `json:"live_id"`
+	LiveName       string    `json:"live_name"`
+	Diamonds       int64     `json:"diamonds"`        // 累计礼物价值（抖币）
+	RecentDiamonds int64     `json:"recent_diamonds"` // 最近窗口内的礼物价值
+	RecentChats    int       `json:"recent_chats"`
+	RecentLikes    uint64    `json:"recent_likes"`
+	Viewers        uint64    `json:"viewers"`
+	PeakViewers    uint64    `json:"peak_viewers"`
+	Heat           float64   `json:"heat"`
+	UpdatedAt      time.Time `json:"updated_at"` // 最近一次收到事件的时间
+}
+
+// Entry 排行榜中的一项
+type Entry struct {
+	Rank int `json:"rank"`
+	RoomStats
+}
+
+// Snapshot 某一时刻的全部直播间统计
+type Snapshot struct {
+	Time  time.Time   `json:"time"`
+	Rooms []RoomStats `json:"rooms"`
+}
+
+// SnapshotSink 快照的输出目标
+type SnapshotSink interface {
+	WriteSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+// SnapshotSinkFunc 函数形式的 SnapshotSink
+type SnapshotSinkFunc func(ctx context.Context, snapshot *Snapshot) error
+
+// WriteSnapshot 实现 SnapshotSink
+func (f SnapshotSinkFunc) WriteSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	return f(ctx, snapshot)
+}
+
+// JSONSnapshotSink 以 JSON Lines 格式写入快照，每个快照一行
+func JSONSnapshotSink(w io.Writer) SnapshotSink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return SnapshotSinkFunc(func(_ context.Context, snapshot *Snapshot) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return enc.Encode(snapshot)
+	})
+}
+
+// Options 排行配置
+type Options struct {
+	Window           time.Duration  // 近期指标的统计窗口，默认 10 分钟
+	Weights          *HeatWeights   // 热度权重，默认 DefaultHeatWeights
+	SnapshotInterval time.Duration  // 快照间隔，默认 1 分钟
+	Sinks            []SnapshotSink // 调用 Start 后定期写入快照
+	Logger           *slog.Logger
+}
+
+// bucket 一个时间片内的计数
+type bucket struct {
+	start    time.Time
+	diamonds int64
+	chats    int
+	likes    uint64
+}
+
+// room 单个直播间的累计数据
+type room struct {
+	dl       *douyinLive.DouyinLive
+	diamonds int64
+	peak     uint64
+	buckets  []bucket
+	updated  time.Time
+}
+
+// current 返回当前时间片，必要时新建，调用方需持有锁
+func (r *room) current(now time.Time) *bucket {
+	start := now.Truncate(bucketSize)
+	if n := len(r.buckets); n == 0 || !r.buckets[n-1].start.Equal(start) {
+		r.buckets = append(r.buckets, bucket{start: start})
+	}
+	return &r.buckets[len(r.buckets)-1]
+}
+
+// Board 跨直播间排行榜
+type Board struct {
+	opts Options
+
+	mu    sync.Mutex
+	rooms map[*douyinLive.DouyinLive]*room
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// New 创建排行榜
+func New(opts Options) *Board {
+	if opts.Window <= 0 {
+		opts.Window = defaultWindow
+	}
+	if opts.Weights == nil {
+		opts.Weights = &DefaultHeatWeights
+	}
+	if opts.SnapshotInterval <= 0 {
+		opts.SnapshotInterval = defaultSnapshotInterval
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Board{opts: opts, rooms: make(map[*douyinLive.DouyinLive]*room)}
+}
+
+// Watch 将直播实例加入排行，返回移出排行并取消订阅的函数
+func (b *Board) Watch(dl *douyinLive.DouyinLive) func() {
+	r := &room{dl: dl}
+	b.mu.Lock()
+	b.rooms[dl] = r
+	b.mu.Unlock()
+
+	ids := []string{
+		dl.SubscribeGiftCombo(0, func(gift *douyinLive.GiftEvent) {
+			b.add(r, gift.Time, func(bk *bucket) {
+				r.diamonds += gift.TotalDiamond()
+				bk.diamonds += gift.TotalDiamond()
+			})
+		}),
+		dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+			switch event.Method {
+			case douyinLive.WebcastChatMessage, douyinLive.WebcastEmojiChatMessage:
+				b.add(r, event.Time, func(bk *bucket) { bk.chats++ })
+			case douyinLive.WebcastLikeMessage:
+				decoded, err := event.Decode()
+				if err != nil {
+					return
+				}
+				if like, ok := decoded.(*new_douyin.Webcast_Im_LikeMessage); ok {
+					b.add(r, event.Time, func(bk *bucket) { bk.likes += like.Count })
+				}
+			}
+		}),
+	}
+	return func() {
+		for _, id := range ids {
+			dl.Unsubscribe(id)
+		}
+		b.mu.Lock()
+		delete(b.rooms, dl)
+		b.mu.Unlock()
+	}
+}
+
+// add 在锁内更新当前时间片
+func (b *Board) add(r *room, t time.Time, update func(*bucket)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	update(r.current(t))
+	r.updated = t
+}
+
+// stats 计算单个直播间的统计，并清理窗口外的时间片，调用方需持有锁
+func (b *Board) stats(r *room, now time.Time) RoomStats {
+	live := r.dl.Stats()
+	r.peak = max(r.peak, live.CurrentViewers, live.PeakViewers)
+	s := RoomStats{
+		RoomID:      r.dl.RoomID(),
+		LiveID:      r.dl.LiveID(),
+		LiveName:    r.dl.LiveName,
+		Diamonds:    r.diamonds,
+		Viewers:     live.CurrentViewers,
+		PeakViewers: r.peak,
+		UpdatedAt:   r.updated,
+	}
+	cutoff := now.Add(-b.opts.Window)
+	kept := r.buckets[:0]
+	for _, bk := range r.buckets {
+		if bk.start.Add(bucketSize).Before(cutoff) {
+			continue
+		}
+		kept = append(kept, bk)
+		s.RecentDiamonds += bk.diamonds
+		s.RecentChats += bk.chats
+		s.RecentLikes += bk.likes
+	}
+	r.buckets = kept
+	w := b.opts.Weights
+	s.Heat = float64(s.RecentDiamonds)*w.Diamond + float64(s.RecentChats)*w.Chat + float64(s.RecentLikes)*w.Like + float64(s.Viewers)*w.Viewer
+	return s
+}
+
+// Snapshot 返回全部直播间的当前统计，按直播间号排序
+func (b *Board) Snapshot() *Snapshot {
+	now := time.Now()
+	b.mu.Lock()
+	snapshot := &Snapshot{Time: now, Rooms: make([]RoomStats, 0, len(b.rooms))}
+	for _, r := range b.rooms {
+		snapshot.Rooms = append(snapshot.Rooms, b.stats(r, now))
+	}
+	b.mu.Unlock()
+	sort.Slice(snapshot.Rooms, func(i, j int) bool {
+		a, b := snapshot.Rooms[i], snapshot.Rooms[j]
+		if a.LiveID != b.LiveID {
+			return a.LiveID < b.LiveID
+		}
+		return a.RoomID < b.RoomID
+	})
+	return snapshot
+}
+
+// Ranking 按指标降序返回前 n 个直播间，n<=0 时返回全部，指标相同的并列同一名次
+func (b *Board) Ranking(metric Metric, n int) []Entry {
+	rooms := b.Snapshot().Rooms
+	value := metricValue(metric)
+	sort.SliceStable(rooms, func(i, j int) bool { return value(rooms[i]) > value(rooms[j]) })
+	if n > 0 && len(rooms) > n {
+		rooms = rooms[:n]
+	}
+	entries := make([]Entry, len(rooms))
+	for i, s := range rooms {
+		entries[i] = Entry{Rank: i + 1, RoomStats: s}
+		if i > 0 && value(s) == value(rooms[i-1]) {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+// metricValue 返回读取指标值的函数，未知指标按累计礼物价值排序
+func metricValue(metric Metric) func(RoomStats) float64 {
+	switch metric {
+	case MetricRecentRevenue:
+		return func(s RoomStats) float64 { return float64(s.RecentDiamonds) }
+	case MetricHeat:
+		return func(s RoomStats) float64 { return s.Heat }
+	case MetricViewers:
+		return func(s RoomStats) float64 { return float64(s.Viewers) }
+	case MetricChats:
+		return func(s RoomStats) float64 { return float64(s.RecentChats) }
+	}
+	return func(s RoomStats) float64 { return float64(s.Diamonds) }
+}
+
+// ServeHTTP 查询接口，返回排行榜 JSON，支持 metric（默认 revenue）与 n（默认全部）参数，如
+//
+//	GET /ranking?metric=heat&n=20
+func (b *Board) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	metric := Metric(query.Get("metric"))
+	if metric == "" {
+		metric = MetricRevenue
+	}
+	n, _ := strconv.Atoi(query.Get("n"))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"time":    time.Now(),
+		"metric":  metric,
+		"entries": b.Ranking(metric, n),
+	})
+}
+
+// Start 开始定期将快照写入 Sinks
+func (b *Board) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stop != nil {
+		return
+	}
+	b.stop = make(chan struct{})
+	b.done = make(chan struct{})
+	go b.run(b.stop, b.done)
+}
+
+// Stop 停止定期快照
+func (b *Board) Stop() {
+	b.mu.Lock()
+	stop, done := b.stop, b.done
+	b.stop = nil
+	b.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// run 定期写入快照
+func (b *Board) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(b.opts.SnapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			snapshot := b.Snapshot()
+			for _, s := range b.opts.Sinks {
+				if err := s.WriteSnapshot(context.Background(), snapshot); err != nil {
+					b.opts.Logger.Warn("写入排行快照失败", "error", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ranking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestBoardRanking(t *testing.T) {
+	b := New(Options{Window: time.Minute})
+	a := douyinLive.NewDouyinLive2("1", "", "A", "", nil)
+	c := douyinLive.NewDouyinLive2("2", "", "C", "", nil)
+	b.Watch(a)
+	stopC := b.Watch(c)
+
+	now := time.Now()
+	ra, rc := b.rooms[a], b.rooms[c]
+	b.add(ra, now.Add(-2*time.Minute), func(bk *bucket) { ra.diamonds += 1000; bk.diamonds += 1000 })
+	b.add(rc, now, func(bk *bucket) { rc.diamonds += 300; bk.diamonds += 300 })
+	b.add(rc, now, func(bk *bucket) { bk.chats += 10 })
+
+	if r := b.Ranking(MetricRevenue, 0); len(r) != 2 || r[0].LiveName != "A" || r[0].Diamonds != 1000 {
+		t.Fatalf("营收榜 = %+v", r)
+	}
+	// A 的礼物已在窗口外，近期营收与热度都是 C 领先
+	if r := b.Ranking(MetricRecentRevenue, 1); len(r) != 1 || r[0].LiveName != "C" || r[0].RecentDiamonds != 300 {
+		t.Fatalf("近期营收榜 = %+v", r)
+	}
+	if r := b.Ranking(MetricHeat, 0); r[0].LiveName != "C" || r[0].Heat != 350 || r[1].Rank != 2 {
+		t.Fatalf("热度榜 = %+v", r)
+	}
+
+	rec := httptest.NewRecorder()
+	b.ServeHTTP(rec, httptest.NewRequest("GET", "/ranking?metric=recent_revenue&n=1", nil))
+	var resp struct {
+		Entries []Entry `json:"entries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Entries) != 1 || resp.Entries[0].RoomID != "2" {
+		t.Fatalf("查询接口 = %s, %v", rec.Body.String(), err)
+	}
+
+	var buf bytes.Buffer
+	if err := JSONSnapshotSink(&buf).WriteSnapshot(context.Background(), b.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(buf.Bytes(), &snapshot); err != nil || len(snapshot.Rooms) != 2 {
+		t.Fatalf("快照 = %s, %v", buf.String(), err)
+	}
+
+	stopC()
+	if r := b.Ranking(MetricRevenue, 0); len(r) != 1 {
+		t.Fatalf("移出后排行 = %+v", r)
+	}
+}
@@ -0,0 +1,121 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/tidwall/gjson"
+)
+
+// 榜单接口，测试中会被替换
+var (
+	audienceRankURL   = "https://live.douyin.com/webcast/ranklist/audience/"
+	onlineAudienceURL = "https://live.douyin.com/webcast/ranklist/online_audience/"
+)
+
+// rankTypeAudience 在线观众贡献榜
+const rankTypeAudience = "30"
+
+// RankUser 榜单中的一位观众
+type RankUser struct {
+	Rank     int    `json:"rank"` // 名次，从 1 开始，在线观众列表中为 0
+	UserID   uint64 `json:"user_id"`
+	SecUID   string `json:"sec_uid"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar"`
+	Score    int64  `json:"score"` // 贡献值，在线观众列表中为 0
+}
+
+// RankList 榜单查询结果
+type RankList struct {
+	Total int64      `json:"total"` // 服务端给出的总人数，可能大于 Users 的长度
+	Users []RankUser `json:"users"`
+}
+
+// FetchAudienceRank 查询直播间当前的观众贡献榜，需在连接后（已知 roomID）调用
+func (dl *DouyinLive) FetchAudienceRank(ctx context.Context) (*RankList, error) {
+	result, err := dl.rankListCall(ctx, "查询贡献榜", audienceRankURL, map[string]string{"rank_type": rankTypeAudience})
+	if err != nil {
+		return nil, err
+	}
+	list := &RankList{Total: result.Get("data.total").Int()}
+	for _, item := range result.Get("data.ranks").Array() {
+		user := parseRankUser(item.Get("user"))
+		user.Rank = int(item.Get("rank").Int())
+		user.Score = item.Get("score").Int()
+		list.Users = append(list.Users, user)
+	}
+	if list.Total == 0 {
+		list.Total = int64(len(list.Users))
+	}
+	return list, nil
+}
+
+// FetchOnlineAudience 查询直播间的在线观众列表，需在连接后（已知 roomID）调用，
+// 服务端通常只返回前若干位
+func (dl *DouyinLive) FetchOnlineAudience(ctx context.Context) (*RankList, error) {
+	result, err := dl.rankListCall(ctx, "查询在线观众", onlineAudienceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := &RankList{Total: result.Get("data.total").Int()}
+	for _, item := range result.Get("data.list").Array() {
+		list.Users = append(list.Users, parseRankUser(item.Get("user")))
+	}
+	if list.Total == 0 {
+		list.Total = int64(len(list.Users))
+	}
+	return list, nil
+}
+
+// rankListCall 调用榜单接口，附加 msToken 与 a_bogus，返回检查过 status_code 的响应
+func (dl *DouyinLive) rankListCall(ctx context.Context, action, endpoint string, params map[string]string) (gjson.Result, error) {
+	if dl.roomID == "" {
+		return gjson.Result{}, fmt.Errorf("%w: 尚未获取 room_id，请在连接后调用", ErrRoomInfoParse)
+	}
+	query := url.Values{}
+	query.Set("aid", webcastAid)
+	query.Set("app_name", "douyin_web")
+	query.Set("device_platform", "web")
+	query.Set("webcast_sdk_version", dl.ProtocolParams().WebcastSDKVersion)
+	query.Set("room_id", dl.roomID)
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	// a_bogus 按签名时的参数顺序计算，msToken 与 a_bogus 需追加在末尾
+	rawQuery := query.Encode()
+	risk, err := dl.riskParams(ctx, rawQuery)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	for _, key := range []string{"msToken", "a_bogus"} {
+		if v := risk.Get(key); v != "" {
+			rawQuery += "&" + key + "=" + url.QueryEscape(v)
+		}
+	}
+
+	resp, err := dl.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", dl.userAgent).
+		SetHeader("Referer", fmt.Sprintf("https://live.douyin.com/%s", dl.liveID)).
+		SetHeader("Cookie", dl.cookieHeader()).
+		Get(endpoint + "?" + rawQuery)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("%s失败: %w", action, err)
+	}
+	if err := checkWebcastResponse(action, resp.StatusCode, resp.String()); err != nil {
+		return gjson.Result{}, err
+	}
+	return gjson.Parse(resp.String()), nil
+}
+
+// parseRankUser 解析榜单中的用户信息
+func parseRankUser(user gjson.Result) RankUser {
+	return RankUser{
+		UserID:   user.Get("id").Uint(),
+		SecUID:   user.Get("sec_uid").String(),
+		Nickname: user.Get("nickname").String(),
+		Avatar:   user.Get("avatar_thumb.url_list.0").String(),
+	}
+}
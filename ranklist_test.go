@@ -0,0 +1,53 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchRankList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("room_id") != "7300000000000000001" || r.URL.Query().Get("msToken") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/audience/":
+			w.Write([]byte(`{"status_code":0,"data":{"ranks":[
+				{"rank":1,"score":5200,"user":{"id":101,"nickname":"榜一","sec_uid":"MS4wLjABAAAAa","avatar_thumb":{"url_list":["https://p3.douyinpic.com/a.jpeg"]}}},
+				{"rank":2,"score":300,"user":{"id":102,"nickname":"榜二"}}]}}`))
+		case "/online/":
+			w.Write([]byte(`{"status_code":0,"data":{"total":3500,"list":[{"user":{"id":201,"nickname":"观众"}}]}}`))
+		}
+	}))
+	defer srv.Close()
+	oldRank, oldOnline := audienceRankURL, onlineAudienceURL
+	audienceRankURL, onlineAudienceURL = srv.URL+"/audience/", srv.URL+"/online/"
+	defer func() { audienceRankURL, onlineAudienceURL = oldRank, oldOnline }()
+
+	dl, _ := NewDouyinLive("123456", nil)
+	if _, err := dl.FetchAudienceRank(context.Background()); !errors.Is(err, ErrRoomInfoParse) {
+		t.Fatalf("未连接时应返回 ErrRoomInfoParse: %v", err)
+	}
+	dl.roomID = "7300000000000000001"
+
+	rank, err := dl.FetchAudienceRank(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RankUser{Rank: 1, UserID: 101, SecUID: "MS4wLjABAAAAa", Nickname: "榜一", Avatar: "https://p3.douyinpic.com/a.jpeg", Score: 5200}
+	if rank.Total != 2 || len(rank.Users) != 2 || rank.Users[0] != want {
+		t.Fatalf("FetchAudienceRank = %+v", rank)
+	}
+
+	online, err := dl.FetchOnlineAudience(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if online.Total != 3500 || len(online.Users) != 1 || online.Users[0].Nickname != "观众" {
+		t.Fatalf("FetchOnlineAudience = %+v", online)
+	}
+}
@@ -0,0 +1,227 @@
+// Package relay 本地 WebSocket 转发网关：每个直播间只保持一条上游连接，
+// 下游任意多个 WebSocket 客户端按各自的过滤条件接收 JSON 事件，跟不上的慢客户端会被断开
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+const (
+	defaultClientBuffer = 256
+	defaultWriteTimeout = 10 * time.Second
+	pingInterval        = 30 * time.Second
+)
+
+// Upstream 直播间的上游连接，*douyinLive.SharedLive 实现了该接口
+type Upstream interface {
+	SubscribeEvent(handler func(*douyinLive.LiveEvent)) string
+	Done() <-chan struct{}
+	Release()
+}
+
+// Options 转发网关配置
+type Options struct {
+	// Acquire 获取直播间的上游连接，默认使用 douyinLive.NewRegistry 的共享连接
+	Acquire      func(ctx context.Context, liveID string) (Upstream, error)
+	Fields       sink.Fields                // 输出字段白名单，客户端可用 fields 参数进一步裁剪
+	ClientBuffer int                        // 每个客户端的缓冲事件数，溢出时断开该客户端，默认 256
+	WriteTimeout time.Duration              // 单次写入超时，超时的客户端被断开，默认 10 秒
+	CheckOrigin  func(r *http.Request) bool // 检查握手请求的来源，nil 时只允许同源
+	Logger       *slog.Logger
+}
+
+// Server 转发网关，实现 http.Handler。
+// 直播间号取自 URL 最后一段路径或 room 参数，method、fields 参数可逗号分隔或重复指定，如
+//
+//	ws://127.0.0.1:8080/ws/933572413882?method=WebcastChatMessage,WebcastGiftMessage
+type Server struct {
+	opts     Options
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[string]int // 直播间号 → 下游客户端数
+}
+
+// New 创建转发网关
+func New(opts Options) *Server {
+	if opts.Acquire == nil {
+		registry := douyinLive.NewRegistry()
+		opts.Acquire = func(ctx context.Context, liveID string) (Upstream, error) {
+			return registry.AcquireContext(ctx, liveID)
+		}
+	}
+	if opts.ClientBuffer <= 0 {
+		opts.ClientBuffer = defaultClientBuffer
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = defaultWriteTimeout
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Server{
+		opts:     opts,
+		upgrader: websocket.Upgrader{CheckOrigin: opts.CheckOrigin},
+		clients:  make(map[string]int),
+	}
+}
+
+// Clients 返回各直播间的下游客户端数
+func (s *Server) Clients() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int, len(s.clients))
+	for k, v := range s.clients {
+		out[k] = v
+	}
+	return out
+}
+
+// systemMessage 网关自身的通知
+func systemMessage(message string) []byte {
+	data, _ := json.Marshal(map[string]string{"type": "system", "message": message})
+	return data
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	liveID := query.Get("room")
+	if liveID == "" {
+		liveID = r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	}
+	if liveID == "" {
+		http.Error(w, "缺少直播间号", http.StatusBadRequest)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	upstream, err := s.opts.Acquire(r.Context(), liveID)
+	if err != nil {
+		s.closeWith(conn, websocket.CloseInternalServerErr, "连接直播间失败: "+err.Error())
+		return
+	}
+	defer upstream.Release()
+
+	log := s.opts.Logger.With("live_id", liveID, "client", conn.RemoteAddr().String())
+	s.track(liveID, 1)
+	defer s.track(liveID, -1)
+	log.Info("客户端已连接")
+
+	methods := set(query["method"])
+	fields := sink.ParseFields(strings.Join(query["fields"], ","))
+	queue := make(chan []byte, s.opts.ClientBuffer)
+	evicted := make(chan struct{})
+	var evictOnce sync.Once
+	upstream.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if len(methods) > 0 && !methods[event.Method] {
+			return
+		}
+		data, err := json.Marshal(fields.Select(s.opts.Fields.Select(event.Fields())))
+		if err != nil {
+			return
+		}
+		select {
+		case queue <- data:
+		default:
+			evictOnce.Do(func() { close(evicted) })
+		}
+	})
+
+	// 读协程：处理 ping 文本与关闭帧
+	closed := make(chan struct{})
+	pong := make(chan struct{}, 1)
+	go func() {
+		defer close(closed)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "ping" {
+				select {
+				case pong <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case data := <-queue:
+			err = s.write(conn, websocket.TextMessage, data)
+		case <-pong:
+			err = s.write(conn, websocket.TextMessage, []byte("pong"))
+		case <-ticker.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.opts.WriteTimeout))
+		case <-evicted:
+			log.Warn("客户端消费过慢，断开连接")
+			s.closeWith(conn, websocket.CloseTryAgainLater, "消费过慢")
+			return
+		case <-upstream.Done():
+			s.closeWith(conn, websocket.CloseNormalClosure, "直播间连接已结束")
+			return
+		case <-closed:
+			log.Info("客户端已断开")
+			return
+		}
+		if err != nil {
+			log.Info("写入客户端失败，断开连接", "error", err)
+			return
+		}
+	}
+}
+
+// write 带超时写入
+func (s *Server) write(conn *websocket.Conn, messageType int, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout))
+	return conn.WriteMessage(messageType, data)
+}
+
+// closeWith 发送系统通知与关闭帧
+func (s *Server) closeWith(conn *websocket.Conn, code int, message string) {
+	s.write(conn, websocket.TextMessage, systemMessage(message))
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, ""), time.Now().Add(s.opts.WriteTimeout))
+}
+
+// track 更新直播间的客户端计数
+func (s *Server) track(liveID string, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[liveID] += delta
+	if s.clients[liveID] <= 0 {
+		delete(s.clients, liveID)
+	}
+}
+
+// set 将逗号分隔或重复指定的参数转为集合
+func set(values []string) map[string]bool {
+	out := make(map[string]bool)
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out[item] = true
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// fakeUpstream 手动推送事件的上游
+type fakeUpstream struct {
+	mu       sync.Mutex
+	handlers []func(*douyinLive.LiveEvent)
+	done     chan struct{}
+	released int
+}
+
+func (u *fakeUpstream) SubscribeEvent(handler func(*douyinLive.LiveEvent)) string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.handlers = append(u.handlers, handler)
+	return ""
+}
+
+func (u *fakeUpstream) Done() <-chan struct{} { return u.done }
+
+func (u *fakeUpstream) Release() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.released++
+}
+
+func (u *fakeUpstream) emit(method string) {
+	u.mu.Lock()
+	handlers := u.handlers
+	u.mu.Unlock()
+	event := douyinLive.NewLiveEvent("100", "", &new_douyin.Webcast_Im_Message{Method: method})
+	for _, h := range handlers {
+		h(event)
+	}
+}
+
+func (u *fakeUpstream) subscribers() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.handlers)
+}
+
+func TestServerFiltersAndEvicts(t *testing.T) {
+	up := &fakeUpstream{done: make(chan struct{})}
+	s := New(Options{
+		Acquire:      func(context.Context, string) (Upstream, error) { return up, nil },
+		ClientBuffer: 2,
+	})
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/933572413882"
+
+	gifts, _, err := websocket.DefaultDialer.Dial(wsURL+"?method=WebcastGiftMessage&fields=method", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gifts.Close()
+	for up.subscribers() < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	up.emit(douyinLive.WebcastChatMessage)
+	up.emit(douyinLive.WebcastGiftMessage)
+	_, data, err := gifts.ReadMessage()
+	if err != nil || string(data) != `{"method":"WebcastGiftMessage"}` {
+		t.Fatalf("收到 %s, %v", data, err)
+	}
+	if s.Clients()["933572413882"] != 1 {
+		t.Fatalf("客户端计数 = %v", s.Clients())
+	}
+
+	// 不读取的客户端在缓冲溢出后被断开
+	slow, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer slow.Close()
+	for up.subscribers() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 1000; i++ {
+		up.emit(douyinLive.WebcastChatMessage)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Clients()["933572413882"] != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("慢客户端未被断开: %v", s.Clients())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,201 @@
+package douyinLive
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// frameHeaderSize 录制帧头：8 字节接收时间（UnixNano）+ 4 字节帧长度，均为大端序
+const frameHeaderSize = 12
+
+// FrameWriter 录制原始 PushFrame，每帧格式为帧头 + PushFrame 的 protobuf 字节
+type FrameWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewFrameWriter 创建 PushFrame 录制器
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{w: w}
+}
+
+// WriteFrame 写入一帧
+func (fw *FrameWriter) WriteFrame(t time.Time, frame []byte) error {
+	var header [frameHeaderSize]byte
+	binary.BigEndian.PutUint64(header[:8], uint64(t.UnixNano()))
+	binary.BigEndian.PutUint32(header[8:], uint32(len(frame)))
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if _, err := fw.w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := fw.w.Write(frame)
+	return err
+}
+
+// WithFrameRecorder 将收到的原始 PushFrame 录制下来，供 ReplayFrames 离线回放
+func WithFrameRecorder(fw *FrameWriter) Option {
+	return func(dl *DouyinLive) {
+		dl.frameRecorder = fw
+	}
+}
+
+// ReplayOptions 回放配置
+type ReplayOptions struct {
+	Speed float64 // 回放速度倍数，1 为原始节奏，2 为两倍速，<=0 时不等待尽快回放
+}
+
+// replayClock 按录制时间间隔控制回放节奏
+type replayClock struct {
+	speed     float64
+	first     time.Time
+	startedAt time.Time
+}
+
+// wait 等待到录制时间 t 对应的回放时刻
+func (c *replayClock) wait(ctx context.Context, t time.Time) error {
+	if c.speed <= 0 || t.IsZero() {
+		return ctx.Err()
+	}
+	if c.first.IsZero() {
+		c.first, c.startedAt = t, time.Now()
+		return ctx.Err()
+	}
+	target := c.startedAt.Add(time.Duration(float64(t.Sub(c.first)) / c.speed))
+	timer := time.NewTimer(time.Until(target))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// beginReplay 准备与实时连接相同的分发流程
+func (dl *DouyinLive) beginReplay() {
+	if dl.dispatchQueueSize > 0 {
+		dl.startDispatcher()
+	}
+}
+
+// endReplay 等待异步分发的消息处理完毕
+func (dl *DouyinLive) endReplay() {
+	if dl.dispatcher != nil {
+		dl.dispatcher.stop()
+		dl.dispatcher = nil
+	}
+}
+
+// ReplayFrames 回放 FrameWriter 录制的原始 PushFrame，
+// 消息经过与实时连接相同的解压、解码与订阅分发流程
+func (dl *DouyinLive) ReplayFrames(ctx context.Context, r io.Reader, opts ReplayOptions) error {
+	dl.beginReplay()
+	defer dl.endReplay()
+
+	br := bufio.NewReader(r)
+	clock := &replayClock{speed: opts.Speed}
+	var pushFrame new_douyin.Webcast_Im_PushFrame
+	var pipeline *decodePipeline
+	if dl.decodeWorkers > 1 {
+		pipeline = dl.startPipeline()
+		defer pipeline.stop()
+	}
+	var header [frameHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("读取录制帧头失败: %w", err)
+		}
+		t := time.Unix(0, int64(binary.BigEndian.Uint64(header[:8])))
+		size := binary.BigEndian.Uint32(header[8:])
+		if size > maxFrameSize {
+			return fmt.Errorf("录制帧长度 %d 超过 %d 字节，文件可能已损坏", size, maxFrameSize)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(br, frame); err != nil {
+			return fmt.Errorf("读取录制帧失败: %w", err)
+		}
+		if err := clock.wait(ctx, t); err != nil {
+			return err
+		}
+		if pipeline != nil {
+			pipeline.submit(frame, nil)
+		} else {
+			dl.handleFrame(&pushFrame, frame, nil)
+		}
+	}
+}
+
+// ReplayJSONL 回放 sink.Recorder 等录制的 JSON Lines 事件，
+// 根据 method 与 data 字段重建消息，录制时未包含这两个字段的行会被跳过
+func (dl *DouyinLive) ReplayJSONL(ctx context.Context, r io.Reader, opts ReplayOptions) error {
+	dl.beginReplay()
+	defer dl.endReplay()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	clock := &replayClock{speed: opts.Speed}
+	for line := 1; scanner.Scan(); line++ {
+		var record struct {
+			Time     time.Time       `json:"time"`
+			RoomID   string          `json:"room_id"`
+			LiveName string          `json:"live_name"`
+			Method   string          `json:"method"`
+			MsgID    string          `json:"msg_id"`
+			Data     json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("第 %d 行不是合法的 JSON: %w", line, err)
+		}
+		msg, err := rebuildMessage(record.Method, record.MsgID, record.Data)
+		if err != nil {
+			dl.log().Debug("跳过无法回放的事件", "line", line, "error", err)
+			continue
+		}
+		if dl.roomID == "" {
+			dl.roomID, dl.LiveName = record.RoomID, record.LiveName
+		}
+		if err := clock.wait(ctx, record.Time); err != nil {
+			return err
+		}
+		dl.handleSingleMessage(ctx, msg)
+	}
+	return scanner.Err()
+}
+
+// rebuildMessage 从导出的 JSON 重建原始消息
+func rebuildMessage(method, msgID string, data json.RawMessage) (*new_douyin.Webcast_Im_Message, error) {
+	if method == "" || len(data) == 0 {
+		return nil, errors.New("缺少 method 或 data 字段")
+	}
+	body, err := generated.GetMessageInstance(method)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, body); err != nil {
+		return nil, err
+	}
+	payload, err := proto.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	id, _ := strconv.ParseUint(msgID, 10, 64)
+	return &new_douyin.Webcast_Im_Message{Method: method, MsgId: id, Payload: payload}, nil
+}
@@ -0,0 +1,120 @@
+package douyinLive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// gzipFrame 构造包含 messages 的 gzip PushFrame
+func gzipFrame(t testing.TB, messages ...*new_douyin.Webcast_Im_Message) []byte {
+	t.Helper()
+	body, err := proto.Marshal(&new_douyin.Webcast_Im_Response{Messages: messages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(body)
+	zw.Close()
+	frame, err := proto.Marshal(&new_douyin.Webcast_Im_PushFrame{
+		PayloadType: "msg",
+		Headers:     []*new_douyin.Webcast_Im_PushHeader{{Key: "compress_type", Value: "gzip"}},
+		Payload:     buf.Bytes(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frame
+}
+
+func TestReplayFrames(t *testing.T) {
+	chat := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+	var recorded bytes.Buffer
+	fw := NewFrameWriter(&recorded)
+	start := time.Now()
+	fw.WriteFrame(start, gzipFrame(t, chat))
+	fw.WriteFrame(start.Add(100*time.Millisecond), gzipFrame(t, chat, chat))
+
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithAsyncDispatch(0))
+	var got int
+	dl.Subscribe(func(*new_douyin.Webcast_Im_Message) { got++ })
+	begin := time.Now()
+	if err := dl.ReplayFrames(context.Background(), &recorded, ReplayOptions{Speed: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if got != 3 {
+		t.Fatalf("回放 %d 条消息, want 3", got)
+	}
+	if elapsed := time.Since(begin); elapsed < 50*time.Millisecond {
+		t.Fatalf("两倍速回放耗时 %s, 应不少于 50ms", elapsed)
+	}
+}
+
+func TestReplayFramesRejectsOversizedFrame(t *testing.T) {
+	var header [frameHeaderSize]byte
+	binary.BigEndian.PutUint32(header[8:], math.MaxUint32)
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	if err := dl.ReplayFrames(context.Background(), bytes.NewReader(header[:]), ReplayOptions{}); err == nil || !strings.Contains(err.Error(), "超过") {
+		t.Fatalf("超长的帧长度应返回错误: %v", err)
+	}
+}
+
+func TestSubscribeFrame(t *testing.T) {
+	chat := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+	raw := gzipFrame(t, chat)
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	var order []string
+	var archived []byte
+	dl.SubscribeFrame(func(frame *new_douyin.Webcast_Im_PushFrame, data []byte) {
+		order = append(order, "frame:"+frame.PayloadType)
+		archived = data
+	})
+	dl.Subscribe(func(*new_douyin.Webcast_Im_Message) { order = append(order, "message") })
+
+	var pushFrame new_douyin.Webcast_Im_PushFrame
+	dl.handleFrame(&pushFrame, raw, nil)
+	if len(order) != 2 || order[0] != "frame:msg" || order[1] != "message" {
+		t.Fatalf("回调顺序 = %v", order)
+	}
+	if !bytes.Equal(archived, raw) {
+		t.Fatal("原始帧应与收到的字节一致")
+	}
+}
+
+func TestReplayJSONL(t *testing.T) {
+	chat := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{
+		User:    &new_douyin.Webcast_Data_User{Id: 42},
+		Content: "回放",
+	})
+	chat.MsgId = 7
+	line, err := json.Marshal(NewLiveEvent("100", "主播", chat).Fields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := string(line) + "\n" + `{"method":"WebcastChatMessage"}` + "\n"
+
+	dl, _ := NewDouyinLive("1", nil)
+	var events []*LiveEvent
+	dl.SubscribeEvent(func(e *LiveEvent) { events = append(events, e) })
+	if err := dl.ReplayJSONL(context.Background(), bytes.NewBufferString(input), ReplayOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("回放 %d 条事件, want 1", len(events))
+	}
+	fields := events[0].Fields()
+	if events[0].RoomID != "100" || events[0].MsgID != 7 || fields[FieldContent] != "回放" || fields[FieldUserID] != "42" {
+		t.Fatalf("回放事件错误: %+v", fields)
+	}
+}
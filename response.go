@@ -0,0 +1,46 @@
+package douyinLive
+
+import "github.com/tiga210/douyinLive/generated/new_douyin"
+
+// ResponseInfo Response 的来源信息
+type ResponseInfo struct {
+	LogID   uint64 // 所在 PushFrame 的 log_id，发送 ack 时使用，HTTP 轮询时为 0
+	Polling bool   // 是否来自 HTTP 轮询
+}
+
+// ResponseInterceptor Response 拦截器，在保存 cursor、发送 ack 与分发消息之前调用。
+// 可以修改 Response（如过滤 Messages、改写 Cursor、置 NeedAck 为 false 后自行调用 Ack），
+// 返回 false 时丢弃其中的全部消息，cursor 与 ack 仍按修改后的 Response 处理
+type ResponseInterceptor interface {
+	InterceptResponse(resp *new_douyin.Webcast_Im_Response, info ResponseInfo) bool
+}
+
+// ResponseInterceptorFunc 函数形式的 ResponseInterceptor
+type ResponseInterceptorFunc func(resp *new_douyin.Webcast_Im_Response, info ResponseInfo) bool
+
+// InterceptResponse 实现 ResponseInterceptor
+func (f ResponseInterceptorFunc) InterceptResponse(resp *new_douyin.Webcast_Im_Response, info ResponseInfo) bool {
+	return f(resp, info)
+}
+
+// WithResponseInterceptor 添加 Response 拦截器，按添加顺序执行，任一拦截器返回 false 后不再执行后续拦截器
+func WithResponseInterceptor(interceptors ...ResponseInterceptor) Option {
+	return func(dl *DouyinLive) {
+		dl.responseInterceptors = append(dl.responseInterceptors, interceptors...)
+	}
+}
+
+// Ack 向服务端确认收到 logID 对应的 PushFrame，配合拦截器自定义 ack 时使用
+func (dl *DouyinLive) Ack(logID uint64, internalExt string) {
+	dl.sendAck(dl.currentConn(), logID, internalExt)
+}
+
+// interceptResponse 依次执行全部拦截器，返回是否分发其中的消息
+func (dl *DouyinLive) interceptResponse(resp *new_douyin.Webcast_Im_Response, info ResponseInfo) bool {
+	for _, i := range dl.responseInterceptors {
+		if !i.InterceptResponse(resp, info) {
+			return false
+		}
+	}
+	return true
+}
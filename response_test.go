@@ -0,0 +1,44 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestResponseInterceptor(t *testing.T) {
+	chat := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"})
+	like := statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{Count: 1})
+
+	var calls int
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithResponseInterceptor(
+		ResponseInterceptorFunc(func(resp *new_douyin.Webcast_Im_Response, info ResponseInfo) bool {
+			calls++
+			if len(resp.Messages) == 1 {
+				return false
+			}
+			resp.Messages = resp.Messages[1:]
+			resp.Cursor = "rewritten"
+			return true
+		}),
+	))
+	var methods []string
+	dl.Subscribe(func(msg *new_douyin.Webcast_Im_Message) { methods = append(methods, msg.Method) })
+
+	for _, raw := range [][]byte{gzipFrame(t, chat, like), gzipFrame(t, chat)} {
+		var frame new_douyin.Webcast_Im_PushFrame
+		if err := proto.Unmarshal(raw, &frame); err != nil {
+			t.Fatal(err)
+		}
+		dl.handleMessageFrame(&frame)
+	}
+
+	if calls != 2 || len(methods) != 1 || methods[0] != WebcastLikeMessage {
+		t.Fatalf("calls = %d, methods = %v", calls, methods)
+	}
+	if cursor, _ := dl.resumeState(); cursor != "rewritten" {
+		t.Fatalf("cursor = %q", cursor)
+	}
+}
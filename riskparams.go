@@ -0,0 +1,171 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/imroc/req/v3"
+)
+
+const (
+	// msTokenLength 浏览器中 msToken 的长度
+	msTokenLength  = 107
+	msTokenCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// riskParamsPath 只给 webcast 接口附加风控参数
+	riskParamsPath = "/webcast/"
+)
+
+// MsTokenProvider 提供 msToken
+type MsTokenProvider interface {
+	MsToken(ctx context.Context) (string, error)
+}
+
+// MsTokenFunc 函数形式的 MsTokenProvider
+type MsTokenFunc func(ctx context.Context) (string, error)
+
+// MsToken 实现 MsTokenProvider
+func (f MsTokenFunc) MsToken(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// ABogusSigner 按请求的查询串（已包含 msToken）生成 a_bogus
+type ABogusSigner interface {
+	ABogus(ctx context.Context, query, userAgent string) (string, error)
+}
+
+// ABogusFunc 函数形式的 ABogusSigner
+type ABogusFunc func(ctx context.Context, query, userAgent string) (string, error)
+
+// ABogus 实现 ABogusSigner
+func (f ABogusFunc) ABogus(ctx context.Context, query, userAgent string) (string, error) {
+	return f(ctx, query, userAgent)
+}
+
+// RandomMsToken 生成随机的 msToken
+func RandomMsToken() string {
+	var b strings.Builder
+	b.Grow(msTokenLength)
+	limit := big.NewInt(int64(len(msTokenCharset)))
+	for i := 0; i < msTokenLength; i++ {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			panic(err)
+		}
+		b.WriteByte(msTokenCharset[n.Int64()])
+	}
+	return b.String()
+}
+
+// WithMsToken 指定 msToken 的来源。默认每个实例生成一个随机值，
+// 之后由响应中的 msToken cookie 或 x-ms-token 头更新
+func WithMsToken(p MsTokenProvider) Option {
+	return func(dl *DouyinLive) {
+		dl.msTokenProvider = p
+	}
+}
+
+// WithABogus 指定 a_bogus 的生成实现，未指定时不附加 a_bogus
+func WithABogus(s ABogusSigner) Option {
+	return func(dl *DouyinLive) {
+		dl.aBogusSigner = s
+	}
+}
+
+// initRiskParams 在 HTTP 客户端上挂载附加与更新风控参数的中间件
+func (dl *DouyinLive) initRiskParams() {
+	dl.client.OnBeforeRequest(func(_ *req.Client, r *req.Request) error {
+		if !strings.Contains(r.RawURL, riskParamsPath) {
+			return nil
+		}
+		if r.QueryParams == nil {
+			r.QueryParams = make(url.Values)
+		}
+		params, err := dl.riskParams(r.Context(), r.QueryParams.Encode())
+		if err != nil {
+			return err
+		}
+		for key, values := range params {
+			r.QueryParams[key] = values
+		}
+		return nil
+	})
+	dl.client.OnAfterResponse(func(_ *req.Client, resp *req.Response) error {
+		if resp.Response == nil {
+			return nil
+		}
+		token := resp.Header.Get("X-Ms-Token")
+		for _, c := range resp.Cookies() {
+			if c.Name == "msToken" && c.Value != "" {
+				token = c.Value
+			}
+		}
+		if token != "" {
+			dl.riskMu.Lock()
+			dl.msToken = token
+			dl.riskMu.Unlock()
+		}
+		return nil
+	})
+}
+
+// currentMsToken 返回当前使用的 msToken
+func (dl *DouyinLive) currentMsToken(ctx context.Context) (string, error) {
+	if dl.msTokenProvider != nil {
+		return dl.msTokenProvider.MsToken(ctx)
+	}
+	dl.riskMu.Lock()
+	defer dl.riskMu.Unlock()
+	if dl.msToken == "" {
+		dl.msToken = RandomMsToken()
+	}
+	return dl.msToken, nil
+}
+
+// riskParams 按查询串生成 msToken 与 a_bogus
+func (dl *DouyinLive) riskParams(ctx context.Context, query string) (url.Values, error) {
+	token, err := dl.currentMsToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 获取 msToken 失败: %w", ErrSignature, err)
+	}
+	params := url.Values{}
+	if token == "" {
+		return params, nil
+	}
+	params.Set("msToken", token)
+	if dl.aBogusSigner == nil {
+		return params, nil
+	}
+	if query != "" {
+		query += "&"
+	}
+	aBogus, err := dl.aBogusSigner.ABogus(ctx, query+"msToken="+url.QueryEscape(token), dl.userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 生成 a_bogus 失败: %w", ErrSignature, err)
+	}
+	params.Set("a_bogus", aBogus)
+	return params, nil
+}
+
+// appendRiskParams 在 WebSocket URL 的 signature 之前插入 msToken 与 a_bogus
+func (dl *DouyinLive) appendRiskParams(ctx context.Context, wssURL string) (string, error) {
+	i := strings.LastIndex(wssURL, "&signature=")
+	q := strings.IndexByte(wssURL, '?')
+	if i < 0 || q < 0 || q > i {
+		return wssURL, nil
+	}
+	params, err := dl.riskParams(ctx, wssURL[q+1:i])
+	if err != nil {
+		return "", err
+	}
+	var extra strings.Builder
+	for _, key := range []string{"msToken", "a_bogus"} {
+		if v := params.Get(key); v != "" {
+			extra.WriteString("&" + key + "=" + url.QueryEscape(v))
+		}
+	}
+	return wssURL[:i] + extra.String() + wssURL[i:], nil
+}
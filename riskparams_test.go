@@ -0,0 +1,52 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRiskParams(t *testing.T) {
+	var signedQuery string
+	dl, _ := NewDouyinLive("1", nil,
+		WithSigner(SignerFunc(func(context.Context, SignRequest) (string, error) { return "sig", nil })),
+		WithABogus(ABogusFunc(func(_ context.Context, query, _ string) (string, error) {
+			signedQuery = query
+			return "ab/1", nil
+		})),
+	)
+	dl.roomID, dl.pushID = "100", "200"
+
+	wssURL, err := dl.makeURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := dl.currentMsToken(context.Background())
+	if len(token) != msTokenLength {
+		t.Fatalf("msToken 长度 = %d", len(token))
+	}
+	if !strings.HasSuffix(wssURL, "&msToken="+token+"&a_bogus=ab%2F1&signature=sig") {
+		t.Fatalf("URL 缺少风控参数: %s", wssURL)
+	}
+	if !strings.Contains(signedQuery, "room_id=100") || !strings.HasSuffix(signedQuery, "msToken="+token) {
+		t.Fatalf("a_bogus 的输入 = %s", signedQuery)
+	}
+
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.RawQuery
+		http.SetCookie(w, &http.Cookie{Name: "msToken", Value: "server-token"})
+	}))
+	defer srv.Close()
+	if _, err := dl.client.R().SetQueryParam("room_id", "100").Get(srv.URL + "/webcast/gift/list/"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "msToken="+token) || !strings.Contains(got, "a_bogus=ab%2F1") {
+		t.Fatalf("HTTP 请求缺少风控参数: %s", got)
+	}
+	if token, _ := dl.currentMsToken(context.Background()); token != "server-token" {
+		t.Fatalf("未使用响应中的 msToken: %s", token)
+	}
+}
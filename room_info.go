@@ -0,0 +1,172 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// 直播间状态，见 RoomInfo.Status
+const (
+	RoomStatusLiving = 2
+	RoomStatusEnded  = 4
+)
+
+var (
+	anchorInfoRegex = regexp.MustCompile(`data-anchor-info="([\s\S]*?)" data-room-info="`)
+	coverRegex      = regexp.MustCompile(`\\"cover\\":\{\\"url_list\\":\[\\"(.*?)\\"`)
+	createTimeRegex = regexp.MustCompile(`\\"create_time\\":(\d+)`)
+)
+
+// roomEnterURL 网页端进房接口，测试中会被替换
+var roomEnterURL = "https://live.douyin.com/webcast/room/web/enter/"
+
+// RoomInfo 直播间与主播信息
+type RoomInfo struct {
+	RoomID    string    `json:"room_id"`
+	LiveID    string    `json:"live_id"` // 网页直播间号（web_rid）
+	Title     string    `json:"title"`
+	CoverURL  string    `json:"cover_url"`
+	Status    int       `json:"status"`     // 2 为直播中，4 为已下播，见 RoomStatusLiving
+	UserCount string    `json:"user_count"` // 页面展示的在线人数，如 "1.2万"
+	StartTime time.Time `json:"start_time"` // 本场开播时间，解析不到时为零值
+
+	AnchorNickname string `json:"anchor_nickname"`
+	AnchorAvatar   string `json:"anchor_avatar"`
+	AnchorSecUID   string `json:"anchor_sec_uid"`
+}
+
+// Living 是否正在直播
+func (r RoomInfo) Living() bool {
+	return r.Status == RoomStatusLiving
+}
+
+// RoomInfo 返回最近一次从直播间页面解析出的房间信息，连接前为零值，
+// 需要最新数据时使用 FetchRoomInfo
+func (dl *DouyinLive) RoomInfo() RoomInfo {
+	dl.pageMu.Lock()
+	defer dl.pageMu.Unlock()
+	return dl.roomInfo
+}
+
+// Prefetch 获取 ttwid 并解析直播间页面，得到 roomID 与 RoomInfo 但不建立连接，
+// 之后即可调用 FetchGiftCatalog、FetchAudienceRank 等依赖 roomID 的接口
+func (dl *DouyinLive) Prefetch(ctx context.Context) error {
+	if _, err := dl.FetchTTWID(ctx); err != nil {
+		return err
+	}
+	return dl.fetchRoomInfo(ctx)
+}
+
+// FetchRoomInfo 通过进房接口查询房间信息，接口不可用时回退到解析直播间页面
+func (dl *DouyinLive) FetchRoomInfo(ctx context.Context) (*RoomInfo, error) {
+	info, err := dl.enterRoomInfo(ctx)
+	if err != nil {
+		dl.log().Debug("进房接口查询失败，回退到页面解析", "error", err)
+		page, pageErr := dl.getPageContent(ctx)
+		if pageErr != nil {
+			return nil, pageErr
+		}
+		info = parseRoomInfo(page.Body)
+		if info.RoomID == "" {
+			return nil, fmt.Errorf("%w: 页面中缺少房间信息", ErrRoomInfoParse)
+		}
+	}
+	info.LiveID = dl.liveID
+	dl.setRoomInfo(*info)
+	return info, nil
+}
+
+// enterRoomInfo 调用进房接口
+func (dl *DouyinLive) enterRoomInfo(ctx context.Context) (*RoomInfo, error) {
+	resp, err := dl.client.R().
+		SetContext(ctx).
+		SetHeader("User-Agent", dl.userAgent).
+		SetHeader("Referer", fmt.Sprintf("https://live.douyin.com/%s", dl.liveID)).
+		SetHeader("Cookie", dl.cookieHeader()).
+		SetQueryParams(map[string]string{
+			"aid":             webcastAid,
+			"app_name":        "douyin_web",
+			"live_id":         "1",
+			"device_platform": "web",
+			"enter_from":      "web_live",
+			"web_rid":         dl.liveID,
+		}).
+		Get(roomEnterURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+	data := gjson.Get(resp.String(), "data")
+	room := data.Get("data.0")
+	if !room.Exists() {
+		return nil, fmt.Errorf("%w: 进房接口未返回房间信息", ErrRoomInfoParse)
+	}
+	user := data.Get("user")
+	if !user.Exists() {
+		user = room.Get("owner")
+	}
+	return &RoomInfo{
+		RoomID:         room.Get("id_str").String(),
+		Title:          room.Get("title").String(),
+		CoverURL:       room.Get("cover.url_list.0").String(),
+		Status:         int(room.Get("status").Int()),
+		UserCount:      room.Get("user_count_str").String(),
+		StartTime:      unixTime(room.Get("create_time").Int()),
+		AnchorNickname: user.Get("nickname").String(),
+		AnchorAvatar:   user.Get("avatar_thumb.url_list.0").String(),
+		AnchorSecUID:   user.Get("sec_uid").String(),
+	}, nil
+}
+
+// parseRoomInfo 从直播间页面解析房间信息，解析不到的字段为零值
+func parseRoomInfo(body string) *RoomInfo {
+	info := &RoomInfo{}
+	if m := isLiveRegex.FindStringSubmatch(body); m != nil {
+		info.RoomID = m[1]
+		info.Status, _ = strconv.Atoi(m[2])
+		info.Title = unescapePage(m[4])
+		info.UserCount = unescapePage(m[5])
+	}
+	if info.RoomID == "" {
+		info.RoomID = extractString(roomIDRegex, body, 1)
+	}
+	info.CoverURL = unescapePage(extractString(coverRegex, body, 1))
+	if created, err := strconv.ParseInt(extractString(createTimeRegex, body, 1), 10, 64); err == nil {
+		info.StartTime = unixTime(created)
+	}
+
+	anchor := strings.ReplaceAll(extractString(anchorInfoRegex, body, 1), `&quot;`, `"`)
+	info.AnchorNickname = gjson.Get(anchor, "nickname").String()
+	info.AnchorAvatar = gjson.Get(anchor, "avatar_thumb.url_list.0").String()
+	info.AnchorSecUID = gjson.Get(anchor, "sec_uid").String()
+	return info
+}
+
+// unescapePage 还原页面内嵌 JSON 中转义的字符
+func unescapePage(s string) string {
+	return strings.NewReplacer(`\\u0026`, "&", `\u0026`, "&", `\\/`, "/", `\/`, "/").Replace(s)
+}
+
+// unixTime 把秒级时间戳转换为 time.Time，非正数返回零值
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// setRoomInfo 保存房间信息
+func (dl *DouyinLive) setRoomInfo(info RoomInfo) {
+	dl.pageMu.Lock()
+	defer dl.pageMu.Unlock()
+	dl.roomInfo = info
+}
@@ -0,0 +1,59 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRoomInfo(t *testing.T) {
+	body := `<div data-anchor-info="{&quot;nickname&quot;:&quot;主播&quot;,&quot;sec_uid&quot;:&quot;MS4wLjABAAAAx&quot;,&quot;avatar_thumb&quot;:{&quot;url_list&quot;:[&quot;https://p3.douyinpic.com/a.jpeg&quot;]}}" data-room-info="">` +
+		`{\"id_str\":\"7300000000000000001\",\"status\":2,\"status_str\":\"2\",\"title\":\"晚间闲聊\",\"user_count_str\":\"1.2万\",` +
+		`\"cover\":{\"url_list\":[\"https://p3.douyinpic.com/c.jpeg?a=1\\u0026b=2\"]},\"create_time\":1700000000}`
+	info := parseRoomInfo(body)
+	want := RoomInfo{
+		RoomID:         "7300000000000000001",
+		Title:          "晚间闲聊",
+		CoverURL:       "https://p3.douyinpic.com/c.jpeg?a=1&b=2",
+		Status:         RoomStatusLiving,
+		UserCount:      "1.2万",
+		AnchorNickname: "主播",
+		AnchorAvatar:   "https://p3.douyinpic.com/a.jpeg",
+		AnchorSecUID:   "MS4wLjABAAAAx",
+	}
+	if info.StartTime.Unix() != 1700000000 {
+		t.Fatalf("StartTime = %v", info.StartTime)
+	}
+	info.StartTime = want.StartTime
+	if *info != want {
+		t.Fatalf("parseRoomInfo = %+v, want %+v", *info, want)
+	}
+}
+
+func TestFetchRoomInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("web_rid") != "123456" {
+			t.Errorf("web_rid = %s", r.URL.Query().Get("web_rid"))
+		}
+		w.Write([]byte(`{"data":{"data":[{"id_str":"7300000000000000001","status":4,"title":"已下播","create_time":1700000000,
+			"cover":{"url_list":["https://p3.douyinpic.com/c.jpeg"]}}],
+			"user":{"nickname":"主播","sec_uid":"MS4wLjABAAAAx","avatar_thumb":{"url_list":["https://p3.douyinpic.com/a.jpeg"]}}}}`))
+	}))
+	defer srv.Close()
+	old := roomEnterURL
+	roomEnterURL = srv.URL
+	defer func() { roomEnterURL = old }()
+
+	dl, _ := NewDouyinLive("123456", nil)
+	info, err := dl.FetchRoomInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Living() || info.Title != "已下播" || info.AnchorSecUID != "MS4wLjABAAAAx" || info.LiveID != "123456" {
+		t.Fatalf("FetchRoomInfo = %+v", info)
+	}
+	if dl.RoomInfo().RoomID != "7300000000000000001" {
+		t.Fatalf("RoomInfo() = %+v", dl.RoomInfo())
+	}
+}
@@ -0,0 +1,85 @@
+package douyinLive
+
+import (
+	"maps"
+	"math/rand"
+)
+
+// SampleAll SampleRates 中对未列出的消息类型生效的键
+const SampleAll = "*"
+
+// SampleRates 按消息类型的采样比例，取值 0~1，如 {WebcastLikeMessage: 0.1, SampleAll: 1}。
+// 未列出的类型使用 SampleAll 的比例，都未设置时全部保留。直播结束等控制消息不受影响
+type SampleRates map[string]float64
+
+// rate 返回某类消息的采样比例
+func (r SampleRates) rate(method string) float64 {
+	if rate, ok := r[method]; ok {
+		return rate
+	}
+	if rate, ok := r[SampleAll]; ok {
+		return rate
+	}
+	return 1
+}
+
+// Keeps 判断消息是否被采样保留。按 msgId 计算，同一条消息（包括重发的）结果相同，
+// 回放时也能得到一致的采样结果；msgId 为 0 时随机决定
+func (r SampleRates) Keeps(method string, msgID uint64) bool {
+	if method == WebcastControlMessage {
+		return true
+	}
+	rate := r.rate(method)
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	case msgID == 0:
+		return rand.Float64() < rate
+	}
+	// splitmix64 打散 msgId，避免递增的 ID 在取模后分布不均
+	x := msgID + 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11)/(1<<53) < rate
+}
+
+// WithSampling 按消息类型采样，降低热门直播间的解码、处理器与 sink 负载，
+// 如保留全部礼物、10% 的点赞与弹幕。点赞数与在线人数统计仍基于全部消息。
+// 运行中可通过 SetSampling 调整
+func WithSampling(rates SampleRates) Option {
+	return func(dl *DouyinLive) {
+		dl.SetSampling(rates)
+	}
+}
+
+// SetSampling 调整采样比例，下一条消息起生效，nil 表示不采样
+func (dl *DouyinLive) SetSampling(rates SampleRates) {
+	if len(rates) == 0 {
+		dl.sampleRates.Store(nil)
+		return
+	}
+	rates = maps.Clone(rates)
+	dl.sampleRates.Store(&rates)
+}
+
+// Sampling 返回当前的采样比例
+func (dl *DouyinLive) Sampling() SampleRates {
+	rates := dl.sampleRates.Load()
+	if rates == nil {
+		return nil
+	}
+	return maps.Clone(*rates)
+}
+
+// sampled 判断消息是否被采样保留，未保留的消息计入 Stats().Sampled
+func (dl *DouyinLive) sampled(method string, msgID uint64) bool {
+	rates := dl.sampleRates.Load()
+	if rates == nil || rates.Keeps(method, msgID) {
+		return true
+	}
+	dl.sampledOut.Add(1)
+	return false
+}
@@ -0,0 +1,54 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestSampleRates(t *testing.T) {
+	rates := SampleRates{WebcastLikeMessage: 0.1, WebcastGiftMessage: 1, SampleAll: 0.5}
+	counts := make(map[string]int)
+	const n = 10000
+	for id := uint64(1); id <= n; id++ {
+		for _, method := range []string{WebcastLikeMessage, WebcastGiftMessage, WebcastChatMessage, WebcastControlMessage} {
+			if rates.Keeps(method, id) {
+				counts[method]++
+			}
+		}
+	}
+	if counts[WebcastGiftMessage] != n || counts[WebcastControlMessage] != n {
+		t.Fatalf("礼物与控制消息应全部保留: %v", counts)
+	}
+	if c := counts[WebcastLikeMessage]; c < 800 || c > 1200 {
+		t.Fatalf("点赞保留 %d 条, 期望约 1000", c)
+	}
+	if c := counts[WebcastChatMessage]; c < 4500 || c > 5500 {
+		t.Fatalf("弹幕保留 %d 条, 期望约 5000", c)
+	}
+	if rates.Keeps(WebcastLikeMessage, 42) != rates.Keeps(WebcastLikeMessage, 42) {
+		t.Fatal("同一 msgId 的采样结果应一致")
+	}
+}
+
+func TestWithSampling(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithSampling(SampleRates{WebcastLikeMessage: 0}))
+	var got int
+	dl.SubscribeEvent(func(*LiveEvent) { got++ })
+	like := statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{Count: 3, Total: 3})
+	dl.handleSingleMessage(context.Background(), like)
+	dl.handleSingleMessage(context.Background(), statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{}))
+	if got != 1 {
+		t.Fatalf("收到 %d 条, want 1", got)
+	}
+	if s := dl.Stats(); s.Sampled != 1 || s.LikesReceived != 3 {
+		t.Fatalf("Sampled = %d, LikesReceived = %d, 统计应基于全部消息", s.Sampled, s.LikesReceived)
+	}
+
+	dl.SetSampling(nil)
+	dl.handleSingleMessage(context.Background(), like)
+	if got != 2 || dl.Sampling() != nil {
+		t.Fatal("SetSampling(nil) 后应不再采样")
+	}
+}
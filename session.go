@@ -0,0 +1,87 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Session 可序列化的会话状态，进程重启后可通过 NewDouyinLiveFromSession 续连
+type Session struct {
+	LiveID      string    `json:"live_id"`
+	RoomID      string    `json:"room_id"`
+	PushID      string    `json:"push_id"`
+	LiveName    string    `json:"live_name"`
+	TTWID       string    `json:"ttwid"`
+	UserAgent   string    `json:"user_agent"`
+	Cursor      string    `json:"cursor"`
+	InternalExt string    `json:"internal_ext"`
+	SavedAt     time.Time `json:"saved_at"`
+}
+
+// Marshal 将会话序列化为 JSON
+func (s Session) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// ParseSession 解析 Marshal 导出的会话
+func ParseSession(data []byte) (Session, error) {
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Session{}, err
+	}
+	if s.RoomID == "" || s.PushID == "" {
+		return Session{}, errors.New("会话缺少 room_id 或 push_id")
+	}
+	return s, nil
+}
+
+// ExportSession 导出当前会话，包含最近一次收到的 cursor 与 internal_ext
+func (dl *DouyinLive) ExportSession() Session {
+	cursor, internalExt := dl.resumeState()
+	return Session{
+		LiveID:      dl.liveID,
+		RoomID:      dl.roomID,
+		PushID:      dl.pushID,
+		LiveName:    dl.LiveName,
+		TTWID:       dl.ttwid,
+		UserAgent:   dl.userAgent,
+		Cursor:      cursor,
+		InternalExt: internalExt,
+		SavedAt:     time.Now(),
+	}
+}
+
+// NewDouyinLiveFromSession 使用导出的会话创建实例，之后调用 Start2 即可从断点续连
+func NewDouyinLiveFromSession(s Session, logger logger, opts ...Option) *DouyinLive {
+	dl := NewDouyinLive2(s.RoomID, s.PushID, s.LiveName, s.TTWID, logger, opts...)
+	dl.liveID = s.LiveID
+	if s.UserAgent != "" {
+		dl.userAgent = s.UserAgent
+		dl.client.SetUserAgent(s.UserAgent)
+	}
+	dl.saveResumeState(s.Cursor, s.InternalExt)
+	return dl
+}
+
+// saveResumeState 记录服务端返回的 cursor 与 internal_ext
+func (dl *DouyinLive) saveResumeState(cursor, internalExt string) {
+	if cursor == "" && internalExt == "" {
+		return
+	}
+	dl.resumeMu.Lock()
+	defer dl.resumeMu.Unlock()
+	if cursor != "" {
+		dl.cursor = cursor
+	}
+	if internalExt != "" {
+		dl.internalExt = internalExt
+	}
+}
+
+// resumeState 返回续连所需的 cursor 与 internal_ext
+func (dl *DouyinLive) resumeState() (cursor, internalExt string) {
+	dl.resumeMu.Lock()
+	defer dl.resumeMu.Unlock()
+	return dl.cursor, dl.internalExt
+}
@@ -0,0 +1,42 @@
+package douyinLive
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	dl := NewDouyinLive2("7380000000000000000", "7390000000000000000", "主播", "ttwid-value", log.Default())
+	dl.saveResumeState("r-7380000000000000001_d-1_u-1", "internal_src:dim|seq:99")
+
+	data, err := dl.ExportSession().Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	session, err := ParseSession(data)
+	if err != nil {
+		t.Fatalf("ParseSession() error = %v", err)
+	}
+
+	restored := NewDouyinLiveFromSession(session, log.Default())
+	if restored.roomID != dl.roomID || restored.pushID != dl.pushID || restored.ttwid != dl.ttwid || restored.userAgent != dl.userAgent {
+		t.Fatalf("会话恢复不完整: %+v", session)
+	}
+	if err := restored.initialize(); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	restored.customSigner = SignerFunc(func(context.Context, SignRequest) (string, error) { return "sig", nil })
+	url, err := restored.makeURL(t.Context())
+	if err != nil {
+		t.Fatalf("makeURL() error = %v", err)
+	}
+	if !strings.Contains(url, "cursor=r-7380000000000000001_d-1_u-1&") || !strings.Contains(url, "internal_ext=internal_src%3Adim%7Cseq%3A99&") {
+		t.Fatalf("URL 未使用续连的 cursor: %s", url)
+	}
+
+	if _, err := ParseSession([]byte(`{}`)); err == nil {
+		t.Fatal("缺少 room_id 的会话应解析失败")
+	}
+}
@@ -0,0 +1,34 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/skip2/go-qrcode"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// defaultQRCodeSize 二维码默认边长（像素）
+const defaultQRCodeSize = 256
+
+// LiveURL 返回直播间的网页链接
+func LiveURL(liveID string) string {
+	return fmt.Sprintf("https://live.douyin.com/%s", liveID)
+}
+
+// QRCode 生成直播间链接的二维码 PNG，size 为图片边长，<=0 时使用默认值
+func QRCode(liveID string, size int) ([]byte, error) {
+	if liveID == "" {
+		return nil, errors.New("直播间号为空")
+	}
+	if size <= 0 {
+		size = defaultQRCodeSize
+	}
+	return qrcode.Encode(LiveURL(liveID), qrcode.Medium, size)
+}
+
+// RoomQRCode 生成直播实例对应直播间的二维码 PNG
+func RoomQRCode(dl *douyinLive.DouyinLive, size int) ([]byte, error) {
+	return QRCode(dl.LiveID(), size)
+}
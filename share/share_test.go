@@ -0,0 +1,51 @@
+package share
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestLiveURL(t *testing.T) {
+	if got := LiveURL("933572413882"); got != "https://live.douyin.com/933572413882" {
+		t.Fatalf("LiveURL = %s", got)
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	for _, c := range []struct {
+		size, want int
+	}{
+		{0, defaultQRCodeSize},
+		{128, 128},
+	} {
+		data, err := QRCode("933572413882", c.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("不是有效的 PNG: %v", err)
+		}
+		if b := img.Bounds(); b.Dx() != c.want || b.Dy() != c.want {
+			t.Fatalf("size=%d 时图片为 %v", c.size, b)
+		}
+	}
+	if _, err := QRCode("", 0); err == nil {
+		t.Fatal("直播间号为空时应返回错误")
+	}
+}
+
+func TestRoomQRCode(t *testing.T) {
+	dl, _ := douyinLive.NewDouyinLive("933572413882", nil)
+	got, err := RoomQRCode(dl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := QRCode("933572413882", 0)
+	if !bytes.Equal(got, want) {
+		t.Fatal("RoomQRCode 应使用实例的直播间号")
+	}
+}
@@ -0,0 +1,185 @@
+package douyinLive
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// Registry 进程内共享连接的注册表：多个业务方订阅同一直播间时只保持一条 WSS，
+// 事件复制后分发给各订阅方，最后一个订阅方释放时关闭连接
+type Registry struct {
+	mu    sync.Mutex
+	rooms map[string]*sharedRoom
+	opts  []Option
+
+	// 便于测试替换
+	resolve func(ctx context.Context, input string) (string, error)
+	start   func(*DouyinLive) error
+	close   func(*DouyinLive)
+}
+
+// sharedRoom 一个直播间的共享连接
+type sharedRoom struct {
+	dl   *DouyinLive
+	refs int
+	done chan struct{}
+	err  error
+}
+
+// NewRegistry 创建注册表，opts 用于注册表创建的每个实例
+func NewRegistry(opts ...Option) *Registry {
+	return &Registry{
+		rooms:   make(map[string]*sharedRoom),
+		opts:    opts,
+		resolve: ResolveLiveID,
+		start:   (*DouyinLive).Start,
+		close:   (*DouyinLive).Close,
+	}
+}
+
+// Acquire 获取直播间的共享连接，首次获取时创建实例并在后台 Start。
+// 连接结束后再次 Acquire 会重新连接
+func (r *Registry) Acquire(liveID string) (*SharedLive, error) {
+	return r.AcquireContext(context.Background(), liveID)
+}
+
+// AcquireContext 同 Acquire，input 可以是链接或分享文案，先在 ctx 内解析出直播间号，
+// 同一直播间的不同写法共享一条连接
+func (r *Registry) AcquireContext(ctx context.Context, input string) (*SharedLive, error) {
+	liveID, err := r.resolve(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	room, ok := r.rooms[liveID]
+	if !ok {
+		dl, err := NewDouyinLive(liveID, nil, r.opts...)
+		if err != nil {
+			return nil, err
+		}
+		room = &sharedRoom{dl: dl, done: make(chan struct{})}
+		r.rooms[liveID] = room
+		go r.run(liveID, room)
+	}
+	room.refs++
+	return &SharedLive{registry: r, liveID: liveID, room: room}, nil
+}
+
+// run 运行连接，结束后从注册表中移除
+func (r *Registry) run(liveID string, room *sharedRoom) {
+	err := r.start(room.dl)
+	r.mu.Lock()
+	if r.rooms[liveID] == room {
+		delete(r.rooms, liveID)
+	}
+	r.mu.Unlock()
+	room.err = err
+	close(room.done)
+}
+
+// release 减少引用计数，归零时关闭连接
+func (r *Registry) release(liveID string, room *sharedRoom) {
+	r.mu.Lock()
+	room.refs--
+	last := room.refs == 0
+	if last && r.rooms[liveID] == room {
+		delete(r.rooms, liveID)
+	}
+	r.mu.Unlock()
+	if last {
+		r.close(room.dl)
+	}
+}
+
+// Rooms 返回当前共享中的直播间及其订阅方数量
+func (r *Registry) Rooms() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rooms := make(map[string]int, len(r.rooms))
+	for liveID, room := range r.rooms {
+		rooms[liveID] = room.refs
+	}
+	return rooms
+}
+
+// SharedLive 订阅方持有的共享连接句柄，订阅只在句柄内可见，Release 时全部取消
+type SharedLive struct {
+	registry *Registry
+	liveID   string
+	room     *sharedRoom
+
+	mu       sync.Mutex
+	subs     []string
+	released bool
+}
+
+// Live 返回底层实例，可用于查询状态，不应调用其 Close
+func (s *SharedLive) Live() *DouyinLive {
+	return s.room.dl
+}
+
+// SubscribeEvent 订阅事件，每个订阅方收到独立的事件副本，修改标签或内容不影响其他订阅方
+func (s *SharedLive) SubscribeEvent(handler func(*LiveEvent)) string {
+	return s.track(s.room.dl.SubscribeEvent(func(event *LiveEvent) {
+		handler(event.Clone())
+	}))
+}
+
+// Subscribe 订阅原始消息，消息在订阅方之间共享，不应修改
+func (s *SharedLive) Subscribe(handler func(*new_douyin.Webcast_Im_Message)) string {
+	return s.track(s.room.dl.Subscribe(handler))
+}
+
+// Unsubscribe 取消本句柄内的订阅
+func (s *SharedLive) Unsubscribe(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, sub := range s.subs {
+		if sub == id {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			s.room.dl.Unsubscribe(id)
+			return
+		}
+	}
+}
+
+// track 记录订阅 ID，句柄已释放时立即取消
+func (s *SharedLive) track(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.released {
+		s.room.dl.Unsubscribe(id)
+		return id
+	}
+	s.subs = append(s.subs, id)
+	return id
+}
+
+// Done 连接结束时关闭
+func (s *SharedLive) Done() <-chan struct{} {
+	return s.room.done
+}
+
+// Err 返回连接结束的原因，应在 Done 关闭后调用
+func (s *SharedLive) Err() error {
+	return s.room.err
+}
+
+// Release 取消本句柄的全部订阅，最后一个订阅方释放时关闭连接，重复调用无效果
+func (s *SharedLive) Release() {
+	s.mu.Lock()
+	if s.released {
+		s.mu.Unlock()
+		return
+	}
+	s.released = true
+	for _, id := range s.subs {
+		s.room.dl.Unsubscribe(id)
+	}
+	s.subs = nil
+	s.mu.Unlock()
+	s.registry.release(s.liveID, s.room)
+}
@@ -0,0 +1,79 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestRegistryShare(t *testing.T) {
+	r := NewRegistry()
+	started := make(chan *DouyinLive, 2)
+	stop := make(chan struct{})
+	r.start = func(dl *DouyinLive) error {
+		started <- dl
+		<-stop
+		return nil
+	}
+	r.close = func(*DouyinLive) { close(stop) }
+
+	a, _ := r.Acquire("100")
+	b, _ := r.Acquire("100")
+	if a.Live() != b.Live() || len(started) > 1 || r.Rooms()["100"] != 2 {
+		t.Fatalf("同一直播间应共享一个实例: %v", r.Rooms())
+	}
+	dl := <-started
+
+	var gotA, gotB *LiveEvent
+	a.SubscribeEvent(func(e *LiveEvent) {
+		e.SetTag("owner", "a")
+		e.SetContent("已修改")
+		gotA = e
+	})
+	b.SubscribeEvent(func(e *LiveEvent) { gotB = e })
+	dl.deliver(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "你好"}))
+	if gotA == gotB || gotB.Tags["owner"] != "" || gotB.Content() != "你好" {
+		t.Fatalf("订阅方应收到独立副本: %v %q", gotB.Tags, gotB.Content())
+	}
+
+	a.Release()
+	a.Release()
+	if r.Rooms()["100"] != 1 || len(dl.eventHandlers) != 1 {
+		t.Fatalf("释放后应只剩一个订阅方: %v, 订阅数 %d", r.Rooms(), len(dl.eventHandlers))
+	}
+	b.Release()
+	select {
+	case <-b.Done():
+	case <-time.After(time.Second):
+		t.Fatal("最后一个订阅方释放后应关闭连接")
+	}
+	if len(r.Rooms()) != 0 {
+		t.Fatalf("连接关闭后应移出注册表: %v", r.Rooms())
+	}
+}
+
+func TestRegistryResolvedKey(t *testing.T) {
+	r := NewRegistry()
+	r.resolve = func(_ context.Context, input string) (string, error) {
+		if input == "https://v.douyin.com/abc/" {
+			return "100", nil
+		}
+		return ResolveLiveID(context.Background(), input)
+	}
+	stop := make(chan struct{})
+	r.start = func(*DouyinLive) error { <-stop; return nil }
+	r.close = func(*DouyinLive) { close(stop) }
+
+	a, err := r.AcquireContext(context.Background(), "https://v.douyin.com/abc/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := r.Acquire("100")
+	if a.Live() != b.Live() || a.Live().LiveID() != "100" || r.Rooms()["100"] != 2 {
+		t.Fatalf("短链与直播间号应共享一个实例: %v", r.Rooms())
+	}
+	a.Release()
+	b.Release()
+}
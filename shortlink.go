@@ -0,0 +1,166 @@
+package douyinLive
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+const (
+	maxShortLinkRedirects = 5
+	resolveTimeout        = 10 * time.Second
+)
+
+var (
+	liveIDRegex     = regexp.MustCompile(`^\d+$`)
+	shareURLRegex   = regexp.MustCompile(`https?://[0-9A-Za-z.\-:]+(?:/[0-9A-Za-z_\-./?=&%#]*)?`)
+	shortHostRegex  = regexp.MustCompile(`\bv\.douyin\.com/[0-9A-Za-z_\-]+/?`)
+	webRidPathRegex = regexp.MustCompile(`live\.douyin\.com/(\d+)`)
+	reflowPathRegex = regexp.MustCompile(`/reflow/(\d+)`)
+)
+
+// shortLinkHosts 允许跟随的链接域名（含子域名），测试中会被替换
+var shortLinkHosts = []string{"douyin.com", "iesdouyin.com"}
+
+// reflowInfoURL 按 room_id 查询直播间信息的接口，用于从 App 分享链接得到网页直播间号，测试中会被替换
+var reflowInfoURL = "https://webcast.amemv.com/webcast/room/reflow/info/"
+
+// ResolveLiveID 把用户粘贴的内容解析为网页直播间号（web_rid），支持：
+// 直播间号本身、live.douyin.com 链接、v.douyin.com 短链、从 App 复制的整段分享文案，
+// 以及主播的 sec_uid 或主页链接（未开过播时返回包装了 ErrRoomOffline 的错误）。
+// 只会访问 douyin.com、iesdouyin.com 及其子域名的链接，其他地址返回 ErrRoomNotFound
+func ResolveLiveID(ctx context.Context, input string) (string, error) {
+	dl := &DouyinLive{client: req.C()}
+	return dl.resolveLiveID(ctx, input)
+}
+
+// resolveLiveID 见 ResolveLiveID，短链跳转使用实例的 HTTP 客户端
+func (dl *DouyinLive) resolveLiveID(ctx context.Context, input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if liveIDRegex.MatchString(input) {
+		return input, nil
+	}
+	if m := webRidPathRegex.FindStringSubmatch(input); m != nil {
+		return m[1], nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	if secUID := secUIDRegex.FindString(input); secUID != "" {
+		return dl.liveIDBySecUID(ctx, secUID)
+	}
+	link := shareURLRegex.FindString(input)
+	if link == "" {
+		// 分享文案中的短链可能没有协议头
+		if short := shortHostRegex.FindString(input); short != "" {
+			link = "https://" + short
+		}
+	}
+	if link == "" {
+		return "", fmt.Errorf("%w: 无法识别的直播间: %q", ErrRoomNotFound, input)
+	}
+
+	var liveID string
+	var lookupErr error
+	final, err := dl.followRedirects(ctx, link, func(link string) bool {
+		u, err := url.Parse(link)
+		if err != nil {
+			return false
+		}
+		liveID, lookupErr = dl.liveIDFromURL(ctx, u)
+		return liveID != "" || lookupErr != nil || secUIDRegex.MatchString(link)
+	})
+	if err != nil {
+		return "", err
+	}
+	if liveID != "" || lookupErr != nil {
+		return liveID, lookupErr
+	}
+	// 跳转到主播主页时按 sec_uid 查询
+	if secUID := secUIDRegex.FindString(final); secUID != "" {
+		return dl.liveIDBySecUID(ctx, secUID)
+	}
+	return "", fmt.Errorf("%w: 链接未指向直播间: %s", ErrRoomNotFound, final)
+}
+
+// followRedirects 逐跳跟随短链，done 对某一跳返回 true 时停止并返回该地址，否则返回最后一跳
+func (dl *DouyinLive) followRedirects(ctx context.Context, link string, done func(string) bool) (string, error) {
+	client := dl.client.Clone().SetRedirectPolicy(req.NoRedirectPolicy())
+	for i := 0; i <= maxShortLinkRedirects; i++ {
+		if done(link) {
+			return link, nil
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			return "", fmt.Errorf("%w: 链接格式错误: %w", ErrRoomNotFound, err)
+		}
+		// 每一跳都检查域名，避免用户输入或跳转把请求引向内网等任意地址
+		if !shortLinkAllowed(u) {
+			return "", fmt.Errorf("%w: 不支持的链接: %s", ErrRoomNotFound, u.Redacted())
+		}
+		resp, err := client.R().SetContext(ctx).Get(link)
+		if err != nil {
+			return "", fmt.Errorf("解析短链失败: %w", err)
+		}
+		location := resp.Header.Get("Location")
+		if location == "" {
+			break
+		}
+		next, err := u.Parse(location)
+		if err != nil {
+			return "", fmt.Errorf("%w: 跳转地址格式错误: %w", ErrRoomNotFound, err)
+		}
+		link = next.String()
+	}
+	return link, nil
+}
+
+// shortLinkAllowed 是否为可以访问的抖音链接，只允许 http(s) 与 shortLinkHosts 中的域名
+func shortLinkAllowed(u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range shortLinkHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// liveIDFromURL 从跳转途中的地址提取直播间号，App 分享链接只带 room_id 时查询对应的 web_rid
+func (dl *DouyinLive) liveIDFromURL(ctx context.Context, u *url.URL) (string, error) {
+	if m := webRidPathRegex.FindStringSubmatch(u.Host + u.Path); m != nil {
+		return m[1], nil
+	}
+	query := u.Query()
+	if id := query.Get("web_rid"); liveIDRegex.MatchString(id) {
+		return id, nil
+	}
+	roomID := query.Get("room_id")
+	if m := reflowPathRegex.FindStringSubmatch(u.Path); m != nil {
+		roomID = m[1]
+	}
+	if !liveIDRegex.MatchString(roomID) {
+		return "", nil
+	}
+	return dl.webRidByRoomID(ctx, roomID)
+}
+
+// webRidByRoomID 按 room_id 查询网页直播间号
+func (dl *DouyinLive) webRidByRoomID(ctx context.Context, roomID string) (string, error) {
+	result, err := dl.reflowInfo(ctx, map[string]string{"room_id": roomID})
+	if err != nil {
+		return "", err
+	}
+	webRid := result.Get("data.room.owner.web_rid").String()
+	if !liveIDRegex.MatchString(webRid) {
+		return "", fmt.Errorf("%w: room_id %s 没有对应的直播间号", ErrRoomNotFound, roomID)
+	}
+	return webRid, nil
+}
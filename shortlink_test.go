@@ -0,0 +1,142 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolveLiveID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/":
+			http.Redirect(w, r, "/douyin/webcast/reflow/7300000000000000001?u_code=x", http.StatusFound)
+		case "/web/":
+			http.Redirect(w, r, "https://live.douyin.com/654321?from=share", http.StatusFound)
+		case "/webcast/room/reflow/info/":
+			if r.URL.Query().Get("room_id") == "7300000000000000001" {
+				w.Write([]byte(`{"data":{"room":{"owner":{"web_rid":"123456"}}}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{}}`))
+		case "/video/":
+			http.Redirect(w, r, "/share/video/1", http.StatusFound)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+	old := reflowInfoURL
+	reflowInfoURL = srv.URL + "/webcast/room/reflow/info/"
+	defer func() { reflowInfoURL = old }()
+	allowShortLinkHost(t, "127.0.0.1")
+
+	cases := map[string]string{
+		"123456": "123456",
+		"https://live.douyin.com/654321?room_id=1": "654321",
+		srv.URL + "/web/":                          "654321",
+		"7- 长按复制此条消息，打开抖音搜索，查看TA的更多作品。 " + srv.URL + "/app/ 8@5.com :0pm": "123456",
+	}
+	for input, want := range cases {
+		got, err := ResolveLiveID(context.Background(), input)
+		if err != nil || got != want {
+			t.Fatalf("%q => %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"随便一段话", srv.URL + "/video/"} {
+		if _, err := ResolveLiveID(context.Background(), input); !errors.Is(err, ErrRoomNotFound) {
+			t.Fatalf("%q 应返回 ErrRoomNotFound: %v", input, err)
+		}
+	}
+
+	dl, err := NewDouyinLive(srv.URL+"/web/", nil)
+	if err != nil || dl.LiveID() != "654321" {
+		t.Fatalf("NewDouyinLive 应自动解析短链: %v", err)
+	}
+}
+
+// allowShortLinkHost 测试期间允许跟随 host 的链接
+func allowShortLinkHost(t *testing.T, host string) {
+	old := shortLinkHosts
+	shortLinkHosts = append([]string{host}, old...)
+	t.Cleanup(func() { shortLinkHosts = old })
+}
+
+func TestResolveLiveIDHosts(t *testing.T) {
+	var hits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		http.Redirect(w, r, "http://127.0.0.1:1/admin", http.StatusFound)
+	}))
+	defer internal.Close()
+
+	// 非抖音域名不访问
+	if _, err := ResolveLiveID(context.Background(), internal.URL+"/secret"); !errors.Is(err, ErrRoomNotFound) || hits.Load() != 0 {
+		t.Fatalf("err = %v, hits = %d", err, hits.Load())
+	}
+	for link, want := range map[string]bool{
+		"https://v.douyin.com/abc/":          true,
+		"https://www.iesdouyin.com/share/1":  true,
+		"https://douyin.com.evil.example/":   false,
+		"file:///etc/passwd":                 false,
+		"https://evil-douyin.com/":           false,
+		"http://LIVE.DOUYIN.COM:8080/x":      true,
+		"https://127.0.0.1/v.douyin.com/abc": false,
+	} {
+		u, _ := url.Parse(link)
+		if got := shortLinkAllowed(u); got != want {
+			t.Errorf("shortLinkAllowed(%s) = %v", link, got)
+		}
+	}
+}
+
+func TestLookupAnchor(t *testing.T) {
+	const living, offline = "MS4wLjABAAAAliving_1", "MS4wLjABAAAAoffline-2"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/profile/":
+			http.Redirect(w, r, "https://www.iesdouyin.com/share/user/1?sec_uid="+living, http.StatusFound)
+		case "/webcast/room/reflow/info/":
+			switch r.URL.Query().Get("sec_user_id") {
+			case living:
+				w.Write([]byte(`{"data":{"room":{"id_str":"7300","status":2,"title":"晚间直播","owner":{"nickname":"主播","web_rid":"123456"}}}}`))
+			case offline:
+				w.Write([]byte(`{"data":{"user":{"nickname":"没开过播"}}}`))
+			default:
+				w.Write([]byte(`{"data":{}}`))
+			}
+		}
+	}))
+	defer srv.Close()
+	old := reflowInfoURL
+	reflowInfoURL = srv.URL + "/webcast/room/reflow/info/"
+	defer func() { reflowInfoURL = old }()
+	allowShortLinkHost(t, "127.0.0.1")
+
+	info, err := LookupAnchor(context.Background(), "https://www.douyin.com/user/"+living+"?from_tab_name=main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Living || info.LiveID != "123456" || info.RoomID != "7300" || info.Nickname != "主播" {
+		t.Fatalf("info = %+v", info)
+	}
+	if info, err := LookupAnchor(context.Background(), srv.URL+"/profile/"); err != nil || info.SecUID != living {
+		t.Fatalf("短链指向主页: %+v, %v", info, err)
+	}
+
+	for _, input := range []string{living, "https://www.douyin.com/user/" + living, srv.URL + "/profile/"} {
+		if id, err := ResolveLiveID(context.Background(), input); err != nil || id != "123456" {
+			t.Fatalf("%q => %q, %v", input, id, err)
+		}
+	}
+	if _, err := ResolveLiveID(context.Background(), offline); !errors.Is(err, ErrRoomOffline) {
+		t.Fatalf("没有直播间号时应返回 ErrRoomOffline: %v", err)
+	}
+	if _, err := LookupAnchor(context.Background(), "MS4wLjABAAAAunknown"); !errors.Is(err, ErrRoomNotFound) {
+		t.Fatalf("未知主播应返回 ErrRoomNotFound: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+// Package signcache 跨进程共享的签名与 ttwid 缓存，配合 douyinLive.WithSignatureCache
+// 与 WithTTWIDStore 使用，多个采集进程在重连风暴时复用同一房间的签名，减少 JS 执行与首页请求
+package signcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// defaultPrefix 键前缀的默认值
+const defaultPrefix = "douyin:sign"
+
+// Redis 基于 Redis 的签名缓存，键为 <prefix>:<SignatureCacheKey>
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ douyinLive.SignatureCache = (*Redis)(nil)
+
+// NewRedis 使用已有客户端创建签名缓存，prefix 为空时为 douyin:sign
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	return &Redis{client: client, prefix: prefix}
+}
+
+// ConnectRedis 按 redis:// URL 连接并创建签名缓存
+func ConnectRedis(ctx context.Context, url, prefix string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Redis 地址失败: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+	return NewRedis(client, prefix), nil
+}
+
+// key 返回缓存键
+func (r *Redis) key(key string) string {
+	return r.prefix + ":" + key
+}
+
+// Get 实现 douyinLive.SignatureCache
+func (r *Redis) Get(ctx context.Context, key string) (string, bool, error) {
+	signature, err := r.client.Get(ctx, r.key(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return signature, true, nil
+}
+
+// Set 实现 douyinLive.SignatureCache
+func (r *Redis) Set(ctx context.Context, key, signature string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.key(key), signature, ttl).Err()
+}
+
+// Delete 实现 douyinLive.SignatureCache
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.key(key)).Err()
+}
+
+// Close 关闭 Redis 客户端
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,64 @@
+package signcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func TestRedisSignatureCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cache := NewRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "")
+
+	calls := 0
+	inner := douyinLive.SignerFunc(func(context.Context, douyinLive.SignRequest) (string, error) {
+		calls++
+		return "sig", nil
+	})
+	// 两个进程各自的 CachedSigner 共享同一 Redis
+	a := &douyinLive.CachedSigner{Signer: inner, Cache: cache, TTL: time.Minute}
+	b := &douyinLive.CachedSigner{Signer: inner, Cache: NewRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "")}
+	req := douyinLive.SignRequest{RoomID: "1", PushID: "2", UserAgent: "ua"}
+
+	for _, s := range []*douyinLive.CachedSigner{a, b, a} {
+		if sig, err := s.Sign(context.Background(), req); err != nil || sig != "sig" {
+			t.Fatalf("sig = %s, err = %v", sig, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("签名应只执行一次: %d", calls)
+	}
+	if ttl := mr.TTL("douyin:sign:" + douyinLive.SignatureCacheKey(req)); ttl != time.Minute {
+		t.Fatalf("TTL = %s", ttl)
+	}
+
+	// 握手失败后删除缓存，下次重新签名
+	b.ReportHandshake("sig", errors.New("403"))
+	a.Sign(context.Background(), req)
+	if calls != 2 {
+		t.Fatalf("握手失败后应重新签名: %d", calls)
+	}
+}
+
+func TestRedisTTWID(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := NewRedisTTWID(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "")
+	if _, ok, err := store.Get(context.Background()); ok || err != nil {
+		t.Fatalf("空缓存应未命中: %v, %v", ok, err)
+	}
+	if err := store.Set(context.Background(), "tw", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttwid, ok, _ := store.Get(context.Background()); !ok || ttwid != "tw" {
+		t.Fatalf("Get = %q, %v", ttwid, ok)
+	}
+	if ttl := mr.TTL("douyin:ttwid"); ttl != time.Hour {
+		t.Fatalf("TTL = %s", ttl)
+	}
+}
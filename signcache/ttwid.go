@@ -0,0 +1,47 @@
+package signcache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// defaultTTWIDKey ttwid 的默认键
+const defaultTTWIDKey = "douyin:ttwid"
+
+// RedisTTWID 基于 Redis 的 ttwid 缓存，多个采集进程共享同一个 ttwid
+type RedisTTWID struct {
+	client redis.UniversalClient
+	key    string
+}
+
+var _ douyinLive.TTWIDStore = (*RedisTTWID)(nil)
+
+// NewRedisTTWID 使用已有客户端创建 ttwid 缓存，key 为空时为 douyin:ttwid
+func NewRedisTTWID(client redis.UniversalClient, key string) *RedisTTWID {
+	if key == "" {
+		key = defaultTTWIDKey
+	}
+	return &RedisTTWID{client: client, key: key}
+}
+
+// Get 实现 douyinLive.TTWIDStore
+func (r *RedisTTWID) Get(ctx context.Context) (string, bool, error) {
+	ttwid, err := r.client.Get(ctx, r.key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return ttwid, true, nil
+}
+
+// Set 实现 douyinLive.TTWIDStore
+func (r *RedisTTWID) Set(ctx context.Context, ttwid string, ttl time.Duration) error {
+	return r.client.Set(ctx, r.key, ttwid, ttl).Err()
+}
@@ -0,0 +1,129 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// SignRequest WebSocket 连接签名的输入
+type SignRequest struct {
+	RoomID    string
+	PushID    string
+	UserAgent string
+}
+
+// Stub 返回参与签名的 X-MS-Stub
+func (r SignRequest) Stub() string {
+	return utils.GetxMSStub(utils.NewOrderedMap(r.RoomID, r.PushID))
+}
+
+// Signer 生成 WebSocket 连接的 signature 参数
+type Signer interface {
+	Sign(ctx context.Context, req SignRequest) (string, error)
+}
+
+// SignerFunc 函数形式的 Signer
+type SignerFunc func(ctx context.Context, req SignRequest) (string, error)
+
+// Sign 实现 Signer
+func (f SignerFunc) Sign(ctx context.Context, req SignRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// signerPreparer 连接前需要按 User-Agent 初始化的 Signer
+type signerPreparer interface {
+	Prepare(userAgent string) error
+}
+
+// WithSigner 替换签名实现，默认在 goja 中执行 webmssdk.js
+func WithSigner(s Signer) Option {
+	return func(dl *DouyinLive) {
+		dl.customSigner = s
+	}
+}
+
+// signer 返回实例使用的签名实现，配置了 WithSignatureCache 时外面再包一层缓存
+func (dl *DouyinLive) signer() Signer {
+	base := dl.baseSigner()
+	if dl.signatureCache == nil || base == nil {
+		return base
+	}
+	dl.signerMu.Lock()
+	defer dl.signerMu.Unlock()
+	if dl.cachedSigner == nil {
+		dl.cachedSigner = &CachedSigner{Signer: base, Cache: dl.signatureCache, TTL: dl.signatureTTL}
+	}
+	return dl.cachedSigner
+}
+
+// baseSigner 返回未加缓存的签名实现
+func (dl *DouyinLive) baseSigner() Signer {
+	if dl.customSigner != nil {
+		return dl.customSigner
+	}
+	return defaultSigner
+}
+
+// prepareSigner 在连接前初始化签名实现
+func (dl *DouyinLive) prepareSigner() error {
+	if p, ok := dl.signer().(signerPreparer); ok {
+		return p.Prepare(dl.userAgent)
+	}
+	return nil
+}
+
+// reportHandshake 将握手结果回报给实现了 HandshakeReporter 的签名实现
+func (dl *DouyinLive) reportHandshake(err error) {
+	if r, ok := dl.signer().(HandshakeReporter); ok && dl.lastSignature != "" {
+		r.ReportHandshake(dl.lastSignature, err)
+	}
+}
+
+// FallbackSigner 优先使用 Primary，失败时改用 Fallback。
+// Fallback 需要按 User-Agent 初始化时（如 JSSigner），只在第一次回退时初始化，
+// 因此 Primary 可用时不会加载 JS 运行时
+type FallbackSigner struct {
+	Primary  Signer
+	Fallback Signer
+
+	mu       sync.Mutex
+	prepared map[string]bool
+}
+
+// Sign 实现 Signer
+func (s *FallbackSigner) Sign(ctx context.Context, req SignRequest) (string, error) {
+	signature, err := s.Primary.Sign(ctx, req)
+	if err == nil || s.Fallback == nil {
+		return signature, err
+	}
+	if err := s.prepareFallback(req.UserAgent); err != nil {
+		return "", err
+	}
+	fallback, fallbackErr := s.Fallback.Sign(ctx, req)
+	if fallbackErr != nil {
+		return "", errors.Join(err, fallbackErr)
+	}
+	return fallback, nil
+}
+
+// prepareFallback 按 User-Agent 初始化回退实现
+func (s *FallbackSigner) prepareFallback(userAgent string) error {
+	p, ok := s.Fallback.(signerPreparer)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prepared[userAgent] {
+		return nil
+	}
+	if err := p.Prepare(userAgent); err != nil {
+		return err
+	}
+	// JS 脚本为进程内单例，换 User-Agent 后之前的记录失效
+	s.prepared = map[string]bool{userAgent: true}
+	return nil
+}
@@ -0,0 +1,163 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultSignatureCacheTTL 签名缓存的默认有效期
+const defaultSignatureCacheTTL = time.Minute
+
+// SignatureCache 签名结果缓存，跨进程共享时可使用 signcache.Redis
+type SignatureCache interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, signature string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CachedSigner 按 roomID+pushID+User-Agent 缓存签名结果，重连风暴时同一房间只需签名一次。
+// 握手失败的签名会从缓存中删除
+type CachedSigner struct {
+	Signer Signer
+	Cache  SignatureCache
+	TTL    time.Duration // 默认 1 分钟
+
+	group  singleflight.Group // 合并本实例内同一键的并发签名，不同实例的内层实现互不影响
+	mu     sync.Mutex
+	issued map[string]string // 发出的签名 → 缓存键
+}
+
+// SignatureCacheKey 返回签名请求的缓存键
+func SignatureCacheKey(req SignRequest) string {
+	sum := sha1.Sum([]byte(req.RoomID + "\x00" + req.PushID + "\x00" + req.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sign 实现 Signer，缓存读写失败时直接签名
+func (s *CachedSigner) Sign(ctx context.Context, req SignRequest) (string, error) {
+	key := SignatureCacheKey(req)
+	if signature, ok, err := s.Cache.Get(ctx, key); err == nil && ok {
+		s.remember(signature, key)
+		return signature, nil
+	}
+	// 合并的签名由多个调用方共享，不随第一个调用方取消；各调用方仍可在自己的 ctx 结束时提前返回
+	shared := context.WithoutCancel(ctx)
+	ch := s.group.DoChan(key, func() (interface{}, error) {
+		signature, err := s.Signer.Sign(shared, req)
+		if err != nil {
+			return "", err
+		}
+		ttl := s.TTL
+		if ttl <= 0 {
+			ttl = defaultSignatureCacheTTL
+		}
+		s.Cache.Set(shared, key, signature, ttl)
+		return signature, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return "", res.Err
+		}
+		signature := res.Val.(string)
+		s.remember(signature, key)
+		return signature, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Prepare 转发给需要初始化的内层实现
+func (s *CachedSigner) Prepare(userAgent string) error {
+	if p, ok := s.Signer.(signerPreparer); ok {
+		return p.Prepare(userAgent)
+	}
+	return nil
+}
+
+// ReportHandshake 实现 HandshakeReporter：握手失败时删除对应的缓存，并转发给内层实现
+func (s *CachedSigner) ReportHandshake(signature string, err error) {
+	if r, ok := s.Signer.(HandshakeReporter); ok {
+		r.ReportHandshake(signature, err)
+	}
+	s.mu.Lock()
+	key, ok := s.issued[signature]
+	delete(s.issued, signature)
+	s.mu.Unlock()
+	if ok && err != nil {
+		s.Cache.Delete(context.Background(), key)
+	}
+}
+
+// remember 记录发出的签名对应的缓存键，供握手失败时删除
+func (s *CachedSigner) remember(signature, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.issued == nil || len(s.issued) >= maxPendingSignatures {
+		s.issued = make(map[string]string)
+	}
+	s.issued[signature] = key
+}
+
+// MemorySignatureCache 进程内的签名缓存
+type MemorySignatureCache struct {
+	mu      sync.Mutex
+	entries map[string]memorySignature
+}
+
+// memorySignature 缓存项
+type memorySignature struct {
+	signature string
+	expires   time.Time
+}
+
+// NewMemorySignatureCache 创建进程内的签名缓存
+func NewMemorySignatureCache() *MemorySignatureCache {
+	return &MemorySignatureCache{entries: make(map[string]memorySignature)}
+}
+
+// Get 实现 SignatureCache
+func (c *MemorySignatureCache) Get(_ context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return "", false, nil
+	}
+	return e.signature, true, nil
+}
+
+// Set 实现 SignatureCache，顺带清理过期项
+func (c *MemorySignatureCache) Set(_ context.Context, key, signature string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memorySignature{signature: signature, expires: now.Add(ttl)}
+	return nil
+}
+
+// Delete 实现 SignatureCache
+func (c *MemorySignatureCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+// WithSignatureCache 为实例的签名实现加上缓存，ttl<=0 时为 1 分钟
+func WithSignatureCache(cache SignatureCache, ttl time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.signatureCache, dl.signatureTTL = cache, ttl
+	}
+}
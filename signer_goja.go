@@ -0,0 +1,39 @@
+//go:build !douyinlive_nojs
+
+package douyinLive
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tiga210/douyinLive/jsScript"
+)
+
+// defaultSignerName 内置签名实现的名称，见 Capabilities
+const defaultSignerName = "goja"
+
+// defaultSigner 未通过 WithSigner 指定时使用的签名实现
+var defaultSigner Signer = JSSigner{}
+
+// JSSigner 在 goja 中执行 webmssdk.js 生成签名，脚本为进程内全局单例
+type JSSigner struct{}
+
+// Prepare 按 User-Agent 加载脚本
+func (JSSigner) Prepare(userAgent string) error {
+	return jsScript.LoadGoja(userAgent)
+}
+
+// Sign 实现 Signer
+func (JSSigner) Sign(_ context.Context, req SignRequest) (string, error) {
+	signature := jsScript.ExecuteJS(req.Stub())
+	if signature == "" {
+		return "", errors.New("签名结果为空")
+	}
+	return signature, nil
+}
+
+// PatchSignScript 运行时替换 webmssdk.js，进程内所有实例的 JS 签名立即生效，无需重启。
+// 新脚本必须定义 get_sign，加载失败时保留原脚本
+func PatchSignScript(script string) error {
+	return jsScript.SetScript(script)
+}
@@ -0,0 +1,24 @@
+//go:build douyinlive_nojs
+
+package douyinLive
+
+import (
+	"context"
+	"errors"
+)
+
+// defaultSignerName 内置签名实现的名称，见 Capabilities
+const defaultSignerName = "none"
+
+// errNoSigner 没有可用的内置签名实现
+var errNoSigner = errors.New("douyinlive_nojs 构建没有内置签名实现，请通过 WithSigner 或 WithRemoteSigner 提供")
+
+// defaultSigner 使用 douyinlive_nojs 构建时不包含 goja，需通过 WithSigner 提供签名实现
+var defaultSigner Signer = SignerFunc(func(context.Context, SignRequest) (string, error) {
+	return "", errNoSigner
+})
+
+// PatchSignScript 不包含 goja，无法替换签名脚本
+func PatchSignScript(string) error {
+	return errors.New("douyinlive_nojs 构建不支持签名脚本")
+}
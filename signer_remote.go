@@ -0,0 +1,109 @@
+package douyinLive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SignerURLEnv 通过环境变量指定远程签名服务，未使用 WithSigner 的实例都会改用该服务
+const SignerURLEnv = "DOUYINLIVE_SIGNER_URL"
+
+// defaultRemoteSignerTimeout 远程签名请求的默认超时
+const defaultRemoteSignerTimeout = 5 * time.Second
+
+// remoteSignRequest 发送给远程签名服务的请求体
+type remoteSignRequest struct {
+	RoomID    string `json:"room_id"`
+	PushID    string `json:"push_id"`
+	UserAgent string `json:"user_agent"`
+	Stub      string `json:"x_ms_stub"`
+}
+
+// remoteSignResponse 远程签名服务的响应体
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+	Error     string `json:"error"`
+}
+
+// RemoteSigner 将签名委托给远程 HTTP 服务。
+// 以 POST JSON {"room_id","push_id","user_agent","x_ms_stub"} 请求 URL，
+// 期望返回 {"signature": "..."}，非 2xx 状态码或 error 字段非空时视为失败
+type RemoteSigner struct {
+	URL    string
+	Client *http.Client // 为空时使用带 5 秒超时的默认客户端
+	Header http.Header  // 附加的请求头，如鉴权信息
+}
+
+// NewRemoteSigner 创建远程签名实现
+func NewRemoteSigner(url string) *RemoteSigner {
+	return &RemoteSigner{URL: url}
+}
+
+// Sign 实现 Signer
+func (s *RemoteSigner) Sign(ctx context.Context, req SignRequest) (string, error) {
+	body, err := json.Marshal(remoteSignRequest{
+		RoomID:    req.RoomID,
+		PushID:    req.PushID,
+		UserAgent: req.UserAgent,
+		Stub:      req.Stub(),
+	})
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	for key, values := range s.Header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultRemoteSignerTimeout}
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("请求远程签名服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", fmt.Errorf("读取远程签名响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("远程签名服务返回状态码 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var result remoteSignResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("解析远程签名响应失败: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("远程签名服务返回错误: %s", result.Error)
+	}
+	if result.Signature == "" {
+		return "", fmt.Errorf("远程签名服务返回的签名为空")
+	}
+	return result.Signature, nil
+}
+
+// WithRemoteSigner 使用远程签名服务，服务不可用时回退到内置实现
+func WithRemoteSigner(url string) Option {
+	return WithSigner(&FallbackSigner{Primary: NewRemoteSigner(url), Fallback: defaultSigner})
+}
+
+// applySignerEnv 读取 SignerURLEnv，在可选项之前应用，可被 WithSigner 覆盖
+func (dl *DouyinLive) applySignerEnv() {
+	if url := os.Getenv(SignerURLEnv); url != "" {
+		WithRemoteSigner(url)(dl)
+	}
+}
@@ -0,0 +1,216 @@
+package douyinLive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithSigner(t *testing.T) {
+	var got SignRequest
+	dl, _ := NewDouyinLive("1", nil, WithSigner(SignerFunc(func(_ context.Context, req SignRequest) (string, error) {
+		got = req
+		return "custom-signature", nil
+	})))
+	dl.roomID, dl.pushID = "100", "200"
+
+	url, err := dl.makeURL(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(url, "&signature=custom-signature") {
+		t.Fatalf("URL 未使用自定义签名: %s", url)
+	}
+	if got.RoomID != "100" || got.PushID != "200" || got.UserAgent != dl.userAgent || len(got.Stub()) != 32 {
+		t.Fatalf("签名参数 = %+v", got)
+	}
+
+	dl.customSigner = SignerFunc(func(context.Context, SignRequest) (string, error) {
+		return "", errors.New("服务不可用")
+	})
+	if _, err := dl.makeURL(context.Background()); !errors.Is(err, ErrSignature) {
+		t.Fatalf("签名失败应包装 ErrSignature: %v", err)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	var got remoteSignRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.RoomID == "bad" {
+			json.NewEncoder(w).Encode(remoteSignResponse{Error: "算法已更新"})
+			return
+		}
+		json.NewEncoder(w).Encode(remoteSignResponse{Signature: "remote-" + got.RoomID})
+	}))
+	defer srv.Close()
+
+	s := NewRemoteSigner(srv.URL)
+	s.Header = http.Header{"Authorization": {"Bearer token"}}
+	req := SignRequest{RoomID: "100", PushID: "200", UserAgent: "ua"}
+	sig, err := s.Sign(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig != "remote-100" || got.PushID != "200" || got.UserAgent != "ua" || got.Stub != req.Stub() {
+		t.Fatalf("sig = %s, 请求 = %+v", sig, got)
+	}
+	if _, err := s.Sign(context.Background(), SignRequest{RoomID: "bad"}); err == nil || !strings.Contains(err.Error(), "算法已更新") {
+		t.Fatalf("应返回服务端错误: %v", err)
+	}
+	s.Header = nil
+	if _, err := s.Sign(context.Background(), req); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("应返回状态码错误: %v", err)
+	}
+
+	t.Setenv(SignerURLEnv, srv.URL)
+	dl, _ := NewDouyinLive("1", nil)
+	if _, ok := dl.signer().(*FallbackSigner); !ok {
+		t.Fatalf("环境变量未生效: %T", dl.signer())
+	}
+}
+
+func TestCachedSigner(t *testing.T) {
+	var calls atomic.Int32
+	s := &CachedSigner{
+		Signer: SignerFunc(func(ctx context.Context, req SignRequest) (string, error) {
+			return fmt.Sprintf("sig-%d", calls.Add(1)), nil
+		}),
+		Cache: NewMemorySignatureCache(),
+	}
+	req := SignRequest{RoomID: "1", PushID: "2", UserAgent: "ua"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sig, err := s.Sign(context.Background(), req); err != nil || sig != "sig-1" {
+				t.Errorf("sig = %s, err = %v", sig, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("并发重连应只签名一次: %d", calls.Load())
+	}
+
+	// 成功的握手不影响缓存，失败后重新签名
+	s.ReportHandshake("sig-1", nil)
+	if sig, _ := s.Sign(context.Background(), req); sig != "sig-1" {
+		t.Fatalf("sig = %s", sig)
+	}
+	s.ReportHandshake("sig-1", errors.New("403"))
+	if sig, _ := s.Sign(context.Background(), req); sig != "sig-2" {
+		t.Fatalf("握手失败后应重新签名: %s", sig)
+	}
+
+	// 不同 User-Agent 使用不同的缓存键
+	req.UserAgent = "other"
+	if sig, _ := s.Sign(context.Background(), req); sig != "sig-3" {
+		t.Fatalf("sig = %s", sig)
+	}
+}
+
+func TestCachedSignerIsolation(t *testing.T) {
+	release := make(chan struct{})
+	blocking := func(name string) Signer {
+		return SignerFunc(func(ctx context.Context, _ SignRequest) (string, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+			return name, nil
+		})
+	}
+	a := &CachedSigner{Signer: blocking("a"), Cache: NewMemorySignatureCache()}
+	b := &CachedSigner{Signer: blocking("b"), Cache: NewMemorySignatureCache()}
+	req := SignRequest{RoomID: "1", PushID: "2", UserAgent: "ua"}
+
+	// 第一个调用方取消后，合并在同一次签名上的其他调用方不受影响
+	cancelled, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := a.Sign(cancelled, req)
+		firstErr <- err
+	}()
+	results := make(chan string, 2)
+	var wg sync.WaitGroup
+	for _, s := range []*CachedSigner{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sig, err := s.Sign(context.Background(), req)
+			if err != nil {
+				t.Error(err)
+			}
+			results <- sig
+		}()
+	}
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("取消的调用方应返回 ctx 错误: %v", err)
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+	got := map[string]bool{}
+	for sig := range results {
+		got[sig] = true
+	}
+	// 不同 CachedSigner 各自调用自己的内层实现
+	if !got["a"] || !got["b"] {
+		t.Fatalf("签名结果 = %v", got)
+	}
+}
+
+type preparedSigner struct {
+	prepares int
+}
+
+func (p *preparedSigner) Prepare(string) error {
+	p.prepares++
+	return nil
+}
+
+func (p *preparedSigner) Sign(context.Context, SignRequest) (string, error) {
+	return "fallback", nil
+}
+
+func TestFallbackSigner(t *testing.T) {
+	fallback := &preparedSigner{}
+	primaryErr := errors.New("primary")
+	s := &FallbackSigner{
+		Primary: SignerFunc(func(_ context.Context, req SignRequest) (string, error) {
+			if req.RoomID == "bad" {
+				return "", primaryErr
+			}
+			return "primary", nil
+		}),
+		Fallback: fallback,
+	}
+
+	if sig, _ := s.Sign(context.Background(), SignRequest{RoomID: "1", UserAgent: "ua"}); sig != "primary" || fallback.prepares != 0 {
+		t.Fatalf("sig = %s, prepares = %d", sig, fallback.prepares)
+	}
+	for i := 0; i < 2; i++ {
+		if sig, _ := s.Sign(context.Background(), SignRequest{RoomID: "bad", UserAgent: "ua"}); sig != "fallback" {
+			t.Fatalf("sig = %s", sig)
+		}
+	}
+	if fallback.prepares != 1 {
+		t.Fatalf("同一 User-Agent 应只初始化一次: %d", fallback.prepares)
+	}
+}
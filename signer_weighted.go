@@ -0,0 +1,231 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+const (
+	// defaultSignerMinShare 每个签名实现至少分到的流量比例，保证表现差的实现仍有机会被重新评估
+	defaultSignerMinShare = 0.05
+	// maxPendingSignatures 等待握手结果的签名数上限
+	maxPendingSignatures = 1024
+)
+
+// HandshakeReporter 关心握手结果的 Signer，握手结束后会收到本次使用的签名与结果
+type HandshakeReporter interface {
+	ReportHandshake(signature string, err error)
+}
+
+// SignerArm 参与分流的签名实现
+type SignerArm struct {
+	Name   string
+	Signer Signer
+	Weight float64 // 初始权重，<=0 时为 1
+}
+
+// SignerArmStats 单个签名实现的分流统计
+type SignerArmStats struct {
+	Name        string  `json:"name"`
+	Weight      float64 `json:"weight"`
+	Signed      uint64  `json:"signed"`      // 签名次数
+	SignErrors  uint64  `json:"sign_errors"` // 签名失败次数
+	Handshakes  uint64  `json:"handshakes"`  // 收到结果的握手次数
+	Successes   uint64  `json:"successes"`   // 握手成功次数
+	SuccessRate float64 `json:"success_rate"`
+	Share       float64 `json:"share"` // 当前分到的流量比例
+}
+
+// weightedArm 分流状态
+type weightedArm struct {
+	SignerArm
+	prepared   string // 已初始化的 User-Agent
+	signed     uint64
+	signErrors uint64
+	handshakes uint64
+	successes  uint64
+}
+
+// score 平滑后的成功率，签名失败计为握手失败
+func (a *weightedArm) score() float64 {
+	return float64(a.successes+1) / float64(a.handshakes+a.signErrors+2)
+}
+
+// WeightedSigner 按权重在多个签名实现间分流，并按握手成功率自动偏向表现好的实现，
+// 适合签名实现更新时灰度。实际权重 = 初始权重 × 平滑成功率，每个实现至少分到 MinShare 的流量。
+// 选中的实现签名失败时依次尝试其余实现
+type WeightedSigner struct {
+	MinShare float64 // 默认 0.05
+
+	mu      sync.Mutex
+	arms    []*weightedArm
+	pending map[string]*weightedArm // 签名 → 产生它的实现，等待握手结果
+	rnd     *rand.Rand
+}
+
+// NewWeightedSigner 创建按权重分流的签名实现
+func NewWeightedSigner(arms ...SignerArm) *WeightedSigner {
+	w := &WeightedSigner{
+		MinShare: defaultSignerMinShare,
+		pending:  make(map[string]*weightedArm),
+		rnd:      rand.New(rand.NewSource(rand.Int63())),
+	}
+	for i, arm := range arms {
+		if arm.Weight <= 0 {
+			arm.Weight = 1
+		}
+		if arm.Name == "" {
+			arm.Name = fmt.Sprintf("signer-%d", i)
+		}
+		w.arms = append(w.arms, &weightedArm{SignerArm: arm})
+	}
+	return w
+}
+
+// Sign 实现 Signer
+func (w *WeightedSigner) Sign(ctx context.Context, req SignRequest) (string, error) {
+	w.mu.Lock()
+	order := w.pickOrder()
+	w.mu.Unlock()
+	if len(order) == 0 {
+		return "", errors.New("没有可用的签名实现")
+	}
+
+	var errs []error
+	for _, arm := range order {
+		signature, err := w.signWith(ctx, arm, req)
+		w.mu.Lock()
+		arm.signed++
+		if err != nil {
+			arm.signErrors++
+			w.mu.Unlock()
+			errs = append(errs, fmt.Errorf("%s: %w", arm.Name, err))
+			continue
+		}
+		if len(w.pending) >= maxPendingSignatures {
+			// 调用方没有回报握手结果，丢弃旧记录
+			w.pending = make(map[string]*weightedArm)
+		}
+		w.pending[signature] = arm
+		w.mu.Unlock()
+		return signature, nil
+	}
+	return "", errors.Join(errs...)
+}
+
+// signWith 使用单个实现签名，需要时先按 User-Agent 初始化
+func (w *WeightedSigner) signWith(ctx context.Context, arm *weightedArm, req SignRequest) (string, error) {
+	if p, ok := arm.Signer.(signerPreparer); ok {
+		w.mu.Lock()
+		prepared := arm.prepared == req.UserAgent
+		w.mu.Unlock()
+		if !prepared {
+			if err := p.Prepare(req.UserAgent); err != nil {
+				return "", err
+			}
+			w.mu.Lock()
+			arm.prepared = req.UserAgent
+			w.mu.Unlock()
+		}
+	}
+	return arm.Signer.Sign(ctx, req)
+}
+
+// pickOrder 按实际权重抽取首选实现，其余按权重从高到低排在后面作为备选
+func (w *WeightedSigner) pickOrder() []*weightedArm {
+	shares := w.shares()
+	if len(shares) == 0 {
+		return nil
+	}
+	first := len(shares) - 1
+	r := w.rnd.Float64()
+	for i, share := range shares {
+		if r < share {
+			first = i
+			break
+		}
+		r -= share
+	}
+	order := []*weightedArm{w.arms[first]}
+	rest := make([]int, 0, len(shares)-1)
+	for i := range shares {
+		if i != first {
+			rest = append(rest, i)
+		}
+	}
+	for len(rest) > 0 {
+		best := 0
+		for j := range rest {
+			if shares[rest[j]] > shares[rest[best]] {
+				best = j
+			}
+		}
+		order = append(order, w.arms[rest[best]])
+		rest = append(rest[:best], rest[best+1:]...)
+	}
+	return order
+}
+
+// shares 返回各实现当前分到的流量比例
+func (w *WeightedSigner) shares() []float64 {
+	n := len(w.arms)
+	if n == 0 {
+		return nil
+	}
+	minShare := w.MinShare
+	if minShare < 0 || minShare*float64(n) > 1 {
+		minShare = 1 / float64(n)
+	}
+	var total float64
+	raw := make([]float64, n)
+	for i, arm := range w.arms {
+		raw[i] = arm.Weight * arm.score()
+		total += raw[i]
+	}
+	shares := make([]float64, n)
+	for i := range raw {
+		shares[i] = minShare + (1-minShare*float64(n))*raw[i]/total
+	}
+	return shares
+}
+
+// ReportHandshake 实现 HandshakeReporter
+func (w *WeightedSigner) ReportHandshake(signature string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	arm, ok := w.pending[signature]
+	if !ok {
+		return
+	}
+	delete(w.pending, signature)
+	arm.handshakes++
+	if err == nil {
+		arm.successes++
+	}
+}
+
+// Stats 返回各实现的分流统计
+func (w *WeightedSigner) Stats() []SignerArmStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	shares := w.shares()
+	out := make([]SignerArmStats, len(w.arms))
+	for i, arm := range w.arms {
+		out[i] = SignerArmStats{
+			Name:       arm.Name,
+			Weight:     arm.Weight,
+			Signed:     arm.signed,
+			SignErrors: arm.signErrors,
+			Handshakes: arm.handshakes,
+			Successes:  arm.successes,
+			Share:      shares[i],
+		}
+		if arm.handshakes > 0 {
+			out[i].SuccessRate = float64(arm.successes) / float64(arm.handshakes)
+		}
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWeightedSigner(t *testing.T) {
+	fixed := func(prefix string) Signer {
+		n := 0
+		return SignerFunc(func(context.Context, SignRequest) (string, error) {
+			n++
+			return prefix + strings.Repeat("x", n), nil
+		})
+	}
+	w := NewWeightedSigner(
+		SignerArm{Name: "good", Signer: fixed("good"), Weight: 1},
+		SignerArm{Name: "bad", Signer: fixed("bad"), Weight: 1},
+	)
+	rejected := errors.New("403")
+	for i := 0; i < 300; i++ {
+		sig, err := w.Sign(context.Background(), SignRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(sig, "bad") {
+			w.ReportHandshake(sig, rejected)
+		} else {
+			w.ReportHandshake(sig, nil)
+		}
+	}
+	stats := w.Stats()
+	if stats[0].SuccessRate != 1 || stats[1].SuccessRate != 0 {
+		t.Fatalf("成功率统计错误: %+v", stats)
+	}
+	if stats[1].Share > 0.1 || stats[1].Signed == 0 {
+		t.Fatalf("应偏向表现好的实现，同时保留少量流量: %+v", stats)
+	}
+
+	// 选中的实现签名失败时改用其余实现
+	failing := NewWeightedSigner(
+		SignerArm{Name: "broken", Signer: SignerFunc(func(context.Context, SignRequest) (string, error) { return "", errors.New("boom") }), Weight: 1000},
+		SignerArm{Name: "ok", Signer: fixed("ok")},
+	)
+	if sig, err := failing.Sign(context.Background(), SignRequest{}); err != nil || !strings.HasPrefix(sig, "ok") {
+		t.Fatalf("sig = %s, err = %v", sig, err)
+	}
+}
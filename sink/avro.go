@@ -0,0 +1,143 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/registry"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// LiveEventSchema 直播事件的 Avro schema，消息的完整内容与扩展字段分别以 JSON 字符串保存在 data、ext 字段中，
+// time 为 UTC 时间戳，time_local 为实例时区下的时间
+const LiveEventSchema = `{
+	"type": "record",
+	"name": "LiveEvent",
+	"namespace": "com.douyinlive",
+	"fields": [
+		{"name": "time", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "room_id", "type": "string"},
+		{"name": "live_name", "type": "string"},
+		{"name": "method", "type": "string"},
+		{"name": "msg_id", "type": "long"},
+		{"name": "user_id", "type": ["null", "long"], "default": null},
+		{"name": "nickname", "type": ["null", "string"], "default": null},
+		{"name": "content", "type": ["null", "string"], "default": null},
+		{"name": "data", "type": ["null", "string"], "default": null},
+		{"name": "ext", "type": ["null", "string"], "default": null},
+		{"name": "time_local", "type": ["null", "string"], "default": null}
+	]
+}`
+
+// avroEvent 与 LiveEventSchema 对应的记录
+type avroEvent struct {
+	Time      int64   `avro:"time"`
+	RoomID    string  `avro:"room_id"`
+	LiveName  string  `avro:"live_name"`
+	Method    string  `avro:"method"`
+	MsgID     int64   `avro:"msg_id"`
+	UserID    *int64  `avro:"user_id"`
+	Nickname  *string `avro:"nickname"`
+	Content   *string `avro:"content"`
+	Data      *string `avro:"data"`
+	Ext       *string `avro:"ext"`
+	TimeLocal *string `avro:"time_local"` // 实例时区下的 RFC 3339 时间
+}
+
+// AvroEncoder 将事件编码为 Avro 二进制。
+// 通过 Schema Registry 创建时输出 Confluent 线格式：0x00 + 4 字节 schema ID + Avro 数据
+type AvroEncoder struct {
+	schema   avro.Schema
+	schemaID int
+	header   []byte
+}
+
+// NewAvroEncoder 创建不带 Schema Registry 的编码器，输出纯 Avro 二进制
+func NewAvroEncoder() (*AvroEncoder, error) {
+	schema, err := avro.Parse(LiveEventSchema)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Avro schema 失败: %w", err)
+	}
+	return &AvroEncoder{schema: schema}, nil
+}
+
+// NewRegistryAvroEncoder 在 Schema Registry 中注册 LiveEventSchema 并创建编码器，
+// subject 通常为 "<topic>-value"
+func NewRegistryAvroEncoder(ctx context.Context, registryURL, subject string, opts ...registry.ClientFunc) (*AvroEncoder, error) {
+	client, err := registry.NewClient(registryURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Schema Registry 客户端失败: %w", err)
+	}
+	id, schema, err := client.CreateSchema(ctx, subject, LiveEventSchema)
+	if err != nil {
+		return nil, fmt.Errorf("注册 Avro schema 失败 (subject: %s): %w", subject, err)
+	}
+	header := make([]byte, 5)
+	binary.BigEndian.PutUint32(header[1:], uint32(id))
+	return &AvroEncoder{schema: schema, schemaID: id, header: header}, nil
+}
+
+// SchemaID 返回 Schema Registry 分配的 ID，未使用 Registry 时为 0
+func (e *AvroEncoder) SchemaID() int {
+	return e.schemaID
+}
+
+// Encode 编码单条事件
+func (e *AvroEncoder) Encode(event *douyinLive.LiveEvent) ([]byte, error) {
+	fields := event.Fields()
+	record := avroEvent{
+		Time:     event.Time.UnixMilli(),
+		RoomID:   event.RoomID,
+		LiveName: event.LiveName,
+		Method:   event.Method,
+		MsgID:    int64(event.MsgID),
+		Nickname: stringField(fields, douyinLive.FieldNickname),
+		Content:  stringField(fields, douyinLive.FieldContent),
+	}
+	local := event.LocalTime().Format(time.RFC3339Nano)
+	record.TimeLocal = &local
+	if s := stringField(fields, douyinLive.FieldUserID); s != nil {
+		if id, err := strconv.ParseInt(*s, 10, 64); err == nil {
+			record.UserID = &id
+		}
+	}
+	if data, ok := fields[douyinLive.FieldData]; ok {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		s := string(b)
+		record.Data = &s
+	}
+	if len(event.Ext) > 0 {
+		b, err := json.Marshal(event.Ext)
+		if err != nil {
+			return nil, err
+		}
+		s := string(b)
+		record.Ext = &s
+	}
+
+	body, err := avro.Marshal(e.schema, record)
+	if err != nil {
+		return nil, err
+	}
+	if e.header == nil {
+		return body, nil
+	}
+	return append(append(make([]byte, 0, len(e.header)+len(body)), e.header...), body...), nil
+}
+
+// stringField 取出字符串字段，不存在时返回 nil
+func stringField(fields map[string]interface{}, key string) *string {
+	if s, ok := fields[key].(string); ok {
+		return &s
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestRegistryAvroEncoder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/subjects/douyin-value/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id":7}`))
+	}))
+	defer srv.Close()
+
+	enc, err := NewRegistryAvroEncoder(context.Background(), srv.URL, "douyin-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _ := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{
+		User:    &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"},
+		Content: "你好",
+	})
+	event := douyinLive.NewLiveEvent("1", "test", &new_douyin.Webcast_Im_Message{
+		Method:  douyinLive.WebcastChatMessage,
+		MsgId:   9,
+		Payload: payload,
+	})
+	b, err := enc.Encode(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[0] != 0 || binary.BigEndian.Uint32(b[1:5]) != 7 {
+		t.Fatalf("线格式头错误: % x", b[:5])
+	}
+
+	var got avroEvent
+	if err := avro.Unmarshal(avro.MustParse(LiveEventSchema), b[5:], &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.MsgID != 9 || got.UserID == nil || *got.UserID != 42 || got.Content == nil || *got.Content != "你好" {
+		t.Fatalf("解码结果错误: %+v", got)
+	}
+}
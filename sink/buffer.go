@@ -0,0 +1,161 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// ErrClosed Sink 已关闭
+var ErrClosed = errors.New("sink 已关闭")
+
+// BufferOptions 缓冲 Sink 的配置
+type BufferOptions struct {
+	Size          int           // 缓冲队列容量，默认 1024
+	BatchSize     int           // 单次写入的最大事件数，默认 100
+	FlushInterval time.Duration // 未凑满一批时的最长等待时间，默认 1 秒
+	OnError       func(error)   // 后台写入失败时的回调
+}
+
+// Buffer 在后台按批写入下游 Sink 的缓冲层
+type Buffer struct {
+	sink    Sink
+	opts    BufferOptions
+	events  chan *douyinLive.LiveEvent
+	pending atomic.Int64 // 已入队但未写入完成的事件数
+	closed  atomic.Bool
+	abort   chan struct{}
+	done    chan struct{}
+	mu      sync.RWMutex // 保证 close(events) 时没有并发写入
+}
+
+// NewBuffer 创建缓冲 Sink 并启动后台写入协程
+func NewBuffer(s Sink, opts BufferOptions) *Buffer {
+	if opts.Size <= 0 {
+		opts.Size = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	b := &Buffer{
+		sink:   s,
+		opts:   opts,
+		events: make(chan *douyinLive.LiveEvent, opts.Size),
+		abort:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Write 将事件放入缓冲队列，队列满时等待直到 ctx 结束
+func (b *Buffer) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed.Load() {
+		return ErrClosed
+	}
+	for _, event := range events {
+		select {
+		case b.events <- event:
+			b.pending.Add(1)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Pending 返回尚未写入下游的事件数
+func (b *Buffer) Pending() int {
+	return int(b.pending.Load())
+}
+
+// Close 写完缓冲中的全部事件后关闭下游 Sink
+func (b *Buffer) Close() error {
+	return b.CloseContext(context.Background())
+}
+
+// CloseContext 在 ctx 结束前尽量写完缓冲中的事件，超时后放弃剩余事件
+func (b *Buffer) CloseContext(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed.Swap(true) {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	close(b.events)
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		// 下游可能无视 ctx 一直阻塞，这里不再等待，由后台协程收尾
+		close(b.abort)
+		go func() {
+			<-b.done
+			b.sink.Close()
+		}()
+		return fmt.Errorf("%w: 丢弃 %d 条事件", ctx.Err(), b.Pending())
+	}
+	return b.sink.Close()
+}
+
+// run 后台按批写入
+func (b *Buffer) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*douyinLive.LiveEvent, 0, b.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-b.abort:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		err := b.sink.Write(ctx, batch)
+		aborted := ctx.Err() != nil
+		cancel()
+		if err != nil && aborted {
+			// 被中止的批次计入丢弃量，保留在 pending 中
+			return
+		}
+		if err != nil && b.opts.OnError != nil {
+			b.opts.OnError(err)
+		}
+		b.pending.Add(-int64(len(batch)))
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-b.abort:
+			return
+		case event, ok := <-b.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= b.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
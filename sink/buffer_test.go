@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// countingSink 记录写入条数，delay 用于模拟写入缓慢的下游
+type countingSink struct {
+	mu      sync.Mutex
+	written int
+	delay   time.Duration
+}
+
+func (s *countingSink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.mu.Lock()
+	s.written += len(events)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *countingSink) Close() error { return nil }
+
+func testEvents(n int) []*douyinLive.LiveEvent {
+	events := make([]*douyinLive.LiveEvent, n)
+	for i := range events {
+		events[i] = douyinLive.NewLiveEvent("1", "test", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage})
+	}
+	return events
+}
+
+func TestGroupCloseReportsFlush(t *testing.T) {
+	fast := &countingSink{}
+	slow := &countingSink{delay: time.Hour}
+
+	g := NewGroup()
+	g.Add("fast", NewBuffer(fast, BufferOptions{BatchSize: 10, FlushInterval: time.Hour}))
+	g.Add("slow", NewBuffer(slow, BufferOptions{BatchSize: 10, FlushInterval: time.Hour}))
+	if err := g.Write(context.Background(), testEvents(25)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	reports := g.CloseContext(ctx)
+
+	if r := reports[0]; r.TimedOut || r.Err != nil || fast.written != 25 {
+		t.Fatalf("fast: report=%v written=%d", r, fast.written)
+	}
+	if r := reports[1]; !r.TimedOut || r.Pending != 25 || r.Dropped != 25 {
+		t.Fatalf("slow: report=%v", r)
+	}
+	t.Log(reports[0].String())
+	t.Log(reports[1].String())
+}
@@ -0,0 +1,156 @@
+// Package clickhouse 提供通过 HTTP 接口将直播事件批量写入 ClickHouse 的 Sink
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("clickhouse")
+}
+
+// defaultTable 默认表名
+const defaultTable = "douyin_events"
+
+// schemaTemplate 事件表，%s 为表名，按房间与时间排序并按月分区
+const schemaTemplate = `CREATE TABLE IF NOT EXISTS %s (
+	ts            DateTime64(3, 'UTC'),
+	room_id       String,
+	live_name     String,
+	method        LowCardinality(String),
+	msg_id        Int64,
+	user_id       Nullable(Int64),
+	nickname      Nullable(String),
+	content       Nullable(String),
+	gift_id       Nullable(Int64),
+	gift_name     Nullable(String),
+	group_id      Nullable(Int64),
+	group_count   Nullable(Int64),
+	repeat_count  Nullable(Int64),
+	repeat_end    Nullable(Bool),
+	diamond_count Nullable(Int64),
+	like_count    Nullable(Int64),
+	data          Nullable(String),
+	ext           Nullable(String)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(ts)
+ORDER BY (room_id, ts)`
+
+// Options ClickHouse Sink 配置
+type Options struct {
+	URL         string // HTTP 接口地址，如 http://localhost:8123
+	Database    string // 数据库，默认使用服务端的默认库
+	Table       string // 表名，默认 douyin_events
+	Username    string
+	Password    string
+	Methods     []string // 持久化的消息类型，为空时使用 sink.DefaultMethods
+	StoreData   bool     // 是否在 data 列保存完整的消息体 JSON
+	CreateTable bool     // 表不存在时自动创建
+	Client      *http.Client
+}
+
+// Sink 以 JSONEachRow 格式批量写入 ClickHouse，建议配合 sink.NewBuffer 攒批
+type Sink struct {
+	opts    Options
+	methods sink.MethodFilter
+	client  *http.Client
+}
+
+// New 创建 ClickHouse Sink
+func New(ctx context.Context, opts Options) (*Sink, error) {
+	if opts.Table == "" {
+		opts.Table = defaultTable
+	}
+	s := &Sink{opts: opts, methods: sink.NewMethodFilter(opts.Methods), client: opts.Client}
+	if s.client == nil {
+		s.client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if opts.CreateTable {
+		if err := s.exec(ctx, fmt.Sprintf(schemaTemplate, s.table()), nil); err != nil {
+			return nil, fmt.Errorf("创建表 %s 失败: %w", opts.Table, err)
+		}
+	}
+	return s, nil
+}
+
+// Write 写入一批事件，不在 Methods 中的事件被忽略
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	n := 0
+	for _, event := range events {
+		if !s.methods[event.Method] {
+			continue
+		}
+		r, err := sink.NewRecord(event, s.opts.StoreData)
+		if err != nil {
+			return err
+		}
+		row := make(map[string]any, len(sink.Columns))
+		for i, v := range r.Values() {
+			row[sink.Columns[i]] = v
+		}
+		row["ts"] = r.Time.UTC().Format("2006-01-02 15:04:05.000")
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return s.exec(ctx, fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", s.table()), &body)
+}
+
+// Close 实现 Sink 接口，HTTP 写入没有需要释放的资源
+func (s *Sink) Close() error {
+	return nil
+}
+
+// table 返回带库名的表名
+func (s *Sink) table() string {
+	if s.opts.Database != "" {
+		return "`" + s.opts.Database + "`.`" + s.opts.Table + "`"
+	}
+	return "`" + s.opts.Table + "`"
+}
+
+// exec 通过 HTTP 接口执行语句，body 为 INSERT 的数据
+func (s *Sink) exec(ctx context.Context, query string, body io.Reader) error {
+	u, err := url.Parse(s.opts.URL)
+	if err != nil {
+		return err
+	}
+	params := u.Query()
+	params.Set("query", query)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if s.opts.Username != "" {
+		req.SetBasicAuth(s.opts.Username, s.opts.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 ClickHouse 失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ClickHouse 返回 %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,51 @@
+package clickhouse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestSinkWrite(t *testing.T) {
+	var queries []string
+	var rows []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var row map[string]any
+			json.Unmarshal(scanner.Bytes(), &row)
+			rows = append(rows, row)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := New(context.Background(), Options{URL: srv.URL, Database: "live", CreateTable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{
+		User:    &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"},
+		Content: "你好",
+	})
+	chat := douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage, Payload: payload})
+	other := douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastRoomUserSeqMessage})
+	if err := s.Write(context.Background(), []*douyinLive.LiveEvent{chat, other}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queries) != 2 || queries[1] != "INSERT INTO `live`.`douyin_events` FORMAT JSONEachRow" {
+		t.Fatalf("语句错误: %q", queries)
+	}
+	if len(rows) != 1 || rows[0]["content"] != "你好" || rows[0]["user_id"] != float64(42) || rows[0]["gift_id"] != nil {
+		t.Fatalf("写入的行错误: %+v", rows)
+	}
+}
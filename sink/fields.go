@@ -0,0 +1,50 @@
+package sink
+
+import (
+	"strings"
+)
+
+// Fields 输出字段白名单，为空时输出全部字段
+// 支持 data.gift.name 形式的点分路径选取消息体中的嵌套字段，输出时以完整路径作为键
+type Fields []string
+
+// ParseFields 解析逗号分隔的字段列表，如 "time,user_id,content"
+func ParseFields(s string) Fields {
+	var fields Fields
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Select 按白名单选取字段，不存在的字段会被忽略
+func (f Fields) Select(all map[string]interface{}) map[string]interface{} {
+	if len(f) == 0 {
+		return all
+	}
+	selected := make(map[string]interface{}, len(f))
+	for _, name := range f {
+		if v, ok := lookup(all, name); ok {
+			selected[name] = v
+		}
+	}
+	return selected
+}
+
+// lookup 按点分路径查找嵌套字段
+func lookup(m map[string]interface{}, path string) (interface{}, bool) {
+	if v, ok := m[path]; ok {
+		return v, true
+	}
+	head, rest, found := strings.Cut(path, ".")
+	if !found {
+		return nil, false
+	}
+	child, ok := m[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(child, rest)
+}
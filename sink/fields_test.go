@@ -0,0 +1,32 @@
+package sink
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsSelect(t *testing.T) {
+	all := map[string]interface{}{
+		"time":    "2024-06-01T12:00:00Z",
+		"user_id": "123",
+		"content": "你好",
+		"data": map[string]interface{}{
+			"gift": map[string]interface{}{"name": "小心心"},
+		},
+	}
+
+	got := ParseFields("time, user_id,content,data.gift.name,missing").Select(all)
+	want := map[string]interface{}{
+		"time":           "2024-06-01T12:00:00Z",
+		"user_id":        "123",
+		"content":        "你好",
+		"data.gift.name": "小心心",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Select() = %v, want %v", got, want)
+	}
+
+	if got := Fields(nil).Select(all); !reflect.DeepEqual(got, all) {
+		t.Fatalf("空白名单应返回全部字段, got %v", got)
+	}
+}
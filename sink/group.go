@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// Pender 可以报告缓冲中剩余事件数的 Sink
+type Pender interface {
+	Pending() int
+}
+
+// ContextCloser 支持在超时前尽量写完缓冲的 Sink
+type ContextCloser interface {
+	CloseContext(ctx context.Context) error
+}
+
+// FlushReport 单个 Sink 关闭时的 flush 汇报
+type FlushReport struct {
+	Name     string
+	Pending  int           // 关闭前缓冲中剩余的事件数
+	Dropped  int           // 超时后被丢弃的事件数
+	Duration time.Duration // flush 与关闭耗时
+	TimedOut bool
+	Err      error
+}
+
+// String 输出 "剩余条数→flush 结果→耗时" 形式的汇报
+func (r FlushReport) String() string {
+	result := "成功"
+	switch {
+	case r.TimedOut:
+		result = fmt.Sprintf("超时，丢弃 %d 条", r.Dropped)
+	case r.Err != nil:
+		result = fmt.Sprintf("失败: %v", r.Err)
+	}
+	return fmt.Sprintf("[%s] 缓冲剩余 %d 条 → flush %s → 耗时 %s", r.Name, r.Pending, result, r.Duration.Round(time.Millisecond))
+}
+
+// Group 统一管理多个具名 Sink，写入时广播到全部 Sink
+type Group struct {
+	mu    sync.RWMutex
+	names []string
+	sinks map[string]Sink
+}
+
+// NewGroup 创建 Sink 组
+func NewGroup() *Group {
+	return &Group{sinks: make(map[string]Sink)}
+}
+
+// Add 添加具名 Sink，同名会覆盖
+func (g *Group) Add(name string, s Sink) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.sinks[name]; !ok {
+		g.names = append(g.names, name)
+	}
+	g.sinks[name] = s
+}
+
+// Write 写入全部 Sink，返回合并后的错误
+func (g *Group) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var errs []error
+	for _, name := range g.names {
+		if err := g.sinks[name].Write(ctx, events); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 关闭全部 Sink，实现 Sink 接口
+func (g *Group) Close() error {
+	var errs []error
+	for _, report := range g.CloseContext(context.Background()) {
+		if report.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", report.Name, report.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseContext 并发关闭全部 Sink 并返回每个 Sink 的 flush 汇报，
+// ctx 结束时仍未完成的 Sink 会被标记为超时，并记录丢弃的事件数
+func (g *Group) CloseContext(ctx context.Context) []FlushReport {
+	g.mu.RLock()
+	names := append([]string(nil), g.names...)
+	sinks := make([]Sink, len(names))
+	for i, name := range names {
+		sinks[i] = g.sinks[name]
+	}
+	g.mu.RUnlock()
+
+	reports := make([]FlushReport, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i] = closeSink(ctx, names[i], sinks[i])
+		}(i)
+	}
+	wg.Wait()
+	return reports
+}
+
+// closeSink 关闭单个 Sink 并生成汇报
+func closeSink(ctx context.Context, name string, s Sink) FlushReport {
+	report := FlushReport{Name: name}
+	pender, hasPending := s.(Pender)
+	if hasPending {
+		report.Pending = pender.Pending()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		if c, ok := s.(ContextCloser); ok {
+			done <- c.CloseContext(ctx)
+			return
+		}
+		done <- s.Close()
+	}()
+
+	select {
+	case report.Err = <-done:
+	case <-ctx.Done():
+		report.Err = ctx.Err()
+	}
+	report.Duration = time.Since(start)
+
+	if ctx.Err() != nil && errors.Is(report.Err, ctx.Err()) {
+		report.TimedOut = true
+		if hasPending {
+			report.Dropped = pender.Pending()
+		}
+	}
+	return report
+}
@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// JSONEncoder 将事件编码为 JSON 对象
+type JSONEncoder struct {
+	Fields Fields // 输出字段白名单
+}
+
+// Encode 编码单条事件
+func (e JSONEncoder) Encode(event *douyinLive.LiveEvent) ([]byte, error) {
+	return json.Marshal(e.Fields.Select(event.Fields()))
+}
+
+// WriterSink 以 JSON Lines 格式将事件写入 io.Writer
+type WriterSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	encoder JSONEncoder
+}
+
+// NewWriterSink 创建 WriterSink，fields 为空时输出全部字段
+func NewWriterSink(w io.Writer, fields Fields) *WriterSink {
+	return &WriterSink{
+		w:       w,
+		encoder: JSONEncoder{Fields: fields},
+	}
+}
+
+// Write 逐行写入事件
+func (s *WriterSink) Write(_ context.Context, events []*douyinLive.LiveEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		line, err := s.encoder.Encode(event)
+		if err != nil {
+			return err
+		}
+		if _, err := s.w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 关闭底层 Writer（如果实现了 io.Closer）
+func (s *WriterSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
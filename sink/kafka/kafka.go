@@ -0,0 +1,140 @@
+// Package kafka 将直播事件发布到 Kafka。
+//
+// Connect 使用内置的 kafka-go 客户端，NewWriter 可传入自行配置的 kafka-go Writer（SASL、TLS 等），
+// 也可以实现 Producer 接口改用 sarama、franz-go 等客户端
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("kafka")
+}
+
+// defaultTopic 未配置 topic 时使用的默认值
+const defaultTopic = "douyin_events"
+
+// Message 发往 Kafka 的一条消息
+type Message struct {
+	Topic string
+	Key   []byte // 房间 roomID，保证同一房间的事件落在同一分区
+	Value []byte
+	Time  time.Time
+}
+
+// Producer Kafka 客户端适配接口
+type Producer interface {
+	// Produce 同步发送一批消息，部分失败时返回错误
+	Produce(ctx context.Context, messages []Message) error
+	// Close 刷新并关闭客户端
+	Close() error
+}
+
+// DeliveryError 投递失败的消息及原因
+type DeliveryError struct {
+	Messages []Message
+	Err      error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("投递 %d 条消息到 Kafka 失败: %v", len(e.Messages), e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// Options Kafka Sink 配置
+type Options struct {
+	Topic         string            // 默认 topic，默认 douyin_events
+	Topics        map[string]string // 按消息类型覆盖 topic，如 WebcastGiftMessage → douyin_gifts
+	Encoder       sink.Encoder      // 消息体编码，默认 sink.JSONEncoder{}，可换成 sink.AvroEncoder
+	QueueSize     int               // 异步队列容量，默认 1024
+	BatchSize     int               // 单次发送的最大消息数，默认 100
+	FlushInterval time.Duration     // 未凑满一批时的最长等待时间，默认 1 秒
+	OnError       func(*DeliveryError)
+}
+
+// Sink 异步攒批发布事件，Write 只负责入队
+type Sink struct {
+	*sink.Buffer
+}
+
+// New 创建 Kafka Sink，Close 时发送剩余消息并关闭 Producer
+func New(p Producer, opts Options) *Sink {
+	if opts.Topic == "" {
+		opts.Topic = defaultTopic
+	}
+	if opts.Encoder == nil {
+		opts.Encoder = sink.JSONEncoder{}
+	}
+	var onError func(error)
+	if opts.OnError != nil {
+		onError = func(err error) {
+			var de *DeliveryError
+			if !errors.As(err, &de) {
+				de = &DeliveryError{Err: err}
+			}
+			opts.OnError(de)
+		}
+	}
+	return &Sink{
+		Buffer: sink.NewBuffer(&publisher{producer: p, opts: opts}, sink.BufferOptions{
+			Size:          opts.QueueSize,
+			BatchSize:     opts.BatchSize,
+			FlushInterval: opts.FlushInterval,
+			OnError:       onError,
+		}),
+	}
+}
+
+// publisher 编码并同步发送一批事件
+type publisher struct {
+	producer Producer
+	opts     Options
+}
+
+// Write 编码并发送，编码失败的事件跳过并随投递错误一并返回
+func (p *publisher) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	messages := make([]Message, 0, len(events))
+	var encodeErrs []error
+	for _, event := range events {
+		value, err := p.opts.Encoder.Encode(event)
+		if err != nil {
+			encodeErrs = append(encodeErrs, fmt.Errorf("编码事件失败 (msg_id: %d): %w", event.MsgID, err))
+			continue
+		}
+		messages = append(messages, Message{
+			Topic: p.topic(event.Method),
+			Key:   []byte(event.RoomID),
+			Value: value,
+			Time:  event.Time,
+		})
+	}
+	if len(messages) > 0 {
+		if err := p.producer.Produce(ctx, messages); err != nil {
+			return &DeliveryError{Messages: messages, Err: err}
+		}
+	}
+	return errors.Join(encodeErrs...)
+}
+
+// Close 关闭 Producer
+func (p *publisher) Close() error {
+	return p.producer.Close()
+}
+
+// topic 返回消息类型对应的 topic
+func (p *publisher) topic(method string) string {
+	if topic, ok := p.opts.Topics[method]; ok {
+		return topic
+	}
+	return p.opts.Topic
+}
@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// fakeProducer 记录发送的消息，fail 为 true 时返回错误
+type fakeProducer struct {
+	mu       sync.Mutex
+	messages []Message
+	fail     bool
+	closed   bool
+}
+
+func (p *fakeProducer) Produce(_ context.Context, messages []Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail {
+		return errors.New("broker 不可用")
+	}
+	p.messages = append(p.messages, messages...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+func events(methods ...string) []*douyinLive.LiveEvent {
+	out := make([]*douyinLive.LiveEvent, len(methods))
+	for i, method := range methods {
+		out[i] = douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: method})
+	}
+	return out
+}
+
+func TestSinkRoutesTopics(t *testing.T) {
+	p := &fakeProducer{}
+	s := New(p, Options{
+		Topics:        map[string]string{douyinLive.WebcastGiftMessage: "gifts"},
+		FlushInterval: time.Hour,
+	})
+	s.Write(context.Background(), events(douyinLive.WebcastChatMessage, douyinLive.WebcastGiftMessage))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.closed || len(p.messages) != 2 {
+		t.Fatalf("关闭时应发送全部消息并关闭 Producer: %+v", p.messages)
+	}
+	if p.messages[0].Topic != defaultTopic || p.messages[1].Topic != "gifts" || string(p.messages[1].Key) != "100" {
+		t.Fatalf("topic 或 key 错误: %+v", p.messages)
+	}
+}
+
+func TestSinkDeliveryError(t *testing.T) {
+	var got *DeliveryError
+	s := New(&fakeProducer{fail: true}, Options{
+		FlushInterval: time.Hour,
+		OnError:       func(err *DeliveryError) { got = err },
+	})
+	s.Write(context.Background(), events(douyinLive.WebcastChatMessage))
+	s.Close()
+	if got == nil || len(got.Messages) != 1 {
+		t.Fatalf("未收到投递失败回调: %+v", got)
+	}
+}
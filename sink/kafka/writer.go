@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+const (
+	// writerBatchTimeout kafka-go 未凑满一批时的等待时间，Sink 已自行攒批，这里只需很短
+	writerBatchTimeout = 10 * time.Millisecond
+	// defaultBatchSize 与 Options.BatchSize 的默认值一致
+	defaultBatchSize = 100
+)
+
+// Writer 基于 kafka-go 的 Producer
+type Writer struct {
+	w *kafkago.Writer
+}
+
+// NewWriter 使用已有的 kafka-go Writer 创建 Producer，w.Topic 需为空，topic 由 Sink 按消息类型指定
+func NewWriter(w *kafkago.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Connect 连接 brokers（形如 127.0.0.1:9092）并创建 Sink，同一房间的事件按 key 哈希到同一分区，
+// 等待全部副本确认。Close 时发送剩余消息并关闭连接
+func Connect(ctx context.Context, brokers []string, opts Options) (*Sink, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("未配置 Kafka broker")
+	}
+	addr := kafkago.TCP(brokers...)
+	client := &kafkago.Client{Addr: addr}
+	if _, err := client.Metadata(ctx, &kafkago.MetadataRequest{}); err != nil {
+		return nil, fmt.Errorf("连接 Kafka 失败: %w", err)
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return New(NewWriter(&kafkago.Writer{
+		Addr:         addr,
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		BatchSize:    batchSize,
+		BatchTimeout: writerBatchTimeout,
+	}), opts), nil
+}
+
+// Produce 实现 Producer
+func (p *Writer) Produce(ctx context.Context, messages []Message) error {
+	out := make([]kafkago.Message, len(messages))
+	for i, m := range messages {
+		out[i] = kafkago.Message{Topic: m.Topic, Key: m.Key, Value: m.Value, Time: m.Time}
+	}
+	return p.w.WriteMessages(ctx, out...)
+}
+
+// Close 实现 Producer
+func (p *Writer) Close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	metadataAPI "github.com/segmentio/kafka-go/protocol/metadata"
+	produceAPI "github.com/segmentio/kafka-go/protocol/produce"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// fakeTransport 模拟单分区的 broker，记录收到的消息
+type fakeTransport struct {
+	mu       sync.Mutex
+	received map[string][]kafkago.Message // topic → 消息
+}
+
+func (f *fakeTransport) RoundTrip(_ context.Context, _ net.Addr, req kafkago.Request) (kafkago.Response, error) {
+	switch req := req.(type) {
+	case *metadataAPI.Request:
+		res := &metadataAPI.Response{Brokers: []metadataAPI.ResponseBroker{{NodeID: 1, Host: "127.0.0.1", Port: 9092}}}
+		for _, name := range req.TopicNames {
+			res.Topics = append(res.Topics, metadataAPI.ResponseTopic{
+				Name:       name,
+				Partitions: []metadataAPI.ResponsePartition{{PartitionIndex: 0, LeaderID: 1}},
+			})
+		}
+		return res, nil
+	case *produceAPI.Request:
+		res := &produceAPI.Response{}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, topic := range req.Topics {
+			for _, partition := range topic.Partitions {
+				for {
+					record, err := partition.RecordSet.Records.ReadRecord()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						return nil, err
+					}
+					key, _ := protocol.ReadAll(record.Key)
+					value, _ := protocol.ReadAll(record.Value)
+					f.received[topic.Topic] = append(f.received[topic.Topic], kafkago.Message{Key: key, Value: value})
+				}
+			}
+			res.Topics = append(res.Topics, produceAPI.ResponseTopic{
+				Topic:      topic.Topic,
+				Partitions: []produceAPI.ResponsePartition{{Partition: 0}},
+			})
+		}
+		return res, nil
+	}
+	return nil, errors.New("不支持的请求")
+}
+
+func TestWriterProducesToKafka(t *testing.T) {
+	transport := &fakeTransport{received: make(map[string][]kafkago.Message)}
+	s := New(NewWriter(&kafkago.Writer{
+		Addr:         kafkago.TCP("127.0.0.1:9092"),
+		Transport:    transport,
+		Balancer:     &kafkago.Hash{},
+		BatchTimeout: writerBatchTimeout,
+	}), Options{
+		Topics:        map[string]string{douyinLive.WebcastGiftMessage: "gifts"},
+		FlushInterval: time.Hour,
+	})
+	s.Write(context.Background(), events(douyinLive.WebcastChatMessage, douyinLive.WebcastGiftMessage, douyinLive.WebcastChatMessage))
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	if len(transport.received[defaultTopic]) != 2 || len(transport.received["gifts"]) != 1 {
+		t.Fatalf("收到的消息 = %v", transport.received)
+	}
+	gift := transport.received["gifts"][0]
+	if string(gift.Key) != "100" || len(gift.Value) == 0 {
+		t.Fatalf("key 或消息体错误: %q %q", gift.Key, gift.Value)
+	}
+}
+
+func TestConnectRequiresBrokers(t *testing.T) {
+	if _, err := Connect(context.Background(), nil, Options{}); err == nil {
+		t.Fatal("未配置 broker 应返回错误")
+	}
+}
@@ -0,0 +1,131 @@
+// Package mqtt 将直播事件发布到 MQTT Broker，供 LED 屏、硬件提醒盒等物联网设备直接订阅
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("mqtt")
+}
+
+const (
+	// DefaultTopic 默认主题模板
+	DefaultTopic = "douyin/{room_id}/{method}"
+	// disconnectQuiesce 断开前等待未完成工作的毫秒数
+	disconnectQuiesce = 250
+)
+
+// Options MQTT Sink 配置
+type Options struct {
+	// Topic 主题模板，支持 {room_id}、{live_name}、{method}、{type} 占位符，
+	// {type} 为去掉 Webcast 前缀与 Message 后缀的类型名，如 Gift。默认 DefaultTopic
+	Topic    string
+	QoS      byte         // 0、1、2，默认 0
+	Retained bool         // 是否保留消息，设备上线时可立即拿到最后一条
+	Encoder  sink.Encoder // 消息体编码，默认 sink.JSONEncoder{}
+	Methods  []string     // 发布的消息类型，为空时使用 sink.DefaultMethods
+}
+
+// Sink 将事件发布到 MQTT
+type Sink struct {
+	client  paho.Client
+	opts    Options
+	methods sink.MethodFilter
+	owned   bool // Close 时是否断开连接
+}
+
+// Connect 连接 Broker 并创建 Sink，Close 时断开连接，broker 形如 tcp://127.0.0.1:1883
+func Connect(ctx context.Context, broker, clientID string, opts Options) (*Sink, error) {
+	client := paho.NewClient(paho.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetAutoReconnect(true))
+	if err := wait(ctx, client.Connect()); err != nil {
+		return nil, fmt.Errorf("连接 MQTT Broker 失败: %w", err)
+	}
+	s, err := New(client, opts)
+	if err != nil {
+		client.Disconnect(disconnectQuiesce)
+		return nil, err
+	}
+	s.owned = true
+	return s, nil
+}
+
+// New 使用已连接的客户端创建 Sink，Close 时不断开连接
+func New(client paho.Client, opts Options) (*Sink, error) {
+	if opts.Topic == "" {
+		opts.Topic = DefaultTopic
+	}
+	if opts.QoS > 2 {
+		return nil, fmt.Errorf("无效的 QoS: %d", opts.QoS)
+	}
+	if opts.Encoder == nil {
+		opts.Encoder = sink.JSONEncoder{}
+	}
+	return &Sink{client: client, opts: opts, methods: sink.NewMethodFilter(opts.Methods)}, nil
+}
+
+// segmentReplacer 替换主题层级分隔符与通配符，直播间名称等内容不会拆出额外层级或变成通配订阅
+var segmentReplacer = strings.NewReplacer("/", "_", "+", "_", "#", "_", "\x00", "")
+
+// Topic 按模板生成事件的主题，占位符的值中的 /、+、# 替换为 _
+func (s *Sink) Topic(event *douyinLive.LiveEvent) string {
+	typ := strings.TrimSuffix(strings.TrimPrefix(event.Method, "Webcast"), "Message")
+	return strings.NewReplacer(
+		"{room_id}", segmentReplacer.Replace(event.RoomID),
+		"{live_name}", segmentReplacer.Replace(event.LiveName),
+		"{method}", segmentReplacer.Replace(event.Method),
+		"{type}", segmentReplacer.Replace(typ),
+	).Replace(s.opts.Topic)
+}
+
+// Write 发布事件，QoS > 0 时等待 Broker 确认
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	var errs []error
+	tokens := make([]paho.Token, 0, len(events))
+	for _, event := range events {
+		if !s.methods[event.Method] {
+			continue
+		}
+		payload, err := s.opts.Encoder.Encode(event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("编码事件失败 (msg_id: %d): %w", event.MsgID, err))
+			continue
+		}
+		tokens = append(tokens, s.client.Publish(s.Topic(event), s.opts.QoS, s.opts.Retained, payload))
+	}
+	for _, token := range tokens {
+		if err := wait(ctx, token); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 通过 Connect 创建时断开连接
+func (s *Sink) Close() error {
+	if s.owned {
+		s.client.Disconnect(disconnectQuiesce)
+	}
+	return nil
+}
+
+// wait 等待 token 完成或 ctx 结束
+func wait(ctx context.Context, token paho.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,81 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// published 一次 Publish 调用
+type published struct {
+	topic    string
+	qos      byte
+	retained bool
+}
+
+// fakeClient 只实现 Publish，其余方法未调用
+type fakeClient struct {
+	paho.Client
+	messages []published
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, _ interface{}) paho.Token {
+	c.messages = append(c.messages, published{topic, qos, retained})
+	return doneToken{}
+}
+
+// doneToken 立即完成的 token
+type doneToken struct {
+	paho.Token
+}
+
+func (doneToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+
+func (doneToken) Error() error { return nil }
+
+func TestSinkPublish(t *testing.T) {
+	client := &fakeClient{}
+	s, err := New(client, Options{
+		Topic:    "led/{room_id}/{type}",
+		QoS:      1,
+		Retained: true,
+		Methods:  []string{douyinLive.WebcastGiftMessage},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []*douyinLive.LiveEvent{
+		douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastGiftMessage}),
+		douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage}),
+	}
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	want := published{"led/100/Gift", 1, true}
+	if len(client.messages) != 1 || client.messages[0] != want {
+		t.Fatalf("发布 = %+v, want %+v", client.messages, want)
+	}
+
+	if _, err := New(client, Options{QoS: 3}); err == nil {
+		t.Fatal("QoS 3 应报错")
+	}
+}
+
+func TestTopicEscapesSegments(t *testing.T) {
+	s, err := New(&fakeClient{}, Options{Topic: "live/{live_name}/{method}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := douyinLive.NewLiveEvent("100", "a/b+#", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage})
+	if got := s.Topic(event); got != "live/a_b__/WebcastChatMessage" {
+		t.Fatalf("Topic = %q", got)
+	}
+}
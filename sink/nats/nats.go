@@ -0,0 +1,163 @@
+// Package nats 将直播事件发布到 NATS，主题形如 douyin.<roomID>.<method>，
+// 下游可按房间或消息类型订阅（如 douyin.*.WebcastGiftMessage），可选 JetStream 持久化
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("nats")
+}
+
+// defaultPrefix 主题前缀的默认值
+const defaultPrefix = "douyin"
+
+// Options NATS Sink 配置
+type Options struct {
+	Prefix  string       // 主题前缀，默认 douyin
+	Encoder sink.Encoder // 消息体编码，默认 sink.JSONEncoder{}
+
+	JetStream bool          // 发布到 JetStream 并等待服务端确认
+	Stream    string        // JetStream 流名称，非空时创建或更新该流以覆盖 <Prefix>.>
+	MaxAge    time.Duration // 流中消息的保留时间，<=0 时不限制
+}
+
+// Sink 将事件发布到 NATS
+type Sink struct {
+	nc    *nats.Conn
+	js    jetstream.JetStream
+	opts  Options
+	owned bool // Close 时是否关闭连接
+}
+
+// Connect 连接 NATS 服务器并创建 Sink，Close 时关闭连接
+func Connect(ctx context.Context, url string, opts Options, natsOpts ...nats.Option) (*Sink, error) {
+	nc, err := nats.Connect(url, natsOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("连接 NATS 失败: %w", err)
+	}
+	s, err := New(ctx, nc, opts)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	s.owned = true
+	return s, nil
+}
+
+// New 使用已有连接创建 Sink，Close 时只刷新不关闭连接
+func New(ctx context.Context, nc *nats.Conn, opts Options) (*Sink, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = defaultPrefix
+	}
+	if opts.Encoder == nil {
+		opts.Encoder = sink.JSONEncoder{}
+	}
+	s := &Sink{nc: nc, opts: opts}
+	if !opts.JetStream {
+		return s, nil
+	}
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 JetStream 失败: %w", err)
+	}
+	if opts.Stream != "" {
+		_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:     opts.Stream,
+			Subjects: []string{opts.Prefix + ".>"},
+			MaxAge:   opts.MaxAge,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("创建 JetStream 流 %s 失败: %w", opts.Stream, err)
+		}
+	}
+	s.js = js
+	return s, nil
+}
+
+// Subject 返回事件发布的主题
+func (s *Sink) Subject(event *douyinLive.LiveEvent) string {
+	return Subject(s.opts.Prefix, event.RoomID, event.Method)
+}
+
+// Subject 拼接主题，各段中的 NATS 保留字符替换为下划线
+func Subject(prefix, roomID, method string) string {
+	return prefix + "." + token(roomID) + "." + token(method)
+}
+
+// token 主题中的单个段
+func token(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// Write 发布事件，JetStream 模式下等待全部确认后返回
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	var errs []error
+	futures := make([]jetstream.PubAckFuture, 0, len(events))
+	for _, event := range events {
+		data, err := s.opts.Encoder.Encode(event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("编码事件失败 (msg_id: %d): %w", event.MsgID, err))
+			continue
+		}
+		msg := &nats.Msg{Subject: s.Subject(event), Data: data}
+		if s.js == nil {
+			if err := s.nc.PublishMsg(msg); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		var opts []jetstream.PublishOpt
+		if event.MsgID != 0 {
+			// 服务端按 msg_id 去重，重连重放的消息不会重复入流
+			opts = append(opts, jetstream.WithMsgID(strconv.FormatUint(event.MsgID, 10)))
+		}
+		future, err := s.js.PublishMsgAsync(msg, opts...)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		futures = append(futures, future)
+	}
+
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs = append(errs, fmt.Errorf("发布到 %s 失败: %w", future.Msg().Subject, err))
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 刷新未发送的消息，通过 Connect 创建时同时关闭连接
+func (s *Sink) Close() error {
+	if !s.owned {
+		return s.nc.Flush()
+	}
+	return s.nc.Drain()
+}
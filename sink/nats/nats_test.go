@@ -0,0 +1,18 @@
+package nats
+
+import "testing"
+
+func TestSubject(t *testing.T) {
+	tests := []struct {
+		roomID, method, want string
+	}{
+		{"7382620942951772256", "WebcastChatMessage", "douyin.7382620942951772256.WebcastChatMessage"},
+		{"", "WebcastChatMessage", "douyin._.WebcastChatMessage"},
+		{"a.b", "x>*", "douyin.a_b.x__"},
+	}
+	for _, tt := range tests {
+		if got := Subject(defaultPrefix, tt.roomID, tt.method); got != tt.want {
+			t.Errorf("Subject(%q, %q) = %q, want %q", tt.roomID, tt.method, got, tt.want)
+		}
+	}
+}
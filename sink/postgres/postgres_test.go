@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaSQL(t *testing.T) {
+	ddl := schemaSQL("Live Events")
+	for _, want := range []string{
+		`CREATE TABLE IF NOT EXISTS "Live Events" (`,
+		`CREATE INDEX IF NOT EXISTS "Live Events_room_ts_idx" ON "Live Events" (room_id, ts);`,
+		`CREATE INDEX IF NOT EXISTS "Live Events_user_id_idx" ON "Live Events" (user_id);`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Fatalf("建表语句缺少 %s:\n%s", want, ddl)
+		}
+	}
+	if strings.Contains(ddl, `"_`) {
+		t.Fatalf("索引名不应拼接在引号之后:\n%s", ddl)
+	}
+}
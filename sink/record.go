@@ -0,0 +1,148 @@
+package sink
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// Columns 数据库类 Sink 的标准列，顺序与 Record.Values 一致
+var Columns = []string{
+	"ts", "room_id", "live_name", "method", "msg_id",
+	"user_id", "nickname", "content",
+	"gift_id", "gift_name", "group_id", "group_count", "repeat_count", "repeat_end", "diamond_count",
+	"like_count", "data", "ext",
+}
+
+// DefaultMethods 数据库类 Sink 默认持久化的消息类型
+var DefaultMethods = []string{
+	douyinLive.WebcastChatMessage,
+	douyinLive.WebcastGiftMessage,
+	douyinLive.WebcastMemberMessage,
+	douyinLive.WebcastLikeMessage,
+}
+
+// Record 按列展开的事件，各类消息的专有字段为 nil 表示不适用
+type Record struct {
+	Time         time.Time
+	RoomID       string
+	LiveName     string
+	Method       string
+	MsgID        uint64
+	UserID       *uint64
+	Nickname     *string
+	Content      *string // 弹幕内容
+	GiftID       *uint64
+	GiftName     *string
+	GroupID      *uint64 // 连击组 ID
+	GroupCount   *uint64
+	RepeatCount  *uint64 // 连击累计次数
+	RepeatEnd    *bool   // 是否为连击的最后一条消息
+	DiamondCount *int64  // 礼物单价（抖币）
+	LikeCount    *uint64
+	Data         *string // 消息体 JSON
+	Ext          *string // LiveEvent.Ext 的 JSON，没有扩展字段时为 nil
+}
+
+// NewRecord 展开事件，withData 为 true 时在 Data 中保存完整的消息体 JSON，
+// 无法解码的消息只填充公共字段
+func NewRecord(event *douyinLive.LiveEvent, withData bool) (*Record, error) {
+	r := &Record{
+		Time:     event.Time,
+		RoomID:   event.RoomID,
+		LiveName: event.LiveName,
+		Method:   event.Method,
+		MsgID:    event.MsgID,
+	}
+	if len(event.Ext) > 0 {
+		b, err := json.Marshal(event.Ext)
+		if err != nil {
+			return nil, err
+		}
+		s := string(b)
+		r.Ext = &s
+	}
+	msg, err := event.Decode()
+	if err != nil {
+		return r, nil
+	}
+
+	var user *new_douyin.Webcast_Data_User
+	switch m := msg.(type) {
+	case *new_douyin.Webcast_Im_ChatMessage:
+		user = m.User
+		r.Content = &m.Content
+	case *new_douyin.Webcast_Im_GiftMessage:
+		user = m.User
+		end := m.RepeatEnd == 1
+		r.GiftID, r.GroupID, r.GroupCount, r.RepeatCount, r.RepeatEnd = &m.GiftId, &m.GroupId, &m.GroupCount, &m.RepeatCount, &end
+		if m.Gift != nil {
+			diamond := int64(m.Gift.DiamondCount)
+			r.GiftName, r.DiamondCount = &m.Gift.Name, &diamond
+		}
+	case *new_douyin.Webcast_Im_MemberMessage:
+		user = m.User
+	case *new_douyin.Webcast_Im_LikeMessage:
+		user = m.User
+		r.LikeCount = &m.Count
+	}
+	if user != nil {
+		r.UserID, r.Nickname = &user.Id, &user.Nickname
+	}
+
+	if withData {
+		if data, err := event.Data(); err == nil {
+			b, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			s := string(b)
+			r.Data = &s
+		}
+	}
+	return r, nil
+}
+
+// Values 按 Columns 的顺序返回列值，ts 为 time.Time，整数统一为 int64，不适用的列为 nil
+func (r *Record) Values() []any {
+	return []any{
+		r.Time, r.RoomID, r.LiveName, r.Method, int64(r.MsgID),
+		intValue(r.UserID), value(r.Nickname), value(r.Content),
+		intValue(r.GiftID), value(r.GiftName), intValue(r.GroupID), intValue(r.GroupCount), intValue(r.RepeatCount),
+		value(r.RepeatEnd), value(r.DiamondCount),
+		intValue(r.LikeCount), value(r.Data), value(r.Ext),
+	}
+}
+
+// MethodFilter 需要持久化的消息类型集合
+type MethodFilter map[string]bool
+
+// NewMethodFilter 创建消息类型过滤器，methods 为空时使用 DefaultMethods
+func NewMethodFilter(methods []string) MethodFilter {
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+	f := make(MethodFilter, len(methods))
+	for _, method := range methods {
+		f[method] = true
+	}
+	return f
+}
+
+// value 解引用，nil 指针返回 nil
+func value[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// intValue 将 uint64 指针转换为 int64，nil 指针返回 nil
+func intValue(p *uint64) any {
+	if p == nil {
+		return nil
+	}
+	return int64(*p)
+}
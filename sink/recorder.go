@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// RecorderOptions 事件录制配置
+type RecorderOptions struct {
+	RotateOptions
+	Fields  Fields   // 输出字段白名单，为空时输出全部字段
+	Methods []string // 只录制这些消息类型，为空时录制全部
+	Topics  []string // 只录制匹配这些主题的事件，为空时录制全部，见 douyinLive.TopicMatch
+}
+
+// Recorder 将事件以 JSON Lines 格式录制到滚动文件，便于归档后离线分析
+type Recorder struct {
+	writer  *WriterSink
+	methods map[string]bool
+	topics  []string
+}
+
+// NewRecorder 创建录制器，path 为当前写入的文件，滚动后的文件在同一目录
+func NewRecorder(path string, opts RecorderOptions) (*Recorder, error) {
+	file, err := OpenRotatingFile(path, opts.RotateOptions)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{writer: NewWriterSink(file, opts.Fields), topics: opts.Topics}
+	if len(opts.Methods) > 0 {
+		r.methods = make(map[string]bool, len(opts.Methods))
+		for _, method := range opts.Methods {
+			r.methods[method] = true
+		}
+	}
+	return r, nil
+}
+
+// Write 录制一批事件，不在 Methods 中或不匹配 Topics 的事件被忽略
+func (r *Recorder) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	if r.methods != nil || r.topics != nil {
+		filtered := make([]*douyinLive.LiveEvent, 0, len(events))
+		for _, event := range events {
+			if r.methods != nil && !r.methods[event.Method] {
+				continue
+			}
+			if r.topics != nil && !douyinLive.TopicMatchAny(event.Topic(), r.topics) {
+				continue
+			}
+			filtered = append(filtered, event)
+		}
+		events = filtered
+	}
+	return r.writer.Write(ctx, events)
+}
+
+// Close 关闭文件并等待滚动文件压缩完成
+func (r *Recorder) Close() error {
+	return r.writer.Close()
+}
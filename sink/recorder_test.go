@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestRecorderRotatesAndCompresses(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRecorder(filepath.Join(dir, "events.jsonl"), RecorderOptions{
+		RotateOptions: RotateOptions{MaxSize: 200, Compress: true},
+		Fields:        Fields{"method", "msg_id"},
+		Methods:       []string{douyinLive.WebcastChatMessage},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := testEvents(20)
+	events = append(events, douyinLive.NewLiveEvent("1", "test", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastLikeMessage}))
+	if err := r.Write(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, _ := filepath.Glob(filepath.Join(dir, "events-*.jsonl.gz"))
+	if len(rotated) == 0 {
+		t.Fatal("没有生成压缩的滚动文件")
+	}
+	lines := countLines(t, filepath.Join(dir, "events.jsonl"), false)
+	for _, name := range rotated {
+		lines += countLines(t, name, true)
+	}
+	if lines != 20 {
+		t.Fatalf("录制 %d 行, want 20", lines)
+	}
+}
+
+func countLines(t *testing.T, name string, gz bool) int {
+	t.Helper()
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var scanner *bufio.Scanner
+	if gz {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner = bufio.NewScanner(zr)
+	} else {
+		scanner = bufio.NewScanner(f)
+	}
+	n := 0
+	for scanner.Scan() {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,124 @@
+// Package redis 将直播事件写入 Redis：按房间 PUBLISH 到频道，或 XADD 到 Stream，
+// 频道与 Stream 的键为 <Prefix>:<roomID>，适合向 Web 后端做轻量扇出
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("redis")
+}
+
+// defaultPrefix 键前缀的默认值
+const defaultPrefix = "douyin"
+
+// Mode 写入方式
+type Mode int
+
+const (
+	ModePublish Mode = iota // PUBLISH 到频道，无订阅者时消息直接丢弃
+	ModeStream              // XADD 到 Stream，消费者可用 XREAD/XREADGROUP 按需读取
+)
+
+// Options Redis Sink 配置
+type Options struct {
+	Mode    Mode
+	Prefix  string       // 键前缀，默认 douyin
+	Encoder sink.Encoder // 消息体编码，默认 sink.JSONEncoder{}
+	MaxLen  int64        // Stream 模式下每个房间保留的大致条数，<=0 时不裁剪
+	Logger  *slog.Logger // 记录编码失败而跳过的事件，默认 slog.Default()
+}
+
+// Sink 将事件写入 Redis
+type Sink struct {
+	client redis.UniversalClient
+	opts   Options
+	owned  bool // Close 时是否关闭客户端
+}
+
+// Connect 按 redis:// URL 连接并创建 Sink，Close 时关闭连接
+func Connect(ctx context.Context, url string, opts Options) (*Sink, error) {
+	ropts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Redis 地址失败: %w", err)
+	}
+	client := redis.NewClient(ropts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
+	}
+	s := New(client, opts)
+	s.owned = true
+	return s, nil
+}
+
+// New 使用已有客户端创建 Sink，Close 时不关闭客户端
+func New(client redis.UniversalClient, opts Options) *Sink {
+	if opts.Prefix == "" {
+		opts.Prefix = defaultPrefix
+	}
+	if opts.Encoder == nil {
+		opts.Encoder = sink.JSONEncoder{}
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &Sink{client: client, opts: opts}
+}
+
+// Key 返回房间对应的频道或 Stream 键
+func (s *Sink) Key(roomID string) string {
+	return s.opts.Prefix + ":" + roomID
+}
+
+// Write 通过 pipeline 一次写入一批事件，无法编码的事件记录日志后跳过
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	pipe := s.client.Pipeline()
+	for _, event := range events {
+		data, err := s.opts.Encoder.Encode(event)
+		if err != nil {
+			// 单条事件无法编码时跳过，不影响同批的其他事件，也避免整批重试
+			s.opts.Logger.Warn("编码事件失败，跳过", "msg_id", event.MsgID, "method", event.Method, "error", err)
+			continue
+		}
+		key := s.Key(event.RoomID)
+		if s.opts.Mode == ModePublish {
+			pipe.Publish(ctx, key, data)
+			continue
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			MaxLen: s.opts.MaxLen,
+			Approx: s.opts.MaxLen > 0,
+			Values: []interface{}{
+				douyinLive.FieldMethod, event.Method,
+				douyinLive.FieldMsgID, strconv.FormatUint(event.MsgID, 10),
+				"event", data,
+			},
+		})
+	}
+	if pipe.Len() == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("写入 Redis 失败: %w", err)
+	}
+	return nil
+}
+
+// Close 通过 Connect 创建时关闭连接
+func (s *Sink) Close() error {
+	if s.owned {
+		return s.client.Close()
+	}
+	return nil
+}
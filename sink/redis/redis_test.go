@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func chatEvent(t *testing.T, roomID, content string) *douyinLive.LiveEvent {
+	t.Helper()
+	payload, err := proto.Marshal(&new_douyin.Webcast_Im_ChatMessage{Content: content})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return douyinLive.NewLiveEvent(roomID, "主播", &new_douyin.Webcast_Im_Message{
+		Method: douyinLive.WebcastChatMessage, MsgId: 1, Payload: payload,
+	})
+}
+
+func TestSinkStream(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	s, err := Connect(ctx, "redis://"+mr.Addr(), Options{Mode: ModeStream})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Write(ctx, []*douyinLive.LiveEvent{chatEvent(t, "100", "你好"), chatEvent(t, "200", "在吗")}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := mr.Stream("douyin:100")
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("douyin:100 = %v, %v", entries, err)
+	}
+	values := entries[0].Values
+	if values[0] != douyinLive.FieldMethod || values[1] != douyinLive.WebcastChatMessage {
+		t.Fatalf("stream 字段 = %v", values)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(values[5]), &fields); err != nil || fields[douyinLive.FieldContent] != "你好" {
+		t.Fatalf("事件 JSON = %s, %v", values[5], err)
+	}
+}
+
+func TestSinkPublish(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	sub := client.Subscribe(ctx, "douyin:100")
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(client, Options{})
+	if err := s.Write(ctx, []*douyinLive.LiveEvent{chatEvent(t, "100", "你好")}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := sub.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(msg.Payload), &fields); err != nil || fields[douyinLive.FieldContent] != "你好" {
+		t.Fatalf("频道消息 = %s, %v", msg.Payload, err)
+	}
+}
+
+// roomEncoder 对指定房间的事件编码失败
+type roomEncoder struct{ bad string }
+
+func (e roomEncoder) Encode(event *douyinLive.LiveEvent) ([]byte, error) {
+	if event.RoomID == e.bad {
+		return nil, errors.New("无法编码")
+	}
+	return []byte(event.RoomID), nil
+}
+
+func TestSinkSkipsUnencodableEvents(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	s, err := Connect(ctx, "redis://"+mr.Addr(), Options{Mode: ModeStream, Encoder: roomEncoder{bad: "100"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Write(ctx, []*douyinLive.LiveEvent{chatEvent(t, "100", "坏"), chatEvent(t, "200", "好")}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := mr.Stream("douyin:200"); len(entries) != 1 {
+		t.Fatalf("同批的其他事件未写入: %v", entries)
+	}
+	if mr.Exists("douyin:100") {
+		t.Fatal("编码失败的事件不应写入")
+	}
+}
@@ -0,0 +1,187 @@
+package sink
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions 滚动文件的配置
+type RotateOptions struct {
+	MaxSize  int64         // 单个文件的最大字节数，<=0 时不按大小滚动
+	Interval time.Duration // 按时间滚动的间隔，<=0 时不按时间滚动
+	Compress bool          // 滚动后的文件用 gzip 压缩
+	Logger   *slog.Logger  // 记录滚动与压缩失败，nil 时使用 slog.Default()
+}
+
+// RotatingFile 按大小或时间滚动的文件，滚动后的文件以时间戳重命名
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	opts     RotateOptions
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	wg       sync.WaitGroup // 后台压缩任务
+}
+
+// OpenRotatingFile 以追加方式打开文件
+func OpenRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	f := &RotatingFile{path: path, opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write 写入数据，写入前检查是否需要滚动，单次写入不会被拆到两个文件
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, ErrClosed
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			if f.file == nil {
+				return 0, err
+			}
+			f.logger().Warn("滚动文件失败，继续写入原文件", "path", f.path, "error", err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close 关闭当前文件并等待压缩任务完成
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	return err
+}
+
+// open 打开当前文件
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+// shouldRotate 判断写入 n 字节前是否需要滚动，空文件不滚动
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.opts.MaxSize > 0 && f.size+n > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.Interval > 0 && time.Since(f.openedAt) >= f.opts.Interval
+}
+
+// rotate 关闭并重命名当前文件，然后打开新文件。失败时重新打开原文件继续追加，
+// 只有原文件也无法打开时 f.file 为 nil
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	rotated := f.rotatedName(time.Now())
+	if err == nil {
+		err = os.Rename(f.path, rotated)
+	}
+	if err != nil {
+		err = fmt.Errorf("滚动文件失败: %w", err)
+		if openErr := f.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return err
+	}
+	if f.opts.Compress {
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			if err := gzipFile(rotated); err != nil {
+				f.logger().Warn("压缩滚动文件失败", "path", rotated, "error", err)
+			}
+		}()
+	}
+	return f.open()
+}
+
+// logger 返回记录日志使用的 Logger
+func (f *RotatingFile) logger() *slog.Logger {
+	if f.opts.Logger != nil {
+		return f.opts.Logger
+	}
+	return slog.Default()
+}
+
+// rotatedName 返回滚动后的文件名，如 events-20240601-120000.jsonl，重名时追加序号
+func (f *RotatingFile) rotatedName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	name := fmt.Sprintf("%s-%s%s", base, t.Format("20060102-150405"), ext)
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, t.Format("20060102-150405"), i, ext)
+	}
+	return name
+}
+
+// exists 判断文件是否存在
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// gzipFile 将文件压缩为 path.gz 并删除原文件
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
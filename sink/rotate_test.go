@@ -0,0 +1,38 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFileKeepsWritingAfterFailedRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	f, err := OpenRotatingFile(path, RotateOptions{MaxSize: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	// 文件被外部删除后重命名失败，应重新打开原路径继续写入
+	os.Remove(path)
+	if _, err := f.Write([]byte("second\n")); err != nil {
+		t.Fatalf("滚动失败后写入返回 %v", err)
+	}
+	if _, err := f.Write([]byte("third\n")); err != nil {
+		t.Fatal(err)
+	}
+	// 之后的滚动恢复正常
+	rotated, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "events-*.jsonl"))
+	if len(rotated) != 1 {
+		t.Fatalf("滚动文件 = %v", rotated)
+	}
+	if data, _ := os.ReadFile(rotated[0]); string(data) != "second\n" {
+		t.Fatalf("滚动文件内容 = %q", data)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "third\n" {
+		t.Fatalf("当前文件内容 = %q", data)
+	}
+}
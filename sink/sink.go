@@ -0,0 +1,34 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/tiga210/douyinLive"
+)
+
+func init() {
+	douyinLive.RegisterSink("writer")
+	douyinLive.RegisterSink("recorder")
+}
+
+// Sink 事件输出目标
+type Sink interface {
+	// Write 写入一批事件
+	Write(ctx context.Context, events []*douyinLive.LiveEvent) error
+	// Close 刷新缓冲并释放资源
+	Close() error
+}
+
+// Attach 将 Sink 订阅到直播实例，每条事件单独写入，写入失败时交给 onError 处理
+func Attach(dl *douyinLive.DouyinLive, s Sink, onError func(error)) string {
+	return dl.SubscribeEvent(func(event *douyinLive.LiveEvent) {
+		if err := s.Write(context.Background(), []*douyinLive.LiveEvent{event}); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+}
+
+// Encoder 将单条事件编码为字节，用于消息队列等按条写入的 Sink
+type Encoder interface {
+	Encode(event *douyinLive.LiveEvent) ([]byte, error)
+}
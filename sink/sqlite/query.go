@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Query 事件查询条件，零值字段不参与过滤
+type Query struct {
+	RoomID  string
+	From    time.Time
+	To      time.Time
+	Keyword string // 弹幕内容或昵称包含的关键词
+	UserID  uint64
+	Methods []string
+	Limit   int // 默认 100
+}
+
+// Record 查询结果中的一条事件
+type Record struct {
+	Time         time.Time `json:"time"`
+	RoomID       string    `json:"room_id"`
+	LiveName     string    `json:"live_name"`
+	Method       string    `json:"method"`
+	MsgID        int64     `json:"msg_id"`
+	UserID       int64     `json:"user_id,omitempty"`
+	Nickname     string    `json:"nickname,omitempty"`
+	Content      string    `json:"content,omitempty"`
+	GiftName     string    `json:"gift_name,omitempty"`
+	GiftCount    int64     `json:"gift_count,omitempty"`
+	DiamondCount int64     `json:"diamond_count,omitempty"`
+	LikeCount    int64     `json:"like_count,omitempty"`
+}
+
+// likeEscaper 转义 LIKE 中的通配符，使关键词按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Find 按条件查询事件，结果按时间升序
+func Find(ctx context.Context, db *sql.DB, q Query) ([]Record, error) {
+	var where []string
+	var args []any
+	if q.RoomID != "" {
+		where = append(where, "room_id = ?")
+		args = append(args, q.RoomID)
+	}
+	if !q.From.IsZero() {
+		where = append(where, "ts >= ?")
+		args = append(args, q.From.UnixMilli())
+	}
+	if !q.To.IsZero() {
+		where = append(where, "ts < ?")
+		args = append(args, q.To.UnixMilli())
+	}
+	if q.Keyword != "" {
+		where = append(where, `(content LIKE ? ESCAPE '\' OR nickname LIKE ? ESCAPE '\')`)
+		like := "%" + likeEscaper.Replace(q.Keyword) + "%"
+		args = append(args, like, like)
+	}
+	if q.UserID != 0 {
+		where = append(where, "user_id = ?")
+		args = append(args, int64(q.UserID))
+	}
+	if len(q.Methods) > 0 {
+		where = append(where, "method IN (?"+strings.Repeat(", ?", len(q.Methods)-1)+")")
+		for _, method := range q.Methods {
+			args = append(args, method)
+		}
+	}
+	if q.Limit <= 0 {
+		q.Limit = 100
+	}
+
+	query := `SELECT ts, room_id, live_name, method, msg_id, user_id, nickname, content,
+		gift_name, group_count, repeat_count, diamond_count, like_count FROM events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY ts, id LIMIT ?"
+	args = append(args, q.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		var ts int64
+		var userID, groupCount, repeatCount, diamond, likes sql.NullInt64
+		var nickname, content, giftName sql.NullString
+		if err := rows.Scan(&ts, &r.RoomID, &r.LiveName, &r.Method, &r.MsgID, &userID, &nickname, &content,
+			&giftName, &groupCount, &repeatCount, &diamond, &likes); err != nil {
+			return nil, err
+		}
+		r.Time = time.UnixMilli(ts)
+		r.UserID, r.Nickname, r.Content = userID.Int64, nickname.String, content.String
+		r.GiftName, r.DiamondCount, r.LikeCount = giftName.String, diamond.Int64, likes.Int64
+		if giftName.Valid {
+			r.GiftCount = max(groupCount.Int64, 1) * max(repeatCount.Int64, 1)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
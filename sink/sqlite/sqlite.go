@@ -0,0 +1,141 @@
+// Package sqlite 提供将直播事件持久化到 SQLite 的 Sink，依赖 cgo
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("sqlite")
+}
+
+// schema 事件表，各类消息的专有字段可为空
+const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	room_id       TEXT    NOT NULL,
+	live_name     TEXT    NOT NULL DEFAULT '',
+	method        TEXT    NOT NULL,
+	msg_id        INTEGER NOT NULL DEFAULT 0,
+	ts            INTEGER NOT NULL, -- 接收时间，Unix 毫秒
+	user_id       INTEGER,
+	nickname      TEXT,
+	content       TEXT,             -- 弹幕内容
+	gift_id       INTEGER,
+	gift_name     TEXT,
+	group_id      INTEGER,          -- 连击组 ID
+	group_count   INTEGER,
+	repeat_count  INTEGER,          -- 连击累计次数
+	repeat_end    INTEGER,          -- 是否为连击的最后一条消息
+	diamond_count INTEGER,          -- 礼物单价（抖币）
+	like_count    INTEGER,
+	data          TEXT,             -- 消息体 JSON，StoreData 为 true 时写入
+	ext           TEXT              -- LiveEvent.Ext 的 JSON
+);
+CREATE INDEX IF NOT EXISTS idx_events_room_ts ON events (room_id, ts);
+CREATE INDEX IF NOT EXISTS idx_events_user_id ON events (user_id);
+CREATE INDEX IF NOT EXISTS idx_events_method_ts ON events (method, ts);
+`
+
+// insertSQL 按 sink.Columns 的顺序插入一行
+var insertSQL = "INSERT INTO events (" + strings.Join(sink.Columns, ", ") +
+	") VALUES (?" + strings.Repeat(", ?", len(sink.Columns)-1) + ")"
+
+// DefaultMethods 默认持久化的消息类型。
+//
+// Deprecated: 使用 sink.DefaultMethods
+var DefaultMethods = sink.DefaultMethods
+
+// Options SQLite Sink 配置
+type Options struct {
+	Methods   []string // 持久化的消息类型，为空时使用 sink.DefaultMethods
+	StoreData bool     // 是否在 data 列保存完整的消息体 JSON
+}
+
+// Sink 将事件写入 SQLite 的 events 表
+type Sink struct {
+	db      *sql.DB
+	opts    Options
+	methods sink.MethodFilter
+	ownsDB  bool
+}
+
+// Open 打开（不存在时创建）SQLite 数据库文件并初始化表结构
+func Open(path string, opts Options) (*Sink, error) {
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	s, err := New(db, opts)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.ownsDB = true
+	return s, nil
+}
+
+// New 使用已打开的数据库创建 Sink，Close 时不会关闭 db
+func New(db *sql.DB, opts Options) (*Sink, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("初始化 SQLite 表结构失败: %w", err)
+	}
+	// 早期版本创建的表没有 ext 列
+	if _, err := db.Exec("ALTER TABLE events ADD COLUMN ext TEXT"); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+		return nil, fmt.Errorf("升级 SQLite 表结构失败: %w", err)
+	}
+	return &Sink{db: db, opts: opts, methods: sink.NewMethodFilter(opts.Methods)}, nil
+}
+
+// DB 返回底层数据库，便于查询
+func (s *Sink) DB() *sql.DB {
+	return s.db
+}
+
+// Write 在一个事务中写入一批事件，不在 Methods 中的事件被忽略
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		if !s.methods[event.Method] {
+			continue
+		}
+		r, err := sink.NewRecord(event, s.opts.StoreData)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		args := r.Values()
+		args[0] = r.Time.UnixMilli() // ts 列保存 Unix 毫秒
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("写入事件失败 (msg_id: %d): %w", event.MsgID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Close 关闭由 Open 打开的数据库
+func (s *Sink) Close() error {
+	if s.ownsDB {
+		return s.db.Close()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func event(t *testing.T, method string, m proto.Message) *douyinLive.LiveEvent {
+	t.Helper()
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: method, Payload: payload})
+}
+
+func TestSinkWrite(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "events.db"), Options{StoreData: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	user := &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"}
+	events := []*douyinLive.LiveEvent{
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "你好"}),
+		event(t, douyinLive.WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{
+			User: user, GiftId: 463, GroupCount: 1, RepeatCount: 3, RepeatEnd: 1,
+			Gift: &new_douyin.Webcast_Data_GiftStruct{Name: "小心心", DiamondCount: 1},
+		}),
+		event(t, douyinLive.WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{User: user, Count: 5}),
+		event(t, douyinLive.WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 1}),
+	}
+	events[0].SetExt("team", "A组")
+	if err := s.Write(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int
+	s.DB().QueryRow(`SELECT COUNT(*) FROM events WHERE room_id = ? AND user_id = ?`, "100", 42).Scan(&count)
+	if count != 3 {
+		t.Fatalf("写入 %d 行, want 3（RoomUserSeq 不在默认类型中）", count)
+	}
+	var content, giftName string
+	var likes int
+	s.DB().QueryRow(`SELECT content FROM events WHERE method = ?`, douyinLive.WebcastChatMessage).Scan(&content)
+	s.DB().QueryRow(`SELECT gift_name FROM events WHERE method = ? AND repeat_end = 1`, douyinLive.WebcastGiftMessage).Scan(&giftName)
+	s.DB().QueryRow(`SELECT like_count FROM events WHERE method = ?`, douyinLive.WebcastLikeMessage).Scan(&likes)
+	var ext string
+	s.DB().QueryRow(`SELECT ext FROM events WHERE method = ?`, douyinLive.WebcastChatMessage).Scan(&ext)
+	if content != "你好" || giftName != "小心心" || likes != 5 || ext != `{"team":"A组"}` {
+		t.Fatalf("字段错误: content=%q gift=%q likes=%d ext=%q", content, giftName, likes, ext)
+	}
+}
+
+func TestFind(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "events.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	user := &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"}
+	old := event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "昨天的弹幕"})
+	old.Time = old.Time.Add(-24 * time.Hour)
+	s.Write(context.Background(), []*douyinLive.LiveEvent{
+		old,
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "今天的弹幕"}),
+		event(t, douyinLive.WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{User: user, Count: 5}),
+	})
+
+	records, err := Find(context.Background(), s.DB(), Query{
+		RoomID:  "100",
+		From:    time.Now().Add(-time.Hour),
+		Keyword: "弹幕",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Content != "今天的弹幕" || records[0].UserID != 42 {
+		t.Fatalf("查询结果错误: %+v", records)
+	}
+}
+
+func TestFindKeywordIsLiteral(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "events.db"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	user := &new_douyin.Webcast_Data_User{Id: 42, Nickname: "观众"}
+	s.Write(context.Background(), []*douyinLive.LiveEvent{
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "打折 50%"}),
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "打折 50 元"}),
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: `a_b\c`}),
+		event(t, douyinLive.WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user, Content: "axb"}),
+	})
+
+	for keyword, want := range map[string]string{"50%": "打折 50%", "a_b": `a_b\c`, `b\c`: `a_b\c`} {
+		records, err := Find(context.Background(), s.DB(), Query{Keyword: keyword})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].Content != want {
+			t.Fatalf("关键词 %q 应按字面匹配: %+v", keyword, records)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Package sse 以 Server-Sent Events 向浏览器推送事件，浏览器叠加层用 EventSource 即可订阅：
+//
+//	new EventSource("/events?room=7382620942951772256&method=WebcastChatMessage,WebcastGiftMessage")
+//
+// Handler 同时实现 sink.Sink 与 http.Handler，写入的事件广播给全部已连接的客户端
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("sse")
+}
+
+const (
+	defaultClientBuffer = 256
+	defaultHeartbeat    = 15 * time.Second
+)
+
+// Options SSE 配置
+type Options struct {
+	Fields       sink.Fields   // 输出字段白名单，客户端可用 fields 参数进一步裁剪
+	ClientBuffer int           // 每个客户端的缓冲事件数，客户端跟不上时丢弃新事件，默认 256
+	Heartbeat    time.Duration // 心跳注释的间隔，防止代理断开空闲连接，默认 15 秒
+	AllowOrigin  string        // 非空时设置 Access-Control-Allow-Origin
+}
+
+// message 编码后的一条 SSE 消息
+type message struct {
+	roomID string
+	method string
+	id     string
+	fields map[string]interface{}
+}
+
+// client 一个已连接的 EventSource
+type client struct {
+	rooms   map[string]bool // 为空时不过滤
+	methods map[string]bool
+	fields  sink.Fields
+	ch      chan message
+	dropped int
+}
+
+// accept 按查询参数过滤
+func (c *client) accept(m message) bool {
+	return (len(c.rooms) == 0 || c.rooms[m.roomID]) && (len(c.methods) == 0 || c.methods[m.method])
+}
+
+// Handler SSE 输出
+type Handler struct {
+	opts Options
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// New 创建 SSE Handler
+func New(opts Options) *Handler {
+	if opts.ClientBuffer <= 0 {
+		opts.ClientBuffer = defaultClientBuffer
+	}
+	if opts.Heartbeat <= 0 {
+		opts.Heartbeat = defaultHeartbeat
+	}
+	return &Handler{opts: opts, clients: make(map[*client]struct{}), closed: make(chan struct{})}
+}
+
+// Clients 返回当前连接的客户端数
+func (h *Handler) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Write 实现 sink.Sink，广播给匹配的客户端，不阻塞
+func (h *Handler) Write(_ context.Context, events []*douyinLive.LiveEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.clients) == 0 {
+		return nil
+	}
+	for _, event := range events {
+		m := message{
+			roomID: event.RoomID,
+			method: event.Method,
+			id:     strconv.FormatUint(event.MsgID, 10),
+			fields: h.opts.Fields.Select(event.Fields()),
+		}
+		for c := range h.clients {
+			if !c.accept(m) {
+				continue
+			}
+			select {
+			case c.ch <- m:
+			default:
+				c.dropped++
+			}
+		}
+	}
+	return nil
+}
+
+// Close 断开全部客户端
+func (h *Handler) Close() error {
+	h.once.Do(func() { close(h.closed) })
+	return nil
+}
+
+// ServeHTTP 实现 http.Handler，支持 room（roomID）、method、fields 查询参数，均可逗号分隔或重复指定
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "不支持流式响应", http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+	c := &client{
+		rooms:   set(query["room"]),
+		methods: set(query["method"]),
+		fields:  sink.ParseFields(strings.Join(query["fields"], ",")),
+		ch:      make(chan message, h.opts.ClientBuffer),
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	if h.opts.AllowOrigin != "" {
+		header.Set("Access-Control-Allow-Origin", h.opts.AllowOrigin)
+	}
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	flusher.Flush()
+
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, c)
+		h.mu.Unlock()
+	}()
+
+	heartbeat := time.NewTicker(h.opts.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.closed:
+			return
+		case <-heartbeat.C:
+			h.mu.Lock()
+			dropped := c.dropped
+			c.dropped = 0
+			h.mu.Unlock()
+			fmt.Fprintf(w, ": ping dropped=%d\n\n", dropped)
+		case m := <-c.ch:
+			data, err := json.Marshal(c.fields.Select(m.fields))
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", m.id, m.method, data)
+		}
+		flusher.Flush()
+	}
+}
+
+// set 将逗号分隔或重复指定的参数转为集合
+func set(values []string) map[string]bool {
+	out := make(map[string]bool)
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out[item] = true
+			}
+		}
+	}
+	return out
+}
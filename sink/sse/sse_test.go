@@ -0,0 +1,60 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestHandlerStreamsFilteredEvents(t *testing.T) {
+	h := New(Options{})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	defer h.Close()
+
+	resp, err := http.Get(srv.URL + "?room=100&method=WebcastGiftMessage&fields=method,room_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	for h.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	h.Write(context.Background(), []*douyinLive.LiveEvent{
+		douyinLive.NewLiveEvent("100", "", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage, MsgId: 1}),
+		douyinLive.NewLiveEvent("200", "", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastGiftMessage, MsgId: 2}),
+		douyinLive.NewLiveEvent("100", "", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastGiftMessage, MsgId: 3}),
+	})
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "retry:") || line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == 3 {
+			break
+		}
+	}
+	want := []string{
+		"id: 3",
+		"event: WebcastGiftMessage",
+		`data: {"method":"WebcastGiftMessage","room_id":"100"}`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("收到:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
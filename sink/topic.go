@@ -0,0 +1,33 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// topicSink 只写入匹配主题的事件
+type topicSink struct {
+	Sink
+	patterns []string
+}
+
+// Topic 包装 Sink，只写入主题匹配任一模式的事件，模式语义见 douyinLive.TopicMatch。
+// 与 Group 组合即可按主题路由，如营收事件写入数据库、互动事件推送到消息队列
+func Topic(s Sink, patterns ...string) Sink {
+	return &topicSink{Sink: s, patterns: patterns}
+}
+
+// Write 过滤后写入被包装的 Sink，过滤后为空时不写入
+func (t *topicSink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	filtered := make([]*douyinLive.LiveEvent, 0, len(events))
+	for _, event := range events {
+		if douyinLive.TopicMatchAny(event.Topic(), t.patterns) {
+			filtered = append(filtered, event)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return t.Sink.Write(ctx, filtered)
+}
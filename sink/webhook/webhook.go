@@ -0,0 +1,237 @@
+// Package webhook 以 HTTP POST 将事件批量推送到指定地址，支持 HMAC 签名、指数退避重试与死信缓冲，
+// 便于 Serverless 后端在不保持长连接的情况下消费直播事件。
+// Write 会阻塞到推送成功或重试耗尽，通常配合 sink.NewBuffer 异步攒批使用
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/sink"
+)
+
+func init() {
+	douyinLive.RegisterSink("webhook")
+}
+
+// 签名相关的请求头
+const (
+	HeaderTimestamp = "X-Douyinlive-Timestamp"
+	HeaderSignature = "X-Douyinlive-Signature"
+)
+
+const (
+	defaultMaxRetries     = 5
+	defaultRetryDelay     = 500 * time.Millisecond
+	defaultMaxRetryDelay  = 30 * time.Second
+	defaultTimeout        = 10 * time.Second
+	defaultDeadLetterSize = 100
+)
+
+// Options Webhook Sink 配置
+type Options struct {
+	URL     string
+	Secret  string            // HMAC-SHA256 签名密钥，为空时不签名
+	Headers map[string]string // 附加的请求头，如鉴权 token
+	Fields  sink.Fields       // 输出字段白名单
+
+	MaxRetries    int           // 失败后的最大重试次数，默认 5，负数表示不重试
+	RetryDelay    time.Duration // 首次重试的等待时间，之后逐次翻倍，默认 500ms
+	MaxRetryDelay time.Duration // 重试等待时间的上限，默认 30 秒
+	Timeout       time.Duration // 单次请求超时，默认 10 秒
+
+	DeadLetterSize int // 保留的死信批次数，超出时丢弃最早的，默认 100
+	Client         *http.Client
+}
+
+// Payload 请求体
+type Payload struct {
+	SentAt int64                    `json:"sent_at"` // 首次发送时间，Unix 毫秒
+	Events []map[string]interface{} `json:"events"`
+}
+
+// DeadLetter 重试耗尽仍未送达的批次
+type DeadLetter struct {
+	Body   []byte
+	Events int
+	Err    error
+	Time   time.Time
+}
+
+// Sink 将事件批量推送到 Webhook
+type Sink struct {
+	opts   Options
+	client *http.Client
+
+	mu          sync.Mutex
+	deadLetters []DeadLetter
+}
+
+// New 创建 Webhook Sink
+func New(opts Options) (*Sink, error) {
+	if opts.URL == "" {
+		return nil, errors.New("webhook 地址不能为空")
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultMaxRetries
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+	if opts.MaxRetryDelay <= 0 {
+		opts.MaxRetryDelay = defaultMaxRetryDelay
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.DeadLetterSize <= 0 {
+		opts.DeadLetterSize = defaultDeadLetterSize
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	return &Sink{opts: opts, client: client}, nil
+}
+
+// Write 推送一批事件，重试耗尽后放入死信缓冲并返回错误
+func (s *Sink) Write(ctx context.Context, events []*douyinLive.LiveEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	payload := Payload{SentAt: time.Now().UnixMilli(), Events: make([]map[string]interface{}, len(events))}
+	for i, event := range events {
+		payload.Events[i] = s.opts.Fields.Select(event.Fields())
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.deliver(ctx, body, len(events))
+}
+
+// deliver 带重试地发送请求体，失败时记入死信
+func (s *Sink) deliver(ctx context.Context, body []byte, events int) error {
+	err := retry.Do(
+		func() error { return s.post(ctx, body) },
+		retry.Context(ctx),
+		retry.Attempts(uint(s.opts.MaxRetries+1)),
+		retry.Delay(s.opts.RetryDelay),
+		retry.MaxDelay(s.opts.MaxRetryDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		s.mu.Lock()
+		s.deadLetters = append(s.deadLetters, DeadLetter{Body: body, Events: events, Err: err, Time: time.Now()})
+		if over := len(s.deadLetters) - s.opts.DeadLetterSize; over > 0 {
+			s.deadLetters = append(s.deadLetters[:0], s.deadLetters[over:]...)
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("推送 %d 条事件到 webhook 失败: %w", events, err)
+	}
+	return nil
+}
+
+// post 发送一次请求，4xx（429 除外）视为不可重试
+func (s *Sink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.opts.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, Sign(s.opts.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("状态码 %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return retry.Unrecoverable(err)
+	}
+	return err
+}
+
+// DeadLetters 返回死信缓冲的副本
+func (s *Sink) DeadLetters() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeadLetter(nil), s.deadLetters...)
+}
+
+// Redeliver 重新推送死信缓冲中的全部批次，仍失败的批次会重新进入缓冲
+func (s *Sink) Redeliver(ctx context.Context) error {
+	s.mu.Lock()
+	letters := s.deadLetters
+	s.deadLetters = nil
+	s.mu.Unlock()
+
+	var errs []error
+	for _, letter := range letters {
+		if err := s.deliver(ctx, letter.Body, letter.Events); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 实现 sink.Sink，死信不会被自动落盘
+func (s *Sink) Close() error {
+	return nil
+}
+
+// Sign 计算签名：sha256= 加 HMAC-SHA256(secret, timestamp + "." + body) 的十六进制
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 供接收端校验签名，maxAge>0 时拒绝时间戳偏差超过 maxAge 的请求以防重放
+func Verify(secret string, header http.Header, body []byte, maxAge time.Duration) error {
+	timestamp := header.Get(HeaderTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("无效的时间戳: %q", timestamp)
+	}
+	if maxAge > 0 {
+		if age := time.Since(time.Unix(ts, 0)); age > maxAge || age < -maxAge {
+			return fmt.Errorf("时间戳已过期: %s", age.Round(time.Second))
+		}
+	}
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return errors.New("签名不匹配")
+	}
+	return nil
+}
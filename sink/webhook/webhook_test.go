@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func events() []*douyinLive.LiveEvent {
+	return []*douyinLive.LiveEvent{
+		douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastChatMessage, MsgId: 1}),
+		douyinLive.NewLiveEvent("100", "主播", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastGiftMessage, MsgId: 2}),
+	}
+}
+
+func TestSinkRetryAndSign(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify("secret", r.Header, body, time.Minute); err != nil {
+			t.Errorf("签名校验失败: %v", err)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var p Payload
+		if err := json.Unmarshal(body, &p); err != nil || len(p.Events) != 2 {
+			t.Errorf("请求体 = %s", body)
+		}
+	}))
+	defer srv.Close()
+
+	s, _ := New(Options{URL: srv.URL, Secret: "secret", RetryDelay: time.Millisecond})
+	if err := s.Write(context.Background(), events()); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 3 || len(s.DeadLetters()) != 0 {
+		t.Fatalf("calls=%d dead=%d", calls.Load(), len(s.DeadLetters()))
+	}
+}
+
+func TestSinkDeadLetter(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusBadRequest)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	s, _ := New(Options{URL: srv.URL, RetryDelay: time.Millisecond})
+	if err := s.Write(context.Background(), events()); err == nil {
+		t.Fatal("4xx 应返回错误")
+	}
+	if calls.Load() != 1 || len(s.DeadLetters()) != 1 {
+		t.Fatalf("4xx 不应重试: calls=%d dead=%d", calls.Load(), len(s.DeadLetters()))
+	}
+
+	status.Store(http.StatusOK)
+	if err := s.Redeliver(context.Background()); err != nil || len(s.DeadLetters()) != 0 {
+		t.Fatalf("重投失败: %v, dead=%d", err, len(s.DeadLetters()))
+	}
+}
@@ -0,0 +1,296 @@
+// Package soak 长时间稳定性自检：持续运行期间周期性采样内存、goroutine、队列深度与消息速率，
+// 结束时根据增长趋势给出稳定性报告，用于 7x24 运行前的验证
+package soak
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+const (
+	defaultInterval           = time.Minute
+	defaultMaxHeapGrowth      = 20 << 20 // 20MB/小时
+	defaultMaxGoroutineGrowth = 50
+)
+
+// Sample 单次采样
+type Sample struct {
+	Time       time.Time `json:"time"`
+	HeapInuse  uint64    `json:"heap_inuse"`  // 堆占用字节数
+	Sys        uint64    `json:"sys"`         // 向系统申请的总字节数
+	NumGC      uint32    `json:"num_gc"`      // 累计 GC 次数
+	Goroutines int       `json:"goroutines"`  // goroutine 数
+	QueueDepth int       `json:"queue_depth"` // 异步分发队列深度
+	Messages   uint64    `json:"messages"`    // 累计消息数
+	Rate       float64   `json:"rate"`        // 本采样周期的消息速率（条/秒）
+}
+
+// Options 自检配置
+type Options struct {
+	Duration time.Duration // 总时长，<=0 时运行到 ctx 结束
+	Interval time.Duration // 采样间隔，默认 1 分钟
+	OnSample func(Sample)  // 每次采样后回调，默认写入日志
+
+	MaxHeapGrowth      float64 // 判定为内存泄漏的堆增长速度（字节/小时），默认 20MB/小时
+	MaxGoroutineGrowth int     // 判定为 goroutine 泄漏的增长数，默认 50
+	Logger             *slog.Logger
+}
+
+// Monitor 采样器，通过 Watch 接入直播实例
+type Monitor struct {
+	opts     Options
+	messages atomic.Uint64
+
+	mu       sync.Mutex
+	lives    []*douyinLive.DouyinLive
+	restarts int
+	errors   []string
+}
+
+// New 创建采样器
+func New(opts Options) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.MaxHeapGrowth <= 0 {
+		opts.MaxHeapGrowth = defaultMaxHeapGrowth
+	}
+	if opts.MaxGoroutineGrowth <= 0 {
+		opts.MaxGoroutineGrowth = defaultMaxGoroutineGrowth
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	m := &Monitor{opts: opts}
+	if m.opts.OnSample == nil {
+		m.opts.OnSample = m.logSample
+	}
+	return m
+}
+
+// Watch 统计直播实例的消息数与队列深度，返回取消订阅的函数
+func (m *Monitor) Watch(dl *douyinLive.DouyinLive) func() {
+	id := dl.Subscribe(func(*new_douyin.Webcast_Im_Message) {
+		m.messages.Add(1)
+	})
+	m.mu.Lock()
+	m.lives = append(m.lives, dl)
+	m.mu.Unlock()
+	return func() {
+		dl.Unsubscribe(id)
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, live := range m.lives {
+			if live == dl {
+				m.lives = append(m.lives[:i], m.lives[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// RecordRestart 记录一次连接中断与重启，错误会出现在报告中
+func (m *Monitor) RecordRestart(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.restarts++
+	if err != nil {
+		m.errors = append(m.errors, fmt.Sprintf("%s %v", time.Now().Format(time.DateTime), err))
+	}
+}
+
+// Run 按间隔采样直到 Duration 到期或 ctx 结束，返回稳定性报告
+func (m *Monitor) Run(ctx context.Context) *Report {
+	if m.opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.opts.Duration)
+		defer cancel()
+	}
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	samples := []Sample{m.sample(nil)}
+	for {
+		select {
+		case <-ticker.C:
+			s := m.sample(&samples[len(samples)-1])
+			samples = append(samples, s)
+			m.opts.OnSample(s)
+		case <-ctx.Done():
+			samples = append(samples, m.sample(&samples[len(samples)-1]))
+			return m.report(samples)
+		}
+	}
+}
+
+// sample 采集当前指标，prev 用于计算消息速率
+func (m *Monitor) sample(prev *Sample) Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	s := Sample{
+		Time:       time.Now(),
+		HeapInuse:  ms.HeapInuse,
+		Sys:        ms.Sys,
+		NumGC:      ms.NumGC,
+		Goroutines: runtime.NumGoroutine(),
+		Messages:   m.messages.Load(),
+	}
+	m.mu.Lock()
+	for _, dl := range m.lives {
+		s.QueueDepth += dl.QueueDepth()
+	}
+	m.mu.Unlock()
+	if prev != nil {
+		if elapsed := s.Time.Sub(prev.Time).Seconds(); elapsed > 0 {
+			s.Rate = float64(s.Messages-prev.Messages) / elapsed
+		}
+	}
+	return s
+}
+
+// logSample 默认的采样输出
+func (m *Monitor) logSample(s Sample) {
+	m.opts.Logger.Info("soak 采样",
+		"heap_mb", float64(s.HeapInuse)/(1<<20),
+		"goroutines", s.Goroutines,
+		"queue_depth", s.QueueDepth,
+		"messages", s.Messages,
+		"rate", fmt.Sprintf("%.1f/s", s.Rate),
+	)
+}
+
+// Report 稳定性报告
+type Report struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	Samples  []Sample      `json:"samples"`
+
+	Messages       uint64   `json:"messages"`
+	AvgRate        float64  `json:"avg_rate"`
+	PeakRate       float64  `json:"peak_rate"`
+	PeakHeap       uint64   `json:"peak_heap"`
+	HeapGrowth     float64  `json:"heap_growth"` // 堆占用的线性增长速度（字节/小时）
+	GoroutineStart int      `json:"goroutine_start"`
+	GoroutineEnd   int      `json:"goroutine_end"`
+	PeakGoroutines int      `json:"peak_goroutines"`
+	PeakQueueDepth int      `json:"peak_queue_depth"`
+	Restarts       int      `json:"restarts"`
+	Errors         []string `json:"errors,omitempty"`
+
+	Passed   bool     `json:"passed"`
+	Problems []string `json:"problems,omitempty"` // 未通过的原因
+}
+
+// report 汇总采样生成报告
+func (m *Monitor) report(samples []Sample) *Report {
+	first, last := samples[0], samples[len(samples)-1]
+	r := &Report{
+		Start:          first.Time,
+		End:            last.Time,
+		Duration:       last.Time.Sub(first.Time),
+		Samples:        samples,
+		Messages:       last.Messages - first.Messages,
+		GoroutineStart: first.Goroutines,
+		GoroutineEnd:   last.Goroutines,
+		HeapGrowth:     heapGrowth(samples),
+	}
+	for _, s := range samples {
+		r.PeakRate = max(r.PeakRate, s.Rate)
+		r.PeakHeap = max(r.PeakHeap, s.HeapInuse)
+		r.PeakGoroutines = max(r.PeakGoroutines, s.Goroutines)
+		r.PeakQueueDepth = max(r.PeakQueueDepth, s.QueueDepth)
+	}
+	if secs := r.Duration.Seconds(); secs > 0 {
+		r.AvgRate = float64(r.Messages) / secs
+	}
+	m.mu.Lock()
+	r.Restarts = m.restarts
+	r.Errors = append([]string(nil), m.errors...)
+	m.mu.Unlock()
+
+	if r.HeapGrowth > m.opts.MaxHeapGrowth {
+		r.Problems = append(r.Problems, fmt.Sprintf("堆占用持续增长 %.1fMB/小时，疑似内存泄漏", r.HeapGrowth/(1<<20)))
+	}
+	if growth := r.GoroutineEnd - r.GoroutineStart; growth > m.opts.MaxGoroutineGrowth {
+		r.Problems = append(r.Problems, fmt.Sprintf("goroutine 增长 %d 个，疑似泄漏", growth))
+	}
+	r.Passed = len(r.Problems) == 0
+	return r
+}
+
+// heapGrowth 对采样后半段的堆占用做最小二乘拟合，返回斜率（字节/小时）。
+// 前半段包含启动与缓存预热，不参与计算
+func heapGrowth(samples []Sample) float64 {
+	samples = samples[len(samples)/2:]
+	if len(samples) < 2 {
+		return 0
+	}
+	t0 := samples[0].Time
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.Time.Sub(t0).Hours()
+		y := float64(s.HeapInuse)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+// WriteText 输出可读的报告
+func (r *Report) WriteText(w io.Writer) error {
+	result := "通过"
+	if !r.Passed {
+		result = "未通过"
+	}
+	_, err := fmt.Fprintf(w, `稳定性报告: %s
+运行时长: %s（%s ~ %s，%d 次采样）
+消息: 共 %d 条，平均 %.1f 条/秒，峰值 %.1f 条/秒
+内存: 峰值 %.1fMB，增长 %.2fMB/小时
+goroutine: %d → %d，峰值 %d
+队列深度峰值: %d
+重启次数: %d
+`, result, r.Duration.Round(time.Second), r.Start.Format(time.DateTime), r.End.Format(time.DateTime), len(r.Samples),
+		r.Messages, r.AvgRate, r.PeakRate,
+		float64(r.PeakHeap)/(1<<20), r.HeapGrowth/(1<<20),
+		r.GoroutineStart, r.GoroutineEnd, r.PeakGoroutines,
+		r.PeakQueueDepth, r.Restarts)
+	if err != nil {
+		return err
+	}
+	for _, p := range r.Problems {
+		if _, err := fmt.Fprintf(w, "问题: %s\n", p); err != nil {
+			return err
+		}
+	}
+	for _, e := range r.Errors {
+		if _, err := fmt.Fprintf(w, "错误: %s\n", e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON 以 JSON 输出完整报告（含全部采样）
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
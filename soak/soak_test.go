@@ -0,0 +1,42 @@
+package soak
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeapGrowth(t *testing.T) {
+	start := time.Now()
+	var samples []Sample
+	for i := 0; i < 10; i++ {
+		samples = append(samples, Sample{Time: start.Add(time.Duration(i) * time.Hour), HeapInuse: uint64(100 + i*10)})
+	}
+	if got := heapGrowth(samples); got < 9.99 || got > 10.01 {
+		t.Fatalf("heapGrowth = %f, want 10", got)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	var samples int
+	m := New(Options{
+		Duration: 50 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+		OnSample: func(Sample) { samples++ },
+	})
+	m.messages.Add(5)
+	m.RecordRestart(context.DeadlineExceeded)
+	r := m.Run(context.Background())
+
+	if samples == 0 || len(r.Samples) != samples+2 {
+		t.Fatalf("采样 %d 次，报告中 %d 条", samples, len(r.Samples))
+	}
+	if r.Restarts != 1 || len(r.Errors) != 1 {
+		t.Fatalf("重启记录 = %d, %v", r.Restarts, r.Errors)
+	}
+	var text strings.Builder
+	if err := r.WriteText(&text); err != nil || !strings.Contains(text.String(), "重启次数: 1") {
+		t.Fatalf("报告 = %s, %v", text.String(), err)
+	}
+}
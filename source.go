@@ -0,0 +1,54 @@
+package douyinLive
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// MessageSource 代替抖音连接的消息来源，如演示数据生成器或测试桩。
+// Run 持续调用 emit 产生消息，直到 ctx 结束或来源耗尽
+type MessageSource interface {
+	Run(ctx context.Context, emit func(*new_douyin.Webcast_Im_Message)) error
+}
+
+// WithMessageSource 使用自定义消息来源，Start 不再检查开播、签名与握手，
+// 消息经过与实时连接相同的过滤、统计与订阅分发流程
+func WithMessageSource(src MessageSource) Option {
+	return func(dl *DouyinLive) {
+		dl.source = src
+	}
+}
+
+// runSource 运行自定义消息来源，Close 时结束并返回 nil
+func (dl *DouyinLive) runSource() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	dl.mu.Lock()
+	dl.sourceCancel = cancel
+	dl.mu.Unlock()
+	defer cancel()
+
+	dl.setLiveStatus(true)
+	dl.beginReplay()
+	err := dl.source.Run(ctx, func(msg *new_douyin.Webcast_Im_Message) {
+		dl.handleSingleMessage(ctx, msg)
+	})
+	dl.endReplay()
+	dl.setLiveStatus(false)
+
+	if dl.manualClose.Load() && (err == nil || errors.Is(err, context.Canceled)) {
+		return nil
+	}
+	return err
+}
+
+// stopSource 结束自定义消息来源
+func (dl *DouyinLive) stopSource() {
+	dl.mu.Lock()
+	cancel := dl.sourceCancel
+	dl.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
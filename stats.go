@@ -0,0 +1,82 @@
+package douyinLive
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// Stats 直播间的点赞与在线人数统计
+type Stats struct {
+	Likes          uint64    // 直播间累计点赞数，取点赞消息中的 total
+	LikesReceived  uint64    // 本实例收到的点赞数之和
+	CurrentViewers uint64    // 当前在线人数
+	TotalViewers   uint64    // 累计观看人数
+	PeakViewers    uint64    // 在线人数峰值
+	UpdatedAt      time.Time // 最近一次更新时间
+	Filtered       uint64    // 被采集开关丢弃的消息数，见 SetMethodFilter
+	Timeouts       uint64    // 处理超时被跳过的消息数，见 WithHandlerTimeout
+	Muted          uint64    // 被用户名单丢弃的消息数，见 WithUserList
+	Duplicates     uint64    // 重复而被丢弃的消息数，见 WithDedup
+	Dropped        uint64    // 因分发队列已满被丢弃的消息数，见 WithDropPolicy
+	Sampled        uint64    // 未被采样保留的消息数，见 WithSampling
+
+	Features map[Feature]bool // 实验性特性的当前开关
+}
+
+// statsTracker 在读取循环中累计统计
+type statsTracker struct {
+	mu    sync.RWMutex
+	stats Stats
+}
+
+// Stats 返回当前统计的快照
+func (dl *DouyinLive) Stats() Stats {
+	dl.stats.mu.RLock()
+	s := dl.stats.stats
+	dl.stats.mu.RUnlock()
+	s.UpdatedAt = dl.localTime(s.UpdatedAt)
+	s.Filtered = dl.filtered.Load()
+	s.Timeouts = dl.handlerTimeouts.Load()
+	s.Muted = dl.muted.Load()
+	s.Duplicates = dl.duplicates.Load()
+	s.Dropped = dl.dropped.Load()
+	s.Sampled = dl.sampledOut.Load()
+	s.Features = dl.Features()
+	return s
+}
+
+// updateStats 解码点赞与在线人数消息并更新统计，其余消息忽略
+func (dl *DouyinLive) updateStats(msg *new_douyin.Webcast_Im_Message) {
+	switch msg.Method {
+	case WebcastLikeMessage:
+		var like new_douyin.Webcast_Im_LikeMessage
+		if err := proto.Unmarshal(msg.Payload, &like); err != nil {
+			dl.log().Debug("解析点赞消息失败", "error", err)
+			return
+		}
+		dl.stats.mu.Lock()
+		s := &dl.stats.stats
+		s.LikesReceived += like.Count
+		// 消息可能乱序，累计点赞数只增不减
+		s.Likes = max(s.Likes, like.Total, s.LikesReceived)
+		s.UpdatedAt = time.Now()
+		dl.stats.mu.Unlock()
+	case WebcastRoomUserSeqMessage:
+		var seq new_douyin.Webcast_Im_RoomUserSeqMessage
+		if err := proto.Unmarshal(msg.Payload, &seq); err != nil {
+			dl.log().Debug("解析在线人数消息失败", "error", err)
+			return
+		}
+		dl.stats.mu.Lock()
+		s := &dl.stats.stats
+		s.CurrentViewers = seq.Total
+		s.PeakViewers = max(s.PeakViewers, seq.Total)
+		s.TotalViewers = max(s.TotalViewers, seq.TotalUser)
+		s.UpdatedAt = time.Now()
+		dl.stats.mu.Unlock()
+	}
+}
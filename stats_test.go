@@ -0,0 +1,34 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func statsMessage(t *testing.T, method string, m proto.Message) *new_douyin.Webcast_Im_Message {
+	t.Helper()
+	payload, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &new_douyin.Webcast_Im_Message{Method: method, Payload: payload}
+}
+
+func TestStats(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil)
+	dl.updateStats(statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{Count: 5, Total: 100}))
+	dl.updateStats(statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{Count: 3, Total: 90}))
+	dl.updateStats(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 42, TotalUser: 1000}))
+	dl.updateStats(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 40, TotalUser: 1010}))
+
+	s := dl.Stats()
+	if s.Likes != 100 || s.LikesReceived != 8 {
+		t.Errorf("点赞统计错误: %+v", s)
+	}
+	if s.CurrentViewers != 40 || s.TotalViewers != 1010 {
+		t.Errorf("在线人数统计错误: %+v", s)
+	}
+}
@@ -1,12 +1,19 @@
 package douyinLive
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"log/slog"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/imroc/req/v3"
 	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -37,13 +44,132 @@ type DouyinLive struct {
 	client        *req.Client
 	conn          *websocket.Conn
 	eventHandlers []EventHandler
+	handlersMu    sync.RWMutex
 	headers       http.Header
 	bufferPool    *sync.Pool
-	isLiving      bool
+	isLiving      atomic.Bool // 直播间是否在播，读取循环与解码流水线并发访问
 	LiveName      string
-	logger        logger // 添加日志接口字段
-	manualClose   bool   // 新增字段：标记是否手动关闭
+	slog          *slog.Logger               // 结构化日志，旧的 logger 接口会被适配到这里
+	roomLog       atomic.Pointer[roomLogger] // 附带房间信息的 logger，见 log()
+	logLevel      slog.LevelVar              // 适配旧 logger 时的日志级别
+	manualClose   atomic.Bool                // 新增字段：标记是否手动关闭
+	tracer        trace.Tracer
+	errs          chan error // 终止性错误，见 Errors()
+
+	resumeMu    sync.Mutex
+	cursor      string // 最近一次 Response 中的 cursor，用于续连
+	internalExt string // 最近一次 Response 中的 internal_ext，用于续连
+	fastStart   bool   // 已知 roomID/pushID 时跳过预检直接握手，见 WithFastStart
+
+	hostMu       sync.Mutex
+	pushHosts    []string // 推送节点列表，见 WithPushHosts
+	hostIndex    int      // 当前节点在列表中的位置
+	hostFailures int      // 当前节点连续握手失败的次数
+
+	source       MessageSource      // 代替抖音连接的消息来源，见 WithMessageSource
+	sourceCancel context.CancelFunc // 结束 source，由 mu 保护
+	pollCancel   context.CancelFunc // 结束 HTTP 轮询，由 mu 保护
+	polling      atomic.Bool        // 正在使用 HTTP 轮询，见 Transport()
+
+	signatureCache    SignatureCache // 签名缓存，见 WithSignatureCache
+	signatureTTL      time.Duration
+	signerMu          sync.Mutex
+	cachedSigner      *CachedSigner       // 包在签名实现外的缓存层
+	lastSignature     string              // 最近一次连接使用的签名，握手后回报给 HandshakeReporter
+	accountCookies    []*http.Cookie      // 账号登录 cookie，见 WithCookies
+	ttwidFixed        bool                // ttwid 由 WithCookies 提供，不再单独获取
+	ttwidStore        TTWIDStore          // ttwid 缓存，见 WithTTWIDStore
+	ttwidTTL          time.Duration       // 写入 ttwidStore 时的有效期
+	cookieStore       CookieStore         // cookie jar 的持久化存储，见 WithCookieStore
+	jar               *persistentJar      // 设置了 cookieStore 时挂载到 client 的 cookie jar
+	userAgentProvider UserAgentProvider   // 为实例选择 UA，见 WithUserAgentProvider
+	location          *time.Location      // 事件与统计时间使用的时区，见 WithTimezone
+	compression       string              // 连接时请求的压缩方式，见 WithCompression
+	readDeadline      time.Duration       // 读超时，见 WithReadDeadline
+	writeDeadline     time.Duration       // 写超时，见 WithWriteDeadline
+	controlTimeout    time.Duration       // 控制帧写入超时，见 WithControlTimeout
+	tlsConfig         *tls.Config         // HTTP 与 WebSocket 共用的 TLS 配置，见 WithTLSConfig
+	tlsDial           TLSDialFunc         // 自定义的 TLS 连接函数，见 WithTLSDialer
+	hosts             map[string][]string // 固定的主机名解析，见 WithHosts
+	resolver          Resolver            // 自定义的 DNS 解析，见 WithResolver
+	proxyPool         *ProxyPool          // 代理池，见 WithProxyPool
+	proxyID           string              // 实例在代理池中的标识
+	chatGuard         *ChatGuard          // SendChat 的发送前检查
+
+	riskMu          sync.Mutex
+	msToken         string // 当前的 msToken，见 WithMsToken
+	msTokenProvider MsTokenProvider
+	aBogusSigner    ABogusSigner
+
+	methodFilter atomic.Pointer[MethodFilter]       // 按消息类型的采集开关
+	filterSource atomic.Pointer[MethodFilterSource] // 按直播间选择采集开关，见 SetMethodFilterSource
+	filtered     atomic.Uint64                      // 被采集开关丢弃的消息数
+	userList     *UserList                          // 用户黑白名单，见 WithUserList
+	muted        atomic.Uint64                      // 被用户名单丢弃的消息数
+	dedup        *msgDedup                          // 按 msgId 去重，见 WithDedup
+	duplicates   atomic.Uint64                      // 被去重丢弃的消息数
+	sampleRates  atomic.Pointer[SampleRates]        // 按消息类型的采样比例，见 WithSampling
+	sampledOut   atomic.Uint64                      // 未被采样保留的消息数
+
+	httpCache      httpCache // 页面与接口的条件请求缓存
+	pageMu         sync.Mutex
+	liveStatus     string            // 上次解析出的直播状态
+	liveStatusHash [sha256.Size]byte // 上次解析直播状态时的页面哈希
+	roomInfoHash   [sha256.Size]byte // 上次解析房间信息时的页面哈希
+	roomInfo       RoomInfo          // 上次解析出的房间信息，见 RoomInfo()
+	protocol       ProtocolParams    // 从页面解析或 WithProtocolParams 指定的协议参数，由 pageMu 保护
+	protocolFixed  bool              // 协议参数由 WithProtocolParams 固定
+
+	giftMu            sync.Mutex
+	giftCatalog       *GiftCatalog
+	giftCatalogTTL    time.Duration
+	giftFetching      bool
+	giftFetchFailedAt time.Time
+
+	timingMu sync.Mutex
+	timings  ConnectTimings // 最近一次接入的各阶段耗时
+
+	features featureFlags // 实验性特性开关
+
+	stats     statsTracker   // 点赞与在线人数统计，见 Stats()
+	summary   summaryTracker // 直播汇总，见 Summary()
+	summaryOn atomic.Bool    // 统计直播汇总，见 WithSummary
+
+	customSigner         Signer                // WithSigner 指定的签名实现
+	classifiers          []Classifier          // 事件分类器，结果附加到 LiveEvent.Tags
+	topicRules           TopicRules            // 消息类型到主题的映射，见 WithTopicRules
+	topics               topicCounter          // 各级主题的消息数，见 TopicCounts
+	transformers         []Transformer         // 事件转换器，在分类器之后修改事件内容
+	responseInterceptors []ResponseInterceptor // Response 拦截器，见 WithResponseInterceptor
+	middlewares          []Middleware          // 对全部订阅生效的处理器中间件，见 WithMiddleware
+	frameRecorder        *FrameWriter          // 录制收到的原始 PushFrame，用于离线回放
+
+	deadLetterFile string     // 死信文件，见 WithDeadLetterFile
+	deadLetterMu   sync.Mutex // 串行写入死信文件
+
+	messagePool       bool          // 复用 Response 与 Message，见 WithMessagePool
+	poolCopyEvents    bool          // 事件处理器收到消息的副本
+	decodeWorkers     int           // 大于 1 时并行解码，见 WithDecodeWorkers
+	dispatchQueueSize int           // 大于 0 时按 method 异步分发
+	dispatcher        *dispatcher   // 异步分发器，仅在 processMessages 运行期间存在
+	queued            atomic.Int64  // 异步分发队列中等待处理的消息数
+	dropPolicy        DropPolicy    // 队列满时的处理方式，见 WithDropPolicy
+	dropped           atomic.Uint64 // 因队列满被丢弃的消息数
+
+	handlerTimeout  time.Duration // 单条消息的处理超时，见 WithHandlerTimeout
+	handlerTimeouts atomic.Uint64 // 处理超时或因同类型处理卡住被丢弃的消息数
+	stuckDeliveries atomic.Int64  // 超时后仍在后台运行的处理数
+	deliveryLanes   sync.Map      // method → *deliveryLane，见 deliverWithTimeout
+
+	eventTiming bool     // 记录消息的下发与接收时间，见 WithEventTiming
+	msgTimings  sync.Map // *Webcast_Im_Message → frameTiming，deliver 时取出
+
+	logUnknown    bool              // 首次收到未知消息类型时记录日志，见 WithLogUnknownMethods
+	unknownMu     sync.Mutex        // 保护 unknownCounts
+	unknownCounts map[string]uint64 // 各未知消息类型的条数
 }
+
+// logger 兼容旧版本的日志接口，新代码推荐使用 WithSlog
 type logger interface {
 	Printf(format string, v ...interface{})
 	Print(v ...interface{})
@@ -53,6 +179,16 @@ type logger interface {
 
 // EventHandler 修改 EventHandler 类型，添加唯一ID
 type EventHandler struct {
-	ID      string
-	Handler func(*new_douyin.Webcast_Im_Message)
+	ID           string
+	Handler      func(*new_douyin.Webcast_Im_Message)
+	EventHandler func(*LiveEvent) // 通过 SubscribeEvent 注册的事件处理器
+
+	CorrectionHandler func(GiftCorrection)    // 通过 SubscribeGiftCorrection 注册的修正处理器
+	SummaryHandler    func(*Summary)          // 通过 SubscribeSummary 注册的下播汇总处理器
+	DeadLetterHandler func(*LiveEvent, error) // 通过 OnDeadLetter 注册的死信处理器
+	UnknownHandler    func(string, []byte)    // 通过 OnUnknown 注册的未知消息处理器
+
+	FrameHandler func(*new_douyin.Webcast_Im_PushFrame, []byte) // 通过 SubscribeFrame 注册的原始帧处理器
+
+	onRemove func() // Unsubscribe 时调用的清理函数
 }
@@ -0,0 +1,248 @@
+package douyinLive
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"github.com/tiga210/douyinLive/utils"
+)
+
+const (
+	// defaultLeaderboardSize 排行榜默认长度
+	defaultLeaderboardSize = 10
+	// summaryComboTTL 连击超过该时间没有新消息时不再跟踪，结束消息丢失的连击不会一直占用内存
+	summaryComboTTL = time.Minute
+)
+
+// LeaderboardEntry 排行榜中的一名用户
+type LeaderboardEntry struct {
+	UserID   uint64
+	Nickname string
+	Value    int64 // 送礼榜为抖币总价值，弹幕榜为弹幕条数
+}
+
+// Summary 一场直播的汇总
+type Summary struct {
+	RoomID         string
+	LiveName       string
+	StartTime      time.Time // 收到第一条消息的时间
+	EndTime        time.Time // 下播或生成汇总的时间
+	Duration       time.Duration
+	ChatMessages   int
+	UniqueChatters int
+	TotalDiamonds  int64
+	PeakViewers    uint64
+	TopGifters     []LeaderboardEntry
+	TopChatters    []LeaderboardEntry
+}
+
+// summaryTracker 在读取循环中累计汇总所需的数据
+type summaryTracker struct {
+	mu        sync.Mutex
+	startTime time.Time
+	endTime   time.Time
+	chats     int
+	chatters  map[uint64]*LeaderboardEntry
+	gifters   map[uint64]*LeaderboardEntry
+	combos    map[comboKey]comboProgress // 进行中的连击，连击消息的个数是累计值
+	prunedAt  time.Time                  // 上次清理过期连击的时间
+	diamonds  int64
+}
+
+// comboProgress 连击已计入的个数与最近一条消息的时间
+type comboProgress struct {
+	counted uint64
+	seenAt  time.Time
+}
+
+// WithSummary 统计直播汇总，见 Summary。SubscribeSummary 也会开启统计，
+// 未开启时不为汇总额外解码弹幕与礼物
+func WithSummary() Option {
+	return func(dl *DouyinLive) {
+		dl.summaryOn.Store(true)
+	}
+}
+
+// Summary 返回当前的直播汇总，下播后返回最终结果，需要通过 WithSummary 或 SubscribeSummary 开启统计
+func (dl *DouyinLive) Summary() Summary {
+	stats := dl.Stats()
+	t := &dl.summary
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	end := t.endTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	s := Summary{
+		RoomID:         dl.roomID,
+		LiveName:       dl.LiveName,
+		StartTime:      dl.localTime(t.startTime),
+		EndTime:        dl.localTime(end),
+		ChatMessages:   t.chats,
+		UniqueChatters: len(t.chatters),
+		TotalDiamonds:  t.diamonds,
+		PeakViewers:    stats.PeakViewers,
+		TopGifters:     leaderboard(t.gifters, defaultLeaderboardSize),
+		TopChatters:    leaderboard(t.chatters, defaultLeaderboardSize),
+	}
+	if !t.startTime.IsZero() {
+		s.Duration = end.Sub(t.startTime)
+	}
+	return s
+}
+
+// SubscribeSummary 订阅下播汇总，直播间关闭（控制消息 status=3）时回调一次，订阅后开始统计
+func (dl *DouyinLive) SubscribeSummary(handler func(*Summary)) string {
+	dl.summaryOn.Store(true)
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:             id,
+		SummaryHandler: handler,
+	})
+	return id
+}
+
+// trackSummary 开启统计时累计弹幕与礼物数据
+func (dl *DouyinLive) trackSummary(msg *new_douyin.Webcast_Im_Message) {
+	if !dl.summaryOn.Load() {
+		return
+	}
+	t := &dl.summary
+	switch msg.Method {
+	case WebcastChatMessage:
+		var chat new_douyin.Webcast_Im_ChatMessage
+		if err := proto.Unmarshal(msg.Payload, &chat); err != nil {
+			return
+		}
+		t.mu.Lock()
+		t.touch()
+		t.chats++
+		if chat.User != nil {
+			t.chatters = addLeaderboard(t.chatters, chat.User.Id, chat.User.Nickname, 1)
+		}
+		t.mu.Unlock()
+	case WebcastGiftMessage:
+		gift, err := dl.DecodeGift(NewLiveEvent(dl.roomID, dl.LiveName, msg))
+		if err != nil {
+			return
+		}
+		now := time.Now()
+		t.mu.Lock()
+		t.touch()
+		t.pruneCombos(now)
+		// 同一连击的消息个数是累计值，只计入增量
+		key := comboKey{userID: gift.UserID, giftID: gift.GiftID, groupID: gift.GroupID}
+		counted := t.combos[key].counted
+		if gift.Count > counted {
+			value := gift.DiamondCount * int64(gift.Count-counted)
+			t.diamonds += value
+			t.gifters = addLeaderboard(t.gifters, gift.UserID, gift.Nickname, value)
+		}
+		if gift.RepeatEnd || gift.GroupID == 0 {
+			delete(t.combos, key)
+		} else {
+			if t.combos == nil {
+				t.combos = make(map[comboKey]comboProgress)
+			}
+			t.combos[key] = comboProgress{counted: max(counted, gift.Count), seenAt: now}
+		}
+		t.mu.Unlock()
+	default:
+		t.mu.Lock()
+		t.touch()
+		t.mu.Unlock()
+	}
+}
+
+// pruneCombos 每隔 summaryComboTTL 删除一次过期的连击，调用方需持有锁
+func (t *summaryTracker) pruneCombos(now time.Time) {
+	if now.Sub(t.prunedAt) < summaryComboTTL {
+		return
+	}
+	t.prunedAt = now
+	for key, p := range t.combos {
+		if now.Sub(p.seenAt) >= summaryComboTTL {
+			delete(t.combos, key)
+		}
+	}
+}
+
+// correct 将礼物修正计入总价值与送礼榜
+func (t *summaryTracker) correct(c GiftCorrection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.diamonds += c.DiamondDelta
+	if c.UserID != 0 {
+		t.gifters = addLeaderboard(t.gifters, c.UserID, "", c.DiamondDelta)
+	}
+}
+
+// emitSummary 记录下播时间并通知汇总订阅者
+func (dl *DouyinLive) emitSummary() {
+	dl.summary.mu.Lock()
+	if dl.summary.endTime.IsZero() {
+		dl.summary.endTime = time.Now()
+	}
+	// 下播后不会再收到这些连击的后续消息
+	clear(dl.summary.combos)
+	dl.summary.mu.Unlock()
+
+	summary := dl.Summary()
+	dl.handlersMu.RLock()
+	handlers := dl.eventHandlers
+	dl.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		if handler.SummaryHandler != nil {
+			handler.SummaryHandler(&summary)
+		}
+	}
+}
+
+// touch 记录开始时间，调用方需持有锁
+func (t *summaryTracker) touch() {
+	if t.startTime.IsZero() {
+		t.startTime = time.Now()
+	}
+}
+
+// addLeaderboard 累加用户的排行值
+func addLeaderboard(m map[uint64]*LeaderboardEntry, userID uint64, nickname string, value int64) map[uint64]*LeaderboardEntry {
+	if m == nil {
+		m = make(map[uint64]*LeaderboardEntry)
+	}
+	entry, ok := m[userID]
+	if !ok {
+		entry = &LeaderboardEntry{UserID: userID}
+		m[userID] = entry
+	}
+	if nickname != "" {
+		entry.Nickname = nickname
+	}
+	entry.Value += value
+	return m
+}
+
+// leaderboard 按排行值降序取前 n 名
+func leaderboard(m map[uint64]*LeaderboardEntry, n int) []LeaderboardEntry {
+	entries := make([]LeaderboardEntry, 0, len(m))
+	for _, entry := range m {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Value != entries[j].Value {
+			return entries[i].Value > entries[j].Value
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
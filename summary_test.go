@@ -0,0 +1,97 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestSummary(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithSummary())
+	alice := &new_douyin.Webcast_Data_User{Id: 1, Nickname: "alice"}
+	bob := &new_douyin.Webcast_Data_User{Id: 2, Nickname: "bob"}
+
+	for _, user := range []*new_douyin.Webcast_Data_User{alice, alice, bob} {
+		dl.trackSummary(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{User: user}))
+	}
+	// bob 连击 3 次，每次 1 个 10 抖币的礼物
+	for repeat := uint64(1); repeat <= 3; repeat++ {
+		var end int32
+		if repeat == 3 {
+			end = 1
+		}
+		dl.trackSummary(statsMessage(t, WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{
+			GiftId: 5, GroupId: 7, GroupCount: 1, RepeatCount: repeat, RepeatEnd: end,
+			User: bob, Gift: &new_douyin.Webcast_Data_GiftStruct{DiamondCount: 10},
+		}))
+	}
+	dl.trackSummary(statsMessage(t, WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{
+		GiftId: 6, GroupCount: 1, RepeatCount: 1, User: alice, Gift: &new_douyin.Webcast_Data_GiftStruct{DiamondCount: 1},
+	}))
+	dl.updateStats(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 50}))
+	dl.updateStats(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 30}))
+
+	var got *Summary
+	dl.SubscribeSummary(func(s *Summary) { got = s })
+	dl.emitSummary()
+
+	if got == nil {
+		t.Fatal("未收到下播汇总")
+	}
+	if got.ChatMessages != 3 || got.UniqueChatters != 2 || got.PeakViewers != 50 || got.TotalDiamonds != 31 {
+		t.Fatalf("汇总错误: %+v", got)
+	}
+	if got.TopGifters[0].UserID != 2 || got.TopGifters[0].Value != 30 {
+		t.Fatalf("送礼榜错误: %+v", got.TopGifters)
+	}
+	if got.TopChatters[0].Nickname != "alice" || got.TopChatters[0].Value != 2 {
+		t.Fatalf("弹幕榜错误: %+v", got.TopChatters)
+	}
+}
+
+func TestSummaryDisabled(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	dl.trackSummary(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{}))
+	if s := dl.Summary(); s.ChatMessages != 0 || !s.StartTime.IsZero() {
+		t.Fatalf("未开启时不应统计: %+v", s)
+	}
+	dl.SubscribeSummary(func(*Summary) {})
+	dl.trackSummary(statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{}))
+	if s := dl.Summary(); s.ChatMessages != 1 {
+		t.Fatalf("订阅后应开始统计: %+v", s)
+	}
+}
+
+func TestSummaryExpiresCombos(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithSummary())
+	bob := &new_douyin.Webcast_Data_User{Id: 2, Nickname: "bob"}
+	combo := func(groupID uint64) *new_douyin.Webcast_Im_Message {
+		return statsMessage(t, WebcastGiftMessage, &new_douyin.Webcast_Im_GiftMessage{
+			GiftId: 5, GroupId: groupID, GroupCount: 1, RepeatCount: 1,
+			User: bob, Gift: &new_douyin.Webcast_Data_GiftStruct{DiamondCount: 10},
+		})
+	}
+	// 结束消息丢失的连击
+	dl.trackSummary(combo(7))
+	dl.summary.mu.Lock()
+	for key, p := range dl.summary.combos {
+		p.seenAt = p.seenAt.Add(-summaryComboTTL)
+		dl.summary.combos[key] = p
+	}
+	dl.summary.prunedAt = dl.summary.prunedAt.Add(-summaryComboTTL)
+	dl.summary.mu.Unlock()
+
+	dl.trackSummary(combo(8))
+	dl.summary.mu.Lock()
+	_, stale := dl.summary.combos[comboKey{userID: 2, giftID: 5, groupID: 7}]
+	n := len(dl.summary.combos)
+	dl.summary.mu.Unlock()
+	if stale || n != 1 {
+		t.Fatalf("过期的连击应被清理: stale=%v, %d 个", stale, n)
+	}
+
+	dl.emitSummary()
+	if n := len(dl.summary.combos); n != 0 {
+		t.Fatalf("下播后应清空连击: %d 个", n)
+	}
+}
@@ -0,0 +1,100 @@
+package douyinLive
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// WithHandlerTimeout 为单条消息的解码与全部处理器设置超时，超时后记录日志并继续处理下一条，
+// 防止某个卡死的处理器（如下游数据库无响应）阻塞整条流水线。
+// 超时的处理器不会被中断，仍在后台运行直到返回；在此之前同类型的新消息直接丢弃并计入 Stats().Timeouts，
+// 同一类型的处理器不会并发执行，但其他类型的消息照常处理，可能与卡住的处理器同时运行。<=0 时不限制
+func WithHandlerTimeout(d time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.handlerTimeout = d
+	}
+}
+
+// deliveryLane 一种消息类型的限时处理：复用同一个后台 goroutine 与计时器，
+// 处理超时后放弃该 goroutine，直到它返回前不再处理同类型的消息
+type deliveryLane struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	jobs  chan *new_douyin.Webcast_Im_Message // 当前的后台 goroutine，未启动或已放弃时为 nil
+	done  chan struct{}                       // 后台 goroutine 处理完一条消息
+	stuck chan *new_douyin.Webcast_Im_Message // 被放弃但仍在处理的 goroutine，没有时为 nil
+}
+
+// deliverWithTimeout 在 handlerTimeout 内处理一条消息，未设置超时时直接处理
+func (dl *DouyinLive) deliverWithTimeout(msg *new_douyin.Webcast_Im_Message) {
+	if dl.handlerTimeout <= 0 {
+		dl.deliver(msg)
+		return
+	}
+	v, ok := dl.deliveryLanes.Load(msg.Method)
+	if !ok {
+		timer := time.NewTimer(dl.handlerTimeout)
+		timer.Stop()
+		v, _ = dl.deliveryLanes.LoadOrStore(msg.Method, &deliveryLane{timer: timer})
+	}
+	lane := v.(*deliveryLane)
+
+	lane.mu.Lock()
+	defer lane.mu.Unlock()
+	if lane.stuck != nil {
+		dl.handlerTimeouts.Add(1)
+		dl.log().Warn("同类型的消息处理仍卡住，丢弃消息", "method", msg.Method, "msg_id", msg.MsgId)
+		dl.takeTiming(msg)
+		dl.releaseMessage(msg)
+		return
+	}
+	if lane.jobs == nil {
+		lane.jobs = make(chan *new_douyin.Webcast_Im_Message)
+		lane.done = make(chan struct{}, 1)
+		go dl.runLane(lane, lane.jobs, lane.done)
+	}
+	lane.jobs <- msg
+	lane.timer.Reset(dl.handlerTimeout)
+	select {
+	case <-lane.done:
+		lane.timer.Stop()
+	case <-lane.timer.C:
+		lane.stuck = lane.jobs
+		dl.stuckDeliveries.Add(1)
+		// 关闭后后台 goroutine 处理完当前消息即退出，并清除 stuck
+		close(lane.jobs)
+		lane.jobs = nil
+		dl.handlerTimeouts.Add(1)
+		dl.log().Warn("消息处理超时，跳过", "method", msg.Method, "msg_id", msg.MsgId, "timeout", dl.handlerTimeout)
+	}
+}
+
+// runLane 依次处理 jobs 中的消息，jobs 关闭后退出
+func (dl *DouyinLive) runLane(lane *deliveryLane, jobs chan *new_douyin.Webcast_Im_Message, done chan<- struct{}) {
+	for msg := range jobs {
+		dl.deliver(msg)
+		done <- struct{}{}
+	}
+	lane.mu.Lock()
+	if lane.stuck == jobs {
+		lane.stuck = nil
+		dl.stuckDeliveries.Add(-1)
+	}
+	lane.mu.Unlock()
+}
+
+// stopDeliveryLanes 结束空闲的后台 goroutine，卡住的会在处理器返回后自行退出
+func (dl *DouyinLive) stopDeliveryLanes() {
+	dl.deliveryLanes.Range(func(key, v any) bool {
+		lane := v.(*deliveryLane)
+		lane.mu.Lock()
+		if lane.jobs != nil {
+			close(lane.jobs)
+			lane.jobs = nil
+		}
+		lane.mu.Unlock()
+		return true
+	})
+}
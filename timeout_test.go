@@ -0,0 +1,62 @@
+package douyinLive
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestHandlerTimeout(t *testing.T) {
+	dl := NewDouyinLive2("100", "200", "test", "t", nil, WithHandlerTimeout(20*time.Millisecond))
+	release := make(chan struct{})
+	var handled []uint64
+	var running, overlapped atomic.Int32
+	dl.Subscribe(func(msg *new_douyin.Webcast_Im_Message) {
+		if msg.Method == WebcastChatMessage && running.Add(1) > 1 {
+			overlapped.Store(1)
+		}
+		if msg.MsgId == 1 {
+			<-release // 模拟卡死的下游
+		} else {
+			handled = append(handled, msg.MsgId)
+		}
+		if msg.Method == WebcastChatMessage {
+			running.Add(-1)
+		}
+	})
+
+	start := time.Now()
+	dl.emitEvent(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 1})
+	dl.emitEvent(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 2})
+	dl.emitEvent(&new_douyin.Webcast_Im_Message{Method: WebcastGiftMessage, MsgId: 3})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("卡死的处理器阻塞了流水线: %s", elapsed)
+	}
+	// 同类型的消息在卡住期间被丢弃，其他类型照常处理
+	if !slices.Equal(handled, []uint64{3}) {
+		t.Fatalf("handled = %v", handled)
+	}
+	if got := dl.Stats().Timeouts; got != 2 {
+		t.Fatalf("Timeouts = %d", got)
+	}
+	if got := dl.stuckDeliveries.Load(); got != 1 {
+		t.Fatalf("stuck = %d", got)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for dl.stuckDeliveries.Load() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := dl.stuckDeliveries.Load(); got != 0 {
+		t.Fatalf("处理器返回后 stuck = %d", got)
+	}
+	dl.emitEvent(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage, MsgId: 4})
+	if !slices.Equal(handled, []uint64{3, 4}) || overlapped.Load() != 0 {
+		t.Fatalf("恢复后 handled = %v, overlapped = %d", handled, overlapped.Load())
+	}
+	dl.stopDeliveryLanes()
+}
@@ -0,0 +1,205 @@
+package douyinLive
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultSampleInterval 默认采样间隔
+const defaultSampleInterval = 10 * time.Second
+
+// StatsSample 一个采样周期内的直播间统计
+type StatsSample struct {
+	Start          time.Time // 周期开始时间
+	Time           time.Time // 采样时间，即周期结束时间
+	CurrentViewers uint64    // 采样时的在线人数
+	MessageRate    float64   // 每秒消息数
+	LikeRate       float64   // 每秒点赞数
+	GiftDiamonds   int64     // 周期内结束的礼物连击总价值（抖币），含礼物修正
+	Corrected      bool      // 采样后是否被礼物修正更新过
+}
+
+// SampleSink 采样结果的输出目标
+type SampleSink interface {
+	WriteSample(sample StatsSample) error
+}
+
+// SampleCorrector 可选接口，已输出的采样被礼物修正更新后回调，用于覆盖下游的旧值
+type SampleCorrector interface {
+	CorrectSample(sample StatsSample) error
+}
+
+// SampleSinkFunc 函数形式的 SampleSink
+type SampleSinkFunc func(sample StatsSample) error
+
+// WriteSample 实现 SampleSink
+func (f SampleSinkFunc) WriteSample(sample StatsSample) error {
+	return f(sample)
+}
+
+// StatsCollectorOptions 统计采集器的配置
+type StatsCollectorOptions struct {
+	Interval   time.Duration // 采样间隔，默认 10 秒
+	MaxSamples int           // 内存中保留的最多采样数，<=0 时不限制
+	Sink       SampleSink    // 每次采样后写入，可为空
+}
+
+// StatsCollector 按固定间隔采样在线人数、消息速率、点赞速率与礼物价值，
+// 采样序列保存在内存中，用于下播后的互动分析
+type StatsCollector struct {
+	dl   *DouyinLive
+	opts StatsCollectorOptions
+
+	messages atomic.Uint64
+	diamonds atomic.Int64
+	subIDs   []string
+	combos   *GiftAggregator // 合并礼物连击，Stop 时关闭
+
+	mu        sync.Mutex
+	samples   []StatsSample
+	lastAt    time.Time
+	lastLikes uint64
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewStatsCollector 创建统计采集器，调用 Start 后开始采样
+func NewStatsCollector(dl *DouyinLive, opts StatsCollectorOptions) *StatsCollector {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSampleInterval
+	}
+	return &StatsCollector{dl: dl, opts: opts}
+}
+
+// Start 订阅事件并开始定时采样
+func (c *StatsCollector) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.reset(time.Now())
+	c.combos = NewGiftAggregator(0, func(g *GiftEvent) { c.diamonds.Add(g.TotalDiamond()) })
+	c.subIDs = []string{
+		c.dl.SubscribeEvent(func(*LiveEvent) { c.messages.Add(1) }),
+		c.dl.SubscribeGift(c.combos.Add),
+		c.dl.SubscribeGiftCorrection(c.correct),
+	}
+	c.stop = make(chan struct{})
+	c.done = make(chan struct{})
+	go c.run(c.stop, c.done)
+}
+
+// Stop 停止采样、取消订阅并关闭连击合并器，已采集的序列仍可通过 Samples 读取
+func (c *StatsCollector) Stop() {
+	c.mu.Lock()
+	stop, done := c.stop, c.done
+	c.stop = nil
+	c.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	for _, id := range c.subIDs {
+		c.dl.Unsubscribe(id)
+	}
+	c.combos.Close()
+}
+
+// Samples 返回已采集序列的副本
+func (c *StatsCollector) Samples() []StatsSample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]StatsSample(nil), c.samples...)
+}
+
+// run 定时采样
+func (c *StatsCollector) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			sample := c.sample(now)
+			if c.opts.Sink != nil {
+				if err := c.opts.Sink.WriteSample(sample); err != nil {
+					c.dl.log().Warn("写入统计采样失败", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// reset 以当前统计为基准开始新的采样周期
+func (c *StatsCollector) reset(now time.Time) {
+	c.lastAt = now
+	c.lastLikes = c.dl.Stats().LikesReceived
+	c.messages.Store(0)
+	c.diamonds.Store(0)
+}
+
+// sample 结束当前周期并记录一次采样
+func (c *StatsCollector) sample(now time.Time) StatsSample {
+	stats := c.dl.Stats()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elapsed := now.Sub(c.lastAt).Seconds()
+	sample := StatsSample{
+		Start:          c.dl.localTime(c.lastAt),
+		Time:           c.dl.localTime(now),
+		CurrentViewers: stats.CurrentViewers,
+		GiftDiamonds:   c.diamonds.Swap(0),
+	}
+	messages := c.messages.Swap(0)
+	if elapsed > 0 {
+		sample.MessageRate = float64(messages) / elapsed
+		sample.LikeRate = float64(stats.LikesReceived-c.lastLikes) / elapsed
+	}
+	c.lastAt = now
+	c.lastLikes = stats.LikesReceived
+
+	c.samples = append(c.samples, sample)
+	if c.opts.MaxSamples > 0 && len(c.samples) > c.opts.MaxSamples {
+		c.samples = c.samples[len(c.samples)-c.opts.MaxSamples:]
+	}
+	return sample
+}
+
+// correct 将礼物修正计入原礼物所在的周期，已输出的采样会通知支持 SampleCorrector 的 Sink
+func (c *StatsCollector) correct(gc GiftCorrection) {
+	c.mu.Lock()
+	if !gc.Time.Before(c.lastAt) {
+		// 仍在当前周期内，随下次采样输出
+		c.diamonds.Add(gc.DiamondDelta)
+		c.mu.Unlock()
+		return
+	}
+	var updated *StatsSample
+	for i := len(c.samples) - 1; i >= 0; i-- {
+		s := &c.samples[i]
+		if !gc.Time.Before(s.Start) && gc.Time.Before(s.Time) {
+			s.GiftDiamonds += gc.DiamondDelta
+			s.Corrected = true
+			sample := *s
+			updated = &sample
+			break
+		}
+	}
+	c.mu.Unlock()
+
+	if updated == nil {
+		c.dl.log().Warn("礼物修正超出保留的统计窗口", "time", gc.Time, "reason", gc.Reason)
+		return
+	}
+	if corrector, ok := c.opts.Sink.(SampleCorrector); ok {
+		if err := corrector.CorrectSample(*updated); err != nil {
+			c.dl.log().Warn("写入统计修正失败", "error", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package douyinLive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestStatsCollectorSample(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	dl.giftCatalog = &GiftCatalog{fetchedAt: time.Now()}
+	c := NewStatsCollector(dl, StatsCollectorOptions{Interval: time.Hour, MaxSamples: 1})
+	c.Start()
+	defer c.Stop()
+	start := c.lastAt
+
+	dl.deliver(&new_douyin.Webcast_Im_Message{Method: WebcastChatMessage})
+	dl.updateStats(statsMessage(t, WebcastLikeMessage, &new_douyin.Webcast_Im_LikeMessage{Count: 20}))
+	dl.updateStats(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 42}))
+	gift := giftLiveEvent(t, &new_douyin.Webcast_Im_GiftMessage{
+		GiftId:     1,
+		GroupCount: 2,
+		Gift:       &new_douyin.Webcast_Data_GiftStruct{DiamondCount: 10},
+	})
+	dl.deliver(gift.Message)
+
+	s := c.sample(start.Add(10 * time.Second))
+	if s.CurrentViewers != 42 || s.MessageRate != 0.2 || s.LikeRate != 2 || s.GiftDiamonds != 20 {
+		t.Fatalf("采样结果错误: %+v", s)
+	}
+	c.sample(start.Add(20 * time.Second))
+	if samples := c.Samples(); len(samples) != 1 || samples[0].GiftDiamonds != 0 {
+		t.Fatalf("MaxSamples 未生效: %+v", samples)
+	}
+}
+
+// correctingSink 记录修正回调
+type correctingSink struct {
+	corrected []StatsSample
+}
+
+func (s *correctingSink) WriteSample(StatsSample) error { return nil }
+
+func (s *correctingSink) CorrectSample(sample StatsSample) error {
+	s.corrected = append(s.corrected, sample)
+	return nil
+}
+
+func TestStatsCollectorCorrection(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithSummary())
+	sink := &correctingSink{}
+	c := NewStatsCollector(dl, StatsCollectorOptions{Interval: time.Hour, Sink: sink})
+	c.Start()
+	defer c.Stop()
+	start := c.lastAt
+
+	c.diamonds.Add(100)
+	c.sample(start.Add(10 * time.Second))
+
+	// 修正已输出的周期
+	dl.CorrectGift(GiftCorrection{UserID: 7, Time: start.Add(5 * time.Second), DiamondDelta: -30, Reason: "refund"})
+	// 修正当前周期
+	dl.CorrectGift(GiftCorrection{Time: start.Add(15 * time.Second), DiamondDelta: -10})
+	c.sample(start.Add(20 * time.Second))
+
+	samples := c.Samples()
+	if samples[0].GiftDiamonds != 70 || !samples[0].Corrected || samples[1].GiftDiamonds != -10 {
+		t.Fatalf("修正结果错误: %+v", samples)
+	}
+	if len(sink.corrected) != 1 || sink.corrected[0].GiftDiamonds != 70 {
+		t.Fatalf("SampleCorrector 未被通知: %+v", sink.corrected)
+	}
+	// 修正同时计入直播汇总
+	if summary := dl.Summary(); summary.TotalDiamonds != -40 || len(summary.TopGifters) != 1 || summary.TopGifters[0].Value != -30 {
+		t.Fatalf("Summary 未计入修正: %+v", summary)
+	}
+}
+
+func TestStatsCollectorStopClosesAggregator(t *testing.T) {
+	dl := NewDouyinLive2("1", "2", "test", "", nil)
+	dl.giftCatalog = &GiftCatalog{fetchedAt: time.Now()}
+	c := NewStatsCollector(dl, StatsCollectorOptions{Interval: time.Hour})
+	c.Start()
+	gift := giftLiveEvent(t, &new_douyin.Webcast_Im_GiftMessage{
+		GiftId:      1,
+		GroupId:     9,
+		RepeatCount: 3,
+		Gift:        &new_douyin.Webcast_Data_GiftStruct{DiamondCount: 10, Combo: true},
+	})
+	dl.deliver(gift.Message)
+	c.Stop()
+
+	c.combos.mu.Lock()
+	pending, closed := len(c.combos.pending), c.combos.closed
+	c.combos.mu.Unlock()
+	if pending != 0 || !closed {
+		t.Fatalf("Stop 后连击合并器未关闭: pending=%d closed=%v", pending, closed)
+	}
+}
@@ -0,0 +1,52 @@
+package douyinLive
+
+import "time"
+
+// DefaultTimezone 事件与统计时间默认使用的时区
+const DefaultTimezone = "Asia/Shanghai"
+
+// defaultLocation DefaultTimezone 对应的时区，系统缺少时区数据时使用固定的 UTC+8（上海没有夏令时）
+var defaultLocation = func() *time.Location {
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*60*60)
+}()
+
+// WithTimezone 设置事件与统计时间使用的时区，默认 Asia/Shanghai。
+// 事件的 Time、统计与汇总中的时间都换算到该时区，导出的事件同时带有 time_utc 与 time_local 字段
+func WithTimezone(loc *time.Location) Option {
+	return func(dl *DouyinLive) {
+		dl.location = loc
+	}
+}
+
+// Location 返回实例使用的时区
+func (dl *DouyinLive) Location() *time.Location {
+	if dl.location == nil {
+		return defaultLocation
+	}
+	return dl.location
+}
+
+// localTime 把时间换算到实例的时区，零值保持不变
+func (dl *DouyinLive) localTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(dl.Location())
+}
+
+// UTCTime 返回事件接收时间的 UTC 形式
+func (e *LiveEvent) UTCTime() time.Time {
+	return e.Time.UTC()
+}
+
+// LocalTime 返回事件接收时间在实例时区下的形式，事件不是由实例分发时使用默认时区
+func (e *LiveEvent) LocalTime() time.Time {
+	loc := e.loc
+	if loc == nil {
+		loc = defaultLocation
+	}
+	return e.Time.In(loc)
+}
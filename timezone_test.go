@@ -0,0 +1,36 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestTimezone(t *testing.T) {
+	ny := time.FixedZone("EST", -5*60*60)
+	dl, _ := NewDouyinLive("1", nil, WithTimezone(ny), WithSummary())
+	var event *LiveEvent
+	dl.SubscribeEvent(func(e *LiveEvent) { event = e })
+	dl.handleSingleMessage(context.Background(), statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{Content: "hi"}))
+	if event == nil {
+		t.Fatal("未收到事件")
+	}
+
+	fields := event.Fields()
+	utc, local := fields[FieldTimeUTC].(time.Time), fields[FieldTimeLocal].(time.Time)
+	if utc.Location() != time.UTC || local.Location() != ny || !utc.Equal(local) {
+		t.Fatalf("time_utc = %v, time_local = %v", utc, local)
+	}
+	if event.Time.Location() != ny || event.Clone().LocalTime().Location() != ny {
+		t.Fatalf("事件时间应使用实例时区: %v", event.Time)
+	}
+	if s := dl.Summary(); s.StartTime.Location() != ny || s.EndTime.Location() != ny {
+		t.Fatalf("汇总时间应使用实例时区: %v %v", s.StartTime, s.EndTime)
+	}
+
+	if _, offset := NewLiveEvent("1", "", &new_douyin.Webcast_Im_Message{}).LocalTime().Zone(); offset != 8*60*60 {
+		t.Fatalf("默认时区偏移 = %d, want UTC+8", offset)
+	}
+}
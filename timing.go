@@ -0,0 +1,77 @@
+package douyinLive
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+// EventTiming 事件在采集链路各环节的时间，未开启 WithEventTiming 时只有 Dispatched
+type EventTiming struct {
+	Server     time.Time // 服务端生成消息的时间（消息 common.create_time），解码失败时为零值
+	Pushed     time.Time // 服务端下发这批消息的时间（Response.now）
+	Received   time.Time // 本地收到这批消息的时间
+	Dispatched time.Time // 创建事件、交给处理器的时间，即 LiveEvent.Time
+}
+
+// frameTiming 一批消息共有的时间，通过 context 传给 handleSingleMessage
+type frameTiming struct {
+	pushed   time.Time
+	received time.Time
+}
+
+// frameTimingKey context 中 frameTiming 的键
+type frameTimingKey struct{}
+
+// WithEventTiming 记录每条消息的下发与接收时间，供 LiveEvent.Timing 与端到端延迟监控使用
+func WithEventTiming() Option {
+	return func(dl *DouyinLive) {
+		dl.eventTiming = true
+	}
+}
+
+// Timing 返回事件在采集链路各环节的时间
+func (e *LiveEvent) Timing() EventTiming {
+	t := EventTiming{Pushed: e.timing.pushed, Received: e.timing.received, Dispatched: e.Time}
+	if msg, err := e.Decode(); err == nil {
+		if common := messageField(msg.ProtoReflect(), "common"); common != nil {
+			if ms, ok := scalarField(common, "create_time", protoreflect.Uint64Kind); ok && ms.Uint() > 0 {
+				t.Server = time.UnixMilli(int64(ms.Uint()))
+			}
+		}
+	}
+	return t
+}
+
+// withResponseTiming 开启 WithEventTiming 时在 ctx 中记录这批消息的下发与接收时间
+func (dl *DouyinLive) withResponseTiming(ctx context.Context, response *new_douyin.Webcast_Im_Response, received time.Time) context.Context {
+	if !dl.eventTiming {
+		return ctx
+	}
+	ft := frameTiming{received: received}
+	if response.Now > 0 {
+		ft.pushed = time.UnixMilli(int64(response.Now))
+	}
+	return context.WithValue(ctx, frameTimingKey{}, ft)
+}
+
+// storeTiming 暂存消息的时间，由 deliver 取出附加到事件上
+func (dl *DouyinLive) storeTiming(ctx context.Context, msg *new_douyin.Webcast_Im_Message) {
+	if ft, ok := ctx.Value(frameTimingKey{}).(frameTiming); ok {
+		dl.msgTimings.Store(msg, ft)
+	}
+}
+
+// takeTiming 取出并删除暂存的消息时间
+func (dl *DouyinLive) takeTiming(msg *new_douyin.Webcast_Im_Message) frameTiming {
+	if !dl.eventTiming {
+		return frameTiming{}
+	}
+	if v, ok := dl.msgTimings.LoadAndDelete(msg); ok {
+		return v.(frameTiming)
+	}
+	return frameTiming{}
+}
@@ -0,0 +1,33 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestEventTiming(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithEventTiming())
+	var got EventTiming
+	dl.SubscribeEvent(func(e *LiveEvent) { got = e.Timing() })
+
+	created := time.Now().Add(-2 * time.Second).Truncate(time.Millisecond)
+	msg := statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{
+		Common: &new_douyin.Webcast_Im_Common{CreateTime: uint64(created.UnixMilli())},
+	})
+	received := time.Now()
+	response := &new_douyin.Webcast_Im_Response{Now: uint64(created.Add(time.Second).UnixMilli())}
+	dl.handleSingleMessage(dl.withResponseTiming(context.Background(), response, received), msg)
+
+	if !got.Server.Equal(created) || !got.Pushed.Equal(created.Add(time.Second)) || !got.Received.Equal(received) {
+		t.Fatalf("Timing = %+v", got)
+	}
+	if got.Dispatched.Before(got.Received) {
+		t.Fatalf("Dispatched 早于 Received: %+v", got)
+	}
+	if _, ok := dl.msgTimings.Load(msg); ok {
+		t.Fatal("分发后应删除暂存的时间")
+	}
+}
@@ -0,0 +1,27 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// TLSDialFunc 建立 TLS 连接的函数，返回的连接需已完成握手。
+// 可接入 uTLS 等实现以自定义或随机化 ClientHello 指纹
+type TLSDialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithTLSConfig 设置 HTTP 客户端与 WebSocket 连接共用的 TLS 配置，如 ServerName（覆盖 SNI）、
+// RootCAs（信任企业代理等中间人的根证书）。配置会被复制，之后修改 cfg 不再生效
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(dl *DouyinLive) {
+		dl.tlsConfig = cfg.Clone()
+	}
+}
+
+// WithTLSDialer 用 dial 建立 HTTP 客户端与 WebSocket 的 TLS 连接，
+// 此时 WithTLSConfig 的配置由 dial 自行处理
+func WithTLSDialer(dial TLSDialFunc) Option {
+	return func(dl *DouyinLive) {
+		dl.tlsDial = dial
+	}
+}
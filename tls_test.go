@@ -0,0 +1,79 @@
+package douyinLive
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestTLSConfig(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			if conn, err := upgrader.Upgrade(w, r, nil); err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	wsURL := "wss" + strings.TrimPrefix(srv.URL, "https")
+
+	plain := NewDouyinLive2("1", "2", "test", "", nil)
+	if _, err := plain.client.R().Get(srv.URL); err == nil {
+		t.Fatal("未信任测试证书时 HTTP 请求应失败")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	cfg := &tls.Config{RootCAs: roots, ServerName: "example.com"}
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithTLSConfig(cfg))
+	cfg.ServerName = "changed"
+	resp, err := dl.client.R().Get(srv.URL)
+	if err != nil || resp.String() != "ok" {
+		t.Fatalf("HTTP 请求失败: %v", err)
+	}
+	conn, _, err := dl.wsDialer().Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("WebSocket 连接失败: %v", err)
+	}
+	conn.Close()
+}
+
+func TestTLSDialer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var dials int
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		raw, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		conn := tls.Client(raw, &tls.Config{InsecureSkipVerify: true})
+		if err := conn.HandshakeContext(ctx); err != nil {
+			raw.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+	dl := NewDouyinLive2("1", "2", "test", "", nil, WithTLSDialer(dial))
+	if _, err := dl.client.R().Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if dials != 1 {
+		t.Fatalf("自定义 TLS 连接函数被调用 %d 次", dials)
+	}
+	if dl.wsDialer().NetDialTLSContext == nil {
+		t.Fatal("WebSocket Dialer 未使用自定义 TLS 连接函数")
+	}
+}
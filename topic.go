@@ -0,0 +1,153 @@
+package douyinLive
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"sync"
+)
+
+// TagTopic 分类器可以设置该标签，按内容改写事件的主题，见 LiveEvent.Topic
+const TagTopic = "topic"
+
+// 一级主题
+const (
+	TopicRevenue     = "revenue"     // 营收：礼物、粉丝团
+	TopicInteraction = "interaction" // 互动：弹幕、表情、点赞、关注
+	TopicAudience    = "audience"    // 观众：进场、在线人数、榜单
+	TopicSystem      = "system"      // 系统：控制消息、房间状态
+	TopicOther       = "other"       // 未配置规则的消息类型
+)
+
+// TopicRules 消息类型到主题的映射
+type TopicRules map[string]string
+
+// DefaultTopicRules 内置的主题映射
+var DefaultTopicRules = TopicRules{
+	WebcastGiftMessage:               "revenue.gift",
+	WebcastFansclubMessage:           "revenue.fansclub",
+	WebcastFansclubStatisticsMessage: "revenue.fansclub",
+	WebcastChatMessage:               "interaction.chat",
+	WebcastEmojiChatMessage:          "interaction.emoji",
+	WebcastLikeMessage:               "interaction.like",
+	WebcastSocialMessage:             "interaction.follow",
+	WebcastMemberMessage:             "audience.enter",
+	WebcastRoomUserSeqMessage:        "audience.online",
+	WebcastRoomStatsMessage:          "audience.stats",
+	WebcastRoomRankMessage:           "audience.rank",
+	WebcastControlMessage:            "system.control",
+	WebcastRoomMessage:               "system.room",
+}
+
+// Topic 返回消息类型所属的主题，未配置时为 TopicOther
+func (r TopicRules) Topic(method string) string {
+	if topic, ok := r[method]; ok {
+		return topic
+	}
+	return TopicOther
+}
+
+// LoadTopicRules 读取 JSON 格式的主题映射，如 {"WebcastGiftMessage": "revenue.gift"}
+func LoadTopicRules(path string) (TopicRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules TopicRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析主题映射失败: %w", err)
+	}
+	return rules, nil
+}
+
+// WithTopicRules 覆盖部分消息类型的主题，未覆盖的沿用 DefaultTopicRules
+func WithTopicRules(rules TopicRules) Option {
+	return func(dl *DouyinLive) {
+		merged := maps.Clone(DefaultTopicRules)
+		maps.Copy(merged, rules)
+		dl.topicRules = merged
+	}
+}
+
+// TopicMatch 判断主题是否匹配模式：模式为上级主题时匹配其下全部主题，
+// 如 revenue 匹配 revenue 与 revenue.gift，* 匹配全部
+func TopicMatch(topic, pattern string) bool {
+	return pattern == "*" || topic == pattern || strings.HasPrefix(topic, pattern+".")
+}
+
+// TopicMatchAny 判断主题是否匹配任一模式
+func TopicMatchAny(topic string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if TopicMatch(topic, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Topic 返回事件所属的主题，多级主题以 . 分隔，如 revenue.gift。
+// 分类器设置的 TagTopic 标签优先，其次为实例的主题映射，离线回放等场景使用 DefaultTopicRules
+func (e *LiveEvent) Topic() string {
+	if topic := e.Tags[TagTopic]; topic != "" {
+		return topic
+	}
+	if e.topic != "" {
+		return e.topic
+	}
+	return DefaultTopicRules.Topic(e.Method)
+}
+
+// SubscribeTopic 订阅匹配任一模式的事件，模式语义见 TopicMatch
+func (dl *DouyinLive) SubscribeTopic(patterns []string, handler func(*LiveEvent)) string {
+	return dl.SubscribeEvent(func(event *LiveEvent) {
+		if TopicMatchAny(event.Topic(), patterns) {
+			handler(event)
+		}
+	})
+}
+
+// rules 返回实例使用的主题映射
+func (dl *DouyinLive) rules() TopicRules {
+	if dl.topicRules != nil {
+		return dl.topicRules
+	}
+	return DefaultTopicRules
+}
+
+// tagTopic 按实例的主题映射设置事件的主题
+func (dl *DouyinLive) tagTopic(event *LiveEvent) {
+	event.topic = dl.rules().Topic(event.Method)
+}
+
+// topicCounter 按主题各级汇总的消息数
+type topicCounter struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// countTopic 按消息类型的主题计数，上级主题同时累加
+func (dl *DouyinLive) countTopic(method string) {
+	topic := dl.rules().Topic(method)
+	dl.topics.mu.Lock()
+	defer dl.topics.mu.Unlock()
+	if dl.topics.counts == nil {
+		dl.topics.counts = make(map[string]uint64)
+	}
+	for {
+		dl.topics.counts[topic]++
+		i := strings.LastIndexByte(topic, '.')
+		if i < 0 {
+			return
+		}
+		topic = topic[:i]
+	}
+}
+
+// TopicCounts 返回各级主题收到的消息数，如 revenue 与 revenue.gift 各自的计数
+func (dl *DouyinLive) TopicCounts() map[string]uint64 {
+	dl.topics.mu.Lock()
+	defer dl.topics.mu.Unlock()
+	return maps.Clone(dl.topics.counts)
+}
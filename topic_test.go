@@ -0,0 +1,42 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestTopics(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithTopicRules(TopicRules{WebcastLikeMessage: "revenue.like"}))
+	var revenue, all []string
+	dl.SubscribeTopic([]string{"revenue"}, func(e *LiveEvent) { revenue = append(revenue, e.Topic()) })
+	dl.SubscribeTopic([]string{"*"}, func(e *LiveEvent) { all = append(all, e.Topic()) })
+
+	for _, method := range []string{WebcastGiftMessage, WebcastChatMessage, WebcastLikeMessage, "WebcastUnknownMessage"} {
+		dl.handleSingleMessage(context.Background(), &new_douyin.Webcast_Im_Message{Method: method})
+	}
+
+	if len(revenue) != 2 || revenue[0] != "revenue.gift" || revenue[1] != "revenue.like" {
+		t.Fatalf("revenue = %v", revenue)
+	}
+	if len(all) != 4 || all[1] != "interaction.chat" || all[3] != TopicOther {
+		t.Fatalf("all = %v", all)
+	}
+	counts := dl.TopicCounts()
+	if counts["revenue"] != 2 || counts["revenue.gift"] != 1 || counts["interaction"] != 1 || counts["other"] != 1 {
+		t.Fatalf("counts = %v", counts)
+	}
+
+	event := NewLiveEvent("1", "", &new_douyin.Webcast_Im_Message{Method: WebcastLikeMessage})
+	if got := event.Topic(); got != "interaction.like" {
+		t.Fatalf("未经实例的事件主题 = %s, want interaction.like", got)
+	}
+	event.SetTag(TagTopic, "interaction.spam")
+	if got := event.Topic(); got != "interaction.spam" {
+		t.Fatalf("分类器改写后的主题 = %s", got)
+	}
+	if TopicMatch("revenuex", "revenue") {
+		t.Fatal("revenuex 不应匹配 revenue")
+	}
+}
@@ -0,0 +1,46 @@
+package douyinLive
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName OpenTelemetry instrumentation 名称
+const tracerName = "github.com/tiga210/douyinLive"
+
+// WithTracerProvider 设置 OpenTelemetry TracerProvider，
+// 未设置时使用 noop 实现，不产生任何 span
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(dl *DouyinLive) {
+		if tp != nil {
+			dl.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+// defaultTracer 默认的空 tracer
+func defaultTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// startSpan 创建带有房间信息的 span
+func (dl *DouyinLive) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("douyin.live_id", dl.liveID),
+		attribute.String("douyin.room_id", dl.roomID),
+	)
+	return dl.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan 结束 span，出错时记录错误并设置状态
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
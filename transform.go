@@ -0,0 +1,29 @@
+package douyinLive
+
+// Transformer 事件转换器，在分类器之后、事件交给订阅者之前修改事件内容。
+// 只作用于 SubscribeEvent 收到的 LiveEvent，Subscribe 收到的原始消息不受影响
+type Transformer interface {
+	Transform(event *LiveEvent)
+}
+
+// TransformerFunc 函数形式的 Transformer
+type TransformerFunc func(event *LiveEvent)
+
+// Transform 实现 Transformer
+func (f TransformerFunc) Transform(event *LiveEvent) {
+	f(event)
+}
+
+// WithTransformer 添加事件转换器，按添加顺序执行
+func WithTransformer(transformers ...Transformer) Option {
+	return func(dl *DouyinLive) {
+		dl.transformers = append(dl.transformers, transformers...)
+	}
+}
+
+// transform 依次执行全部转换器
+func (dl *DouyinLive) transform(event *LiveEvent) {
+	for _, t := range dl.transformers {
+		t.Transform(event)
+	}
+}
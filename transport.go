@@ -0,0 +1,36 @@
+package douyinLive
+
+import "github.com/gorilla/websocket"
+
+// initTransport 把 TLS、DNS、代理等连接配置应用到 HTTP 客户端
+func (dl *DouyinLive) initTransport() {
+	if dl.tlsConfig != nil {
+		dl.client.SetTLSClientConfig(dl.tlsConfig.Clone())
+	}
+	if dl.tlsDial != nil {
+		dl.client.SetDialTLS(dl.tlsDial)
+	}
+	if dl.customDial() {
+		dl.client.SetDial(dl.dialContext)
+	}
+	dl.initProxy()
+}
+
+// wsDialer 返回连接 WebSocket 使用的 Dialer，每次返回新的副本，不修改 websocket.DefaultDialer
+func (dl *DouyinLive) wsDialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = websocketConnectTimeout
+	if dl.tlsConfig != nil {
+		dialer.TLSClientConfig = dl.tlsConfig.Clone()
+	}
+	if dl.tlsDial != nil {
+		dialer.NetDialTLSContext = dl.tlsDial
+	}
+	if dl.customDial() {
+		dialer.NetDialContext = dl.dialContext
+	}
+	if dl.proxyPool != nil {
+		dialer.Proxy = dl.proxy
+	}
+	return &dialer
+}
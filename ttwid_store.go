@@ -0,0 +1,166 @@
+package douyinLive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ttwidURL 下发 ttwid cookie 的首页，测试中会被替换
+var ttwidURL = "https://live.douyin.com/"
+
+// defaultTTWIDTTL ttwid 缓存的默认有效期
+const defaultTTWIDTTL = 12 * time.Hour
+
+// TTWIDStore ttwid 缓存，多个实例共享同一个 store 即可复用一个 ttwid，
+// 避免同时采集大量直播间时频繁请求首页被限流。跨进程共享时可使用 signcache.RedisTTWID
+type TTWIDStore interface {
+	Get(ctx context.Context) (string, bool, error)
+	Set(ctx context.Context, ttwid string, ttl time.Duration) error
+}
+
+// ttwidGroup 合并进程内并发的 ttwid 请求
+var ttwidGroup singleflight.Group
+
+// WithTTWIDStore 获取 ttwid 时先查询 store，未命中时请求首页并写回，ttl<=0 时为 12 小时
+func WithTTWIDStore(store TTWIDStore, ttl time.Duration) Option {
+	return func(dl *DouyinLive) {
+		dl.ttwidStore, dl.ttwidTTL = store, ttl
+	}
+}
+
+// FetchTTWID 获取并返回实例使用的 ttwid，已有时直接返回，供只使用 HTTP 接口的场景
+func (dl *DouyinLive) FetchTTWID(ctx context.Context) (string, error) {
+	if dl.ttwid == "" {
+		if err := dl.fetchTTWID(ctx); err != nil {
+			return "", err
+		}
+	}
+	return dl.ttwid, nil
+}
+
+// cachedTTWID 从 store 读取或获取 ttwid，store 读写失败时直接请求首页
+func (dl *DouyinLive) cachedTTWID(ctx context.Context) (string, error) {
+	if ttwid, ok, err := dl.ttwidStore.Get(ctx); err == nil && ok && ttwid != "" {
+		return ttwid, nil
+	} else if err != nil {
+		dl.log().Warn("读取ttwid缓存失败", "error", err)
+	}
+	v, err, _ := ttwidGroup.Do("ttwid", func() (interface{}, error) {
+		ttwid, err := dl.requestTTWID(ctx)
+		if err != nil {
+			return "", err
+		}
+		ttl := dl.ttwidTTL
+		if ttl <= 0 {
+			ttl = defaultTTWIDTTL
+		}
+		if err := dl.ttwidStore.Set(ctx, ttwid, ttl); err != nil {
+			dl.log().Warn("写入ttwid缓存失败", "error", err)
+		}
+		return ttwid, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// MemoryTTWIDStore 进程内的 ttwid 缓存
+type MemoryTTWIDStore struct {
+	mu      sync.Mutex
+	ttwid   string
+	expires time.Time
+}
+
+// Get 实现 TTWIDStore
+func (s *MemoryTTWIDStore) Get(context.Context) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ttwid == "" || time.Now().After(s.expires) {
+		return "", false, nil
+	}
+	return s.ttwid, true, nil
+}
+
+// Set 实现 TTWIDStore
+func (s *MemoryTTWIDStore) Set(_ context.Context, ttwid string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttwid, s.expires = ttwid, time.Now().Add(ttl)
+	return nil
+}
+
+// FileTTWIDStore 把 ttwid 保存在本地 JSON 文件中，进程重启后仍可复用
+type FileTTWIDStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// fileTTWID 文件中保存的内容
+type fileTTWID struct {
+	TTWID   string    `json:"ttwid"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewFileTTWIDStore 创建基于文件的 ttwid 缓存
+func NewFileTTWIDStore(path string) *FileTTWIDStore {
+	return &FileTTWIDStore{Path: path}
+}
+
+// Get 实现 TTWIDStore，文件不存在时视为未命中
+func (s *FileTTWIDStore) Get(context.Context) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	var saved fileTTWID
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return "", false, err
+	}
+	if saved.TTWID == "" || time.Now().After(saved.Expires) {
+		return "", false, nil
+	}
+	return saved.TTWID, true, nil
+}
+
+// Set 实现 TTWIDStore
+func (s *FileTTWIDStore) Set(_ context.Context, ttwid string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(fileTTWID{TTWID: ttwid, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, data)
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免并发读到半个文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
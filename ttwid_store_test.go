@@ -0,0 +1,50 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTTWIDStore(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "ttwid", Value: "shared"})
+	}))
+	defer srv.Close()
+	old := ttwidURL
+	ttwidURL = srv.URL
+	defer func() { ttwidURL = old }()
+
+	path := filepath.Join(t.TempDir(), "ttwid.json")
+	for _, store := range []TTWIDStore{&MemoryTTWIDStore{}, NewFileTTWIDStore(path)} {
+		requests.Store(0)
+		for _, id := range []string{"1", "2", "3"} {
+			dl, _ := NewDouyinLive(id, nil, WithTTWIDStore(store, time.Hour))
+			if err := dl.fetchTTWID(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if dl.ttwid != "shared" {
+				t.Fatalf("ttwid = %q", dl.ttwid)
+			}
+		}
+		if n := requests.Load(); n != 1 {
+			t.Fatalf("%T: 首页请求 %d 次, want 1", store, n)
+		}
+	}
+
+	// 重启后从文件读取，过期后重新获取
+	restarted := NewFileTTWIDStore(path)
+	if ttwid, ok, err := restarted.Get(context.Background()); !ok || err != nil || ttwid != "shared" {
+		t.Fatalf("Get = %q, %v, %v", ttwid, ok, err)
+	}
+	restarted.Set(context.Background(), "expired", -time.Second)
+	if _, ok, _ := restarted.Get(context.Background()); ok {
+		t.Fatal("过期的 ttwid 不应命中")
+	}
+}
@@ -0,0 +1,64 @@
+package douyinLive
+
+import (
+	"github.com/tiga210/douyinLive/generated"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// OnUnknown 订阅未注册消息类型的原始消息体，用于发现与抓取抖音新增的消息类型。
+// 回调在读取循环中同步执行，payload 不可在回调返回后修改。返回的 ID 可用于 Unsubscribe
+func (dl *DouyinLive) OnUnknown(handler func(method string, payload []byte)) string {
+	id := utils.GenerateUniqueID()
+	dl.handlersMu.Lock()
+	defer dl.handlersMu.Unlock()
+	dl.eventHandlers = append(dl.eventHandlers, EventHandler{
+		ID:             id,
+		UnknownHandler: handler,
+	})
+	return id
+}
+
+// WithLogUnknownMethods 首次收到某个未注册的消息类型时记录一条日志
+func WithLogUnknownMethods() Option {
+	return func(dl *DouyinLive) {
+		dl.logUnknown = true
+	}
+}
+
+// UnknownMethods 返回收到过的未注册消息类型及其条数
+func (dl *DouyinLive) UnknownMethods() map[string]uint64 {
+	dl.unknownMu.Lock()
+	defer dl.unknownMu.Unlock()
+	counts := make(map[string]uint64, len(dl.unknownCounts))
+	for method, n := range dl.unknownCounts {
+		counts[method] = n
+	}
+	return counts
+}
+
+// handleUnknown 统计未注册的消息类型并通知 OnUnknown 订阅者
+func (dl *DouyinLive) handleUnknown(msg *new_douyin.Webcast_Im_Message) {
+	if generated.Known(msg.Method) {
+		return
+	}
+	dl.unknownMu.Lock()
+	if dl.unknownCounts == nil {
+		dl.unknownCounts = make(map[string]uint64)
+	}
+	dl.unknownCounts[msg.Method]++
+	first := dl.unknownCounts[msg.Method] == 1
+	dl.unknownMu.Unlock()
+	if first && dl.logUnknown {
+		dl.log().Info("发现未知消息类型", "method", msg.Method, "payload_size", len(msg.Payload))
+	}
+
+	dl.handlersMu.RLock()
+	handlers := dl.eventHandlers
+	dl.handlersMu.RUnlock()
+	for _, handler := range handlers {
+		if handler.UnknownHandler != nil {
+			_ = dl.callHandler(func() { handler.UnknownHandler(msg.Method, msg.Payload) })
+		}
+	}
+}
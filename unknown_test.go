@@ -0,0 +1,34 @@
+package douyinLive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestOnUnknown(t *testing.T) {
+	dl, _ := NewDouyinLive("1", nil, WithLogUnknownMethods())
+	var methods []string
+	var payload []byte
+	dl.OnUnknown(func(method string, p []byte) {
+		methods = append(methods, method)
+		payload = p
+	})
+	dl.OnUnknown(func(string, []byte) { panic("boom") })
+
+	for _, msg := range []*new_douyin.Webcast_Im_Message{
+		{Method: WebcastChatMessage},
+		{Method: "WebcastBrandNewMessage", Payload: []byte{1, 2}},
+		{Method: "WebcastBrandNewMessage", Payload: []byte{3}},
+	} {
+		dl.handleSingleMessage(context.Background(), msg)
+	}
+
+	if len(methods) != 2 || methods[0] != "WebcastBrandNewMessage" || string(payload) != "\x03" {
+		t.Fatalf("methods = %v, payload = %v", methods, payload)
+	}
+	if counts := dl.UnknownMethods(); len(counts) != 1 || counts["WebcastBrandNewMessage"] != 2 {
+		t.Fatalf("UnknownMethods = %v", counts)
+	}
+}
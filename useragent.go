@@ -0,0 +1,65 @@
+package douyinLive
+
+import (
+	"hash/fnv"
+
+	"github.com/tiga210/douyinLive/utils"
+)
+
+// UserAgentProvider 为直播间选择浏览器 UA，同一实例的页面请求、签名与 WebSocket 握手都使用这一个 UA
+type UserAgentProvider interface {
+	UserAgent(liveID string) string
+}
+
+// UserAgentFunc 函数形式的 UserAgentProvider
+type UserAgentFunc func(liveID string) string
+
+// UserAgent 实现 UserAgentProvider
+func (f UserAgentFunc) UserAgent(liveID string) string {
+	return f(liveID)
+}
+
+// StickyUserAgent 按直播间号的哈希从候选 UA 中固定选择一个，进程重启后同一直播间仍使用同一个 UA
+type StickyUserAgent []string
+
+// DefaultUserAgentProvider 默认的 UA 选择，候选为 utils.UserAgents
+var DefaultUserAgentProvider UserAgentProvider = StickyUserAgent(utils.UserAgents())
+
+// UserAgent 实现 UserAgentProvider，没有候选时随机生成
+func (s StickyUserAgent) UserAgent(liveID string) string {
+	if len(s) == 0 {
+		return utils.RandomUserAgent()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(liveID))
+	return s[h.Sum32()%uint32(len(s))]
+}
+
+// WithUserAgentProvider 设置 UA 的选择方式，默认为 DefaultUserAgentProvider
+func WithUserAgentProvider(p UserAgentProvider) Option {
+	return func(dl *DouyinLive) {
+		dl.userAgentProvider = p
+	}
+}
+
+// WithUserAgent 固定使用给定的 UA
+func WithUserAgent(ua string) Option {
+	return WithUserAgentProvider(UserAgentFunc(func(string) string { return ua }))
+}
+
+// UserAgent 返回实例使用的 UA
+func (dl *DouyinLive) UserAgent() string {
+	return dl.userAgent
+}
+
+// initUserAgent 为实例选择 UA 并设置到 HTTP 客户端，key 为创建时传入的直播间号或 room_id
+func (dl *DouyinLive) initUserAgent(key string) {
+	p := dl.userAgentProvider
+	if p == nil {
+		p = DefaultUserAgentProvider
+	}
+	if dl.userAgent = p.UserAgent(key); dl.userAgent == "" {
+		dl.userAgent = utils.RandomUserAgent()
+	}
+	dl.client.SetUserAgent(dl.userAgent)
+}
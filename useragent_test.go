@@ -0,0 +1,46 @@
+package douyinLive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserAgentSticky(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		http.SetCookie(w, &http.Cookie{Name: "ttwid", Value: "t"})
+	}))
+	defer srv.Close()
+	old := ttwidURL
+	ttwidURL = srv.URL
+	defer func() { ttwidURL = old }()
+
+	a, _ := NewDouyinLive("123456", nil)
+	b, _ := NewDouyinLive("123456", nil)
+	if a.UserAgent() == "" || a.UserAgent() != b.UserAgent() {
+		t.Fatalf("同一直播间的 UA 应相同: %q %q", a.UserAgent(), b.UserAgent())
+	}
+	if err := a.fetchTTWID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got != a.UserAgent() {
+		t.Fatalf("请求使用的 UA = %q, want %q", got, a.UserAgent())
+	}
+	if err := a.initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if ua := a.headers.Get("User-Agent"); ua != a.UserAgent() {
+		t.Fatalf("握手 UA = %q, want %q", ua, a.UserAgent())
+	}
+
+	fixed, _ := NewDouyinLive("123456", nil, WithUserAgent("test-agent"))
+	if err := fixed.fetchTTWID(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fixed.UserAgent() != "test-agent" || got != "test-agent" {
+		t.Fatalf("WithUserAgent 未生效: %q %q", fixed.UserAgent(), got)
+	}
+}
@@ -0,0 +1,180 @@
+package douyinLive
+
+import (
+	"regexp"
+	"sync"
+)
+
+// UserList 运行中可修改的用户黑名单与白名单，按用户 ID 或昵称正则匹配。
+// 黑名单优先于白名单；白名单非空时只放行名单内的用户。不含用户的消息（在线人数、控制消息等）不受影响。
+// 同一个 UserList 可被多个实例共享，修改在下一条消息起生效
+type UserList struct {
+	mu           sync.RWMutex
+	blockedIDs   map[uint64]bool
+	blockedNames []*regexp.Regexp
+	allowedIDs   map[uint64]bool
+	allowedNames []*regexp.Regexp
+}
+
+// NewUserList 创建空的名单，放行全部用户
+func NewUserList() *UserList {
+	return &UserList{
+		blockedIDs: make(map[uint64]bool),
+		allowedIDs: make(map[uint64]bool),
+	}
+}
+
+// Block 把用户 ID 加入黑名单
+func (l *UserList) Block(ids ...uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		l.blockedIDs[id] = true
+	}
+}
+
+// Unblock 把用户 ID 移出黑名单
+func (l *UserList) Unblock(ids ...uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		delete(l.blockedIDs, id)
+	}
+}
+
+// BlockNickname 屏蔽昵称匹配 pattern 的用户，pattern 为 Go 正则表达式
+func (l *UserList) BlockNickname(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.blockedNames = appendPattern(l.blockedNames, re)
+	return nil
+}
+
+// UnblockNickname 移除之前通过 BlockNickname 添加的 pattern
+func (l *UserList) UnblockNickname(pattern string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.blockedNames = removePattern(l.blockedNames, pattern)
+}
+
+// Allow 把用户 ID 加入白名单
+func (l *UserList) Allow(ids ...uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		l.allowedIDs[id] = true
+	}
+}
+
+// Disallow 把用户 ID 移出白名单
+func (l *UserList) Disallow(ids ...uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		delete(l.allowedIDs, id)
+	}
+}
+
+// AllowNickname 放行昵称匹配 pattern 的用户
+func (l *UserList) AllowNickname(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.allowedNames = appendPattern(l.allowedNames, re)
+	return nil
+}
+
+// DisallowNickname 移除之前通过 AllowNickname 添加的 pattern
+func (l *UserList) DisallowNickname(pattern string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.allowedNames = removePattern(l.allowedNames, pattern)
+}
+
+// Reset 清空黑名单与白名单
+func (l *UserList) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.blockedIDs = make(map[uint64]bool)
+	l.allowedIDs = make(map[uint64]bool)
+	l.blockedNames, l.allowedNames = nil, nil
+}
+
+// Permits 判断用户的消息是否放行，userID 为 0 且昵称为空表示消息不含用户
+func (l *UserList) Permits(userID uint64, nickname string) bool {
+	if userID == 0 && nickname == "" {
+		return true
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.blockedIDs[userID] || matchAny(l.blockedNames, nickname) {
+		return false
+	}
+	if len(l.allowedIDs) == 0 && len(l.allowedNames) == 0 {
+		return true
+	}
+	return l.allowedIDs[userID] || matchAny(l.allowedNames, nickname)
+}
+
+// appendPattern 追加正则，已有相同 pattern 时不重复添加
+func appendPattern(patterns []*regexp.Regexp, re *regexp.Regexp) []*regexp.Regexp {
+	for _, p := range patterns {
+		if p.String() == re.String() {
+			return patterns
+		}
+	}
+	return append(patterns, re)
+}
+
+// removePattern 返回去掉 pattern 后的新切片，不修改原切片
+func removePattern(patterns []*regexp.Regexp, pattern string) []*regexp.Regexp {
+	kept := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if p.String() != pattern {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// matchAny 昵称是否满足任一正则
+func matchAny(patterns []*regexp.Regexp, nickname string) bool {
+	if nickname == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if p.MatchString(nickname) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithUserList 在分发前按名单丢弃用户的消息，所有处理器与 sink 都收不到，
+// 被丢弃的消息计入 Stats().Muted。运行中直接修改 list 即可
+func WithUserList(list *UserList) Option {
+	return func(dl *DouyinLive) {
+		dl.userList = list
+	}
+}
+
+// UserList 返回 WithUserList 设置的名单，未设置时为 nil
+func (dl *DouyinLive) UserList() *UserList {
+	return dl.userList
+}
+
+// userPermitted 按名单判断事件是否放行，被丢弃的消息计入 Stats().Muted
+func (dl *DouyinLive) userPermitted(event *LiveEvent) bool {
+	if dl.userList == nil || dl.userList.Permits(event.UserID(), event.Nickname()) {
+		return true
+	}
+	dl.muted.Add(1)
+	return false
+}
@@ -0,0 +1,63 @@
+package douyinLive
+
+import (
+	"testing"
+
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestUserList(t *testing.T) {
+	list := NewUserList()
+	if !list.Permits(1, "路人") {
+		t.Fatal("空名单应放行全部用户")
+	}
+	list.Block(1)
+	if err := list.BlockNickname(`^广告`); err != nil {
+		t.Fatal(err)
+	}
+	if list.Permits(1, "路人") || list.Permits(2, "广告小号") || !list.Permits(3, "路人") {
+		t.Fatal("黑名单匹配错误")
+	}
+	list.Allow(3, 1)
+	if list.Permits(4, "路人") || !list.Permits(3, "路人") || list.Permits(1, "路人") {
+		t.Fatal("白名单匹配错误，黑名单应优先")
+	}
+	if !list.Permits(0, "") {
+		t.Fatal("不含用户的消息应放行")
+	}
+	list.Unblock(1)
+	list.UnblockNickname(`^广告`)
+	list.Disallow(3)
+	if !list.Permits(1, "广告小号") || list.Permits(3, "路人") {
+		t.Fatal("移出名单后匹配错误")
+	}
+	list.Reset()
+	if !list.Permits(4, "路人") {
+		t.Fatal("Reset 后应放行全部用户")
+	}
+}
+
+func TestWithUserList(t *testing.T) {
+	list := NewUserList()
+	dl, _ := NewDouyinLive("1", nil, WithUserList(list))
+	var raw, events int
+	dl.Subscribe(func(*new_douyin.Webcast_Im_Message) { raw++ })
+	dl.SubscribeEvent(func(*LiveEvent) { events++ })
+	chat := func(id uint64) *new_douyin.Webcast_Im_Message {
+		return statsMessage(t, WebcastChatMessage, &new_douyin.Webcast_Im_ChatMessage{
+			User: &new_douyin.Webcast_Data_User{Id: id, Nickname: "观众"}, Content: "你好",
+		})
+	}
+
+	dl.deliver(chat(1))
+	list.Block(1)
+	dl.deliver(chat(1))
+	dl.deliver(chat(2))
+	dl.deliver(statsMessage(t, WebcastRoomUserSeqMessage, &new_douyin.Webcast_Im_RoomUserSeqMessage{Total: 10}))
+	if raw != 3 || events != 3 {
+		t.Fatalf("收到 %d/%d 条, want 3/3", raw, events)
+	}
+	if got := dl.Stats().Muted; got != 1 {
+		t.Fatalf("Muted = %d, want 1", got)
+	}
+}
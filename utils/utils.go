@@ -27,6 +27,16 @@ func HasGzipEncoding(headers []*new_douyin.Webcast_Im_PushHeader) bool {
 	return false
 }
 
+// PayloadEncoding 返回消息头中的压缩方式（compress_type），没有时为空
+func PayloadEncoding(headers []*new_douyin.Webcast_Im_PushHeader) string {
+	for _, header := range headers {
+		if header.Key == "compress_type" {
+			return header.Value
+		}
+	}
+	return ""
+}
+
 // GetxMSStub 拼接map并返回其MD5哈希值的十六进制字符串
 func GetxMSStub(params *orderedmap.OrderedMap) string {
 	var sigParams strings.Builder
@@ -93,24 +103,41 @@ func NewOrderedMap(roomID, pushID string) *orderedmap.OrderedMap {
 	return smap
 }
 
-// RandomUserAgent 生成随机的浏览器用户代理字符串
-func RandomUserAgent() string {
-	osList := []string{
+// userAgentOS 与 userAgentChrome 组合生成浏览器用户代理字符串
+var (
+	userAgentOS = []string{
 		"(Windows NT 10.0; WOW64)", "(Windows NT 10.0; Win64; x64)",
 		"(Windows NT 6.3; WOW64)", "(Windows NT 6.3; Win64; x64)",
 		"(Windows NT 6.1; Win64; x64)", "(Windows NT 6.1; WOW64)",
 		"(X11; Linux x86_64)",
 		"(Macintosh; Intel Mac OS X 10_12_6)",
 	}
-
-	chromeVersionList := []string{
+	userAgentChrome = []string{
 		"110.0.5481.77", "110.0.5481.30", "109.0.5414.74", "108.0.5359.71",
 		"108.0.5359.22", "98.0.4758.48", "97.0.4692.71",
 	}
+)
+
+// RandomUserAgent 生成随机的浏览器用户代理字符串
+func RandomUserAgent() string {
+	os := userAgentOS[rand.Intn(len(userAgentOS))]
+	chromeVersion := userAgentChrome[rand.Intn(len(userAgentChrome))]
+	return chromeUserAgent(os, chromeVersion)
+}
 
-	os := osList[rand.Intn(len(osList))]
-	chromeVersion := chromeVersionList[rand.Intn(len(chromeVersionList))]
+// UserAgents 返回 RandomUserAgent 可能生成的全部用户代理字符串，顺序固定
+func UserAgents() []string {
+	uas := make([]string, 0, len(userAgentOS)*len(userAgentChrome))
+	for _, os := range userAgentOS {
+		for _, chromeVersion := range userAgentChrome {
+			uas = append(uas, chromeUserAgent(os, chromeVersion))
+		}
+	}
+	return uas
+}
 
+// chromeUserAgent 拼接 Chrome 浏览器的用户代理字符串
+func chromeUserAgent(os, chromeVersion string) string {
 	return fmt.Sprintf("Mozilla/5.0 %s AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s Safari/537.36", os, chromeVersion)
 }
 
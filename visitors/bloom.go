@@ -0,0 +1,147 @@
+package visitors
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// bloomMagic 导出文件的头部标识
+const bloomMagic = "DYBF"
+
+// bloomVersion 导出格式的版本
+const bloomVersion = 1
+
+// ErrBadFormat 导入的数据不是 Bloom 导出的格式
+var ErrBadFormat = errors.New("visitors: 不是有效的 bloom filter 数据")
+
+// Bloom 用户 ID 的 bloom filter，只会误判为"见过"，不会漏判。非并发安全
+type Bloom struct {
+	k    uint32   // 哈希函数个数
+	m    uint64   // 位数
+	n    uint64   // 已加入的元素数（近似，重复加入不计）
+	bits []uint64 // 位图
+}
+
+// NewBloom 按预计容量与目标误判率创建 filter，加入的元素数不超过 capacity 时误判率不高于 fpRate
+func NewBloom(capacity uint64, fpRate float64) *Bloom {
+	if capacity == 0 {
+		capacity = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint32(math.Round(float64(m) / float64(capacity) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	words := (m + 63) / 64
+	return &Bloom{k: k, m: words * 64, bits: make([]uint64, words)}
+}
+
+// Add 加入 id，返回加入前是否可能已存在
+func (b *Bloom) Add(id uint64) bool {
+	h1, h2 := bloomHash(id)
+	present := true
+	for i := uint32(0); i < b.k; i++ {
+		pos := (h1 + uint64(i)*h2) % b.m
+		word, mask := pos/64, uint64(1)<<(pos%64)
+		if b.bits[word]&mask == 0 {
+			present = false
+			b.bits[word] |= mask
+		}
+	}
+	if !present {
+		b.n++
+	}
+	return present
+}
+
+// Contains id 是否可能已存在
+func (b *Bloom) Contains(id uint64) bool {
+	h1, h2 := bloomHash(id)
+	for i := uint32(0); i < b.k; i++ {
+		pos := (h1 + uint64(i)*h2) % b.m
+		if b.bits[pos/64]&(uint64(1)<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count 返回已加入的不同元素数（近似）
+func (b *Bloom) Count() uint64 {
+	return b.n
+}
+
+// SizeBytes 返回位图占用的字节数
+func (b *Bloom) SizeBytes() int {
+	return len(b.bits) * 8
+}
+
+// FalsePositiveRate 按当前元素数估算的误判率
+func (b *Bloom) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(b.k)*float64(b.n)/float64(b.m)), float64(b.k))
+}
+
+// WriteTo 实现 io.WriterTo，导出为 "DYBF" + 版本 + k + m + n + 位图（小端序）
+func (b *Bloom) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, 4+1+4+8+8+len(b.bits)*8)
+	buf = append(buf, bloomMagic...)
+	buf = append(buf, bloomVersion)
+	buf = binary.LittleEndian.AppendUint32(buf, b.k)
+	buf = binary.LittleEndian.AppendUint64(buf, b.m)
+	buf = binary.LittleEndian.AppendUint64(buf, b.n)
+	for _, word := range b.bits {
+		buf = binary.LittleEndian.AppendUint64(buf, word)
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadFrom 实现 io.ReaderFrom，用导出的数据替换当前内容
+func (b *Bloom) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, 4+1+4+8+8)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), err
+	}
+	if string(header[:4]) != bloomMagic || header[4] != bloomVersion {
+		return int64(n), ErrBadFormat
+	}
+	k := binary.LittleEndian.Uint32(header[5:])
+	m := binary.LittleEndian.Uint64(header[9:])
+	count := binary.LittleEndian.Uint64(header[17:])
+	if k == 0 || m == 0 || m%64 != 0 || m > 1<<40 {
+		return int64(n), ErrBadFormat
+	}
+	data := make([]byte, m/8)
+	read, err := io.ReadFull(r, data)
+	total := int64(n + read)
+	if err != nil {
+		return total, err
+	}
+	bits := make([]uint64, m/64)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	b.k, b.m, b.n, b.bits = k, m, count, bits
+	return total, nil
+}
+
+// bloomHash 由 id 得到双重哈希的两个值，h2 取奇数避免为 0
+func bloomHash(id uint64) (uint64, uint64) {
+	h1 := splitmix64(id)
+	h2 := splitmix64(h1) | 1
+	return h1, h2
+}
+
+// splitmix64 64 位整数的混洗函数
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,164 @@
+// Package visitors 新观众识别：用可持久化的 bloom filter 记录见过的用户，几百万用户 ID 只占约 10 MB 内存。
+// 新观众有 FalsePositiveRate 的概率被误判为老观众，老观众不会被误判为新观众
+package visitors
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tiga210/douyinLive"
+)
+
+// TagViewer 事件上标注观众新旧的标签名，取值为 ViewerNew 或 ViewerReturning
+const TagViewer = "viewer"
+
+// TagViewer 的取值
+const (
+	ViewerNew       = "new"
+	ViewerReturning = "returning"
+)
+
+// 默认的预计容量与误判率
+const (
+	defaultCapacity          = 5_000_000
+	defaultFalsePositiveRate = 0.001
+)
+
+// Options 配置
+type Options struct {
+	Capacity          uint64  // 预计的历史用户数，默认 500 万
+	FalsePositiveRate float64 // 达到 Capacity 时的误判率，默认 0.1%
+	Path              string  // 持久化文件，非空时 New 会加载已有的数据，Save 写回该文件
+}
+
+// Tracker 记录见过的用户，实现 douyinLive.Classifier，可并发使用
+type Tracker struct {
+	opts Options
+
+	mu    sync.Mutex
+	bloom *Bloom
+}
+
+// New 创建 Tracker，Path 指向的文件存在时加载其中的数据
+func New(opts Options) (*Tracker, error) {
+	if opts.Capacity == 0 {
+		opts.Capacity = defaultCapacity
+	}
+	if opts.FalsePositiveRate <= 0 {
+		opts.FalsePositiveRate = defaultFalsePositiveRate
+	}
+	t := &Tracker{opts: opts, bloom: NewBloom(opts.Capacity, opts.FalsePositiveRate)}
+	if opts.Path == "" {
+		return t, nil
+	}
+	f, err := os.Open(opts.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := t.Import(f); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Observe 记录用户，返回是否为首次见到，userID 为 0 时返回 false
+func (t *Tracker) Observe(userID uint64) bool {
+	if userID == 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.bloom.Add(userID)
+}
+
+// Seen 用户是否（可能）见过，不记录
+func (t *Tracker) Seen(userID uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bloom.Contains(userID)
+}
+
+// Classify 实现 douyinLive.Classifier，为带用户的事件标注 TagViewer 并记录该用户
+func (t *Tracker) Classify(event *douyinLive.LiveEvent) map[string]string {
+	userID := event.UserID()
+	if userID == 0 {
+		return nil
+	}
+	if t.Observe(userID) {
+		return map[string]string{TagViewer: ViewerNew}
+	}
+	return map[string]string{TagViewer: ViewerReturning}
+}
+
+// Option 返回把 Tracker 注册为分类器的选项
+func (t *Tracker) Option() douyinLive.Option {
+	return douyinLive.WithClassifier(t)
+}
+
+// Stats Tracker 的当前状态
+type Stats struct {
+	Users             uint64  // 记录的用户数（近似）
+	SizeBytes         int     // 占用的内存
+	FalsePositiveRate float64 // 按当前用户数估算的误判率
+}
+
+// Stats 返回当前状态
+func (t *Tracker) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Stats{Users: t.bloom.Count(), SizeBytes: t.bloom.SizeBytes(), FalsePositiveRate: t.bloom.FalsePositiveRate()}
+}
+
+// Export 导出全部数据
+func (t *Tracker) Export(w io.Writer) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, err := t.bloom.WriteTo(w)
+	return err
+}
+
+// Import 用 Export 导出的数据替换当前内容，容量与误判率以导入的数据为准
+func (t *Tracker) Import(r io.Reader) error {
+	b := &Bloom{}
+	if _, err := b.ReadFrom(r); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.bloom = b
+	t.mu.Unlock()
+	return nil
+}
+
+// Save 把数据写回 Options.Path，先写临时文件再重命名
+func (t *Tracker) Save() error {
+	if t.opts.Path == "" {
+		return errors.New("visitors: 未设置 Path")
+	}
+	var buf bytes.Buffer
+	if err := t.Export(&buf); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.opts.Path), filepath.Base(t.opts.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), t.opts.Path)
+}
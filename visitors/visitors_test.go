@@ -0,0 +1,62 @@
+package visitors
+
+import (
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tiga210/douyinLive"
+	"github.com/tiga210/douyinLive/generated/new_douyin"
+)
+
+func TestBloomFalsePositiveRate(t *testing.T) {
+	b := NewBloom(10000, 0.01)
+	for id := uint64(1); id <= 10000; id++ {
+		b.Add(id)
+	}
+	var fp int
+	for id := uint64(1_000_000); id < 1_100_000; id++ {
+		if b.Contains(id) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / 100000; rate > 0.02 {
+		t.Fatalf("误判率 %.4f 超出预期", rate)
+	}
+	for id := uint64(1); id <= 10000; id++ {
+		if !b.Contains(id) {
+			t.Fatalf("漏判 %d", id)
+		}
+	}
+}
+
+func TestTrackerPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "viewers.bf")
+	tracker, err := New(Options{Capacity: 1000, Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, _ := proto.Marshal(&new_douyin.Webcast_Im_MemberMessage{User: &new_douyin.Webcast_Data_User{Id: 42}})
+	event := douyinLive.NewLiveEvent("1", "", &new_douyin.Webcast_Im_Message{Method: douyinLive.WebcastMemberMessage, Payload: payload})
+	if tags := tracker.Classify(event); tags[TagViewer] != ViewerNew {
+		t.Fatalf("首次进场 = %v", tags)
+	}
+	if tags := tracker.Classify(event); tags[TagViewer] != ViewerReturning {
+		t.Fatalf("再次进场 = %v", tags)
+	}
+	if err := tracker.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := New(Options{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.Seen(42) || restarted.Observe(42) || !restarted.Observe(43) {
+		t.Fatal("重启后应记得 42")
+	}
+	if s := restarted.Stats(); s.Users != 2 || s.SizeBytes != tracker.Stats().SizeBytes {
+		t.Fatalf("Stats = %+v", s)
+	}
+}